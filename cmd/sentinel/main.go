@@ -2,9 +2,11 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -25,7 +27,8 @@ func main() {
 		Short: "Self-hosted uptime monitoring",
 		Long:  "Sentinel monitors your services and alerts you when they go down.",
 		Run: func(cmd *cobra.Command, args []string) {
-			serve()
+			memory, _ := cmd.Flags().GetBool("memory")
+			serve(memory)
 		},
 	}
 
@@ -33,10 +36,16 @@ func main() {
 		Use:   "serve",
 		Short: "Start the monitoring server",
 		Run: func(cmd *cobra.Command, args []string) {
-			serve()
+			memory, _ := cmd.Flags().GetBool("memory")
+			serve(memory)
 		},
 	}
 
+	// Nothing is written to the database path; for demos and trying config out
+	for _, cmd := range []*cobra.Command{rootCmd, serveCmd} {
+		cmd.Flags().Bool("memory", false, "Keep all data in memory, losing it on exit")
+	}
+
 	versionCmd := &cobra.Command{
 		Use:   "version",
 		Short: "Print version information",
@@ -81,8 +90,120 @@ func main() {
 		},
 	}
 
-	checkCmd.AddCommand(checkAddCmd, checkListCmd, checkTestCmd)
-	rootCmd.AddCommand(serveCmd, versionCmd, checkCmd)
+	checkRmCmd := &cobra.Command{
+		Use:   "rm <id>",
+		Short: "Delete a check and its history",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			checkRemove(args[0])
+		},
+	}
+
+	checkEnableCmd := &cobra.Command{
+		Use:   "enable <id>",
+		Short: "Enable a check",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			checkSetEnabled(args[0], true)
+		},
+	}
+
+	checkDisableCmd := &cobra.Command{
+		Use:   "disable <id>",
+		Short: "Disable a check without deleting it",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			checkSetEnabled(args[0], false)
+		},
+	}
+
+	checkImportCmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Add checks from a JSON or YAML file",
+		Long: `Add checks from a JSON or YAML list with the API's fields, e.g.
+[{"name":"API","url":"https://api.example.com/health","interval_seconds":60}]
+
+Checks whose URL is already monitored are skipped, and invalid ones are
+reported without stopping the rest.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			checkImport(args[0])
+		},
+	}
+
+	checkExportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Print every check as config YAML",
+		Long: `Print every check in the config file's checks format, e.g. to keep
+monitoring under version control:
+
+  sentinel check export > checks.yaml
+
+The file can be listed under include in another instance's config.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			checkExport()
+		},
+	}
+
+	checkCmd.AddCommand(checkAddCmd, checkListCmd, checkTestCmd, checkRmCmd, checkEnableCmd, checkDisableCmd, checkImportCmd, checkExportCmd)
+
+	// Result commands
+	resultsCmd := &cobra.Command{
+		Use:   "results",
+		Short: "Manage check results",
+	}
+
+	resultsImportCmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import historical results from a JSON file",
+		Long: `Import historical results from a JSON array of results, e.g.
+[{"check_id":1,"status":"up","status_code":200,"response_time_ms":120,"checked_at":"2026-01-01T00:00:00Z"}]
+
+Results already stored for the same check and time are skipped.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			resultsImport(args[0])
+		},
+	}
+
+	// Exporting is both "export-results <id>" and "results export <id>",
+	// beside import
+	newResultsExportCmd := func(use string) *cobra.Command {
+		cmd := &cobra.Command{
+			Use:   use + " <id>",
+			Short: "Print a check's results as CSV",
+			Long: `Print a check's results between --from and --to (RFC3339, default the
+last 7 days) as CSV, oldest first, e.g. for a compliance report:
+
+  sentinel export-results 3 --from 2026-01-01T00:00:00Z > api-january.csv`,
+			Args: cobra.ExactArgs(1),
+			Run: func(cmd *cobra.Command, args []string) {
+				resultsExport(cmd, args[0])
+			},
+		}
+		cmd.Flags().String("from", "", "Start of the range, RFC3339 (default 7 days before --to)")
+		cmd.Flags().String("to", "", "End of the range, RFC3339 (default now)")
+		return cmd
+	}
+
+	resultsCmd.AddCommand(resultsImportCmd, newResultsExportCmd("export"))
+
+	backupCmd := &cobra.Command{
+		Use:   "backup <path>",
+		Short: "Write a consistent copy of the database to a new file",
+		Long: `Write a consistent snapshot of the SQLite database to a new file. It's
+safe while sentinel serve is running and writing results; restore by
+pointing database.path at the copy.
+
+  sentinel backup sentinel-2026-10-15.db`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			backup(args[0])
+		},
+	}
+
+	rootCmd.AddCommand(serveCmd, versionCmd, checkCmd, resultsCmd, newResultsExportCmd("export-results"), backupCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -90,7 +211,7 @@ func main() {
 	}
 }
 
-func serve() {
+func serve(memory bool) {
 	fmt.Printf("Sentinel %s starting...\n", Version)
 
 	// Load configuration
@@ -100,8 +221,19 @@ func serve() {
 		os.Exit(1)
 	}
 
+	// Suspicious settings are logged but don't stop startup
+	for _, warning := range cfg.ValidateAll().Warnings {
+		fmt.Printf("Config warning: %s\n", warning)
+	}
+
 	// Initialize storage
-	store, err := storage.NewSQLiteStorage(cfg.Database.Path)
+	var store storage.Storage
+	if memory {
+		fmt.Println("Keeping data in memory; it is lost on exit")
+		store, err = storage.NewMemoryStorage()
+	} else {
+		store, err = openStorage(&cfg.Database)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to initialize storage: %v\n", err)
 		os.Exit(1)
@@ -116,16 +248,7 @@ func serve() {
 			continue
 		}
 
-		check := &storage.Check{
-			Name:           checkCfg.Name,
-			URL:            checkCfg.URL,
-			IntervalSecs:   int(checkCfg.GetInterval().Seconds()),
-			TimeoutSecs:    int(checkCfg.GetTimeout().Seconds()),
-			ExpectedStatus: checkCfg.GetExpectedStatus(),
-			Enabled:        checkCfg.IsEnabled(),
-			Tags:           checkCfg.Tags,
-		}
-
+		check := checker.CheckFromConfig(&checkCfg)
 		if err := store.CreateCheck(check); err != nil {
 			fmt.Printf("Failed to create check %s: %v\n", checkCfg.Name, err)
 		} else {
@@ -133,16 +256,89 @@ func serve() {
 		}
 	}
 
+	// Cache per-check stats so dashboard views don't re-run the aggregate queries
+	var st storage.Storage = store
+	if ttl := cfg.Database.GetStatsCacheTTL(); ttl > 0 {
+		st = storage.NewStatsCache(store, ttl)
+	}
+
+	// Configured cause categories take precedence over the built-in ones
+	var causeRules []checker.CauseRule
+	for _, rule := range cfg.Alerts.CauseRules {
+		causeRules = append(causeRules, checker.CauseRule{Match: rule.Match, Category: rule.Category})
+	}
+	checker.SetCauseRules(causeRules)
+
 	// Initialize alerter
-	alertMgr := alerter.NewManager(&cfg.Alerts, store)
+	alertMgr := alerter.NewManager(&cfg.Alerts, st)
+
+	// Send alerts held outside business hours once they start again
+	stopDigest := make(chan struct{})
+	if cfg.Alerts.BusinessHours.Enabled {
+		go alertMgr.RunDigest(stopDigest)
+	}
 
 	// Initialize scheduler
-	sched := checker.NewScheduler(store, alertMgr, checker.SchedulerConfig{
+	schedCfg := checker.SchedulerConfig{
 		ConsecutiveFailures: cfg.Alerts.ConsecutiveFailures,
 		RetentionDays:       cfg.Retention.ResultsDays,
 		AggregatesDays:      cfg.Retention.AggregatesDays,
+		ArchiveDays:         cfg.Retention.ArchiveDays,
 		SSLExpiryDays:       cfg.Alerts.SSLExpiryDays,
-	})
+		AlertOnFirstCheck:   cfg.Alerts.AlertOnFirstCheck,
+		MaxConcurrentChecks: cfg.Limits.GetMaxConcurrentChecks(),
+		StaleIntervals:      cfg.Alerts.GetStaleIntervals(),
+		StartupRamp:         cfg.Limits.GetStartupRamp(),
+		RetryOn:             cfg.Limits.RetryOn,
+		SlowMultiplier:      cfg.Alerts.SlowMultiplier,
+		SlowCooldown:        time.Duration(cfg.Alerts.CooldownMinutes) * time.Minute,
+		Transport: checker.TransportLimits{
+			MaxConnsPerHost: cfg.Limits.GetMaxConnsPerHost(),
+			MaxIdleConns:    cfg.Limits.GetMaxIdleConns(),
+		},
+	}
+	if cfg.Checker.CABundle != "" {
+		schedCfg.RootCAs, err = checker.LoadCABundle(cfg.Checker.CABundle)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load checker.ca_bundle: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if cfg.Drift.Enabled {
+		schedCfg.Drift = cfg.Checks
+		schedCfg.DriftInterval = cfg.Drift.GetInterval()
+		schedCfg.DriftCorrect = cfg.Drift.Corrects()
+	}
+	sched := checker.NewScheduler(st, alertMgr, schedCfg)
+
+	// Raw state change feed, independent of alert thresholds, for the events
+	// webhook and the dashboard's live updates
+	broker := web.NewEventBroker()
+	sinks := checker.EventSinks{broker}
+	if cfg.Events.Enabled {
+		sinks = append(sinks, alerter.NewEventSender(&cfg.Events))
+	}
+	var statsd *alerter.StatsDSender
+	if cfg.StatsD.Enabled {
+		statsd, err = alerter.NewStatsDSender(&cfg.StatsD)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "WARNING: statsd disabled: %v\n", err)
+		} else {
+			sinks = append(sinks, statsd)
+		}
+	}
+	sched.SetEventSink(sinks)
+
+	// Warn, but keep going, when the host can't reach the outside world; every
+	// check would fail and it would look like the targets are down
+	if url := cfg.Server.ConnectivityCheckURL; url != "" {
+		if err := checker.CheckConnectivity(url); err != nil {
+			fmt.Fprintf(os.Stderr, "WARNING: outbound connectivity check failed: %v\n", err)
+			fmt.Fprintf(os.Stderr, "WARNING: checks will likely fail until this host's egress is fixed; this is not a problem with your targets\n")
+		} else {
+			fmt.Printf("Outbound connectivity OK (%s)\n", url)
+		}
+	}
 
 	// Start scheduler
 	if err := sched.Start(); err != nil {
@@ -151,7 +347,9 @@ func serve() {
 	}
 
 	// Initialize web server
-	server := web.NewServer(&cfg.Server, cfg, store, sched, cfg.Server.Users, nil, nil)
+	server := web.NewServer(&cfg.Server, cfg, st, sched, cfg.Server.Users, nil, nil)
+	server.SetAlertManager(alertMgr)
+	server.SetEventBroker(broker)
 
 	// Handle shutdown
 	quit := make(chan os.Signal, 1)
@@ -171,6 +369,10 @@ func serve() {
 	defer cancel()
 
 	sched.Stop()
+	close(stopDigest)
+	if statsd != nil {
+		statsd.Close()
+	}
 
 	if err := server.Shutdown(ctx); err != nil {
 		fmt.Fprintf(os.Stderr, "Server shutdown error: %v\n", err)
@@ -186,7 +388,7 @@ func checkAdd(cmd *cobra.Command, url string) {
 		os.Exit(1)
 	}
 
-	store, err := storage.NewSQLiteStorage(cfg.Database.Path)
+	store, err := openStorage(&cfg.Database)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to initialize storage: %v\n", err)
 		os.Exit(1)
@@ -230,7 +432,7 @@ func checkList() {
 		os.Exit(1)
 	}
 
-	store, err := storage.NewSQLiteStorage(cfg.Database.Path)
+	store, err := openStorage(&cfg.Database)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to initialize storage: %v\n", err)
 		os.Exit(1)
@@ -267,6 +469,74 @@ func checkList() {
 	}
 }
 
+func checkRemove(idArg string) {
+	store, check := loadCheck(idArg)
+	defer store.Close()
+
+	if err := store.DeleteCheck(check.ID); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to delete check: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Deleted check: %s (ID: %d)\n", check.Name, check.ID)
+}
+
+func checkSetEnabled(idArg string, enabled bool) {
+	store, check := loadCheck(idArg)
+	defer store.Close()
+
+	action := "Enabled"
+	if !enabled {
+		action = "Disabled"
+	}
+
+	if check.Enabled != enabled {
+		check.Enabled = enabled
+		if err := store.UpdateCheck(check); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to update check: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	fmt.Printf("%s check: %s (ID: %d)\n", action, check.Name, check.ID)
+}
+
+// loadCheck opens storage and fetches the check with the given ID, exiting
+// if it doesn't exist. The caller closes the store.
+func loadCheck(idArg string) (storage.Storage, *storage.Check) {
+	id, err := strconv.ParseInt(idArg, 10, 64)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid check ID: %s\n", idArg)
+		os.Exit(1)
+	}
+
+	cfg, err := config.LoadWithEnv("sentinel.yaml")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	store, err := openStorage(&cfg.Database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize storage: %v\n", err)
+		os.Exit(1)
+	}
+
+	check, err := store.GetCheck(id)
+	if err != nil {
+		store.Close()
+		fmt.Fprintf(os.Stderr, "Failed to load check: %v\n", err)
+		os.Exit(1)
+	}
+	if check == nil {
+		store.Close()
+		fmt.Fprintf(os.Stderr, "Check %d not found\n", id)
+		os.Exit(1)
+	}
+
+	return store, check
+}
+
 func checkTest(url string) {
 	fmt.Printf("Testing %s...\n", url)
 
@@ -292,3 +562,190 @@ func checkTest(url string) {
 		os.Exit(1)
 	}
 }
+
+func checkImport(path string) {
+	cfg, err := config.LoadWithEnv("sentinel.yaml")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read %s: %v\n", path, err)
+		os.Exit(1)
+	}
+
+	inputs, err := checker.ParseCheckImport(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to parse %s: %v\n", path, err)
+		os.Exit(1)
+	}
+
+	store, err := openStorage(&cfg.Database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize storage: %v\n", err)
+		os.Exit(1)
+	}
+	defer store.Close()
+
+	summary, _ := checker.ImportChecks(store, inputs)
+	for _, f := range summary.Failed {
+		fmt.Fprintf(os.Stderr, "Entry %d (%s): %s\n", f.Index+1, f.URL, f.Error)
+	}
+
+	fmt.Printf("Created %d checks (%d already monitored, %d failed)\n", len(summary.Created), len(summary.Skipped), len(summary.Failed))
+	if len(summary.Failed) > 0 {
+		os.Exit(1)
+	}
+}
+
+func checkExport() {
+	cfg, err := config.LoadWithEnv("sentinel.yaml")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	store, err := openStorage(&cfg.Database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize storage: %v\n", err)
+		os.Exit(1)
+	}
+	defer store.Close()
+
+	checks, err := store.ListChecks()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to list checks: %v\n", err)
+		os.Exit(1)
+	}
+
+	data, err := checker.ExportChecks(checks)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	os.Stdout.Write(data)
+}
+
+func resultsImport(path string) {
+	cfg, err := config.LoadWithEnv("sentinel.yaml")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read %s: %v\n", path, err)
+		os.Exit(1)
+	}
+
+	var results []*storage.CheckResult
+	if err := json.Unmarshal(data, &results); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to parse %s: %v\n", path, err)
+		os.Exit(1)
+	}
+
+	store, err := openStorage(&cfg.Database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize storage: %v\n", err)
+		os.Exit(1)
+	}
+	defer store.Close()
+
+	// Every result must belong to an existing check
+	known := make(map[int64]bool)
+	for _, r := range results {
+		if known[r.CheckID] {
+			continue
+		}
+		check, err := store.GetCheck(r.CheckID)
+		if err != nil || check == nil {
+			fmt.Fprintf(os.Stderr, "Unknown check ID %d\n", r.CheckID)
+			os.Exit(1)
+		}
+		known[r.CheckID] = true
+	}
+
+	imported, skipped, err := store.ImportResults(results)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to import results: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Roll completed hours up so history survives results retention
+	if err := store.AggregateResults(time.Now().Truncate(time.Hour)); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to aggregate results: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Imported %d results (%d duplicates skipped)\n", imported, skipped)
+}
+
+func resultsExport(cmd *cobra.Command, idArg string) {
+	to := time.Now()
+	if v, _ := cmd.Flags().GetString("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid --to time (expected RFC3339): %s\n", v)
+			os.Exit(1)
+		}
+		to = t
+	}
+	from := to.Add(-7 * 24 * time.Hour)
+	if v, _ := cmd.Flags().GetString("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid --from time (expected RFC3339): %s\n", v)
+			os.Exit(1)
+		}
+		from = t
+	}
+	if !from.Before(to) {
+		fmt.Fprintln(os.Stderr, "--from must be before --to")
+		os.Exit(1)
+	}
+
+	store, check := loadCheck(idArg)
+	defer store.Close()
+
+	results, err := store.GetResultsInRange(check.ID, from, to)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load results: %v\n", err)
+		os.Exit(1)
+	}
+	if err := storage.WriteResultsCSV(os.Stdout, results); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write results: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func backup(path string) {
+	cfg, err := config.LoadWithEnv("sentinel.yaml")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	store, err := openStorage(&cfg.Database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize storage: %v\n", err)
+		os.Exit(1)
+	}
+	defer store.Close()
+
+	if err := store.Backup(path); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to back up: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Backed up to %s\n", path)
+}
+
+// openStorage opens the backend the database config selects
+func openStorage(cfg *config.DatabaseConfig) (storage.Storage, error) {
+	if cfg.GetDriver() == config.DriverPostgres {
+		return storage.NewPostgresStorage(cfg.DSN)
+	}
+	return storage.NewSQLiteStorage(cfg.Path)
+}
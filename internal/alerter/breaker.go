@@ -0,0 +1,108 @@
+package alerter
+
+import (
+	"sync"
+	"time"
+)
+
+// Circuit breaker states reported by the channels API
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half_open"
+)
+
+// ChannelState is the delivery health of one alert channel
+type ChannelState struct {
+	Channel             string     `json:"channel"`
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenUntil           *time.Time `json:"open_until,omitempty"`
+}
+
+// circuitBreaker stops delivering to a channel after repeated failures. Once
+// the cooldown has passed a single probe delivery is let through; success
+// closes the circuit, failure opens it for another cooldown.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	channels map[string]*breakerChannel
+}
+
+type breakerChannel struct {
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		channels:  make(map[string]*breakerChannel),
+	}
+}
+
+// allow reports whether a delivery to the channel should be attempted
+func (b *circuitBreaker) allow(channel string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.channels[channel]
+	if !ok || c.failures < b.threshold {
+		return true
+	}
+	if c.probing || time.Since(c.openedAt) < b.cooldown {
+		return false
+	}
+	c.probing = true
+	return true
+}
+
+// record updates the channel after a delivery attempt
+func (b *circuitBreaker) record(channel string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.channels[channel]
+	if !ok {
+		c = &breakerChannel{}
+		b.channels[channel] = c
+	}
+	c.probing = false
+
+	if err == nil {
+		c.failures = 0
+		return
+	}
+	c.failures++
+	if c.failures >= b.threshold {
+		c.openedAt = time.Now()
+	}
+}
+
+func (b *circuitBreaker) state(channel string) ChannelState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s := ChannelState{Channel: channel, State: BreakerClosed}
+	c, ok := b.channels[channel]
+	if !ok {
+		return s
+	}
+	s.ConsecutiveFailures = c.failures
+	if c.failures < b.threshold {
+		return s
+	}
+
+	until := c.openedAt.Add(b.cooldown)
+	if c.probing || time.Now().After(until) {
+		s.State = BreakerHalfOpen
+	} else {
+		s.State = BreakerOpen
+		s.OpenUntil = &until
+	}
+	return s
+}
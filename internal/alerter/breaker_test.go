@@ -0,0 +1,61 @@
+package alerter
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerOpensAfterFailures(t *testing.T) {
+	b := newCircuitBreaker(2, time.Hour)
+	failure := errors.New("webhook returned status 500")
+
+	b.record("slack", failure)
+	if !b.allow("slack") {
+		t.Error("expected channel to stay closed below the threshold")
+	}
+
+	b.record("slack", failure)
+	if b.allow("slack") {
+		t.Error("expected channel to be skipped once the circuit opens")
+	}
+	if s := b.state("slack"); s.State != BreakerOpen || s.OpenUntil == nil || s.ConsecutiveFailures != 2 {
+		t.Errorf("unexpected state: %+v", s)
+	}
+
+	// Other channels are unaffected
+	if !b.allow("email") {
+		t.Error("expected other channels to stay closed")
+	}
+}
+
+func TestCircuitBreakerProbesAfterCooldown(t *testing.T) {
+	b := newCircuitBreaker(1, 10*time.Millisecond)
+	b.record("discord", errors.New("timeout"))
+
+	time.Sleep(20 * time.Millisecond)
+
+	if !b.allow("discord") {
+		t.Fatal("expected a probe after the cooldown")
+	}
+	if b.allow("discord") {
+		t.Error("expected only one probe at a time")
+	}
+	if s := b.state("discord"); s.State != BreakerHalfOpen {
+		t.Errorf("expected half_open while probing, got %s", s.State)
+	}
+
+	// A failed probe reopens the circuit
+	b.record("discord", errors.New("timeout"))
+	if b.allow("discord") {
+		t.Error("expected failed probe to reopen the circuit")
+	}
+
+	// A successful probe closes it
+	time.Sleep(20 * time.Millisecond)
+	b.allow("discord")
+	b.record("discord", nil)
+	if s := b.state("discord"); s.State != BreakerClosed || s.ConsecutiveFailures != 0 {
+		t.Errorf("expected closed after a successful probe, got %+v", s)
+	}
+}
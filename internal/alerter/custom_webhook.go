@@ -0,0 +1,79 @@
+package alerter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"text/template"
+	"time"
+
+	"github.com/katieblackabee/sentinel/internal/config"
+)
+
+// maxWebhookResponse caps how much of a response is read
+const maxWebhookResponse = 1 << 20
+
+// WebhookSender sends alerts to a generic HTTP endpoint, rendering the body
+// from a template so any receiver's JSON shape can be produced
+type WebhookSender struct {
+	config *config.WebhookConfig
+	client *http.Client
+	body   *template.Template
+}
+
+func NewWebhookSender(cfg *config.WebhookConfig) (*WebhookSender, error) {
+	body, err := cfg.Template()
+	if err != nil {
+		return nil, fmt.Errorf("parsing webhook body_template: %w", err)
+	}
+
+	return newWebhookSender(cfg, body), nil
+}
+
+func newWebhookSender(cfg *config.WebhookConfig, body *template.Template) *WebhookSender {
+	return &WebhookSender{
+		config: cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+		body:   body,
+	}
+}
+
+func (w *WebhookSender) Send(alert *Alert) error {
+	_, err := w.send(alert)
+	return err
+}
+
+// send renders the body from data and delivers it, returning the response
+// body
+func (w *WebhookSender) send(data any) ([]byte, error) {
+	var body bytes.Buffer
+	if err := w.body.Execute(&body, data); err != nil {
+		return nil, fmt.Errorf("rendering webhook body: %w", err)
+	}
+
+	req, err := http.NewRequest(w.config.GetMethod(), w.config.URL, &body)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range w.config.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sending webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxWebhookResponse))
+	if err != nil {
+		return nil, fmt.Errorf("reading webhook response: %w", err)
+	}
+	return respBody, nil
+}
@@ -0,0 +1,111 @@
+package alerter
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/katieblackabee/sentinel/internal/config"
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+func TestWebhookSenderDefaultTemplate(t *testing.T) {
+	var method, contentType string
+	var payload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		contentType = r.Header.Get("Content-Type")
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sender, err := NewWebhookSender(&config.WebhookConfig{Enabled: true, URL: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	check := &storage.Check{ID: 7, Name: `API "prod"`, URL: "https://api.example.com", Tags: []string{"api"}}
+	alert := &Alert{Type: "down", Check: check, Incident: &storage.Incident{ID: 3}, Error: "connection refused", Timestamp: time.Now()}
+	if err := sender.Send(alert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if method != "POST" || contentType != "application/json" {
+		t.Errorf("expected JSON POST, got %s %s", method, contentType)
+	}
+	if payload == nil {
+		t.Fatal("expected the default template to render valid JSON")
+	}
+	if payload["severity"] != "critical" || payload["error"] != "connection refused" || payload["incident_id"] != float64(3) {
+		t.Errorf("unexpected payload: %v", payload)
+	}
+	if check, _ := payload["check"].(map[string]any); check["name"] != `API "prod"` {
+		t.Errorf("expected escaped check name, got %v", payload["check"])
+	}
+}
+
+func TestWebhookSenderCustomTemplate(t *testing.T) {
+	var method, auth, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		auth = r.Header.Get("Authorization")
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sender, err := NewWebhookSender(&config.WebhookConfig{
+		Enabled:      true,
+		URL:          server.URL,
+		Method:       "put",
+		Headers:      map[string]string{"Authorization": "Bearer secret"},
+		BodyTemplate: `{"summary":{{json (printf "%s is %s" .Check.Name .Type)}}}`,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := sender.Send(&Alert{Type: "recovery", Check: &storage.Check{Name: "API"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if method != "PUT" || auth != "Bearer secret" {
+		t.Errorf("expected PUT with configured header, got %s %q", method, auth)
+	}
+	if body != `{"summary":"API is recovery"}` {
+		t.Errorf("unexpected body: %s", body)
+	}
+}
+
+func TestSendAlertLogsWebhookFailure(t *testing.T) {
+	store := setupTestStorage(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	manager := NewManager(&config.AlertsConfig{Webhook: config.WebhookConfig{Enabled: true, URL: server.URL}}, store)
+
+	check := &storage.Check{Name: "Test", URL: "https://test.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(check)
+	incident := &storage.Incident{CheckID: check.ID, StartedAt: time.Now()}
+	store.CreateIncident(incident)
+
+	if err := manager.SendDownAlert(check, incident, "boom"); err == nil {
+		t.Error("expected error for a non-2xx webhook response")
+	}
+
+	last, err := store.GetLastAlertForIncident(incident.ID, "webhook")
+	if err != nil {
+		t.Fatalf("failed to get last alert: %v", err)
+	}
+	if last == nil || last.Success || last.ErrorMessage == "" {
+		t.Errorf("expected failed webhook delivery logged, got %+v", last)
+	}
+}
@@ -0,0 +1,124 @@
+package alerter
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+// digestInterval is how often RunDigest looks for business hours starting
+const digestInterval = time.Minute
+
+// severityRank orders severities so a threshold can be compared against
+var severityRank = map[string]int{"info": 0, "warning": 1, "critical": 2}
+
+// digest holds alerts kept back outside business hours until they start
+type digest struct {
+	mu     sync.Mutex
+	alerts []*Alert
+}
+
+func (d *digest) add(alert *Alert) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.alerts = append(d.alerts, alert)
+}
+
+// take empties the digest, returning what it held
+func (d *digest) take() []*Alert {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	alerts := d.alerts
+	d.alerts = nil
+	return alerts
+}
+
+func (d *digest) len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.alerts)
+}
+
+// holdOffHours reports whether an alert is kept back because it falls outside
+// business hours and is less severe than the off-hours threshold. Held alerts
+// join the digest unless off-hours alerts are dropped.
+func (m *Manager) holdOffHours(alert *Alert) bool {
+	hours := &m.config.BusinessHours
+	if !hours.Enabled || alert.Type == "digest" {
+		return false
+	}
+	if m.inBusinessHours(alert.Timestamp) {
+		return false
+	}
+	if severityRank[alert.Severity()] >= severityRank[hours.GetMinSeverity()] {
+		return false
+	}
+
+	if !hours.Drops() {
+		m.digest.add(alert)
+	}
+	return true
+}
+
+// inBusinessHours reports whether t is within business hours in the alerts
+// zone, or the local one if none is set
+func (m *Manager) inBusinessHours(t time.Time) bool {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	if loc := m.config.GetLocation(); loc != nil {
+		t = t.In(loc)
+	} else {
+		t = t.Local()
+	}
+	return m.config.BusinessHours.Contains(t)
+}
+
+// FlushDigest sends the alerts held outside business hours as one digest
+// alert, once now is within hours again. Nothing is sent while the digest is
+// empty or hours haven't started.
+func (m *Manager) FlushDigest(now time.Time) error {
+	if m.digest.len() == 0 || !m.inBusinessHours(now) {
+		return nil
+	}
+	alerts := m.digest.take()
+
+	times := newTimeDisplay(m.config)
+	lines := make([]string, len(alerts))
+	for i, alert := range alerts {
+		line := fmt.Sprintf("%s %s at %s", strings.ToUpper(alert.Type), alert.Check.Name, times.format(alert.Timestamp))
+		if alert.Error != "" {
+			line += ": " + alert.Error
+		}
+		lines[i] = line
+	}
+
+	summary := &Alert{
+		Type:      "digest",
+		Check:     &storage.Check{Name: fmt.Sprintf("%d alerts held outside business hours", len(alerts))},
+		Error:     strings.Join(lines, "\n"),
+		Timestamp: now,
+	}
+	return lastDeliveryError(m.deliver(summary))
+}
+
+// RunDigest sends the off-hours digest when business hours start, until stop
+// is closed
+func (m *Manager) RunDigest(stop <-chan struct{}) {
+	ticker := time.NewTicker(digestInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			if err := m.FlushDigest(now); err != nil {
+				fmt.Printf("failed to send alert digest: %v\n", err)
+			}
+		}
+	}
+}
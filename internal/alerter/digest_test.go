@@ -0,0 +1,117 @@
+package alerter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/katieblackabee/sentinel/internal/config"
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+// Monday 2026-03-02 in UTC
+var (
+	workday = time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	night   = time.Date(2026, 3, 2, 23, 0, 0, 0, time.UTC)
+)
+
+func TestBusinessHoursHoldsLessSevereAlerts(t *testing.T) {
+	store := setupTestStorage(t)
+
+	var titles []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg SlackMessage
+		json.NewDecoder(r.Body).Decode(&msg)
+		titles = append(titles, msg.Attachments[0].Title)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := &config.AlertsConfig{
+		Timezone:      "UTC",
+		Slack:         config.SlackConfig{Enabled: true, WebhookURL: server.URL},
+		BusinessHours: config.BusinessHoursConfig{Enabled: true, Start: "09:00", End: "17:00"},
+	}
+	manager := NewManager(cfg, store)
+	check := &storage.Check{Name: "API", URL: "https://api.example.com"}
+
+	// Critical alerts go out at any hour; the rest wait
+	manager.sendAlert(&Alert{Type: "down", Check: check, Timestamp: night})
+	manager.sendAlert(&Alert{Type: "stale", Check: check, Error: "No results", Timestamp: night})
+	manager.sendAlert(&Alert{Type: "recovery", Check: check, Timestamp: night})
+	if len(titles) != 1 || !strings.Contains(titles[0], "DOWN") {
+		t.Fatalf("expected only the down alert overnight, got %v", titles)
+	}
+
+	// Within hours everything is sent straight away
+	manager.sendAlert(&Alert{Type: "recovery", Check: check, Timestamp: workday})
+	if len(titles) != 2 {
+		t.Fatalf("expected the recovery during hours, got %v", titles)
+	}
+
+	// The digest waits for hours to start, then goes out once
+	if err := manager.FlushDigest(night); err != nil || len(titles) != 2 {
+		t.Fatalf("expected no digest outside hours, got %v (%v)", titles, err)
+	}
+	if err := manager.FlushDigest(workday); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(titles) != 3 || !strings.Contains(titles[2], "2 alerts held") {
+		t.Fatalf("expected a digest of the 2 held alerts, got %v", titles)
+	}
+	manager.FlushDigest(workday)
+	if len(titles) != 3 {
+		t.Errorf("expected an empty digest not to be sent, got %v", titles)
+	}
+}
+
+func TestBusinessHoursMinSeverityAndDrop(t *testing.T) {
+	store := setupTestStorage(t)
+
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := &config.AlertsConfig{
+		Timezone:      "UTC",
+		Slack:         config.SlackConfig{Enabled: true, WebhookURL: server.URL},
+		BusinessHours: config.BusinessHoursConfig{Enabled: true, MinSeverity: "warning", OffHours: "drop"},
+	}
+	manager := NewManager(cfg, store)
+	check := &storage.Check{Name: "API", URL: "https://api.example.com"}
+
+	manager.sendAlert(&Alert{Type: "ssl_expiry", Check: check, Timestamp: night})
+	manager.sendAlert(&Alert{Type: "recovery", Check: check, Timestamp: night})
+	if hits != 1 {
+		t.Errorf("expected the warning to be sent and the info alert held, got %d sends", hits)
+	}
+
+	manager.FlushDigest(workday)
+	if hits != 1 {
+		t.Errorf("expected dropped alerts not to be sent later, got %d sends", hits)
+	}
+}
+
+func TestBuildDigestEmail(t *testing.T) {
+	sender := &EmailSender{config: &config.EmailConfig{}}
+	alert := &Alert{
+		Type:      "digest",
+		Check:     &storage.Check{Name: "2 alerts held outside business hours"},
+		Error:     "STALE API at Mon, 02 Mar 2026 23:00:00 UTC: No results\nRECOVERY API at Mon, 02 Mar 2026 23:05:00 UTC",
+		Timestamp: workday,
+	}
+
+	subject, body := sender.buildEmail(alert)
+	if subject != "[SENTINEL] DIGEST: 2 alerts held outside business hours" {
+		t.Errorf("unexpected subject %q", subject)
+	}
+	if !strings.Contains(body, "STALE API") || !strings.Contains(body, "RECOVERY API") {
+		t.Errorf("expected each held alert in the body, got %q", body)
+	}
+}
@@ -5,13 +5,14 @@ import (
 	"fmt"
 	"net/smtp"
 	"strings"
-	"time"
 
 	"github.com/katieblackabee/sentinel/internal/config"
+	"github.com/katieblackabee/sentinel/internal/storage"
 )
 
 type EmailSender struct {
 	config *config.EmailConfig
+	times  timeDisplay
 }
 
 func NewEmailSender(cfg *config.EmailConfig) *EmailSender {
@@ -19,6 +20,12 @@ func NewEmailSender(cfg *config.EmailConfig) *EmailSender {
 }
 
 func (e *EmailSender) Send(alert *Alert) error {
+	// One message over one connection, addressed to every matching set
+	to := e.config.RecipientsFor(alert.Severity(), alert.Check.Tags)
+	if len(to) == 0 {
+		return nil
+	}
+
 	subject, body := e.buildEmail(alert)
 
 	msg := fmt.Sprintf("From: %s\r\n"+
@@ -29,7 +36,7 @@ func (e *EmailSender) Send(alert *Alert) error {
 		"\r\n"+
 		"%s",
 		e.config.FromAddress,
-		strings.Join(e.config.ToAddresses, ", "),
+		strings.Join(to, ", "),
 		subject,
 		body,
 	)
@@ -42,19 +49,19 @@ func (e *EmailSender) Send(alert *Alert) error {
 	}
 
 	if e.config.SMTPTLS {
-		return e.sendWithTLS(addr, auth, msg)
+		return e.sendWithTLS(addr, auth, to, msg)
 	}
 
-	return smtp.SendMail(addr, auth, e.config.FromAddress, e.config.ToAddresses, []byte(msg))
+	return smtp.SendMail(addr, auth, e.config.FromAddress, to, []byte(msg))
 }
 
-func (e *EmailSender) sendWithTLS(addr string, auth smtp.Auth, msg string) error {
+func (e *EmailSender) sendWithTLS(addr string, auth smtp.Auth, to []string, msg string) error {
 	conn, err := tls.Dial("tcp", addr, &tls.Config{
 		ServerName: e.config.SMTPHost,
 	})
 	if err != nil {
 		// Try STARTTLS instead
-		return e.sendWithSTARTTLS(addr, auth, msg)
+		return e.sendWithSTARTTLS(addr, auth, to, msg)
 	}
 	defer conn.Close()
 
@@ -74,8 +81,8 @@ func (e *EmailSender) sendWithTLS(addr string, auth smtp.Auth, msg string) error
 		return fmt.Errorf("SMTP mail: %w", err)
 	}
 
-	for _, to := range e.config.ToAddresses {
-		if err := client.Rcpt(to); err != nil {
+	for _, rcpt := range to {
+		if err := client.Rcpt(rcpt); err != nil {
 			return fmt.Errorf("SMTP rcpt: %w", err)
 		}
 	}
@@ -98,7 +105,7 @@ func (e *EmailSender) sendWithTLS(addr string, auth smtp.Auth, msg string) error
 	return client.Quit()
 }
 
-func (e *EmailSender) sendWithSTARTTLS(addr string, auth smtp.Auth, msg string) error {
+func (e *EmailSender) sendWithSTARTTLS(addr string, auth smtp.Auth, to []string, msg string) error {
 	client, err := smtp.Dial(addr)
 	if err != nil {
 		return fmt.Errorf("dialing SMTP: %w", err)
@@ -123,8 +130,8 @@ func (e *EmailSender) sendWithSTARTTLS(addr string, auth smtp.Auth, msg string)
 		return fmt.Errorf("SMTP mail: %w", err)
 	}
 
-	for _, to := range e.config.ToAddresses {
-		if err := client.Rcpt(to); err != nil {
+	for _, rcpt := range to {
+		if err := client.Rcpt(rcpt); err != nil {
 			return fmt.Errorf("SMTP rcpt: %w", err)
 		}
 	}
@@ -148,18 +155,69 @@ func (e *EmailSender) sendWithSTARTTLS(addr string, auth smtp.Auth, msg string)
 }
 
 func (e *EmailSender) buildEmail(alert *Alert) (subject, body string) {
-	if alert.Type == "down" {
+	switch alert.Type {
+	case "down", "escalation":
 		return e.buildDownEmail(alert)
+	case "stale", "drift", "slow":
+		return e.buildWarningEmail(alert)
+	case "digest":
+		return e.buildDigestEmail(alert)
 	}
 	return e.buildRecoveryEmail(alert)
 }
 
+// buildWarningEmail covers alerts about the check itself rather than its
+// target, titled by alert type
+func (e *EmailSender) buildWarningEmail(alert *Alert) (subject, body string) {
+	kind := strings.ToUpper(alert.Type)
+	subject = fmt.Sprintf("[SENTINEL] %s: %s", kind, alert.Check.Name)
+
+	body = fmt.Sprintf(`Service: %s
+URL: %s
+%sStatus: %s
+Time: %s
+Warning: %s
+
+--
+Sentinel Uptime Monitor`,
+		alert.Check.Name,
+		alert.Check.URL,
+		emailContext(alert.Check),
+		kind,
+		e.times.format(alert.Timestamp),
+		alert.Error,
+	)
+
+	return subject, body
+}
+
+func (e *EmailSender) buildDigestEmail(alert *Alert) (subject, body string) {
+	subject = fmt.Sprintf("[SENTINEL] DIGEST: %s", alert.Check.Name)
+
+	body = fmt.Sprintf(`%s
+Time: %s
+
+%s
+
+--
+Sentinel Uptime Monitor`,
+		alert.Check.Name,
+		e.times.format(alert.Timestamp),
+		alert.Error,
+	)
+
+	return subject, body
+}
+
 func (e *EmailSender) buildDownEmail(alert *Alert) (subject, body string) {
 	subject = fmt.Sprintf("[SENTINEL] DOWN: %s", alert.Check.Name)
+	if alert.Type == "escalation" {
+		subject = fmt.Sprintf("[SENTINEL] ESCALATED: %s", alert.Check.Name)
+	}
 
 	body = fmt.Sprintf(`Service: %s
 URL: %s
-Status: DOWN
+%sStatus: DOWN
 Time: %s
 Error: %s
 
@@ -167,7 +225,8 @@ Error: %s
 Sentinel Uptime Monitor`,
 		alert.Check.Name,
 		alert.Check.URL,
-		alert.Timestamp.Format(time.RFC1123),
+		emailContext(alert.Check),
+		e.times.format(alert.Timestamp),
 		alert.Error,
 	)
 
@@ -177,14 +236,11 @@ Sentinel Uptime Monitor`,
 func (e *EmailSender) buildRecoveryEmail(alert *Alert) (subject, body string) {
 	subject = fmt.Sprintf("[SENTINEL] RECOVERED: %s", alert.Check.Name)
 
-	duration := "unknown"
-	if alert.Incident != nil {
-		duration = alert.Incident.DurationString()
-	}
+	duration := e.times.downtime(alert.Incident)
 
 	body = fmt.Sprintf(`Service: %s
 URL: %s
-Status: UP
+%sStatus: UP
 Time: %s
 Downtime: %s
 
@@ -192,9 +248,23 @@ Downtime: %s
 Sentinel Uptime Monitor`,
 		alert.Check.Name,
 		alert.Check.URL,
-		alert.Timestamp.Format(time.RFC1123),
+		emailContext(alert.Check),
+		e.times.format(alert.Timestamp),
 		duration,
 	)
 
 	return subject, body
 }
+
+// emailContext renders a check's description and runbook link as header
+// lines, each ending in a newline
+func emailContext(check *storage.Check) string {
+	var lines string
+	if check.Description != "" {
+		lines += fmt.Sprintf("About: %s\n", check.Description)
+	}
+	if check.RunbookURL != "" {
+		lines += fmt.Sprintf("Runbook: %s\n", check.RunbookURL)
+	}
+	return lines
+}
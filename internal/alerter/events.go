@@ -0,0 +1,74 @@
+package alerter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/katieblackabee/sentinel/internal/config"
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+// EventSender posts every check state change to a webhook. Unlike the alert
+// channels it is never throttled: it feeds machines, not people.
+type EventSender struct {
+	config *config.EventsConfig
+	client *http.Client
+}
+
+// StateChangeEvent is the events webhook payload
+type StateChangeEvent struct {
+	Event     string               `json:"event"` // always "state_change"
+	CheckID   int64                `json:"check_id"`
+	CheckName string               `json:"check_name"`
+	URL       string               `json:"url"`
+	From      string               `json:"from"`
+	To        string               `json:"to"`
+	Result    *storage.CheckResult `json:"result"`
+	Timestamp time.Time            `json:"timestamp"`
+}
+
+func NewEventSender(cfg *config.EventsConfig) *EventSender {
+	return &EventSender{
+		config: cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (e *EventSender) SendStateChange(check *storage.Check, from, to string, result *storage.CheckResult) error {
+	event := &StateChangeEvent{
+		Event:     "state_change",
+		CheckID:   check.ID,
+		CheckName: check.Name,
+		URL:       check.URL,
+		From:      from,
+		To:        to,
+		Result:    result,
+		Timestamp: time.Now(),
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshaling state change event: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", e.config.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending events webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("events webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package alerter
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/katieblackabee/sentinel/internal/config"
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+func TestEventSender_SendStateChange(t *testing.T) {
+	var receivedBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sender := NewEventSender(&config.EventsConfig{Enabled: true, WebhookURL: server.URL})
+
+	check := &storage.Check{ID: 7, Name: "Test Service", URL: "https://example.com"}
+	result := &storage.CheckResult{CheckID: 7, Status: "down", StatusCode: 503}
+
+	if err := sender.SendStateChange(check, "up", "down", result); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var event StateChangeEvent
+	if err := json.Unmarshal(receivedBody, &event); err != nil {
+		t.Fatalf("failed to unmarshal event: %v", err)
+	}
+
+	if event.Event != "state_change" {
+		t.Errorf("expected event state_change, got %s", event.Event)
+	}
+	if event.CheckID != 7 || event.From != "up" || event.To != "down" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+	if event.Result == nil || event.Result.StatusCode != 503 {
+		t.Errorf("expected result with status 503, got %+v", event.Result)
+	}
+}
+
+func TestEventSender_SendError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	sender := NewEventSender(&config.EventsConfig{Enabled: true, WebhookURL: server.URL})
+
+	err := sender.SendStateChange(&storage.Check{Name: "Test"}, "down", "up", &storage.CheckResult{})
+	if err == nil {
+		t.Error("expected error for 500 response")
+	}
+}
@@ -0,0 +1,80 @@
+package alerter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/katieblackabee/sentinel/internal/config"
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+// IncidentEvent is what the incident sync body template is rendered with
+type IncidentEvent struct {
+	Event     string // "opened" or "closed"
+	Check     *storage.Check
+	Incident  *storage.Incident
+	Timestamp time.Time
+}
+
+// IncidentSyncer sends incidents to an external incident management system,
+// over the same templated request as the generic webhook
+type IncidentSyncer struct {
+	webhook *WebhookSender
+	idField string
+}
+
+func NewIncidentSyncer(cfg *config.IncidentSyncConfig) (*IncidentSyncer, error) {
+	body, err := cfg.Template()
+	if err != nil {
+		return nil, fmt.Errorf("parsing incident_sync body_template: %w", err)
+	}
+
+	return &IncidentSyncer{
+		webhook: newWebhookSender(&cfg.WebhookConfig, body),
+		idField: cfg.IDField,
+	}, nil
+}
+
+// Sync sends an incident event and returns the ticket ID the response holds
+// at the configured field, if any
+func (s *IncidentSyncer) Sync(event *IncidentEvent) (string, error) {
+	body, err := s.webhook.send(event)
+	if err != nil {
+		return "", err
+	}
+	if s.idField == "" {
+		return "", nil
+	}
+	return responseField(body, s.idField)
+}
+
+// responseField reads a dotted path such as "result.sys_id" out of a JSON
+// body, as a string or number
+func responseField(body []byte, path string) (string, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber() // Keep large numeric IDs exact
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return "", fmt.Errorf("parsing incident sync response: %w", err)
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return "", fmt.Errorf("incident sync response has no %s", path)
+		}
+		value = obj[key]
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	default:
+		return "", fmt.Errorf("incident sync response has no %s", path)
+	}
+}
@@ -0,0 +1,87 @@
+package alerter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/katieblackabee/sentinel/internal/config"
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+func TestSyncIncident(t *testing.T) {
+	store := setupTestStorage(t)
+
+	var payloads []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		json.NewDecoder(r.Body).Decode(&payload)
+		payloads = append(payloads, payload)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"result":{"sys_id":"INC0012345"}}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.AlertsConfig{IncidentSync: config.IncidentSyncConfig{
+		WebhookConfig: config.WebhookConfig{Enabled: true, URL: server.URL},
+		IDField:       "result.sys_id",
+	}}
+	manager := NewManager(cfg, store)
+
+	check := &storage.Check{Name: "API", URL: "https://api.example.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(check)
+	incident := &storage.Incident{CheckID: check.ID, StartedAt: time.Now(), Cause: "connection refused", Category: "connection"}
+	store.CreateIncident(incident)
+
+	if err := manager.SyncIncident(check, incident, "opened"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(payloads) != 1 || payloads[0]["event"] != "opened" || payloads[0]["external_id"] != "" {
+		t.Fatalf("expected an opened event without a ticket ID, got %v", payloads)
+	}
+	if inc, _ := payloads[0]["incident"].(map[string]any); inc["cause"] != "connection refused" {
+		t.Errorf("expected the incident cause in the payload, got %v", payloads[0]["incident"])
+	}
+
+	stored, _ := store.GetIncident(incident.ID)
+	if stored.ExternalID != "INC0012345" {
+		t.Fatalf("expected the ticket ID recorded, got %q", stored.ExternalID)
+	}
+
+	// The closing request refers to the ticket
+	store.CloseIncident(incident.ID, time.Now())
+	closed, _ := store.GetIncident(incident.ID)
+	if err := manager.SyncIncident(check, closed, "closed"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(payloads) != 2 || payloads[1]["event"] != "closed" || payloads[1]["external_id"] != "INC0012345" {
+		t.Errorf("expected a closed event with the ticket ID, got %v", payloads[1])
+	}
+}
+
+func TestSyncIncidentDisabled(t *testing.T) {
+	manager := NewManager(&config.AlertsConfig{}, setupTestStorage(t))
+	if err := manager.SyncIncident(&storage.Check{}, &storage.Incident{}, "opened"); err != nil {
+		t.Errorf("expected no-op without incident_sync, got %v", err)
+	}
+}
+
+func TestResponseField(t *testing.T) {
+	body := []byte(`{"id":9007199254740993,"key":"OPS-12","result":{"sys_id":"abc"}}`)
+
+	tests := map[string]string{"id": "9007199254740993", "key": "OPS-12", "result.sys_id": "abc"}
+	for path, want := range tests {
+		if got, err := responseField(body, path); err != nil || got != want {
+			t.Errorf("%s: expected %q, got %q (%v)", path, want, got, err)
+		}
+	}
+
+	if _, err := responseField(body, "result.missing"); err == nil {
+		t.Error("expected an error for a missing field")
+	}
+	if _, err := responseField([]byte("created"), "id"); err == nil {
+		t.Error("expected an error for a non-JSON response")
+	}
+}
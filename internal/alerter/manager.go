@@ -2,6 +2,7 @@ package alerter
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/katieblackabee/sentinel/internal/config"
@@ -14,32 +15,165 @@ type Manager struct {
 	email   *EmailSender
 	slack   *SlackSender
 	discord *DiscordSender
+	webhook *WebhookSender
+	sms     *TwilioSender
+	sync    *IncidentSyncer
+	breaker *circuitBreaker
+	digest  *digest
 }
 
 type Alert struct {
-	Type      string // "down" or "recovery"
+	Type      string // "down", "recovery", "ssl_expiry", "escalation", "stale", "drift", "slow" or "digest"
 	Check     *storage.Check
 	Incident  *storage.Incident
 	Error     string
 	Timestamp time.Time
+
+	// Escalation is the rule an escalation alert was sent for
+	Escalation *storage.EscalationRule
+
+	// Reminder marks a down alert repeated for an incident still open, which
+	// each channel gets at most once per renotify interval
+	Reminder bool
+}
+
+// Severity maps the alert type onto the severities webhook targets and email
+// recipient sets filter on
+func (a *Alert) Severity() string {
+	switch a.Type {
+	case "down":
+		return "critical"
+	case "escalation":
+		if a.Escalation != nil && a.Escalation.Severity != "" {
+			return a.Escalation.Severity
+		}
+		return "critical"
+	case "ssl_expiry", "stale", "drift", "slow":
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+// routesTo reports whether an alert routed to routes may go to channel. A
+// route naming a provider ("slack") covers all of its targets
+// ("slack:oncall"); no routes means every enabled channel.
+func routesTo(routes []string, channel string) bool {
+	if len(routes) == 0 {
+		return true
+	}
+	for _, route := range routes {
+		if channel == route || strings.HasPrefix(channel, route+":") {
+			return true
+		}
+	}
+	return false
+}
+
+// routes returns the channels an alert may go to: the check's route for the
+// alert's type, else the check's alert channels, else the configured route
+// for the type. A check's own settings win, so one limited to a channel
+// never reaches others through a configured route.
+func (m *Manager) routes(alert *Alert) []string {
+	if alert.Check != nil {
+		if routes := alert.Check.AlertRoutes[alert.Type]; len(routes) > 0 {
+			return routes
+		}
+		if len(alert.Check.AlertChannels) > 0 {
+			return alert.Check.AlertChannels
+		}
+	}
+	return m.config.Routes[alert.Type]
+}
+
+// timeDisplay renders alert times for the people reading them. The zero value
+// keeps each timestamp's own zone and RFC1123, which is how alerts always read.
+type timeDisplay struct {
+	loc    *time.Location
+	layout string
+}
+
+func newTimeDisplay(cfg *config.AlertsConfig) timeDisplay {
+	return timeDisplay{loc: cfg.GetLocation(), layout: cfg.TimeFormat}
+}
+
+// local moves t into the display zone, if one is set
+func (d timeDisplay) local(t time.Time) time.Time {
+	if d.loc != nil {
+		return t.In(d.loc)
+	}
+	return t
+}
+
+func (d timeDisplay) format(t time.Time) string {
+	if d.layout == "" {
+		return d.local(t).Format(time.RFC1123)
+	}
+	return d.local(t).Format(d.layout)
+}
+
+// custom reports whether a zone or layout was configured
+func (d timeDisplay) custom() bool {
+	return d.loc != nil || d.layout != ""
+}
+
+// downtime renders how long an incident lasted, adding when it started once
+// a display zone or layout is configured
+func (d timeDisplay) downtime(incident *storage.Incident) string {
+	if incident == nil {
+		return "unknown"
+	}
+	if !d.custom() {
+		return incident.DurationString()
+	}
+	return fmt.Sprintf("%s (since %s)", incident.DurationString(), d.format(incident.StartedAt))
 }
 
 func NewManager(cfg *config.AlertsConfig, store storage.Storage) *Manager {
 	m := &Manager{
 		config:  cfg,
 		storage: store,
+		breaker: newCircuitBreaker(cfg.GetBreakerFailures(), cfg.GetBreakerCooldown()),
+		digest:  &digest{},
 	}
 
+	times := newTimeDisplay(cfg)
+
 	if cfg.Email.Enabled {
 		m.email = NewEmailSender(&cfg.Email)
+		m.email.times = times
 	}
 
 	if cfg.Slack.Enabled {
 		m.slack = NewSlackSender(&cfg.Slack)
+		m.slack.times = times
 	}
 
 	if cfg.Discord.Enabled {
 		m.discord = NewDiscordSender(&cfg.Discord)
+		m.discord.times = times
+	}
+
+	if cfg.Webhook.Enabled {
+		webhook, err := NewWebhookSender(&cfg.Webhook)
+		if err != nil {
+			fmt.Printf("webhook alerts disabled: %v\n", err)
+		} else {
+			m.webhook = webhook
+		}
+	}
+
+	if cfg.Twilio.Enabled {
+		m.sms = NewTwilioSender(&cfg.Twilio)
+	}
+
+	if cfg.IncidentSync.Enabled {
+		sync, err := NewIncidentSyncer(&cfg.IncidentSync)
+		if err != nil {
+			fmt.Printf("incident sync disabled: %v\n", err)
+		} else {
+			m.sync = sync
+		}
 	}
 
 	return m
@@ -72,6 +206,31 @@ func (m *Manager) SendRecoveryAlert(check *storage.Check, incident *storage.Inci
 	return m.sendAlert(alert)
 }
 
+// SyncIncident sends an opened or closed incident to the incident sync
+// endpoint. The first ticket ID a response returns is stored on the incident,
+// so the closing request can refer to it.
+func (m *Manager) SyncIncident(check *storage.Check, incident *storage.Incident, event string) error {
+	if m.sync == nil {
+		return nil
+	}
+
+	externalID, err := m.sync.Sync(&IncidentEvent{
+		Event:     event,
+		Check:     check,
+		Incident:  incident,
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	if externalID == "" || incident.ExternalID != "" {
+		return nil
+	}
+
+	incident.ExternalID = externalID
+	return m.storage.SetIncidentExternalID(incident.ID, externalID)
+}
+
 func (m *Manager) SendSSLExpiryAlert(check *storage.Check, daysLeft int, expiresAt time.Time) error {
 	if m.config.SSLExpiryDays == 0 {
 		return nil // SSL alerts disabled
@@ -87,57 +246,232 @@ func (m *Manager) SendSSLExpiryAlert(check *storage.Check, daysLeft int, expires
 	return m.sendAlert(alert)
 }
 
-func (m *Manager) sendAlert(alert *Alert) error {
-	// Check cooldown
-	if !m.shouldSendAlert(alert) {
+// SendStaleAlert warns that a check has stopped producing results, so its
+// dashboard status can't be trusted. lastResult is zero if it never ran.
+func (m *Manager) SendStaleAlert(check *storage.Check, lastResult time.Time) error {
+	msg := "No results since Sentinel started; the check may not be running"
+	if !lastResult.IsZero() {
+		msg = fmt.Sprintf("No results since %s; the check may not be running", lastResult.Format(time.RFC3339))
+	}
+
+	alert := &Alert{
+		Type:      "stale",
+		Check:     check,
+		Error:     msg,
+		Timestamp: time.Now(),
+	}
+
+	return m.sendAlert(alert)
+}
+
+// SendDriftAlert warns that a check no longer matches its definition in the
+// config file, naming the changed keys and whether it was restored
+func (m *Manager) SendDriftAlert(check *storage.Check, fields []string, corrected bool) error {
+	msg := fmt.Sprintf("Changed outside the config file: %s", strings.Join(fields, ", "))
+	if corrected {
+		msg += "; restored from the config file"
+	}
+
+	alert := &Alert{
+		Type:      "drift",
+		Check:     check,
+		Error:     msg,
+		Timestamp: time.Now(),
+	}
+
+	return m.sendAlert(alert)
+}
+
+// SendSlowAlert warns that a check is up but answering many times slower
+// than its 24h average, which often comes before an outage
+func (m *Manager) SendSlowAlert(check *storage.Check, responseMs, averageMs int) error {
+	alert := &Alert{
+		Type:      "slow",
+		Check:     check,
+		Error:     fmt.Sprintf("Responded in %dms, %.1fx its 24h average of %dms", responseMs, float64(responseMs)/float64(averageMs), averageMs),
+		Timestamp: time.Now(),
+	}
+
+	return m.sendAlert(alert)
+}
+
+// SendReminderAlert repeats the down alert for an incident still open, to
+// every channel that hasn't heard about it for renotify_minutes
+func (m *Manager) SendReminderAlert(check *storage.Check, incident *storage.Incident) error {
+	if m.config.RenotifyMinutes == 0 {
 		return nil
 	}
 
-	var lastErr error
+	open := time.Since(incident.StartedAt).Truncate(time.Minute)
+	alert := &Alert{
+		Type:      "down",
+		Check:     check,
+		Incident:  incident,
+		Error:     fmt.Sprintf("Still down after %s: %s", open, incident.Cause),
+		Timestamp: time.Now(),
+		Reminder:  true,
+	}
 
-	// Send via email if enabled
-	if m.email != nil {
-		if err := m.email.Send(alert); err != nil {
-			lastErr = err
-			m.logAlert(alert, "email", false, err.Error())
+	return m.sendAlert(alert)
+}
+
+// SendEscalationAlert re-alerts on an incident through its check's
+// escalation rule level. Each level goes out once per incident, recorded in
+// the alert log as "escalation:<level>", and skips the cooldown since it is
+// meant to reach someone new.
+func (m *Manager) SendEscalationAlert(check *storage.Check, incident *storage.Incident, level int) error {
+	if level < 0 || level >= len(check.Escalations) {
+		return fmt.Errorf("check %s has no escalation level %d", check.Name, level+1)
+	}
+
+	marker := fmt.Sprintf("escalation:%d", level+1)
+	if sent, err := m.storage.GetLastAlertForIncident(incident.ID, marker); err == nil && sent != nil {
+		return nil
+	}
+
+	rule := check.Escalations[level]
+	open := time.Since(incident.StartedAt).Truncate(time.Minute)
+	alert := &Alert{
+		Type:       "escalation",
+		Check:      check,
+		Incident:   incident,
+		Error:      fmt.Sprintf("Still down after %s without acknowledgement: %s", open, incident.Cause),
+		Timestamp:  time.Now(),
+		Escalation: &rule,
+	}
+
+	deliveries := m.deliver(alert)
+
+	// Retry next scan only if every delivery failed; an escalation nothing
+	// routes to is marked sent so it isn't retried forever
+	failed := len(deliveries) > 0
+	var lastErr error
+	for _, d := range deliveries {
+		if d.Err == nil {
+			failed = false
 		} else {
-			m.logAlert(alert, "email", true, "")
+			lastErr = d.Err
 		}
 	}
+	if !failed {
+		m.logAlert(alert, marker, true, "")
+	}
 
-	// Send via Slack if enabled
-	if m.slack != nil {
-		if err := m.slack.Send(alert); err != nil {
-			lastErr = err
-			m.logAlert(alert, "slack", false, err.Error())
-		} else {
-			m.logAlert(alert, "slack", true, "")
+	return lastErr
+}
+
+func (m *Manager) sendAlert(alert *Alert) error {
+	return lastDeliveryError(m.deliver(alert))
+}
+
+// deliver sends an alert through every channel it routes to, logging each
+// delivery
+func (m *Manager) deliver(alert *Alert) []Delivery {
+	if m.holdOffHours(alert) {
+		return nil
+	}
+
+	// Channels whose circuit is open are skipped until their cooldown passes.
+	// An escalation naming a channel goes only there; otherwise the alert
+	// only reaches the channels routed to for its type, if any, and not
+	// those alerted about the incident too recently.
+	routes := m.routes(alert)
+	allow := func(channel string) bool {
+		if alert.Escalation != nil && alert.Escalation.Channel != "" {
+			if channel != alert.Escalation.Channel {
+				return false
+			}
+		} else if !routesTo(routes, channel) {
+			return false
 		}
+		if alert.Escalation == nil && !m.shouldSendAlert(alert, channel) {
+			return false
+		}
+		if m.breaker.allow(channel) {
+			return true
+		}
+		fmt.Printf("skipping %s alert for %s: circuit open after repeated failures\n", channel, alert.Check.Name)
+		return false
+	}
+
+	// Send via email if enabled
+	var deliveries []Delivery
+	if m.email != nil && allow("email") {
+		deliveries = append(deliveries, Delivery{Channel: "email", Err: m.email.Send(alert)})
 	}
 
-	// Send via Discord if enabled
+	// Send via each matching Slack and Discord target if enabled
+	if m.slack != nil {
+		deliveries = append(deliveries, m.slack.sendTargets(alert, allow)...)
+	}
 	if m.discord != nil {
-		if err := m.discord.Send(alert); err != nil {
-			lastErr = err
-			m.logAlert(alert, "discord", false, err.Error())
+		deliveries = append(deliveries, m.discord.sendTargets(alert, allow)...)
+	}
+
+	// Send via the generic webhook if enabled
+	if m.webhook != nil && allow("webhook") {
+		deliveries = append(deliveries, Delivery{Channel: "webhook", Err: m.webhook.Send(alert)})
+	}
+
+	// Text only the alerts worth a phone buzzing
+	if m.sms != nil && m.sms.Sends(alert) && allow("sms") {
+		deliveries = append(deliveries, Delivery{Channel: "sms", Err: m.sms.Send(alert)})
+	}
+
+	for _, d := range deliveries {
+		m.breaker.record(d.Channel, d.Err)
+		if d.Err != nil {
+			m.logAlert(alert, d.Channel, false, d.Err.Error())
 		} else {
-			m.logAlert(alert, "discord", true, "")
+			m.logAlert(alert, d.Channel, true, "")
 		}
 	}
 
-	return lastErr
+	return deliveries
+}
+
+// ChannelStates reports the circuit breaker state of every configured channel
+func (m *Manager) ChannelStates() []ChannelState {
+	var channels []string
+	if m.email != nil {
+		channels = append(channels, "email")
+	}
+	if m.slack != nil {
+		channels = append(channels, m.slack.Channels()...)
+	}
+	if m.discord != nil {
+		channels = append(channels, m.discord.Channels()...)
+	}
+	if m.webhook != nil {
+		channels = append(channels, "webhook")
+	}
+	if m.sms != nil {
+		channels = append(channels, "sms")
+	}
+
+	states := make([]ChannelState, len(channels))
+	for i, channel := range channels {
+		states[i] = m.breaker.state(channel)
+	}
+	return states
 }
 
-func (m *Manager) shouldSendAlert(alert *Alert) bool {
+// shouldSendAlert holds back an incident's alert on a channel that was sent
+// one for it within the cooldown, or for a reminder, within the renotify
+// interval
+func (m *Manager) shouldSendAlert(alert *Alert, channel string) bool {
 	if alert.Incident == nil {
 		return true
 	}
 
 	// Check cooldown period
 	cooldown := time.Duration(m.config.CooldownMinutes) * time.Minute
+	if alert.Reminder {
+		cooldown = max(cooldown, time.Duration(m.config.RenotifyMinutes)*time.Minute)
+	}
 
-	// Get last alert for this incident
-	lastAlert, err := m.storage.GetLastAlertForIncident(alert.Incident.ID, "email")
+	// Get last alert for this incident on this channel
+	lastAlert, err := m.storage.GetLastAlertForIncident(alert.Incident.ID, channel)
 	if err != nil {
 		return true // On error, allow the alert
 	}
@@ -1,8 +1,10 @@
 package alerter
 
 import (
-	"os"
-	"path/filepath"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,17 +13,13 @@ import (
 )
 
 func setupTestStorage(t *testing.T) storage.Storage {
-	tmpDir := t.TempDir()
-	dbPath := filepath.Join(tmpDir, "test.db")
-
-	store, err := storage.NewSQLiteStorage(dbPath)
+	store, err := storage.NewMemoryStorage()
 	if err != nil {
 		t.Fatalf("failed to create storage: %v", err)
 	}
 
 	t.Cleanup(func() {
 		store.Close()
-		os.Remove(dbPath)
 	})
 
 	return store
@@ -174,6 +172,87 @@ func TestBuildDownEmail(t *testing.T) {
 	}
 }
 
+func TestEmailSendSkipsUnroutedAlert(t *testing.T) {
+	// Only critical alerts are routed; an unreachable SMTP host proves an
+	// info alert never dials out
+	sender := NewEmailSender(&config.EmailConfig{
+		SMTPHost:    "127.0.0.1",
+		SMTPPort:    1,
+		FromAddress: "sentinel@example.com",
+		Recipients: []config.EmailRecipients{
+			{Name: "oncall", To: []string{"oncall@example.com"}, Severities: []string{"critical"}},
+		},
+	})
+
+	alert := &Alert{
+		Type:      "recovery",
+		Check:     &storage.Check{Name: "Test API"},
+		Timestamp: time.Now(),
+	}
+	if err := sender.Send(alert); err != nil {
+		t.Errorf("expected unrouted alert to be skipped, got %v", err)
+	}
+
+	alert.Type = "down"
+	if err := sender.Send(alert); err == nil {
+		t.Error("expected routed alert to attempt delivery")
+	}
+}
+
+func TestBuildDownEmailWithRunbook(t *testing.T) {
+	sender := &EmailSender{config: &config.EmailConfig{}}
+
+	alert := &Alert{
+		Type: "down",
+		Check: &storage.Check{
+			Name:        "Test API",
+			URL:         "https://api.example.com/health",
+			Description: "Public REST API",
+			RunbookURL:  "https://wiki.example.com/runbooks/api",
+		},
+		Error:     "connection refused",
+		Timestamp: time.Now(),
+	}
+
+	_, body := sender.buildDownEmail(alert)
+
+	if !contains(body, "About: Public REST API") {
+		t.Error("body should contain check description")
+	}
+	if !contains(body, "Runbook: https://wiki.example.com/runbooks/api") {
+		t.Error("body should contain runbook URL")
+	}
+}
+
+func TestBuildEmailDisplayTimezone(t *testing.T) {
+	cfg := &config.AlertsConfig{Timezone: "Europe/Berlin", TimeFormat: "2006-01-02 15:04 MST"}
+	sender := &EmailSender{config: &config.EmailConfig{}, times: newTimeDisplay(cfg)}
+
+	started := time.Date(2024, 1, 15, 13, 0, 0, 0, time.UTC)
+	alert := &Alert{
+		Type:      "recovery",
+		Check:     &storage.Check{Name: "Test API", URL: "https://api.example.com/health"},
+		Incident:  &storage.Incident{ID: 1, StartedAt: started, DurationSeconds: 300},
+		Timestamp: started.Add(5 * time.Minute),
+	}
+
+	_, body := sender.buildRecoveryEmail(alert)
+
+	if !contains(body, "Time: 2024-01-15 14:05 CET") {
+		t.Errorf("expected time in CET, got %q", body)
+	}
+	if !contains(body, "Downtime: 5m0s (since 2024-01-15 14:00 CET)") {
+		t.Errorf("expected downtime with local start, got %q", body)
+	}
+
+	// Unconfigured keeps the timestamp's own zone in RFC1123
+	sender.times = timeDisplay{}
+	_, body = sender.buildRecoveryEmail(alert)
+	if !contains(body, "Time: Mon, 15 Jan 2024 13:05:00 UTC") || contains(body, "since") {
+		t.Errorf("expected default formatting, got %q", body)
+	}
+}
+
 func TestBuildRecoveryEmail(t *testing.T) {
 	sender := &EmailSender{
 		config: &config.EmailConfig{
@@ -329,7 +408,7 @@ func TestShouldSendAlertCooldown(t *testing.T) {
 	}
 
 	// First alert should be allowed
-	if !manager.shouldSendAlert(alert) {
+	if !manager.shouldSendAlert(alert, "email") {
 		t.Error("first alert should be allowed")
 	}
 
@@ -341,9 +420,46 @@ func TestShouldSendAlertCooldown(t *testing.T) {
 	})
 
 	// Second alert within cooldown should be blocked
-	if manager.shouldSendAlert(alert) {
+	if manager.shouldSendAlert(alert, "email") {
 		t.Error("second alert within cooldown should be blocked")
 	}
+
+	// The cooldown is per channel
+	if !manager.shouldSendAlert(alert, "slack") {
+		t.Error("alert on a channel not yet alerted should be allowed")
+	}
+}
+
+func TestSlackOnlyCooldown(t *testing.T) {
+	store := setupTestStorage(t)
+
+	var sent int
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer slack.Close()
+
+	// No email channel for the cooldown to be read from
+	cfg := &config.AlertsConfig{
+		CooldownMinutes: 5,
+		Slack:           config.SlackConfig{Enabled: true, WebhookURL: slack.URL},
+	}
+	manager := NewManager(cfg, store)
+
+	check := &storage.Check{Name: "Test", URL: "https://test.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(check)
+	incident := &storage.Incident{CheckID: check.ID, StartedAt: time.Now()}
+	store.CreateIncident(incident)
+
+	for range 2 {
+		if err := manager.SendDownAlert(check, incident, "connection refused"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if sent != 1 {
+		t.Errorf("expected the second alert inside the cooldown suppressed, got %d sent", sent)
+	}
 }
 
 func TestShouldSendAlertNoIncident(t *testing.T) {
@@ -370,7 +486,7 @@ func TestShouldSendAlertNoIncident(t *testing.T) {
 		Incident: nil,
 	}
 
-	if !manager.shouldSendAlert(alert) {
+	if !manager.shouldSendAlert(alert, "email") {
 		t.Error("alert without incident should be allowed")
 	}
 }
@@ -418,7 +534,7 @@ func TestShouldSendAlertFailedPreviousAlert(t *testing.T) {
 	}
 
 	// Alert should be allowed because previous one failed
-	if !manager.shouldSendAlert(alert) {
+	if !manager.shouldSendAlert(alert, "email") {
 		t.Error("alert should be allowed after failed previous alert")
 	}
 }
@@ -594,6 +710,378 @@ func TestSendAlertNoChannels(t *testing.T) {
 	}
 }
 
+func TestSendAlertLogsEachTarget(t *testing.T) {
+	store := setupTestStorage(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := &config.AlertsConfig{
+		Slack: config.SlackConfig{
+			Enabled: true,
+			Targets: []config.WebhookTarget{
+				{Name: "oncall", URL: server.URL, Severities: []string{"critical"}},
+				{Name: "general", URL: server.URL},
+			},
+		},
+	}
+	manager := NewManager(cfg, store)
+
+	check := &storage.Check{Name: "Test", URL: "https://test.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(check)
+	incident := &storage.Incident{CheckID: check.ID, StartedAt: time.Now()}
+	store.CreateIncident(incident)
+
+	alert := &Alert{Type: "down", Check: check, Incident: incident, Timestamp: time.Now()}
+	if err := manager.sendAlert(alert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, channel := range []string{"slack:oncall", "slack:general"} {
+		last, err := store.GetLastAlertForIncident(incident.ID, channel)
+		if err != nil {
+			t.Fatalf("failed to get last alert: %v", err)
+		}
+		if last == nil || !last.Success {
+			t.Errorf("expected successful delivery logged for %s", channel)
+		}
+	}
+}
+
+func TestSendAlertSkipsOpenChannel(t *testing.T) {
+	store := setupTestStorage(t)
+
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	cfg := &config.AlertsConfig{
+		BreakerFailures: 2,
+		Slack:           config.SlackConfig{Enabled: true, WebhookURL: server.URL},
+	}
+	manager := NewManager(cfg, store)
+
+	check := &storage.Check{Name: "Test", URL: "https://test.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(check)
+
+	for i := 0; i < 4; i++ {
+		manager.sendAlert(&Alert{Type: "down", Check: check, Timestamp: time.Now()})
+	}
+
+	if hits != 2 {
+		t.Errorf("expected delivery to stop after 2 failures, got %d attempts", hits)
+	}
+
+	states := manager.ChannelStates()
+	if len(states) != 1 || states[0].Channel != "slack" || states[0].State != BreakerOpen {
+		t.Errorf("expected open slack channel, got %+v", states)
+	}
+}
+
+func TestSendAlertRoutesToCheckChannels(t *testing.T) {
+	store := setupTestStorage(t)
+
+	hits := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits[r.URL.Path]++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := &config.AlertsConfig{
+		Slack:   config.SlackConfig{Enabled: true, WebhookURL: server.URL + "/slack"},
+		Discord: config.DiscordConfig{Enabled: true, WebhookURL: server.URL + "/discord"},
+		Webhook: config.WebhookConfig{Enabled: true, URL: server.URL + "/webhook"},
+	}
+	manager := NewManager(cfg, store)
+
+	// A check routed to slack skips the other enabled channels
+	noisy := &storage.Check{Name: "Noisy", URL: "https://test.com", AlertChannels: []string{"slack"}}
+	if err := manager.sendAlert(&Alert{Type: "down", Check: noisy, Timestamp: time.Now()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hits["/slack"] != 1 || hits["/discord"] != 0 || hits["/webhook"] != 0 {
+		t.Errorf("expected only slack for a routed check, got %v", hits)
+	}
+
+	// Without routes every enabled channel is used
+	critical := &storage.Check{Name: "Critical", URL: "https://test.com"}
+	if err := manager.sendAlert(&Alert{Type: "down", Check: critical, Timestamp: time.Now()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hits["/slack"] != 2 || hits["/discord"] != 1 || hits["/webhook"] != 1 {
+		t.Errorf("expected every channel for an unrouted check, got %v", hits)
+	}
+}
+
+func TestSendAlertRoutesByType(t *testing.T) {
+	store := setupTestStorage(t)
+
+	hits := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits[r.URL.Path]++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := &config.AlertsConfig{
+		Slack:   config.SlackConfig{Enabled: true, WebhookURL: server.URL + "/slack"},
+		Webhook: config.WebhookConfig{Enabled: true, URL: server.URL + "/webhook"},
+		Routes:  map[string][]string{"down": {"webhook"}, "recovery": {"slack"}},
+	}
+	manager := NewManager(cfg, store)
+
+	// Configured routes pick the channels for each type
+	check := &storage.Check{Name: "Paged", URL: "https://test.com"}
+	manager.sendAlert(&Alert{Type: "down", Check: check, Timestamp: time.Now()})
+	manager.sendAlert(&Alert{Type: "recovery", Check: check, Timestamp: time.Now()})
+	if hits["/webhook"] != 1 || hits["/slack"] != 1 {
+		t.Errorf("expected down to the webhook and recovery to slack, got %v", hits)
+	}
+
+	// Types without a route go to every channel
+	manager.sendAlert(&Alert{Type: "stale", Check: check, Timestamp: time.Now()})
+	if hits["/webhook"] != 2 || hits["/slack"] != 2 {
+		t.Errorf("expected a stale alert on every channel, got %v", hits)
+	}
+
+	// A check's alert channels win over the configured routes, so a check
+	// limited to slack isn't paged through the webhook
+	check.AlertChannels = []string{"slack"}
+	manager.sendAlert(&Alert{Type: "down", Check: check, Timestamp: time.Now()})
+	if hits["/webhook"] != 2 || hits["/slack"] != 3 {
+		t.Errorf("expected the check's down alert only on slack, got %v", hits)
+	}
+
+	// A check's own route for a type wins over both
+	check.AlertRoutes = map[string][]string{"recovery": {"webhook"}}
+	manager.sendAlert(&Alert{Type: "recovery", Check: check, Timestamp: time.Now()})
+	if hits["/webhook"] != 3 || hits["/slack"] != 3 {
+		t.Errorf("expected the check's recovery route to win, got %v", hits)
+	}
+}
+
+func TestRoutesTo(t *testing.T) {
+	check := &storage.Check{AlertChannels: []string{"slack", "discord:ops"}}
+	tests := []struct {
+		channel string
+		want    bool
+	}{
+		{"slack", true},
+		{"slack:oncall", true},
+		{"discord:ops", true},
+		{"discord", false},
+		{"discord:general", false},
+		{"email", false},
+		{"slackware", false},
+	}
+
+	for _, tt := range tests {
+		if got := routesTo(check.AlertChannels, tt.channel); got != tt.want {
+			t.Errorf("routesTo(%q) = %v, want %v", tt.channel, got, tt.want)
+		}
+	}
+	if !routesTo(nil, "email") {
+		t.Error("expected a check without routes to use every channel")
+	}
+}
+
+func TestSendEscalationAlert(t *testing.T) {
+	store := setupTestStorage(t)
+
+	hits := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits[r.URL.Path]++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := &config.AlertsConfig{
+		CooldownMinutes: 60,
+		Slack: config.SlackConfig{
+			Enabled: true,
+			Targets: []config.WebhookTarget{
+				{Name: "oncall", URL: server.URL + "/oncall"},
+				{Name: "general", URL: server.URL + "/general"},
+			},
+		},
+	}
+	manager := NewManager(cfg, store)
+
+	check := &storage.Check{
+		Name: "Test", URL: "https://test.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true,
+		Escalations: []storage.EscalationRule{{AfterSecs: 3600, Channel: "slack:oncall"}},
+	}
+	store.CreateCheck(check)
+	incident := &storage.Incident{CheckID: check.ID, StartedAt: time.Now().Add(-time.Hour), Cause: "connection refused"}
+	store.CreateIncident(incident)
+
+	// The down alert went out moments ago; escalation ignores the cooldown
+	manager.SendDownAlert(check, incident, "connection refused")
+
+	if err := manager.SendEscalationAlert(check, incident, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hits["/oncall"] != 2 || hits["/general"] != 1 {
+		t.Errorf("expected the escalation only on slack:oncall, got %v", hits)
+	}
+
+	// Each level is sent once per incident
+	manager.SendEscalationAlert(check, incident, 0)
+	if hits["/oncall"] != 2 {
+		t.Errorf("expected a level to escalate once, got %d oncall deliveries", hits["/oncall"])
+	}
+
+	if err := manager.SendEscalationAlert(check, incident, 1); err == nil {
+		t.Error("expected error for a missing escalation level")
+	}
+}
+
+func TestEscalationAlertSeverity(t *testing.T) {
+	alert := &Alert{Type: "escalation"}
+	if alert.Severity() != "critical" {
+		t.Errorf("expected escalations to default to critical, got %s", alert.Severity())
+	}
+
+	alert.Escalation = &storage.EscalationRule{Severity: "warning"}
+	if alert.Severity() != "warning" {
+		t.Errorf("expected rule severity, got %s", alert.Severity())
+	}
+}
+
+func TestSendStaleAlert(t *testing.T) {
+	store := setupTestStorage(t)
+
+	var payload SlackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	manager := NewManager(&config.AlertsConfig{Slack: config.SlackConfig{Enabled: true, WebhookURL: server.URL}}, store)
+
+	check := &storage.Check{Name: "Stuck", URL: "https://test.com"}
+	last := time.Date(2024, 7, 1, 8, 30, 0, 0, time.UTC)
+	if err := manager.SendStaleAlert(check, last); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(payload.Attachments) != 1 || payload.Attachments[0].Color != "warning" {
+		t.Fatalf("expected one warning attachment, got %+v", payload)
+	}
+	if !strings.Contains(payload.Attachments[0].Title, "STALE: Stuck") || !strings.Contains(payload.Attachments[0].Text, "2024-07-01T08:30:00Z") {
+		t.Errorf("unexpected stale message: %+v", payload.Attachments[0])
+	}
+}
+
+func TestSendDriftAlert(t *testing.T) {
+	store := setupTestStorage(t)
+
+	var payload SlackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	manager := NewManager(&config.AlertsConfig{Slack: config.SlackConfig{Enabled: true, WebhookURL: server.URL}}, store)
+
+	check := &storage.Check{Name: "Edited", URL: "https://test.com"}
+	if err := manager.SendDriftAlert(check, []string{"interval", "tags"}, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(payload.Attachments) != 1 || payload.Attachments[0].Color != "warning" {
+		t.Fatalf("expected one warning attachment, got %+v", payload)
+	}
+	text := payload.Attachments[0].Text
+	if !strings.Contains(payload.Attachments[0].Title, "DRIFT: Edited") || !strings.Contains(text, "interval, tags") || !strings.Contains(text, "restored") {
+		t.Errorf("unexpected drift message: %+v", payload.Attachments[0])
+	}
+}
+
+func TestSendSlowAlert(t *testing.T) {
+	store := setupTestStorage(t)
+
+	var payload SlackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	manager := NewManager(&config.AlertsConfig{Slack: config.SlackConfig{Enabled: true, WebhookURL: server.URL}}, store)
+
+	check := &storage.Check{Name: "Sluggish", URL: "https://test.com"}
+	if err := manager.SendSlowAlert(check, 1500, 300); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(payload.Attachments) != 1 || payload.Attachments[0].Color != "warning" {
+		t.Fatalf("expected one warning attachment, got %+v", payload)
+	}
+	if !strings.Contains(payload.Attachments[0].Title, "SLOW: Sluggish") || !strings.Contains(payload.Attachments[0].Text, "1500ms, 5.0x its 24h average of 300ms") {
+		t.Errorf("unexpected slow message: %+v", payload.Attachments[0])
+	}
+}
+
+func TestSendReminderAlert(t *testing.T) {
+	store := setupTestStorage(t)
+
+	var slackSent, discordSent int
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slackSent++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer slack.Close()
+	discord := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		discordSent++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer discord.Close()
+
+	cfg := &config.AlertsConfig{
+		Slack:   config.SlackConfig{Enabled: true, WebhookURL: slack.URL},
+		Discord: config.DiscordConfig{Enabled: true, WebhookURL: discord.URL},
+	}
+	manager := NewManager(cfg, store)
+
+	check := &storage.Check{Name: "Down", URL: "https://test.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(check)
+	incident := &storage.Incident{CheckID: check.ID, StartedAt: time.Now().Add(-time.Hour), Cause: "connection refused"}
+	store.CreateIncident(incident)
+
+	// Reminders are off by default
+	if err := manager.SendReminderAlert(check, incident); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if slackSent+discordSent != 0 {
+		t.Fatalf("expected no reminders without renotify_minutes, got %d", slackSent+discordSent)
+	}
+
+	// Slack heard about it just now, Discord never did
+	cfg.RenotifyMinutes = 30
+	store.LogAlert(&storage.AlertLog{IncidentID: incident.ID, Channel: "slack", Success: true})
+	if err := manager.SendReminderAlert(check, incident); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if slackSent != 0 || discordSent != 1 {
+		t.Errorf("expected a reminder on discord only, got slack %d, discord %d", slackSent, discordSent)
+	}
+
+	// Both are now inside the renotify interval
+	manager.SendReminderAlert(check, incident)
+	if slackSent != 0 || discordSent != 1 {
+		t.Errorf("expected no reminder within the interval, got slack %d, discord %d", slackSent, discordSent)
+	}
+}
+
 func TestAlertStructure(t *testing.T) {
 	check := &storage.Check{
 		ID:   1,
@@ -0,0 +1,122 @@
+package alerter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/katieblackabee/sentinel/internal/config"
+)
+
+// twilioAPI is the Twilio REST API the Messages endpoint lives under
+const twilioAPI = "https://api.twilio.com"
+
+// maxSMSLength keeps a text to a single 160-character segment
+const maxSMSLength = 160
+
+// TwilioSender texts down, escalation and recovery alerts to phones through
+// Twilio's Messages API. The other alert types are too chatty for SMS and
+// aren't sent.
+type TwilioSender struct {
+	config  *config.TwilioConfig
+	client  *http.Client
+	baseURL string
+}
+
+func NewTwilioSender(cfg *config.TwilioConfig) *TwilioSender {
+	return &TwilioSender{
+		config:  cfg,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		baseURL: twilioAPI,
+	}
+}
+
+// Sends reports whether the alert is one that goes out by SMS: down
+// (including reminders), escalation and recovery
+func (t *TwilioSender) Sends(alert *Alert) bool {
+	switch alert.Type {
+	case "down", "escalation", "recovery":
+		return true
+	}
+	return false
+}
+
+// Send texts the alert to every configured number. A number that fails
+// doesn't stop the rest; the errors are returned together.
+func (t *TwilioSender) Send(alert *Alert) error {
+	if !t.Sends(alert) {
+		return nil
+	}
+
+	body := t.buildMessage(alert)
+	var errs []error
+	for _, to := range t.config.ToNumbers {
+		if err := t.post(to, body); err != nil {
+			errs = append(errs, fmt.Errorf("texting %s: %w", to, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (t *TwilioSender) post(to, body string) error {
+	form := url.Values{
+		"To":   {to},
+		"From": {t.config.FromNumber},
+		"Body": {body},
+	}
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", t.baseURL, url.PathEscape(t.config.AccountSID))
+
+	req, err := http.NewRequest("POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(t.config.AccountSID, t.config.AuthToken)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending twilio message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// Twilio explains rejections, e.g. an unverified number on a trial
+		// account, in a JSON message
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.NewDecoder(io.LimitReader(resp.Body, maxWebhookResponse)).Decode(&apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("twilio returned status %d: %s", resp.StatusCode, apiErr.Message)
+		}
+		return fmt.Errorf("twilio returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// buildMessage writes the alert as one short line, e.g. "Sentinel: DOWN API -
+// connection refused", cut to fit a single SMS
+func (t *TwilioSender) buildMessage(alert *Alert) string {
+	var msg string
+	switch alert.Type {
+	case "down":
+		msg = fmt.Sprintf("Sentinel: DOWN %s - %s", alert.Check.Name, alert.Error)
+	case "escalation":
+		msg = fmt.Sprintf("Sentinel: ESCALATED %s - %s", alert.Check.Name, alert.Error)
+	case "recovery":
+		msg = fmt.Sprintf("Sentinel: RECOVERED %s", alert.Check.Name)
+		if alert.Incident != nil {
+			msg += " after " + alert.Incident.DurationString()
+		}
+	}
+
+	if runes := []rune(msg); len(runes) > maxSMSLength {
+		msg = string(runes[:maxSMSLength-3]) + "..."
+	}
+	return msg
+}
@@ -0,0 +1,136 @@
+package alerter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/katieblackabee/sentinel/internal/config"
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+func TestTwilioSenderSend(t *testing.T) {
+	var to []string
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/2010-04-01/Accounts/AC123/Messages.json" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if user, pass, ok := r.BasicAuth(); !ok || user != "AC123" || pass != "token" {
+			t.Errorf("expected basic auth with the account SID and token, got %q %q", user, pass)
+		}
+		if r.FormValue("From") != "+15005550006" {
+			t.Errorf("unexpected From %q", r.FormValue("From"))
+		}
+		to = append(to, r.FormValue("To"))
+		body = r.FormValue("Body")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	sender := NewTwilioSender(&config.TwilioConfig{
+		Enabled:    true,
+		AccountSID: "AC123",
+		AuthToken:  "token",
+		FromNumber: "+15005550006",
+		ToNumbers:  []string{"+14155550100", "+14155550101"},
+	})
+	sender.baseURL = server.URL
+
+	check := &storage.Check{Name: "API", URL: "https://api.example.com"}
+	if err := sender.Send(&Alert{Type: "down", Check: check, Error: "connection refused"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(to) != 2 || to[0] != "+14155550100" || to[1] != "+14155550101" {
+		t.Errorf("expected a text to each number, got %v", to)
+	}
+	if body != "Sentinel: DOWN API - connection refused" {
+		t.Errorf("unexpected body %q", body)
+	}
+
+	// Warnings aren't texted
+	if err := sender.Send(&Alert{Type: "ssl_expiry", Check: check, Error: "expires soon"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(to) != 2 {
+		t.Errorf("expected no text for an SSL warning, got %d", len(to)-2)
+	}
+}
+
+func TestTwilioSenderError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"code":21608,"message":"The number is unverified"}`))
+	}))
+	defer server.Close()
+
+	sender := NewTwilioSender(&config.TwilioConfig{AccountSID: "AC123", FromNumber: "+15005550006", ToNumbers: []string{"+14155550100"}})
+	sender.baseURL = server.URL
+
+	err := sender.Send(&Alert{Type: "down", Check: &storage.Check{Name: "API"}})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !strings.Contains(err.Error(), "The number is unverified") || !strings.Contains(err.Error(), "+14155550100") {
+		t.Errorf("expected Twilio's message and the number in the error, got %v", err)
+	}
+}
+
+func TestTwilioBuildMessage(t *testing.T) {
+	sender := NewTwilioSender(&config.TwilioConfig{})
+	check := &storage.Check{Name: "API"}
+
+	incident := &storage.Incident{StartedAt: time.Now().Add(-12 * time.Minute)}
+	ended := time.Now()
+	incident.EndedAt = &ended
+	if got := sender.buildMessage(&Alert{Type: "recovery", Check: check, Incident: incident}); got != "Sentinel: RECOVERED API after 12m0s" {
+		t.Errorf("unexpected recovery message %q", got)
+	}
+
+	long := sender.buildMessage(&Alert{Type: "down", Check: check, Error: strings.Repeat("x", 300)})
+	if len(long) != maxSMSLength || !strings.HasSuffix(long, "...") {
+		t.Errorf("expected the message cut to %d characters, got %d: %q", maxSMSLength, len(long), long)
+	}
+}
+
+func TestSendAlertLogsSMS(t *testing.T) {
+	store := setupTestStorage(t)
+
+	texts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		texts++
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	manager := NewManager(&config.AlertsConfig{
+		Twilio: config.TwilioConfig{Enabled: true, AccountSID: "AC123", AuthToken: "token", FromNumber: "+15005550006", ToNumbers: []string{"+14155550100"}},
+	}, store)
+	manager.sms.baseURL = server.URL
+
+	check := &storage.Check{Name: "API", URL: "https://api.example.com"}
+	if err := store.CreateCheck(check); err != nil {
+		t.Fatalf("failed to create check: %v", err)
+	}
+	incident := &storage.Incident{CheckID: check.ID, StartedAt: time.Now(), Cause: "timeout"}
+	if err := store.CreateIncident(incident); err != nil {
+		t.Fatalf("failed to create incident: %v", err)
+	}
+
+	if err := manager.SendDownAlert(check, incident, "timeout"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if texts != 1 {
+		t.Errorf("expected one text, got %d", texts)
+	}
+	if last, err := store.GetLastAlertForIncident(incident.ID, "sms"); err != nil || last == nil || !last.Success {
+		t.Errorf("expected a successful sms delivery logged, got %+v (%v)", last, err)
+	}
+
+	states := manager.ChannelStates()
+	if len(states) != 1 || states[0].Channel != "sms" {
+		t.Errorf("expected the sms channel listed, got %+v", states)
+	}
+}
@@ -0,0 +1,152 @@
+package alerter
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/katieblackabee/sentinel/internal/config"
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+// statsdPacketSize keeps a batch inside one unfragmented UDP packet on a
+// typical 1500 byte MTU
+const statsdPacketSize = 1432
+
+// StatsDSender pushes check metrics to a StatsD agent. Lines are buffered and
+// sent together when the packet fills or the flush interval passes, so a
+// busy scheduler doesn't cost a packet per check.
+type StatsDSender struct {
+	conn   net.Conn
+	prefix string
+	tags   bool
+
+	mu   sync.Mutex
+	buf  []byte
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewStatsDSender connects to the agent and starts the flush loop. UDP has
+// no handshake, so an agent that isn't listening only loses the metrics.
+func NewStatsDSender(cfg *config.StatsDConfig) (*StatsDSender, error) {
+	conn, err := net.Dial("udp", cfg.GetAddr())
+	if err != nil {
+		return nil, fmt.Errorf("connecting to statsd: %w", err)
+	}
+
+	s := &StatsDSender{
+		conn:   conn,
+		prefix: cfg.GetPrefix(),
+		tags:   cfg.Tags,
+		done:   make(chan struct{}),
+	}
+	s.wg.Add(1)
+	go s.flushLoop(cfg.GetFlushInterval())
+	return s, nil
+}
+
+// RecordResult emits the response time and an up, down or degraded count
+func (s *StatsDSender) RecordResult(check *storage.Check, result *storage.CheckResult) {
+	s.add(check, "response_time", result.Region, fmt.Sprintf("%d|ms", result.ResponseTimeMs))
+	s.add(check, "status."+result.Status, result.Region, "1|c")
+}
+
+// RecordAlert counts an alert sent for the check, by type
+func (s *StatsDSender) RecordAlert(check *storage.Check, alertType string) {
+	s.add(check, "alerts."+alertType, "", "1|c")
+}
+
+// SendStateChange counts transitions, so flapping shows up in the pipeline
+func (s *StatsDSender) SendStateChange(check *storage.Check, from, to string, result *storage.CheckResult) error {
+	s.add(check, "state_changes", result.Region, "1|c")
+	return nil
+}
+
+// Close sends what is buffered and stops the flush loop
+func (s *StatsDSender) Close() error {
+	close(s.done)
+	s.wg.Wait()
+	return s.conn.Close()
+}
+
+// line formats one of a check's metrics. Plain StatsD has no tags, so the
+// check and region go in the metric path; with tags they are sent as
+// check:<name> and region:<name> instead.
+func (s *StatsDSender) line(check *storage.Check, name, region, value string) string {
+	if !s.tags {
+		path := s.prefix + ".check." + statsdName(check.Name) + "." + name
+		if region != "" {
+			path += "." + statsdName(region)
+		}
+		return path + ":" + value
+	}
+	tags := "check:" + statsdName(check.Name)
+	if region != "" {
+		tags += ",region:" + statsdName(region)
+	}
+	return s.prefix + ".check." + name + ":" + value + "|#" + tags
+}
+
+// add buffers one line, sending the batch first if the line wouldn't fit
+func (s *StatsDSender) add(check *storage.Check, name, region, value string) {
+	line := s.line(check, name, region, value)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.buf) > 0 && len(s.buf)+1+len(line) > statsdPacketSize {
+		s.flushLocked()
+	}
+	if len(s.buf) > 0 {
+		s.buf = append(s.buf, '\n')
+	}
+	s.buf = append(s.buf, line...)
+}
+
+func (s *StatsDSender) flushLoop(interval time.Duration) {
+	defer s.wg.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.flush()
+		case <-s.done:
+			s.flush()
+			return
+		}
+	}
+}
+
+func (s *StatsDSender) flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.flushLocked()
+}
+
+// flushLocked sends the batch; a write error drops it, as StatsD is best effort
+func (s *StatsDSender) flushLocked() {
+	if len(s.buf) == 0 {
+		return
+	}
+	s.conn.Write(s.buf)
+	s.buf = s.buf[:0]
+}
+
+// statsdName makes a check or region name safe as a metric path segment or
+// tag value
+func statsdName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_', r == '-':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		default:
+			return '_'
+		}
+	}, name)
+}
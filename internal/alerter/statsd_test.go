@@ -0,0 +1,102 @@
+package alerter
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/katieblackabee/sentinel/internal/config"
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+func listenStatsD(t *testing.T) (*net.UDPConn, *config.StatsDConfig) {
+	t.Helper()
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	addr := conn.LocalAddr().(*net.UDPAddr)
+	return conn, &config.StatsDConfig{Enabled: true, Host: "127.0.0.1", Port: addr.Port, FlushInterval: "1h"}
+}
+
+func readPacket(t *testing.T, conn *net.UDPConn) string {
+	t.Helper()
+	buf := make([]byte, 65536)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("expected a packet: %v", err)
+	}
+	return string(buf[:n])
+}
+
+func TestStatsDSenderBatches(t *testing.T) {
+	conn, cfg := listenStatsD(t)
+	sender, err := NewStatsDSender(cfg)
+	if err != nil {
+		t.Fatalf("failed to create sender: %v", err)
+	}
+
+	check := &storage.Check{ID: 1, Name: "My API"}
+	sender.RecordResult(check, &storage.CheckResult{Status: "up", ResponseTimeMs: 120})
+	sender.RecordResult(check, &storage.CheckResult{Status: "down", Region: "eu-west"})
+	sender.RecordAlert(check, "down")
+
+	// Nothing goes out until the flush, which Close forces
+	sender.Close()
+
+	want := strings.Join([]string{
+		"sentinel.check.my_api.response_time:120|ms",
+		"sentinel.check.my_api.status.up:1|c",
+		"sentinel.check.my_api.response_time.eu-west:0|ms",
+		"sentinel.check.my_api.status.down.eu-west:1|c",
+		"sentinel.check.my_api.alerts.down:1|c",
+	}, "\n")
+	if got := readPacket(t, conn); got != want {
+		t.Errorf("expected one batched packet:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestStatsDSenderTags(t *testing.T) {
+	conn, cfg := listenStatsD(t)
+	cfg.Tags = true
+	cfg.Prefix = "mon."
+	sender, err := NewStatsDSender(cfg)
+	if err != nil {
+		t.Fatalf("failed to create sender: %v", err)
+	}
+
+	sender.RecordResult(&storage.Check{Name: "Web"}, &storage.CheckResult{Status: "degraded", ResponseTimeMs: 900, Region: "us"})
+	sender.Close()
+
+	want := "mon.check.response_time:900|ms|#check:web,region:us\nmon.check.status.degraded:1|c|#check:web,region:us"
+	if got := readPacket(t, conn); got != want {
+		t.Errorf("expected tagged metrics:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestStatsDSenderSplitsFullPackets(t *testing.T) {
+	conn, cfg := listenStatsD(t)
+	sender, err := NewStatsDSender(cfg)
+	if err != nil {
+		t.Fatalf("failed to create sender: %v", err)
+	}
+
+	check := &storage.Check{Name: "Busy"}
+	for i := 0; i < 100; i++ {
+		sender.RecordResult(check, &storage.CheckResult{Status: "up", ResponseTimeMs: i})
+	}
+
+	// The buffer filled before any flush, so a full packet is already out
+	first := readPacket(t, conn)
+	if len(first) > statsdPacketSize {
+		t.Errorf("expected packets of at most %d bytes, got %d", statsdPacketSize, len(first))
+	}
+	if !strings.HasPrefix(first, "sentinel.check.busy.response_time:0|ms\n") {
+		t.Errorf("expected the first batch to start with the first result, got %q", first[:40])
+	}
+	sender.Close()
+}
@@ -8,18 +8,21 @@ import (
 	"time"
 
 	"github.com/katieblackabee/sentinel/internal/config"
+	"github.com/katieblackabee/sentinel/internal/storage"
 )
 
 // SlackSender sends alerts to Slack via webhook
 type SlackSender struct {
 	config *config.SlackConfig
 	client *http.Client
+	times  timeDisplay
 }
 
 // DiscordSender sends alerts to Discord via webhook
 type DiscordSender struct {
 	config *config.DiscordConfig
 	client *http.Client
+	times  timeDisplay
 }
 
 // SlackMessage is the Slack webhook payload
@@ -68,15 +71,54 @@ func NewDiscordSender(cfg *config.DiscordConfig) *DiscordSender {
 	}
 }
 
+// Delivery is the outcome of sending an alert to one webhook target
+type Delivery struct {
+	Channel string // alert_log channel, e.g. "slack" or "slack:oncall"
+	Err     error
+}
+
+// Send delivers an alert to every matching target, returning the last error
 func (s *SlackSender) Send(alert *Alert) error {
-	msg := s.buildMessage(alert)
+	return lastDeliveryError(s.SendTargets(alert))
+}
+
+// SendTargets delivers an alert to every target whose filters match it
+func (s *SlackSender) SendTargets(alert *Alert) []Delivery {
+	return s.sendTargets(alert, nil)
+}
+
+// Channels lists the alert_log channel name of every target
+func (s *SlackSender) Channels() []string {
+	return targetChannels("slack", s.config.GetTargets())
+}
 
-	body, err := json.Marshal(msg)
+// sendTargets is SendTargets with an optional gate; targets whose channel
+// allow rejects are skipped
+func (s *SlackSender) sendTargets(alert *Alert, allow func(channel string) bool) []Delivery {
+	body, err := json.Marshal(s.buildMessage(alert))
 	if err != nil {
-		return fmt.Errorf("marshaling slack message: %w", err)
+		return []Delivery{{Channel: "slack", Err: fmt.Errorf("marshaling slack message: %w", err)}}
+	}
+
+	var deliveries []Delivery
+	for i, target := range s.config.GetTargets() {
+		if !target.Matches(alert.Severity(), alert.Check.Tags) {
+			continue
+		}
+		channel := config.TargetChannel("slack", i, target)
+		if allow != nil && !allow(channel) {
+			continue
+		}
+		deliveries = append(deliveries, Delivery{
+			Channel: channel,
+			Err:     s.post(target.URL, body),
+		})
 	}
+	return deliveries
+}
 
-	req, err := http.NewRequest("POST", s.config.WebhookURL, bytes.NewReader(body))
+func (s *SlackSender) post(url string, body []byte) error {
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
 	if err != nil {
 		return fmt.Errorf("creating request: %w", err)
 	}
@@ -106,20 +148,41 @@ func (s *SlackSender) buildMessage(alert *Alert) *SlackMessage {
 	case "recovery":
 		color = "good" // green
 		title = fmt.Sprintf("✅ RECOVERED: %s", alert.Check.Name)
-		duration := "unknown"
-		if alert.Incident != nil {
-			duration = alert.Incident.DurationString()
-		}
-		text = fmt.Sprintf("*URL:* %s\n*Downtime:* %s", alert.Check.URL, duration)
+		text = fmt.Sprintf("*URL:* %s\n*Downtime:* %s", alert.Check.URL, s.times.downtime(alert.Incident))
+	case "escalation":
+		color = "danger"
+		title = fmt.Sprintf("🚨 ESCALATED: %s", alert.Check.Name)
+		text = fmt.Sprintf("*URL:* %s\n*Error:* %s", alert.Check.URL, alert.Error)
 	case "ssl_expiry":
 		color = "warning" // yellow
 		title = fmt.Sprintf("⚠️ SSL EXPIRING: %s", alert.Check.Name)
 		text = fmt.Sprintf("*URL:* %s\n*Warning:* %s", alert.Check.URL, alert.Error)
+	case "stale":
+		color = "warning"
+		title = fmt.Sprintf("⏸️ STALE: %s", alert.Check.Name)
+		text = fmt.Sprintf("*URL:* %s\n*Warning:* %s", alert.Check.URL, alert.Error)
+	case "drift":
+		color = "warning"
+		title = fmt.Sprintf("📝 DRIFT: %s", alert.Check.Name)
+		text = fmt.Sprintf("*URL:* %s\n*Warning:* %s", alert.Check.URL, alert.Error)
+	case "slow":
+		color = "warning"
+		title = fmt.Sprintf("🐢 SLOW: %s", alert.Check.Name)
+		text = fmt.Sprintf("*URL:* %s\n*Warning:* %s", alert.Check.URL, alert.Error)
+	case "digest":
+		color = "#439FE0" // blue
+		title = fmt.Sprintf("🗒️ DIGEST: %s", alert.Check.Name)
+		text = alert.Error
 	default:
 		color = "danger"
 		title = fmt.Sprintf("Alert: %s", alert.Check.Name)
 		text = alert.Error
 	}
+	// Slack renders ts in each reader's zone; spell it out when a team zone is set
+	if s.times.custom() {
+		text += fmt.Sprintf("\n*Time:* %s", s.times.format(alert.Timestamp))
+	}
+	text += checkContext(alert.Check, "*")
 
 	return &SlackMessage{
 		Attachments: []SlackAttachment{
@@ -134,15 +197,48 @@ func (s *SlackSender) buildMessage(alert *Alert) *SlackMessage {
 	}
 }
 
+// Send delivers an alert to every matching target, returning the last error
 func (d *DiscordSender) Send(alert *Alert) error {
-	msg := d.buildMessage(alert)
+	return lastDeliveryError(d.SendTargets(alert))
+}
+
+// SendTargets delivers an alert to every target whose filters match it
+func (d *DiscordSender) SendTargets(alert *Alert) []Delivery {
+	return d.sendTargets(alert, nil)
+}
+
+// Channels lists the alert_log channel name of every target
+func (d *DiscordSender) Channels() []string {
+	return targetChannels("discord", d.config.GetTargets())
+}
 
-	body, err := json.Marshal(msg)
+// sendTargets is SendTargets with an optional gate; targets whose channel
+// allow rejects are skipped
+func (d *DiscordSender) sendTargets(alert *Alert, allow func(channel string) bool) []Delivery {
+	body, err := json.Marshal(d.buildMessage(alert))
 	if err != nil {
-		return fmt.Errorf("marshaling discord message: %w", err)
+		return []Delivery{{Channel: "discord", Err: fmt.Errorf("marshaling discord message: %w", err)}}
+	}
+
+	var deliveries []Delivery
+	for i, target := range d.config.GetTargets() {
+		if !target.Matches(alert.Severity(), alert.Check.Tags) {
+			continue
+		}
+		channel := config.TargetChannel("discord", i, target)
+		if allow != nil && !allow(channel) {
+			continue
+		}
+		deliveries = append(deliveries, Delivery{
+			Channel: channel,
+			Err:     d.post(target.URL, body),
+		})
 	}
+	return deliveries
+}
 
-	req, err := http.NewRequest("POST", d.config.WebhookURL, bytes.NewReader(body))
+func (d *DiscordSender) post(url string, body []byte) error {
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
 	if err != nil {
 		return fmt.Errorf("creating request: %w", err)
 	}
@@ -174,20 +270,40 @@ func (d *DiscordSender) buildMessage(alert *Alert) *DiscordMessage {
 	case "recovery":
 		color = 3066993 // green (#2ECC71)
 		title = fmt.Sprintf("✅ RECOVERED: %s", alert.Check.Name)
-		duration := "unknown"
-		if alert.Incident != nil {
-			duration = alert.Incident.DurationString()
-		}
-		description = fmt.Sprintf("**URL:** %s\n**Downtime:** %s", alert.Check.URL, duration)
+		description = fmt.Sprintf("**URL:** %s\n**Downtime:** %s", alert.Check.URL, d.times.downtime(alert.Incident))
+	case "escalation":
+		color = 10038562 // dark red (#992D22)
+		title = fmt.Sprintf("🚨 ESCALATED: %s", alert.Check.Name)
+		description = fmt.Sprintf("**URL:** %s\n**Error:** %s", alert.Check.URL, alert.Error)
 	case "ssl_expiry":
 		color = 16776960 // yellow (#FFFF00)
 		title = fmt.Sprintf("⚠️ SSL EXPIRING: %s", alert.Check.Name)
 		description = fmt.Sprintf("**URL:** %s\n**Warning:** %s", alert.Check.URL, alert.Error)
+	case "stale":
+		color = 15105570 // orange (#E67E22)
+		title = fmt.Sprintf("⏸️ STALE: %s", alert.Check.Name)
+		description = fmt.Sprintf("**URL:** %s\n**Warning:** %s", alert.Check.URL, alert.Error)
+	case "drift":
+		color = 15105570
+		title = fmt.Sprintf("📝 DRIFT: %s", alert.Check.Name)
+		description = fmt.Sprintf("**URL:** %s\n**Warning:** %s", alert.Check.URL, alert.Error)
+	case "slow":
+		color = 15105570
+		title = fmt.Sprintf("🐢 SLOW: %s", alert.Check.Name)
+		description = fmt.Sprintf("**URL:** %s\n**Warning:** %s", alert.Check.URL, alert.Error)
+	case "digest":
+		color = 3447003 // blue (#3498DB)
+		title = fmt.Sprintf("🗒️ DIGEST: %s", alert.Check.Name)
+		description = alert.Error
 	default:
 		color = 15158332
 		title = fmt.Sprintf("Alert: %s", alert.Check.Name)
 		description = alert.Error
 	}
+	if d.times.custom() {
+		description += fmt.Sprintf("\n**Time:** %s", d.times.format(alert.Timestamp))
+	}
+	description += checkContext(alert.Check, "**")
 
 	return &DiscordMessage{
 		Embeds: []DiscordEmbed{
@@ -196,8 +312,39 @@ func (d *DiscordSender) buildMessage(alert *Alert) *DiscordMessage {
 				Description: description,
 				Color:       color,
 				Footer:      DiscordFooter{Text: "Sentinel Uptime Monitor"},
-				Timestamp:   alert.Timestamp.Format(time.RFC3339),
+				Timestamp:   d.times.local(alert.Timestamp).Format(time.RFC3339),
 			},
 		},
 	}
 }
+
+// checkContext renders a check's description and runbook link as extra
+// message lines, wrapping labels in the given bold marker
+func checkContext(check *storage.Check, bold string) string {
+	var lines string
+	if check.Description != "" {
+		lines += fmt.Sprintf("\n%sAbout:%s %s", bold, bold, check.Description)
+	}
+	if check.RunbookURL != "" {
+		lines += fmt.Sprintf("\n%sRunbook:%s %s", bold, bold, check.RunbookURL)
+	}
+	return lines
+}
+
+func targetChannels(provider string, targets []config.WebhookTarget) []string {
+	channels := make([]string, len(targets))
+	for i, target := range targets {
+		channels[i] = config.TargetChannel(provider, i, target)
+	}
+	return channels
+}
+
+func lastDeliveryError(deliveries []Delivery) error {
+	var lastErr error
+	for _, d := range deliveries {
+		if d.Err != nil {
+			lastErr = d.Err
+		}
+	}
+	return lastErr
+}
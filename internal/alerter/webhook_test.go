@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -305,3 +306,145 @@ func TestDiscordSender_SSLExpiryAlert(t *testing.T) {
 		t.Errorf("expected color 16776960, got %d", msg.Embeds[0].Color)
 	}
 }
+
+func TestWebhookMessagesIncludeRunbook(t *testing.T) {
+	alert := &Alert{
+		Type: "down",
+		Check: &storage.Check{
+			Name:        "Test Service",
+			URL:         "https://example.com",
+			Description: "Checkout backend",
+			RunbookURL:  "https://wiki.example.com/runbooks/checkout",
+		},
+		Error:     "Connection refused",
+		Timestamp: time.Now(),
+	}
+
+	slack := NewSlackSender(&config.SlackConfig{}).buildMessage(alert)
+	if !strings.Contains(slack.Attachments[0].Text, "*Runbook:* https://wiki.example.com/runbooks/checkout") {
+		t.Errorf("expected slack text to include runbook, got %q", slack.Attachments[0].Text)
+	}
+	if !strings.Contains(slack.Attachments[0].Text, "*About:* Checkout backend") {
+		t.Errorf("expected slack text to include description, got %q", slack.Attachments[0].Text)
+	}
+
+	discord := NewDiscordSender(&config.DiscordConfig{}).buildMessage(alert)
+	if !strings.Contains(discord.Embeds[0].Description, "**Runbook:** https://wiki.example.com/runbooks/checkout") {
+		t.Errorf("expected discord description to include runbook, got %q", discord.Embeds[0].Description)
+	}
+
+	// Nothing extra when the check has no context
+	alert.Check = &storage.Check{Name: "Bare", URL: "https://example.com"}
+	slack = NewSlackSender(&config.SlackConfig{}).buildMessage(alert)
+	if strings.Contains(slack.Attachments[0].Text, "Runbook") {
+		t.Errorf("expected no runbook line, got %q", slack.Attachments[0].Text)
+	}
+}
+
+func TestWebhookMessagesDisplayTimezone(t *testing.T) {
+	times := newTimeDisplay(&config.AlertsConfig{Timezone: "Europe/Berlin"})
+	alert := &Alert{
+		Type:      "down",
+		Check:     &storage.Check{Name: "Test Service", URL: "https://example.com"},
+		Error:     "Connection refused",
+		Timestamp: time.Date(2024, 7, 1, 8, 30, 0, 0, time.UTC),
+	}
+
+	slack := &SlackSender{config: &config.SlackConfig{}, times: times}
+	text := slack.buildMessage(alert).Attachments[0].Text
+	if !strings.Contains(text, "*Time:* Mon, 01 Jul 2024 10:30:00 CEST") {
+		t.Errorf("expected slack time in CEST, got %q", text)
+	}
+
+	discord := &DiscordSender{config: &config.DiscordConfig{}, times: times}
+	embed := discord.buildMessage(alert).Embeds[0]
+	if !strings.Contains(embed.Description, "**Time:** Mon, 01 Jul 2024 10:30:00 CEST") {
+		t.Errorf("expected discord time in CEST, got %q", embed.Description)
+	}
+	if embed.Timestamp != "2024-07-01T10:30:00+02:00" {
+		t.Errorf("expected embed timestamp with offset, got %q", embed.Timestamp)
+	}
+
+	// No time line by default; Slack and Discord localize ts themselves
+	text = NewSlackSender(&config.SlackConfig{}).buildMessage(alert).Attachments[0].Text
+	if strings.Contains(text, "Time:") {
+		t.Errorf("expected no time line by default, got %q", text)
+	}
+}
+
+func TestSlackSenderTargets(t *testing.T) {
+	hits := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits[r.URL.Path]++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sender := NewSlackSender(&config.SlackConfig{
+		Enabled:    true,
+		WebhookURL: server.URL + "/general",
+		Targets: []config.WebhookTarget{
+			{Name: "oncall", URL: server.URL + "/oncall", Severities: []string{"critical"}},
+			{URL: server.URL + "/payments", Tags: []string{"payments"}},
+		},
+	})
+
+	alert := &Alert{
+		Type:      "down",
+		Check:     &storage.Check{Name: "API", URL: "https://example.com", Tags: []string{"api"}},
+		Timestamp: time.Now(),
+	}
+
+	deliveries := sender.SendTargets(alert)
+	if len(deliveries) != 2 {
+		t.Fatalf("expected 2 deliveries, got %d", len(deliveries))
+	}
+	if deliveries[0].Channel != "slack" || deliveries[1].Channel != "slack:oncall" {
+		t.Errorf("unexpected channels: %+v", deliveries)
+	}
+
+	// Recoveries skip the critical-only target; tagged checks reach the tag target
+	alert.Type = "recovery"
+	alert.Check.Tags = []string{"payments"}
+	deliveries = sender.SendTargets(alert)
+	if len(deliveries) != 2 || deliveries[1].Channel != "slack:3" {
+		t.Errorf("expected general and payments deliveries, got %+v", deliveries)
+	}
+
+	if hits["/general"] != 2 || hits["/oncall"] != 1 || hits["/payments"] != 1 {
+		t.Errorf("unexpected hits: %v", hits)
+	}
+}
+
+func TestDiscordSenderTargetFailure(t *testing.T) {
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ok.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+
+	sender := NewDiscordSender(&config.DiscordConfig{
+		Enabled: true,
+		Targets: []config.WebhookTarget{
+			{Name: "main", URL: ok.URL},
+			{Name: "backup", URL: broken.URL},
+		},
+	})
+
+	alert := &Alert{
+		Type:      "down",
+		Check:     &storage.Check{Name: "API", URL: "https://example.com"},
+		Timestamp: time.Now(),
+	}
+
+	deliveries := sender.SendTargets(alert)
+	if len(deliveries) != 2 || deliveries[0].Err != nil || deliveries[1].Err == nil {
+		t.Errorf("expected only the backup target to fail, got %+v", deliveries)
+	}
+	if err := sender.Send(alert); err == nil {
+		t.Error("expected Send to report the failed target")
+	}
+}
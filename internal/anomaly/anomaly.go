@@ -11,29 +11,39 @@ import (
 
 // Baseline represents the historical latency baseline for a check.
 type Baseline struct {
-	CheckID       int64     `json:"check_id"`
-	Mean          float64   `json:"mean_ms"`
-	StdDev        float64   `json:"std_dev_ms"`
-	Min           float64   `json:"min_ms"`
-	Max           float64   `json:"max_ms"`
-	SampleCount   int       `json:"sample_count"`
-	CalculatedAt  time.Time `json:"calculated_at"`
-	PeriodHours   int       `json:"period_hours"`
+	CheckID      int64     `json:"check_id"`
+	Mean         float64   `json:"mean_ms"`
+	StdDev       float64   `json:"std_dev_ms"`
+	Min          float64   `json:"min_ms"`
+	Max          float64   `json:"max_ms"`
+	SampleCount  int       `json:"sample_count"`
+	CalculatedAt time.Time `json:"calculated_at"`
+	PeriodHours  int       `json:"period_hours"`
 }
 
 // AnomalyType represents the type of detected anomaly.
 type AnomalyType string
 
 const (
-	AnomalyTypeSpike    AnomalyType = "spike"    // Sudden increase
-	AnomalyTypeDrop     AnomalyType = "drop"     // Sudden decrease (unusual but could indicate caching issue)
+	AnomalyTypeSpike     AnomalyType = "spike"     // Sudden increase
+	AnomalyTypeDrop      AnomalyType = "drop"      // Too fast: often a stub, cache or short-circuited error
 	AnomalyTypeSustained AnomalyType = "sustained" // Consistently elevated
 )
 
+// Severity ranks an anomaly the way check statuses do: a warning leaves the
+// check up but degraded, a critical one is worth alerting on.
+type Severity string
+
+const (
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
 // Anomaly represents a detected latency anomaly.
 type Anomaly struct {
 	CheckID      int64       `json:"check_id"`
 	Type         AnomalyType `json:"type"`
+	Severity     Severity    `json:"severity"`
 	CurrentMs    float64     `json:"current_ms"`
 	BaselineMean float64     `json:"baseline_mean_ms"`
 	Deviation    float64     `json:"deviation"` // Number of standard deviations
@@ -53,6 +63,12 @@ type Config struct {
 	BaselineHours int
 	// Minimum std dev to avoid false positives on very stable services
 	MinStdDev float64
+	// Flag responses this many standard deviations faster than the baseline
+	// (0 disables the baseline-derived bound)
+	DropThreshold float64
+	// Flag responses faster than this fraction of the baseline mean, e.g. 0.1
+	// for under a tenth of it, however noisy the baseline (0 disables)
+	DropRatio float64
 }
 
 // DefaultConfig returns sensible defaults.
@@ -63,6 +79,7 @@ func DefaultConfig() Config {
 		MinSamples:     30,   // Need at least 30 samples
 		BaselineHours:  24,   // 24 hours of history
 		MinStdDev:      10.0, // Minimum 10ms std dev
+		DropThreshold:  3.0,  // 3 sigma below
 	}
 }
 
@@ -146,6 +163,7 @@ func (d *Detector) DetectAnomaly(checkID int64, currentMs int) (*Anomaly, error)
 		return &Anomaly{
 			CheckID:      checkID,
 			Type:         AnomalyTypeSpike,
+			Severity:     SeverityCritical,
 			CurrentMs:    current,
 			BaselineMean: baseline.Mean,
 			Deviation:    deviation,
@@ -154,11 +172,13 @@ func (d *Detector) DetectAnomaly(checkID int64, currentMs int) (*Anomaly, error)
 		}, nil
 	}
 
-	// Check for unusual drop (very fast response)
-	if deviation <= -d.config.SpikeThreshold && current > 0 {
+	// Check for a response too fast to be real, a warning since the check is
+	// still answering
+	if d.tooFast(current, baseline, deviation) {
 		return &Anomaly{
 			CheckID:      checkID,
 			Type:         AnomalyTypeDrop,
+			Severity:     SeverityWarning,
 			CurrentMs:    current,
 			BaselineMean: baseline.Mean,
 			Deviation:    deviation,
@@ -170,6 +190,18 @@ func (d *Detector) DetectAnomaly(checkID int64, currentMs int) (*Anomaly, error)
 	return nil, nil // No anomaly
 }
 
+// tooFast reports whether current is below either lower bound: the
+// baseline-derived DropThreshold or the fixed DropRatio of the mean
+func (d *Detector) tooFast(current float64, baseline *Baseline, deviation float64) bool {
+	if current <= 0 {
+		return false // No timing recorded
+	}
+	if d.config.DropThreshold > 0 && deviation <= -d.config.DropThreshold {
+		return true
+	}
+	return d.config.DropRatio > 0 && current < baseline.Mean*d.config.DropRatio
+}
+
 // DetectSustainedAnomaly checks for consistently elevated latency over recent results.
 func (d *Detector) DetectSustainedAnomaly(checkID int64, recentCount int) (*Anomaly, error) {
 	baseline, err := d.CalculateBaseline(checkID)
@@ -212,6 +244,7 @@ func (d *Detector) DetectSustainedAnomaly(checkID int64, recentCount int) (*Anom
 		return &Anomaly{
 			CheckID:      checkID,
 			Type:         AnomalyTypeSustained,
+			Severity:     SeverityWarning,
 			CurrentMs:    avgRecent,
 			BaselineMean: baseline.Mean,
 			Deviation:    deviation,
@@ -347,7 +380,7 @@ func formatSpikeMessage(current, baseline, deviation float64) string {
 }
 
 func formatDropMessage(current, baseline, deviation float64) string {
-	return formatMessage("Unusual latency drop", current, baseline, deviation)
+	return formatMessage("Response suspiciously fast", current, baseline, deviation)
 }
 
 func formatSustainedMessage(avg, baseline float64, count int) string {
@@ -23,67 +23,120 @@ func (m *MockStorage) GetRecentResults(checkID int64, count int) ([]*storage.Che
 	return m.results[:count], nil
 }
 
+func (m *MockStorage) GetResultDetails(checkID int64, limit int) ([]*storage.CheckResult, error) {
+	return m.GetRecentResults(checkID, limit)
+}
+
 // Implement other storage.Storage methods as no-ops
-func (m *MockStorage) CreateCheck(check *storage.Check) error                          { return nil }
-func (m *MockStorage) GetCheck(id int64) (*storage.Check, error)                        { return nil, nil }
-func (m *MockStorage) GetCheckByURL(url string) (*storage.Check, error)                 { return nil, nil }
-func (m *MockStorage) ListChecks() ([]*storage.Check, error)                            { return nil, nil }
-func (m *MockStorage) ListEnabledChecks() ([]*storage.Check, error)                     { return nil, nil }
-func (m *MockStorage) ListChecksByTag(tag string) ([]*storage.Check, error)             { return nil, nil }
-func (m *MockStorage) UpdateCheck(check *storage.Check) error                           { return nil }
-func (m *MockStorage) DeleteCheck(id int64) error                                       { return nil }
-func (m *MockStorage) SaveResult(result *storage.CheckResult) error                     { return nil }
+func (m *MockStorage) CreateCheck(check *storage.Check) error                       { return nil }
+func (m *MockStorage) GetCheck(id int64) (*storage.Check, error)                    { return nil, nil }
+func (m *MockStorage) GetCheckByURL(url string) (*storage.Check, error)             { return nil, nil }
+func (m *MockStorage) ListChecks() ([]*storage.Check, error)                        { return nil, nil }
+func (m *MockStorage) ListEnabledChecks() ([]*storage.Check, error)                 { return nil, nil }
+func (m *MockStorage) ListChecksByTag(tag string) ([]*storage.Check, error)         { return nil, nil }
+func (m *MockStorage) GetChecksModifiedSince(t time.Time) ([]*storage.Check, error) { return nil, nil }
+func (m *MockStorage) UpdateCheck(check *storage.Check) error                       { return nil }
+func (m *MockStorage) SetCheckPaused(id int64, paused bool) error                   { return nil }
+func (m *MockStorage) PauseCheckUntil(id int64, until time.Time) error              { return nil }
+func (m *MockStorage) DeleteCheck(id int64) error                                   { return nil }
+func (m *MockStorage) SaveResult(result *storage.CheckResult) error                 { return nil }
+func (m *MockStorage) ResetCheckHistory(checkID int64) error                        { return nil }
+func (m *MockStorage) ExtendResult(result *storage.CheckResult) error               { return nil }
+func (m *MockStorage) ImportResults(results []*storage.CheckResult) (int, int, error) {
+	return 0, 0, nil
+}
 func (m *MockStorage) GetResults(checkID int64, limit int, offset int) ([]*storage.CheckResult, error) {
 	return nil, nil
 }
-func (m *MockStorage) GetLatestResult(checkID int64) (*storage.CheckResult, error)      { return nil, nil }
+func (m *MockStorage) CountResults(checkID int64) (int, error)                     { return 0, nil }
+func (m *MockStorage) GetLatestResult(checkID int64) (*storage.CheckResult, error) { return nil, nil }
+func (m *MockStorage) GetLatestResults() (map[int64][]*storage.CheckResult, error) {
+	return nil, nil
+}
+func (m *MockStorage) ListChecksByStatus(status string) ([]*storage.Check, error) {
+	return nil, nil
+}
+func (m *MockStorage) FilterChecks(filter storage.CheckFilter) ([]*storage.Check, error) {
+	return nil, nil
+}
 func (m *MockStorage) GetLatestResultsByRegion(checkID int64) (map[string]*storage.CheckResult, error) {
 	return nil, nil
 }
-func (m *MockStorage) CountFailingRegions(checkID int64) (int, error)                   { return 0, nil }
-func (m *MockStorage) GetStats(checkID int64) (*storage.CheckStats, error)              { return nil, nil }
-func (m *MockStorage) CreateIncident(incident *storage.Incident) error                  { return nil }
-func (m *MockStorage) GetIncident(id int64) (*storage.Incident, error)                  { return nil, nil }
-func (m *MockStorage) GetIncidentWithNotes(id int64) (*storage.Incident, error)         { return nil, nil }
-func (m *MockStorage) GetActiveIncident(checkID int64) (*storage.Incident, error)       { return nil, nil }
-func (m *MockStorage) CloseIncident(id int64, endedAt time.Time) error                  { return nil }
+func (m *MockStorage) CountFailingRegions(checkID int64) (int, error)            { return 0, nil }
+func (m *MockStorage) GetStats(checkID int64) (*storage.CheckStats, error)       { return nil, nil }
+func (m *MockStorage) SaveGoldenSnapshot(snapshot *storage.GoldenSnapshot) error { return nil }
+func (m *MockStorage) GetGoldenSnapshot(checkID int64) (*storage.GoldenSnapshot, error) {
+	return nil, nil
+}
+func (m *MockStorage) DeleteGoldenSnapshot(checkID int64) error                           { return nil }
+func (m *MockStorage) CreateIncident(incident *storage.Incident) error                    { return nil }
+func (m *MockStorage) GetIncident(id int64) (*storage.Incident, error)                    { return nil, nil }
+func (m *MockStorage) GetIncidentWithNotes(id int64) (*storage.Incident, error)           { return nil, nil }
+func (m *MockStorage) GetActiveIncident(checkID int64) (*storage.Incident, error)         { return nil, nil }
+func (m *MockStorage) CloseIncident(id int64, endedAt time.Time) error                    { return nil }
 func (m *MockStorage) UpdateIncidentStatus(id int64, status storage.IncidentStatus) error { return nil }
-func (m *MockStorage) UpdateIncidentTitle(id int64, title string) error                 { return nil }
-func (m *MockStorage) ListIncidents(limit int, offset int) ([]*storage.Incident, error) { return nil, nil }
+func (m *MockStorage) UpdateIncidentTitle(id int64, title string) error                   { return nil }
+func (m *MockStorage) SetIncidentExternalID(id int64, externalID string) error            { return nil }
+func (m *MockStorage) ListIncidents(limit int, offset int) ([]*storage.Incident, error) {
+	return nil, nil
+}
+func (m *MockStorage) CountIncidents() (int, error) { return 0, nil }
 func (m *MockStorage) ListIncidentsForCheck(checkID int64, limit int) ([]*storage.Incident, error) {
 	return nil, nil
 }
-func (m *MockStorage) ListActiveIncidents() ([]*storage.Incident, error)                { return nil, nil }
-func (m *MockStorage) AddIncidentNote(note *storage.IncidentNote) error                 { return nil }
-func (m *MockStorage) GetIncidentNotes(incidentID int64) ([]*storage.IncidentNote, error) { return nil, nil }
-func (m *MockStorage) DeleteIncidentNote(id int64) error                                { return nil }
-func (m *MockStorage) LogAlert(log *storage.AlertLog) error                             { return nil }
+func (m *MockStorage) ListActiveIncidents() ([]*storage.Incident, error) { return nil, nil }
+func (m *MockStorage) GetUptimeSeries(checkID int64, start, end time.Time, resolution time.Duration) ([]*storage.UptimePoint, error) {
+	return nil, nil
+}
+func (m *MockStorage) GetIncidentStats(checkID int64, start, end time.Time) (*storage.IncidentStats, error) {
+	return nil, nil
+}
+func (m *MockStorage) AddIncidentNote(note *storage.IncidentNote) error { return nil }
+func (m *MockStorage) GetIncidentNotes(incidentID int64) ([]*storage.IncidentNote, error) {
+	return nil, nil
+}
+func (m *MockStorage) DeleteIncidentNote(id int64) error                               { return nil }
+func (m *MockStorage) CreateMaintenanceWindow(window *storage.MaintenanceWindow) error { return nil }
+func (m *MockStorage) ListMaintenanceWindows() ([]*storage.MaintenanceWindow, error) {
+	return nil, nil
+}
+func (m *MockStorage) ListActiveMaintenanceWindows(at time.Time) ([]*storage.MaintenanceWindow, error) {
+	return nil, nil
+}
+func (m *MockStorage) DeleteMaintenanceWindow(id int64) error { return nil }
+func (m *MockStorage) GetSetting(key string) (string, error)  { return "", nil }
+func (m *MockStorage) SetSetting(key, value string) error     { return nil }
+func (m *MockStorage) DeleteSetting(key string) error         { return nil }
+func (m *MockStorage) LogAlert(log *storage.AlertLog) error   { return nil }
 func (m *MockStorage) GetLastAlertForIncident(incidentID int64, channel string) (*storage.AlertLog, error) {
 	return nil, nil
 }
-func (m *MockStorage) CreateHourlyAggregate(agg *storage.HourlyAggregate) error         { return nil }
+func (m *MockStorage) CreateHourlyAggregate(agg *storage.HourlyAggregate) error { return nil }
 func (m *MockStorage) GetHourlyAggregates(checkID int64, start, end time.Time) ([]*storage.HourlyAggregate, error) {
 	return nil, nil
 }
-func (m *MockStorage) CleanupOldResults(olderThan time.Time) error                      { return nil }
-func (m *MockStorage) AggregateResults(olderThan time.Time) error                       { return nil }
-func (m *MockStorage) CleanupOldAggregates(olderThan time.Time) error                   { return nil }
-func (m *MockStorage) Close() error                                                     { return nil }
+func (m *MockStorage) CleanupOldResults(olderThan time.Time) error    { return nil }
+func (m *MockStorage) AggregateResults(olderThan time.Time) error     { return nil }
+func (m *MockStorage) CleanupOldAggregates(olderThan time.Time) error { return nil }
+func (m *MockStorage) ArchiveResults(olderThan time.Time) error       { return nil }
+func (m *MockStorage) CleanupOldArchives(olderThan time.Time) error   { return nil }
+func (m *MockStorage) Backup(path string) error                       { return nil }
+func (m *MockStorage) Close() error                                   { return nil }
 
 // Probe methods
-func (m *MockStorage) CreateProbe(probe *storage.Probe) error                           { return nil }
-func (m *MockStorage) GetProbe(id int64) (*storage.Probe, error)                        { return nil, nil }
-func (m *MockStorage) GetProbeByAPIKey(apiKey string) (*storage.Probe, error)           { return nil, nil }
-func (m *MockStorage) ListProbes() ([]*storage.Probe, error)                            { return nil, nil }
-func (m *MockStorage) ListActiveProbes() ([]*storage.Probe, error)                      { return nil, nil }
-func (m *MockStorage) ListProbesByRegion(region string) ([]*storage.Probe, error)       { return nil, nil }
-func (m *MockStorage) UpdateProbeHeartbeat(id int64) error                              { return nil }
-func (m *MockStorage) UpdateProbeStatus(id int64, status string) error                  { return nil }
-func (m *MockStorage) DeleteProbe(id int64) error                                       { return nil }
-func (m *MockStorage) CleanupStaleProbes() (int, error)                                 { return 0, nil }
+func (m *MockStorage) CreateProbe(probe *storage.Probe) error                     { return nil }
+func (m *MockStorage) GetProbe(id int64) (*storage.Probe, error)                  { return nil, nil }
+func (m *MockStorage) GetProbeByAPIKey(apiKey string) (*storage.Probe, error)     { return nil, nil }
+func (m *MockStorage) ListProbes() ([]*storage.Probe, error)                      { return nil, nil }
+func (m *MockStorage) ListActiveProbes() ([]*storage.Probe, error)                { return nil, nil }
+func (m *MockStorage) ListProbesByRegion(region string) ([]*storage.Probe, error) { return nil, nil }
+func (m *MockStorage) UpdateProbeHeartbeat(id int64) error                        { return nil }
+func (m *MockStorage) UpdateProbeStatus(id int64, status string) error            { return nil }
+func (m *MockStorage) DeleteProbe(id int64) error                                 { return nil }
+func (m *MockStorage) CleanupStaleProbes() (int, error)                           { return 0, nil }
 
 // Probe Result methods
-func (m *MockStorage) SaveProbeResult(result *storage.ProbeResult) error                { return nil }
+func (m *MockStorage) SaveProbeResult(result *storage.ProbeResult) error { return nil }
 func (m *MockStorage) GetProbeResults(checkID int64, limit int, offset int) ([]*storage.ProbeResult, error) {
 	return nil, nil
 }
@@ -93,7 +146,7 @@ func (m *MockStorage) GetProbeResultsByProbe(probeID int64, limit int) ([]*stora
 func (m *MockStorage) GetLatestProbeResultsByRegion(checkID int64) (map[string]*storage.ProbeResult, error) {
 	return nil, nil
 }
-func (m *MockStorage) CountFailingProbeRegions(checkID int64) (int, error)              { return 0, nil }
+func (m *MockStorage) CountFailingProbeRegions(checkID int64) (int, error) { return 0, nil }
 
 func makeResult(status string, responseMs int) *storage.CheckResult {
 	return &storage.CheckResult{
@@ -237,6 +290,61 @@ func TestDetectAnomalyNormal(t *testing.T) {
 	}
 }
 
+func TestDetectAnomalyTooFast(t *testing.T) {
+	// Baseline around 100ms
+	results := make([]*storage.CheckResult, 50)
+	for i := 0; i < 50; i++ {
+		results[i] = makeResult("up", 95+i%10)
+	}
+
+	mock := &MockStorage{results: results}
+	detector := NewDetector(mock, DefaultConfig())
+
+	// 1ms is far below the baseline: likely a stub or cached error
+	anomaly, err := detector.DetectAnomaly(1, 1)
+	if err != nil {
+		t.Fatalf("DetectAnomaly error: %v", err)
+	}
+	if anomaly == nil {
+		t.Fatal("expected anomaly for a too-fast response, got nil")
+	}
+	if anomaly.Type != AnomalyTypeDrop || anomaly.Severity != SeverityWarning {
+		t.Errorf("got %v/%v, want drop/warning", anomaly.Type, anomaly.Severity)
+	}
+
+	// With the baseline bound off, nothing flags it
+	cfg := DefaultConfig()
+	cfg.DropThreshold = 0
+	detector = NewDetector(mock, cfg)
+	if anomaly, _ := detector.DetectAnomaly(1, 1); anomaly != nil {
+		t.Errorf("expected no anomaly with drop detection off, got %v", anomaly.Type)
+	}
+}
+
+func TestDetectAnomalyDropRatio(t *testing.T) {
+	// A noisy baseline, 50-250ms, where 3 sigma below the mean is negative
+	results := make([]*storage.CheckResult, 50)
+	for i := 0; i < 50; i++ {
+		results[i] = makeResult("up", 50+(i%5)*50)
+	}
+
+	cfg := DefaultConfig()
+	cfg.DropRatio = 0.1
+	detector := NewDetector(&MockStorage{results: results}, cfg)
+
+	anomaly, err := detector.DetectAnomaly(1, 5)
+	if err != nil {
+		t.Fatalf("DetectAnomaly error: %v", err)
+	}
+	if anomaly == nil || anomaly.Type != AnomalyTypeDrop {
+		t.Fatalf("expected a drop below a tenth of the mean, got %v", anomaly)
+	}
+
+	if anomaly, _ := detector.DetectAnomaly(1, 40); anomaly != nil {
+		t.Errorf("expected 40ms to be within bounds, got %v", anomaly.Type)
+	}
+}
+
 func TestGetTrendIncreasing(t *testing.T) {
 	// First half: 100ms, second half: 150ms
 	results := make([]*storage.CheckResult, 20)
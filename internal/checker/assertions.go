@@ -0,0 +1,203 @@
+package checker
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Assertion types name the matcher a check's assertions are written for
+const (
+	AssertionAuto = "auto" // Picked from the response's Content-Type
+	AssertionJSON = "json"
+	AssertionXML  = "xml"
+	AssertionText = "text"
+)
+
+// BodyMatcher evaluates assertions written for one body format. Adding a
+// format means implementing this and registering it in bodyMatchers.
+type BodyMatcher interface {
+	// Validate reports whether one assertion is well formed for the matcher
+	Validate(assertion string) error
+	// Match returns an error for the first assertion the body fails
+	Match(assertions []string, body []byte) error
+}
+
+var bodyMatchers = map[string]BodyMatcher{
+	AssertionJSON: jsonMatcher{},
+	AssertionXML:  xmlMatcher{},
+	AssertionText: textMatcher{},
+}
+
+type jsonMatcher struct{}
+
+func (jsonMatcher) Validate(assertion string) error {
+	_, err := ParseJSONAssertion(assertion)
+	return err
+}
+
+func (jsonMatcher) Match(assertions []string, body []byte) error {
+	return CheckJSONAssertions(assertions, body)
+}
+
+type xmlMatcher struct{}
+
+func (xmlMatcher) Validate(assertion string) error {
+	_, err := ParseXMLAssertion(assertion)
+	return err
+}
+
+func (xmlMatcher) Match(assertions []string, body []byte) error {
+	return CheckXMLAssertions(assertions, body)
+}
+
+type textMatcher struct{}
+
+func (textMatcher) Validate(assertion string) error {
+	_, err := ParseTextAssertion(assertion)
+	return err
+}
+
+func (textMatcher) Match(assertions []string, body []byte) error {
+	for _, s := range assertions {
+		a, err := ParseTextAssertion(s)
+		if err != nil {
+			return err
+		}
+		if err := a.Evaluate(body); err != nil {
+			return fmt.Errorf("assertion failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// textAssertionOps are checked in order so "not contains" isn't read as
+// "contains"
+var textAssertionOps = []string{"not contains ", "contains ", "matches "}
+
+// TextAssertion checks a body as plain text: "contains <text>",
+// "not contains <text>" or "matches <regex>"
+type TextAssertion struct {
+	Op    string
+	Value string
+	re    *regexp.Regexp
+}
+
+func ParseTextAssertion(s string) (*TextAssertion, error) {
+	for _, op := range textAssertionOps {
+		if !strings.HasPrefix(s, op) {
+			continue
+		}
+		a := &TextAssertion{Op: strings.TrimSpace(op), Value: s[len(op):]}
+		if a.Value == "" {
+			return nil, fmt.Errorf("invalid assertion %q: missing value", s)
+		}
+		if a.Op == "matches" {
+			re, err := regexp.Compile(a.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid assertion %q: %w", s, err)
+			}
+			a.re = re
+		}
+		return a, nil
+	}
+	return nil, fmt.Errorf("invalid assertion %q: expected contains, not contains or matches", s)
+}
+
+func (a *TextAssertion) String() string {
+	return a.Op + " " + a.Value
+}
+
+// Evaluate checks the assertion against the raw body
+func (a *TextAssertion) Evaluate(body []byte) error {
+	switch a.Op {
+	case "contains":
+		if !bytes.Contains(body, []byte(a.Value)) {
+			return fmt.Errorf("expected body to contain %q", a.Value)
+		}
+	case "not contains":
+		if bytes.Contains(body, []byte(a.Value)) {
+			return fmt.Errorf("expected body not to contain %q", a.Value)
+		}
+	case "matches":
+		if !a.re.Match(body) {
+			return fmt.Errorf("expected body to match %s", a.Value)
+		}
+	}
+	return nil
+}
+
+// AssertionTypeFor picks the matcher for a response's Content-Type: JSON and
+// XML media types (including +json and +xml suffixes) get their own, and
+// everything else is matched as text
+func AssertionTypeFor(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return AssertionText
+	}
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return AssertionJSON
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		return AssertionXML
+	}
+	return AssertionText
+}
+
+// CheckBodyAssertions evaluates assertions with the matcher assertionType
+// names, or the one the response's Content-Type calls for when it is auto
+func CheckBodyAssertions(assertionType, contentType string, assertions []string, body []byte) error {
+	if assertionType == "" || assertionType == AssertionAuto {
+		assertionType = AssertionTypeFor(contentType)
+	}
+	matcher, ok := bodyMatchers[assertionType]
+	if !ok {
+		return fmt.Errorf("unknown assertion type %q", assertionType)
+	}
+	return matcher.Match(assertions, body)
+}
+
+// ValidateBodyAssertions rejects an unknown assertion type, or assertions its
+// matcher could not evaluate. With auto the matcher isn't known until the
+// response arrives, so each assertion only has to suit one of them.
+func ValidateBodyAssertions(assertionType string, assertions []string) error {
+	if assertionType != "" && assertionType != AssertionAuto {
+		matcher, ok := bodyMatchers[assertionType]
+		if !ok {
+			return fmt.Errorf("invalid assertion type %q (use %s)", assertionType, strings.Join(AssertionTypes(), ", "))
+		}
+		for _, a := range assertions {
+			if err := matcher.Validate(a); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, a := range assertions {
+		valid := false
+		for _, matcher := range bodyMatchers {
+			if matcher.Validate(a) == nil {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("invalid assertion %q: not a JSON, XML or text assertion", a)
+		}
+	}
+	return nil
+}
+
+// AssertionTypes lists the accepted assertion types, auto first
+func AssertionTypes() []string {
+	types := make([]string, 0, len(bodyMatchers))
+	for t := range bodyMatchers {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return append([]string{AssertionAuto}, types...)
+}
@@ -0,0 +1,77 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAssertionTypeFor(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        string
+	}{
+		{"application/json", AssertionJSON},
+		{"application/problem+json; charset=utf-8", AssertionJSON},
+		{"text/xml", AssertionXML},
+		{"application/atom+xml", AssertionXML},
+		{"text/html; charset=utf-8", AssertionText},
+		{"", AssertionText},
+	}
+	for _, tt := range tests {
+		if got := AssertionTypeFor(tt.contentType); got != tt.want {
+			t.Errorf("AssertionTypeFor(%q) = %q, want %q", tt.contentType, got, tt.want)
+		}
+	}
+}
+
+func TestCheckBodyAssertionsText(t *testing.T) {
+	body := []byte("<html><h1>Ready</h1> build 1.42</html>")
+
+	if err := CheckBodyAssertions(AssertionText, "", []string{"contains Ready", "not contains Error", `matches build \d+\.\d+`}, body); err != nil {
+		t.Errorf("expected text assertions to pass, got %v", err)
+	}
+
+	err := CheckBodyAssertions(AssertionText, "", []string{"not contains Ready"}, body)
+	if err == nil || !strings.Contains(err.Error(), `not to contain "Ready"`) {
+		t.Errorf("expected forbidden text to fail, got %v", err)
+	}
+}
+
+func TestCheckBodyAssertionsAuto(t *testing.T) {
+	// The same body is read as JSON or text depending on the Content-Type
+	body := []byte(`{"status": "ok"}`)
+
+	if err := CheckBodyAssertions(AssertionAuto, "application/json", []string{"$.status == ok"}, body); err != nil {
+		t.Errorf("expected JSON matcher for application/json, got %v", err)
+	}
+	if err := CheckBodyAssertions("", "text/plain", []string{`contains "ok"`}, body); err != nil {
+		t.Errorf("expected text matcher for text/plain, got %v", err)
+	}
+	if err := CheckBodyAssertions(AssertionAuto, "text/plain", []string{"$.status == ok"}, body); err == nil {
+		t.Error("expected a JSON assertion to fail against the text matcher")
+	}
+}
+
+func TestValidateBodyAssertions(t *testing.T) {
+	tests := []struct {
+		name          string
+		assertionType string
+		assertions    []string
+		wantErr       bool
+	}{
+		{"auto mixed", AssertionAuto, []string{"$.status == ok", "/health/status == ok", "contains ok"}, false},
+		{"auto unknown", "", []string{"status is ok"}, true},
+		{"json", AssertionJSON, []string{"$.depth < 10"}, false},
+		{"json given xpath", AssertionJSON, []string{"/health/status == ok"}, true},
+		{"text bad regex", AssertionText, []string{"matches ("}, true},
+		{"unknown type", "yaml", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBodyAssertions(tt.assertionType, tt.assertions)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateBodyAssertions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package checker
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Cause categories group failures that are reported differently but mean the
+// same thing, e.g. "i/o timeout" and "context deadline exceeded"
+const (
+	CauseTimeout           = "timeout"
+	CauseConnectionRefused = "connection_refused"
+	CauseConnectionReset   = "connection_reset"
+	CauseDNS               = "dns"
+	CauseTLS               = "tls"
+	CauseHTTP              = "http"
+	CauseOther             = "other"
+)
+
+// CauseRule maps error messages containing Match (case-insensitive) to Category
+type CauseRule struct {
+	Match    string
+	Category string
+}
+
+// builtinCauseRules are tried in order after any configured rules. DNS comes
+// before timeout since a lookup that times out is still a DNS failure.
+var builtinCauseRules = []CauseRule{
+	{Match: "no such host", Category: CauseDNS},
+	{Match: "server misbehaving", Category: CauseDNS},
+	{Match: "lookup ", Category: CauseDNS},
+	{Match: "timeout", Category: CauseTimeout},
+	{Match: "deadline exceeded", Category: CauseTimeout},
+	{Match: "connection refused", Category: CauseConnectionRefused},
+	{Match: "connection reset", Category: CauseConnectionReset},
+	{Match: "broken pipe", Category: CauseConnectionReset},
+	{Match: "x509:", Category: CauseTLS},
+	{Match: "tls:", Category: CauseTLS},
+	{Match: "certificate", Category: CauseTLS},
+}
+
+var (
+	causeRulesMu sync.RWMutex
+	causeRules   []CauseRule
+)
+
+// SetCauseRules installs configured rules, which take precedence over the
+// built-in ones
+func SetCauseRules(rules []CauseRule) {
+	causeRulesMu.Lock()
+	defer causeRulesMu.Unlock()
+	causeRules = rules
+}
+
+// ClassifyCause maps a raw error message to a cause category. A failure with
+// no error but an HTTP status is an HTTP error; anything unmatched is other.
+func ClassifyCause(cause string, statusCode int) string {
+	lower := strings.ToLower(cause)
+
+	causeRulesMu.RLock()
+	configured := causeRules
+	causeRulesMu.RUnlock()
+
+	for _, rules := range [][]CauseRule{configured, builtinCauseRules} {
+		for _, rule := range rules {
+			if rule.Match != "" && strings.Contains(lower, strings.ToLower(rule.Match)) {
+				return rule.Category
+			}
+		}
+	}
+
+	if cause == "" && statusCode > 0 {
+		return CauseHTTP
+	}
+	return CauseOther
+}
+
+// RetryTransport stands for every failure that got no response at all, as a
+// retry category alongside the cause categories
+const RetryTransport = "transport"
+
+// DefaultRetryOn retries transport errors only: a clean 404 or a failed
+// assertion won't change a few seconds later
+var DefaultRetryOn = []string{RetryTransport}
+
+// Retryable reports whether a failed response is worth the one retry: its
+// cause category is in retryOn, or retryOn has RetryTransport and no response
+// came back. A nil retryOn means DefaultRetryOn.
+func Retryable(resp *CheckResponse, retryOn []string) bool {
+	if retryOn == nil {
+		retryOn = DefaultRetryOn
+	}
+	if resp.StatusCode == 0 && resp.Error != nil && slices.Contains(retryOn, RetryTransport) {
+		return true
+	}
+
+	var cause string
+	if resp.Error != nil {
+		cause = resp.Error.Error()
+	}
+	return slices.Contains(retryOn, ClassifyCause(cause, resp.StatusCode))
+}
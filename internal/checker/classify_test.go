@@ -0,0 +1,103 @@
+package checker
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClassifyCause(t *testing.T) {
+	tests := []struct {
+		cause      string
+		statusCode int
+		want       string
+	}{
+		{`Get "https://example.com": dial tcp 10.0.0.1:443: i/o timeout`, 0, CauseTimeout},
+		{`Get "https://example.com": context deadline exceeded (Client.Timeout exceeded while awaiting headers)`, 0, CauseTimeout},
+		{`Get "https://example.com": net/http: TLS handshake timeout`, 0, CauseTimeout},
+		{`Get "https://example.com": dial tcp 127.0.0.1:8080: connect: connection refused`, 0, CauseConnectionRefused},
+		{`Get "https://nope.invalid": dial tcp: lookup nope.invalid: no such host`, 0, CauseDNS},
+		{`Get "https://example.com": dial tcp: lookup example.com on 10.0.0.2:53: read udp: i/o timeout`, 0, CauseDNS},
+		{`Get "https://example.com": x509: certificate has expired or is not yet valid`, 0, CauseTLS},
+		{`certificate fingerprint ab12 does not match pinned cd34`, 0, CauseTLS},
+		{`read tcp 10.0.0.5:51234->10.0.0.1:443: read: connection reset by peer`, 0, CauseConnectionReset},
+		{"", 503, CauseHTTP},
+		{`response differs from golden snapshot at line 1`, 200, CauseOther},
+		{"", 0, CauseOther},
+	}
+
+	for _, tt := range tests {
+		if got := ClassifyCause(tt.cause, tt.statusCode); got != tt.want {
+			t.Errorf("ClassifyCause(%q, %d) = %s, want %s", tt.cause, tt.statusCode, got, tt.want)
+		}
+	}
+}
+
+func TestClassifyCauseConfiguredRules(t *testing.T) {
+	SetCauseRules([]CauseRule{{Match: "Request Blocked", Category: "waf"}, {Match: "i/o timeout", Category: "network"}})
+	defer SetCauseRules(nil)
+
+	if got := ClassifyCause("golden mismatch: expected ok, got request blocked", 200); got != "waf" {
+		t.Errorf("expected configured category waf, got %s", got)
+	}
+	// Configured rules win over the built-ins
+	if got := ClassifyCause("dial tcp 10.0.0.1:443: i/o timeout", 0); got != "network" {
+		t.Errorf("expected configured rule to take precedence, got %s", got)
+	}
+	if got := ClassifyCause("connection refused", 0); got != CauseConnectionRefused {
+		t.Errorf("expected built-in fallback, got %s", got)
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	timeout := &CheckResponse{Error: errors.New("dial tcp 10.0.0.1:443: i/o timeout")}
+	reset := &CheckResponse{Error: errors.New("read: connection reset by peer")}
+	notFound := &CheckResponse{StatusCode: 404}
+	assertion := &CheckResponse{StatusCode: 200, Error: errors.New("body does not contain \"ok\"")}
+
+	tests := []struct {
+		name    string
+		resp    *CheckResponse
+		retryOn []string
+		want    bool
+	}{
+		{"timeout by default", timeout, nil, true},
+		{"reset by default", reset, nil, true},
+		{"404 by default", notFound, nil, false},
+		{"assertion by default", assertion, nil, false},
+		{"404 when http is listed", notFound, []string{CauseHTTP}, true},
+		{"reset not listed", reset, []string{CauseTimeout}, false},
+		{"timeout listed", timeout, []string{CauseTimeout}, true},
+		{"never", timeout, []string{}, false},
+	}
+	for _, tt := range tests {
+		if got := Retryable(tt.resp, tt.retryOn); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestHTTPCheckerSkipsPermanentRetry(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	checker := NewHTTPCheckerWithRetry(time.Millisecond)
+	checker.Execute(&CheckRequest{URL: server.URL, Timeout: 5 * time.Second, ExpectedStatus: 200})
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected a 404 not to be retried, got %d requests", got)
+	}
+
+	requests.Store(0)
+	checker.RetryOn = []string{CauseHTTP}
+	checker.Execute(&CheckRequest{URL: server.URL, Timeout: 5 * time.Second, ExpectedStatus: 200})
+	if got := requests.Load(); got != 2 {
+		t.Errorf("expected a 404 to be retried once with http listed, got %d requests", got)
+	}
+}
@@ -0,0 +1,164 @@
+package checker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Fields a comparison check can compare its canary against the baseline on
+const (
+	CompareStatus  = "status"
+	CompareLatency = "latency"
+	CompareBody    = "body"
+)
+
+// DefaultCompareFields are compared when a check doesn't name any. Latency is
+// opt-in since it is the noisiest of the three.
+var DefaultCompareFields = []string{CompareStatus, CompareBody}
+
+// DefaultLatencyTolerancePct is how much slower than the baseline a canary
+// may be before latency counts as diverged
+const DefaultLatencyTolerancePct = 50
+
+// latencySlackMs keeps fast endpoints from flapping on a few milliseconds of
+// jitter: a canary within this of the baseline is never too slow
+const latencySlackMs = 25
+
+// ValidateCompareFields rejects names CompareResponses doesn't know
+func ValidateCompareFields(fields []string) error {
+	for _, field := range fields {
+		switch field {
+		case CompareStatus, CompareLatency, CompareBody:
+		default:
+			return fmt.Errorf("invalid compare field %q (use status, latency, or body)", field)
+		}
+	}
+	return nil
+}
+
+// ExecuteComparison requests the canary (req.URL) and the baseline
+// (req.BaselineURL) side by side and fails the canary's response when the two
+// diverge on the compared fields. The baseline's response rides along on
+// Baseline so both sides' metrics can be stored.
+func (h *HTTPChecker) ExecuteComparison(req *CheckRequest) *CheckResponse {
+	fields := req.CompareFields
+	if len(fields) == 0 {
+		fields = DefaultCompareFields
+	}
+	hashBodies := containsField(fields, CompareBody)
+
+	canaryReq := *req
+	canaryReq.CaptureBody = req.CaptureBody || hashBodies
+
+	// The baseline is only fetched; assertions and pins belong to the canary
+	baselineReq := &CheckRequest{
+		URL:               req.BaselineURL,
+		Timeout:           req.Timeout,
+		Method:            req.Method,
+		Headers:           req.Headers,
+		Body:              req.Body,
+		QueryParams:       req.QueryParams,
+		ExpectedStatus:    req.ExpectedStatus,
+		ExpectedStatuses:  req.ExpectedStatuses,
+		CaptureBody:       hashBodies,
+		Streaming:         req.Streaming,
+		NoFollowRedirects: req.NoFollowRedirects,
+		BypassCache:       req.BypassCache,
+		// Canary and baseline are usually signed by the same private CA
+		InsecureSkipVerify: req.InsecureSkipVerify,
+	}
+
+	var canary, baseline *CheckResponse
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		canary = h.Execute(&canaryReq)
+	}()
+	go func() {
+		defer wg.Done()
+		baseline = h.Execute(baselineReq)
+	}()
+	wg.Wait()
+
+	if hashBodies {
+		canary.BodyHash = hashBody(canary.Body)
+		baseline.BodyHash = hashBody(baseline.Body)
+	}
+	if !req.CaptureBody {
+		canary.Body = nil
+	}
+	baseline.Body = nil
+	canary.Baseline = baseline
+
+	// The canary's own failure is the more useful cause
+	if canary.Error != nil {
+		return canary
+	}
+	if baseline.Error != nil {
+		canary.Error = fmt.Errorf("baseline %s failed: %w", req.BaselineURL, baseline.Error)
+		return canary
+	}
+	if err := CompareResponses(fields, req.LatencyTolerancePct, canary, baseline); err != nil {
+		canary.Error = err
+	}
+
+	return canary
+}
+
+// CompareResponses lists every compared field on which canary diverges from
+// baseline. A canary is too slow when it exceeds the baseline's latency by
+// more than tolerancePct percent (DefaultLatencyTolerancePct when zero).
+func CompareResponses(fields []string, tolerancePct int, canary, baseline *CheckResponse) error {
+	if tolerancePct <= 0 {
+		tolerancePct = DefaultLatencyTolerancePct
+	}
+
+	var diffs []string
+	for _, field := range fields {
+		switch field {
+		case CompareStatus:
+			if canary.StatusCode != baseline.StatusCode {
+				diffs = append(diffs, fmt.Sprintf("status %d vs baseline %d", canary.StatusCode, baseline.StatusCode))
+			}
+		case CompareLatency:
+			allowed := baseline.ResponseTimeMs + baseline.ResponseTimeMs*tolerancePct/100
+			if canary.ResponseTimeMs > allowed && canary.ResponseTimeMs-baseline.ResponseTimeMs > latencySlackMs {
+				diffs = append(diffs, fmt.Sprintf("latency %dms vs baseline %dms (tolerance %d%%)", canary.ResponseTimeMs, baseline.ResponseTimeMs, tolerancePct))
+			}
+		case CompareBody:
+			if canary.BodyHash != baseline.BodyHash {
+				diffs = append(diffs, fmt.Sprintf("body %s vs baseline %s", shortHash(canary.BodyHash), shortHash(baseline.BodyHash)))
+			}
+		}
+	}
+
+	if len(diffs) > 0 {
+		return fmt.Errorf("canary diverges from baseline: %s", strings.Join(diffs, "; "))
+	}
+	return nil
+}
+
+func hashBody(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
+
+func containsField(fields []string, field string) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
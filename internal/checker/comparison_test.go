@@ -0,0 +1,133 @@
+package checker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func comparisonServer(status int, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+}
+
+func TestExecuteComparisonMatches(t *testing.T) {
+	canary := comparisonServer(http.StatusOK, `{"version":"2"}`)
+	defer canary.Close()
+	baseline := comparisonServer(http.StatusOK, `{"version":"2"}`)
+	defer baseline.Close()
+
+	resp := newTestChecker().ExecuteComparison(&CheckRequest{
+		URL:            canary.URL,
+		BaselineURL:    baseline.URL,
+		Timeout:        5 * time.Second,
+		ExpectedStatus: 200,
+	})
+
+	if resp.Error != nil {
+		t.Fatalf("expected matching responses to pass, got %v", resp.Error)
+	}
+	if resp.Baseline == nil || resp.Baseline.StatusCode != 200 {
+		t.Fatalf("expected baseline metrics on the response, got %+v", resp.Baseline)
+	}
+	if resp.BodyHash == "" || resp.BodyHash != resp.Baseline.BodyHash {
+		t.Errorf("expected equal body hashes, got %q and %q", resp.BodyHash, resp.Baseline.BodyHash)
+	}
+	if resp.Body != nil {
+		t.Error("expected body to be dropped when not requested")
+	}
+}
+
+func TestExecuteComparisonDiverges(t *testing.T) {
+	canary := comparisonServer(http.StatusOK, `{"version":"3"}`)
+	defer canary.Close()
+	baseline := comparisonServer(http.StatusOK, `{"version":"2"}`)
+	defer baseline.Close()
+
+	req := &CheckRequest{
+		URL:            canary.URL,
+		BaselineURL:    baseline.URL,
+		Timeout:        5 * time.Second,
+		ExpectedStatus: 200,
+	}
+	resp := newTestChecker().ExecuteComparison(req)
+
+	if resp.Error == nil || !strings.Contains(resp.Error.Error(), "body") {
+		t.Fatalf("expected body divergence, got %v", resp.Error)
+	}
+	if resp.IsSuccess(200) {
+		t.Error("expected diverged canary to count as down")
+	}
+
+	// Only the named fields are compared
+	req.CompareFields = []string{CompareStatus}
+	if resp := newTestChecker().ExecuteComparison(req); resp.Error != nil {
+		t.Errorf("expected status-only comparison to pass, got %v", resp.Error)
+	}
+}
+
+func TestExecuteComparisonBaselineDown(t *testing.T) {
+	canary := comparisonServer(http.StatusOK, "ok")
+	defer canary.Close()
+
+	resp := newTestChecker().ExecuteComparison(&CheckRequest{
+		URL:            canary.URL,
+		BaselineURL:    "http://127.0.0.1:1",
+		Timeout:        time.Second,
+		ExpectedStatus: 200,
+	})
+
+	if resp.Error == nil || !strings.Contains(resp.Error.Error(), "baseline") {
+		t.Errorf("expected baseline failure, got %v", resp.Error)
+	}
+}
+
+func TestCompareResponses(t *testing.T) {
+	base := &CheckResponse{StatusCode: 200, ResponseTimeMs: 200, BodyHash: "aaa"}
+	tests := []struct {
+		name      string
+		fields    []string
+		tolerance int
+		canary    *CheckResponse
+		want      string
+	}{
+		{"identical", []string{CompareStatus, CompareLatency, CompareBody}, 0, &CheckResponse{StatusCode: 200, ResponseTimeMs: 200, BodyHash: "aaa"}, ""},
+		{"status", []string{CompareStatus}, 0, &CheckResponse{StatusCode: 503}, "status 503 vs baseline 200"},
+		{"within default tolerance", []string{CompareLatency}, 0, &CheckResponse{ResponseTimeMs: 300}, ""},
+		{"beyond default tolerance", []string{CompareLatency}, 0, &CheckResponse{ResponseTimeMs: 301}, "latency 301ms vs baseline 200ms"},
+		{"custom tolerance", []string{CompareLatency}, 200, &CheckResponse{ResponseTimeMs: 550}, ""},
+		{"body", []string{CompareBody}, 0, &CheckResponse{BodyHash: "bbb"}, "body bbb vs baseline aaa"},
+	}
+
+	for _, tt := range tests {
+		err := CompareResponses(tt.fields, tt.tolerance, tt.canary, base)
+		if tt.want == "" {
+			if err != nil {
+				t.Errorf("%s: expected no divergence, got %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected %q, got %v", tt.name, tt.want, err)
+		}
+	}
+
+	// A few milliseconds of jitter on a fast endpoint is not a divergence
+	fast := &CheckResponse{ResponseTimeMs: 4}
+	if err := CompareResponses([]string{CompareLatency}, 0, &CheckResponse{ResponseTimeMs: 20}, fast); err != nil {
+		t.Errorf("expected slack for fast endpoints, got %v", err)
+	}
+}
+
+func TestValidateCompareFields(t *testing.T) {
+	if err := ValidateCompareFields([]string{"status", "latency", "body"}); err != nil {
+		t.Errorf("expected valid fields, got %v", err)
+	}
+	if err := ValidateCompareFields([]string{"headers"}); err == nil {
+		t.Error("expected error for unknown field")
+	}
+}
@@ -0,0 +1,26 @@
+package checker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+// connectivityTimeout bounds the startup probe so a blackholed network delays
+// startup by seconds, not a full check timeout plus retry
+const connectivityTimeout = 5 * time.Second
+
+// CheckConnectivity fetches url once to tell "my targets are down" apart from
+// "Sentinel can't reach anything". Any HTTP response means egress works.
+func CheckConnectivity(url string) error {
+	resp := NewHTTPCheckerWithRetry(0).Execute(&CheckRequest{
+		URL:            url,
+		Timeout:        connectivityTimeout,
+		ExpectedStatus: storage.AnyStatus,
+	})
+	if resp.Error != nil {
+		return fmt.Errorf("cannot reach %s: %w", url, resp.Error)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package checker
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckConnectivity(t *testing.T) {
+	// Any response proves egress works, even an error page
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	if err := CheckConnectivity(server.URL); err != nil {
+		t.Errorf("expected connectivity with any response, got %v", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	if err := CheckConnectivity("http://" + addr); err == nil {
+		t.Error("expected an error when nothing is reachable")
+	}
+}
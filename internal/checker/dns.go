@@ -0,0 +1,209 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DNS record types a DNS check can look up
+const (
+	RecordA     = "A"
+	RecordAAAA  = "AAAA"
+	RecordCNAME = "CNAME"
+	RecordMX    = "MX"
+	RecordNS    = "NS"
+	RecordTXT   = "TXT"
+)
+
+var recordTypes = map[string]bool{
+	RecordA: true, RecordAAAA: true, RecordCNAME: true, RecordMX: true, RecordNS: true, RecordTXT: true,
+}
+
+// DNSChecker checks that a hostname resolves, and optionally that one of its
+// records has an expected value. Resolution time is reported as the response
+// time; there is no status code, and a lookup error (NXDOMAIN included) or a
+// missing expected answer is down.
+type DNSChecker struct {
+	RetryDelay time.Duration
+	RetryOn    []string // Failure categories worth a retry; nil means DefaultRetryOn
+	// Resolver does the lookups; nil uses the system resolver
+	Resolver *net.Resolver
+}
+
+func NewDNSChecker() *DNSChecker {
+	return NewDNSCheckerWithRetry(5 * time.Second)
+}
+
+func NewDNSCheckerWithRetry(retryDelay time.Duration) *DNSChecker {
+	return &DNSChecker{RetryDelay: retryDelay}
+}
+
+func (d *DNSChecker) Execute(req *CheckRequest) *CheckResponse {
+	response := d.resolve(req)
+
+	// Same single retry as HTTP checks
+	if response.Error != nil && d.RetryDelay > 0 && Retryable(response, d.RetryOn) {
+		time.Sleep(d.RetryDelay)
+		response = d.resolve(req)
+	}
+
+	return response
+}
+
+func (d *DNSChecker) resolve(req *CheckRequest) *CheckResponse {
+	host, err := DNSHost(req.URL)
+	if err != nil {
+		return &CheckResponse{Error: err}
+	}
+	recordType := RecordTypeOrDefault(req.RecordType)
+
+	ctx, cancel := context.WithTimeout(context.Background(), req.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	answers, err := d.lookup(ctx, recordType, host)
+	response := &CheckResponse{
+		ResponseTimeMs: int(time.Since(start).Milliseconds()),
+	}
+	if err != nil {
+		response.Error = err
+		return response
+	}
+	if len(answers) == 0 {
+		response.Error = fmt.Errorf("lookup %s: no %s records", host, recordType)
+		return response
+	}
+
+	if req.ExpectedAnswer != "" {
+		response.Error = MatchAnswer(recordType, req.ExpectedAnswer, answers)
+	}
+	return response
+}
+
+// lookup returns the answers for one record type, with names lowercased and
+// without their trailing dot
+func (d *DNSChecker) lookup(ctx context.Context, recordType, host string) ([]string, error) {
+	resolver := d.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	var answers []string
+	switch recordType {
+	case RecordA, RecordAAAA:
+		network := "ip4"
+		if recordType == RecordAAAA {
+			network = "ip6"
+		}
+		ips, err := resolver.LookupIP(ctx, network, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			answers = append(answers, ip.String())
+		}
+	case RecordCNAME:
+		cname, err := resolver.LookupCNAME(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, normalizeName(cname))
+	case RecordMX:
+		mxs, err := resolver.LookupMX(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, mx := range mxs {
+			answers = append(answers, normalizeName(mx.Host))
+		}
+	case RecordNS:
+		nss, err := resolver.LookupNS(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range nss {
+			answers = append(answers, normalizeName(ns.Host))
+		}
+	case RecordTXT:
+		return resolver.LookupTXT(ctx, host)
+	default:
+		return nil, fmt.Errorf("unsupported record type %q", recordType)
+	}
+	return answers, nil
+}
+
+// MatchAnswer reports whether expected is among a lookup's answers. IPs are
+// compared as addresses and names without case or a trailing dot; TXT
+// records must match exactly.
+func MatchAnswer(recordType, expected string, answers []string) error {
+	for _, answer := range answers {
+		switch RecordTypeOrDefault(recordType) {
+		case RecordA, RecordAAAA:
+			if ip := net.ParseIP(expected); ip != nil && ip.Equal(net.ParseIP(answer)) {
+				return nil
+			}
+		case RecordTXT:
+			if answer == expected {
+				return nil
+			}
+		default:
+			if normalizeName(answer) == normalizeName(expected) {
+				return nil
+			}
+		}
+	}
+
+	sorted := append([]string(nil), answers...)
+	sort.Strings(sorted)
+	return fmt.Errorf("expected %s record %s, got %s", RecordTypeOrDefault(recordType), expected, strings.Join(sorted, ", "))
+}
+
+// RecordTypeOrDefault returns the record type a DNS check looks up: A
+// unless the check names another
+func RecordTypeOrDefault(recordType string) string {
+	if recordType == "" {
+		return RecordA
+	}
+	return strings.ToUpper(recordType)
+}
+
+// ValidateDNSExpectation rejects unknown record types, and expected answers
+// for A and AAAA records that aren't an address of that family
+func ValidateDNSExpectation(recordType, expected string) error {
+	recordType = RecordTypeOrDefault(recordType)
+	if !recordTypes[recordType] {
+		return fmt.Errorf("invalid record type %q (use A, AAAA, CNAME, MX, NS, or TXT)", recordType)
+	}
+	if expected == "" {
+		return nil
+	}
+	switch recordType {
+	case RecordA:
+		if ip := net.ParseIP(expected); ip == nil || ip.To4() == nil {
+			return fmt.Errorf("expected answer %q is not an IPv4 address", expected)
+		}
+	case RecordAAAA:
+		if ip := net.ParseIP(expected); ip == nil || ip.To4() != nil {
+			return fmt.Errorf("expected answer %q is not an IPv6 address", expected)
+		}
+	}
+	return nil
+}
+
+// DNSHost extracts the hostname from a DNS check target, written either as
+// dns://host or a bare hostname
+func DNSHost(target string) (string, error) {
+	host := strings.TrimSuffix(strings.TrimPrefix(target, "dns://"), ".")
+	if host == "" || strings.ContainsAny(host, "/: ") {
+		return "", fmt.Errorf("dns check target %q must be a hostname", target)
+	}
+	return host, nil
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
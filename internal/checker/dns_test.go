@@ -0,0 +1,103 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDNSCheckerLocalhost(t *testing.T) {
+	checker := NewDNSCheckerWithRetry(0)
+	req := &CheckRequest{Type: TypeDNS, URL: "localhost", Timeout: 2 * time.Second, ExpectedAnswer: "127.0.0.1"}
+
+	resp := checker.Execute(req)
+	if resp.Error != nil {
+		t.Fatalf("expected localhost to resolve to 127.0.0.1, got %v", resp.Error)
+	}
+	if resp.StatusCode != 0 || resp.SSLExpiresAt != nil {
+		t.Error("expected no status code or SSL info for a dns check")
+	}
+
+	req.ExpectedAnswer = "10.0.0.1"
+	resp = checker.Execute(req)
+	if resp.Error == nil || !strings.Contains(resp.Error.Error(), "expected A record 10.0.0.1") {
+		t.Errorf("expected a mismatch error, got %v", resp.Error)
+	}
+
+	req.URL = "https://localhost"
+	if resp := checker.Execute(req); resp.Error == nil {
+		t.Error("expected a URL target to be rejected")
+	}
+}
+
+func TestMatchAnswer(t *testing.T) {
+	tests := []struct {
+		recordType string
+		expected   string
+		answers    []string
+		wantErr    bool
+	}{
+		{"", "93.184.216.34", []string{"10.0.0.1", "93.184.216.34"}, false},
+		{"A", "10.0.0.2", []string{"10.0.0.1"}, true},
+		{"AAAA", "2606:2800:220:1::", []string{"2606:2800:0220:0001:0000:0000:0000:0000"}, false},
+		{"CNAME", "Edge.Example.NET.", []string{"edge.example.net"}, false},
+		{"mx", "mail.example.com", []string{"mx1.example.com"}, true},
+		{"TXT", "v=spf1 -all", []string{"v=spf1 -all"}, false},
+		{"TXT", "V=SPF1 -all", []string{"v=spf1 -all"}, true},
+	}
+
+	for _, tt := range tests {
+		err := MatchAnswer(tt.recordType, tt.expected, tt.answers)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("MatchAnswer(%q, %q, %v) error = %v, wantErr %v", tt.recordType, tt.expected, tt.answers, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateDNSExpectation(t *testing.T) {
+	tests := []struct {
+		recordType string
+		expected   string
+		wantErr    bool
+	}{
+		{"", "", false},
+		{"a", "93.184.216.34", false},
+		{"A", "2606:2800:220:1::", true},
+		{"AAAA", "2606:2800:220:1::", false},
+		{"AAAA", "93.184.216.34", true},
+		{"CNAME", "edge.example.net", false},
+		{"SRV", "", true},
+	}
+
+	for _, tt := range tests {
+		err := ValidateDNSExpectation(tt.recordType, tt.expected)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateDNSExpectation(%q, %q) error = %v, wantErr %v", tt.recordType, tt.expected, err, tt.wantErr)
+		}
+	}
+}
+
+func TestDNSHost(t *testing.T) {
+	tests := []struct {
+		target  string
+		want    string
+		wantErr bool
+	}{
+		{"example.com", "example.com", false},
+		{"dns://example.com.", "example.com", false},
+		{"example.com:53", "", true},
+		{"https://example.com", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := DNSHost(tt.target)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("DNSHost(%q) error = %v, wantErr %v", tt.target, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("DNSHost(%q) = %q, want %q", tt.target, got, tt.want)
+		}
+	}
+}
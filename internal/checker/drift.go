@@ -0,0 +1,179 @@
+package checker
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/katieblackabee/sentinel/internal/config"
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+// DriftAlerter warns when a check defined in the config file has been
+// edited away from its definition, e.g. through the UI
+type DriftAlerter interface {
+	SendDriftAlert(check *storage.Check, fields []string, corrected bool) error
+}
+
+// CheckFromConfig builds the check a config file entry defines
+func CheckFromConfig(checkCfg *config.CheckConfig) *storage.Check {
+	check := &storage.Check{
+		Name:                    checkCfg.Name,
+		URL:                     checkCfg.URL,
+		Type:                    checkCfg.Type,
+		IntervalSecs:            int(checkCfg.GetInterval().Seconds()),
+		Cron:                    checkCfg.Cron,
+		TimeoutSecs:             int(checkCfg.GetTimeout().Seconds()),
+		ExpectedStatus:          checkCfg.GetExpectedStatus(),
+		ExpectedStatuses:        checkCfg.ExpectedStatuses,
+		Enabled:                 checkCfg.IsEnabled(),
+		Tags:                    checkCfg.Tags,
+		Group:                   checkCfg.Group,
+		Description:             checkCfg.Description,
+		RunbookURL:              checkCfg.RunbookURL,
+		Streaming:               checkCfg.Streaming,
+		SampleSecs:              int(checkCfg.GetSampleInterval().Seconds()),
+		CompressResults:         checkCfg.CompressResults,
+		AlertOnFirstCheck:       checkCfg.AlertOnFirstCheck,
+		JSONAssertions:          checkCfg.JSONAssertions,
+		NoFollowRedirects:       !checkCfg.FollowsRedirects(),
+		BypassCache:             checkCfg.BypassCache,
+		DualStack:               checkCfg.DualStack,
+		InsecureSkipVerify:      checkCfg.InsecureSkipVerify,
+		RecordType:              checkCfg.RecordType,
+		ExpectedAnswer:          checkCfg.ExpectedAnswer,
+		ExpectedLocation:        checkCfg.ExpectedLocation,
+		ExpectedTrailer:         checkCfg.ExpectedTrailer,
+		MinBodyBytes:            checkCfg.MinBodyBytes,
+		ExpectedContentType:     checkCfg.ExpectedContentType,
+		ExpectedRedirects:       checkCfg.ExpectedRedirects,
+		Method:                  checkCfg.GetMethod(),
+		Headers:                 checkCfg.Headers,
+		QueryParams:             checkCfg.QueryParams,
+		RequestBody:             checkCfg.RequestBody,
+		ExpectedCertFingerprint: checkCfg.ExpectedCertFingerprint,
+		BaselineURL:             checkCfg.BaselineURL,
+		CompareFields:           checkCfg.CompareFields,
+		LatencyTolerancePct:     checkCfg.LatencyTolerancePct,
+		DegradedThresholdMs:     checkCfg.DegradedThresholdMs,
+		SlowMultiplier:          checkCfg.SlowMultiplier,
+		ConsecutiveSuccesses:    checkCfg.ConsecutiveSuccesses,
+		ConsecutiveFailures:     checkCfg.ConsecutiveFailures,
+		BodyContains:            checkCfg.BodyContains,
+		BodyNotContains:         checkCfg.BodyNotContains,
+		AlertChannels:           checkCfg.AlertChannels,
+		AlertRoutes:             checkCfg.AlertRoutes,
+		AssertionType:           checkCfg.AssertionType,
+		Assertions:              checkCfg.Assertions,
+	}
+	for _, esc := range checkCfg.Escalations {
+		check.Escalations = append(check.Escalations, storage.EscalationRule{
+			AfterSecs: int(esc.GetAfter().Seconds()),
+			Channel:   esc.Channel,
+			Severity:  esc.Severity,
+		})
+	}
+	return check
+}
+
+// CheckDrift lists the config keys where a stored check differs from its
+// definition. Both sides go through ExportCheck, so a default spelled out in
+// one and left out of the other doesn't count.
+func CheckDrift(stored *storage.Check, def *config.CheckConfig) []string {
+	have := reflect.ValueOf(ExportCheck(stored))
+	want := reflect.ValueOf(ExportCheck(CheckFromConfig(def)))
+
+	var fields []string
+	for i := 0; i < have.NumField(); i++ {
+		a, b := have.Field(i), want.Field(i)
+		// nil and empty lists or maps read the same in YAML
+		if (a.Kind() == reflect.Slice || a.Kind() == reflect.Map) && a.Len() == 0 && b.Len() == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			name, _, _ := strings.Cut(have.Type().Field(i).Tag.Get("yaml"), ",")
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+func (s *Scheduler) runDriftJob() {
+	ticker := time.NewTicker(s.config.DriftInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.doDrift()
+		case <-s.stopChan:
+			return
+		}
+	}
+}
+
+// doDrift compares each configured check with its stored copy, matched by
+// URL as at startup. A drifted check is alerted on once per distinct set of
+// changed fields and, with DriftCorrect, restored to its definition.
+func (s *Scheduler) doDrift() {
+	for i := range s.config.Drift {
+		def := &s.config.Drift[i]
+		stored, err := s.storage.GetCheckByURL(def.URL)
+		if err != nil {
+			fmt.Printf("drift scan error for %s: %v\n", def.Name, err)
+			continue
+		}
+		if stored == nil {
+			// Only startup creates configured checks, so a deleted one stays gone
+			continue
+		}
+
+		fields := CheckDrift(stored, def)
+		key := strings.Join(fields, ",")
+		if len(fields) == 0 {
+			delete(s.drifted, stored.ID)
+			continue
+		}
+
+		corrected := false
+		if s.config.DriftCorrect {
+			corrected = s.restoreCheck(stored, def)
+		}
+
+		if s.drifted[stored.ID] == key && !corrected {
+			continue
+		}
+		if corrected {
+			delete(s.drifted, stored.ID)
+		} else {
+			s.drifted[stored.ID] = key
+		}
+
+		fmt.Printf("check %s has drifted from its config: %s\n", stored.Name, strings.Join(fields, ", "))
+		if alerter, ok := s.alerter.(DriftAlerter); ok {
+			if err := alerter.SendDriftAlert(stored, fields, corrected); err != nil {
+				fmt.Printf("failed to send drift alert for %s: %v\n", stored.Name, err)
+			}
+		}
+	}
+}
+
+// restoreCheck overwrites a stored check with its definition, keeping its ID,
+// history and pause state, and reschedules it
+func (s *Scheduler) restoreCheck(stored *storage.Check, def *config.CheckConfig) bool {
+	check := CheckFromConfig(def)
+	check.ID = stored.ID
+	check.Paused = stored.Paused
+	check.PausedUntil = stored.PausedUntil
+	check.MinProbes = stored.MinProbes
+
+	if err := s.storage.UpdateCheck(check); err != nil {
+		fmt.Printf("failed to restore check %s: %v\n", stored.Name, err)
+		return false
+	}
+	if err := s.UpdateCheck(check); err != nil {
+		fmt.Printf("failed to reschedule check %s: %v\n", stored.Name, err)
+	}
+	return true
+}
@@ -0,0 +1,115 @@
+package checker
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/katieblackabee/sentinel/internal/config"
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+type mockDriftAlerter struct {
+	mockAlerter
+	drift     [][]string
+	corrected []bool
+}
+
+func (m *mockDriftAlerter) SendDriftAlert(check *storage.Check, fields []string, corrected bool) error {
+	m.drift = append(m.drift, fields)
+	m.corrected = append(m.corrected, corrected)
+	return nil
+}
+
+func TestCheckDrift(t *testing.T) {
+	def := config.CheckConfig{Name: "API", URL: "https://api.example.com", Interval: "30s", Tags: []string{"prod"}}
+
+	stored := CheckFromConfig(&def)
+	if fields := CheckDrift(stored, &def); len(fields) != 0 {
+		t.Errorf("expected a check built from its definition not to drift, got %v", fields)
+	}
+
+	// Storage hands back empty lists and maps, not nil
+	stored.Headers = map[string]string{}
+	stored.Regions = []string{}
+	if fields := CheckDrift(stored, &def); len(fields) != 0 {
+		t.Errorf("expected empty and nil to match, got %v", fields)
+	}
+
+	stored.IntervalSecs = 120
+	stored.ExpectedStatus = 204
+	stored.Enabled = false
+	if fields := CheckDrift(stored, &def); !slices.Equal(fields, []string{"interval", "expected_status", "enabled"}) {
+		t.Errorf("expected interval, expected_status and enabled to drift, got %v", fields)
+	}
+}
+
+func TestSchedulerDoDrift(t *testing.T) {
+	store, _ := setupSchedulerTest(t)
+	alerter := &mockDriftAlerter{}
+	defs := []config.CheckConfig{
+		{Name: "API", URL: "https://api.example.com", Interval: "30s"},
+		{Name: "Gone", URL: "https://gone.example.com"},
+	}
+	scheduler := NewScheduler(store, alerter, SchedulerConfig{Drift: defs})
+
+	check := CheckFromConfig(&defs[0])
+	store.CreateCheck(check)
+
+	scheduler.doDrift()
+	if len(alerter.drift) != 0 {
+		t.Fatalf("expected no drift alert for an untouched check, got %v", alerter.drift)
+	}
+
+	// An edit alerts once, not on every scan
+	check.IntervalSecs = 300
+	store.UpdateCheck(check)
+	scheduler.doDrift()
+	scheduler.doDrift()
+	if len(alerter.drift) != 1 || !slices.Equal(alerter.drift[0], []string{"interval"}) || alerter.corrected[0] {
+		t.Fatalf("expected one uncorrected interval drift alert, got %v %v", alerter.drift, alerter.corrected)
+	}
+	if got, _ := store.GetCheck(check.ID); got.IntervalSecs != 300 {
+		t.Errorf("expected warn mode to leave the edit, got %ds", got.IntervalSecs)
+	}
+
+	// A further edit is a new drift
+	check.Description = "edited"
+	store.UpdateCheck(check)
+	scheduler.doDrift()
+	if len(alerter.drift) != 2 || !slices.Equal(alerter.drift[1], []string{"interval", "description"}) {
+		t.Errorf("expected a second alert for the new field, got %v", alerter.drift)
+	}
+}
+
+func TestSchedulerDoDriftCorrects(t *testing.T) {
+	store, _ := setupSchedulerTest(t)
+	alerter := &mockDriftAlerter{}
+	defs := []config.CheckConfig{{Name: "API", URL: "https://api.example.com", Interval: "30s"}}
+	scheduler := NewScheduler(store, alerter, SchedulerConfig{Drift: defs, DriftCorrect: true})
+	defer scheduler.Stop() // The restore reschedules the check
+
+	check := CheckFromConfig(&defs[0])
+	store.CreateCheck(check)
+	store.SetCheckPaused(check.ID, true)
+	check, _ = store.GetCheck(check.ID)
+	check.Name = "Renamed"
+	store.UpdateCheck(check)
+
+	scheduler.doDrift()
+	if len(alerter.drift) != 1 || !alerter.corrected[0] {
+		t.Fatalf("expected one corrected drift alert, got %v %v", alerter.drift, alerter.corrected)
+	}
+
+	got, _ := store.GetCheck(check.ID)
+	if got.Name != "API" {
+		t.Errorf("expected the name restored, got %q", got.Name)
+	}
+	if !got.Paused {
+		t.Error("expected the pause kept through the restore")
+	}
+
+	scheduler.doDrift()
+	if len(alerter.drift) != 1 {
+		t.Errorf("expected no alert once restored, got %v", alerter.drift)
+	}
+}
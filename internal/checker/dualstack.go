@@ -0,0 +1,93 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Address families a dual-stack check runs over
+const (
+	FamilyIPv4 = "ipv4"
+	FamilyIPv6 = "ipv6"
+)
+
+// familyNetworks are the networks dials are forced onto for each family
+var familyNetworks = map[string]string{
+	FamilyIPv4: "tcp4",
+	FamilyIPv6: "tcp6",
+}
+
+// familyClients are an HTTPChecker's clients whose dials only use one family
+type familyClients struct {
+	client           *http.Client
+	noRedirectClient *http.Client
+}
+
+// newFamilyClients builds clients for each family on copies of transport,
+// so their connections are never shared with the resolver-chosen pool
+func newFamilyClients(transport *http.Transport) map[string]familyClients {
+	clients := make(map[string]familyClients, len(familyNetworks))
+	for family, network := range familyNetworks {
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+		t := transport.Clone()
+		t.DialContext = func(ctx context.Context, _, addr string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, addr)
+		}
+		client, noRedirectClient := newClients(t)
+		clients[family] = familyClients{client: client, noRedirectClient: noRedirectClient}
+	}
+	return clients
+}
+
+// executeDualStack runs req over IPv4 and IPv6 side by side and combines the
+// two: the check is only up when both families are. Each side is run through
+// execute, so both get the checker's usual retry.
+func executeDualStack(req *CheckRequest, execute func(*CheckRequest) *CheckResponse) *CheckResponse {
+	families := []string{FamilyIPv4, FamilyIPv6}
+	responses := make([]*CheckResponse, len(families))
+
+	var wg sync.WaitGroup
+	for i, family := range families {
+		sub := *req
+		sub.Family = family
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			responses[i] = execute(&sub)
+		}()
+	}
+	wg.Wait()
+
+	// The first failing family's response stands for the check, so its status
+	// code and timing are the ones stored; when both are up it's IPv4's
+	combined := *responses[0]
+	var failures []string
+	for i, family := range families {
+		resp := responses[i]
+		if resp.IsSuccess(req.ExpectedStatus, req.ExpectedStatuses) {
+			continue
+		}
+		if len(failures) == 0 {
+			combined = *resp
+		}
+		if resp.Error != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", family, resp.Error))
+		} else {
+			failures = append(failures, fmt.Sprintf("%s: unexpected status %d", family, resp.StatusCode))
+		}
+	}
+	if len(failures) > 0 {
+		combined.Error = fmt.Errorf("%s", strings.Join(failures, "; "))
+	}
+
+	combined.Families = make(map[string]*CheckResponse, len(families))
+	for i, family := range families {
+		combined.Families[family] = responses[i]
+	}
+	return &combined
+}
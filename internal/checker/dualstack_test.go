@@ -0,0 +1,150 @@
+package checker
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+func TestExecuteDualStack(t *testing.T) {
+	tests := []struct {
+		name      string
+		v4, v6    *CheckResponse
+		wantError string
+		wantCode  int
+	}{
+		{
+			name:     "both up",
+			v4:       &CheckResponse{StatusCode: 200, ResponseTimeMs: 10},
+			v6:       &CheckResponse{StatusCode: 200, ResponseTimeMs: 20},
+			wantCode: 200,
+		},
+		{
+			name:      "ipv6 unreachable",
+			v4:        &CheckResponse{StatusCode: 200},
+			v6:        &CheckResponse{Error: errors.New("network is unreachable")},
+			wantError: "ipv6: network is unreachable",
+		},
+		{
+			name:      "ipv4 bad status",
+			v4:        &CheckResponse{StatusCode: 503},
+			v6:        &CheckResponse{StatusCode: 200},
+			wantError: "ipv4: unexpected status 503",
+			wantCode:  503,
+		},
+		{
+			name:      "both down",
+			v4:        &CheckResponse{Error: errors.New("timeout")},
+			v6:        &CheckResponse{StatusCode: 502},
+			wantError: "ipv4: timeout; ipv6: unexpected status 502",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			execute := func(req *CheckRequest) *CheckResponse {
+				if req.Family == FamilyIPv4 {
+					return tt.v4
+				}
+				return tt.v6
+			}
+
+			resp := executeDualStack(&CheckRequest{ExpectedStatus: 200, DualStack: true}, execute)
+
+			if tt.wantError == "" && resp.Error != nil {
+				t.Errorf("unexpected error: %v", resp.Error)
+			}
+			if tt.wantError != "" && (resp.Error == nil || resp.Error.Error() != tt.wantError) {
+				t.Errorf("expected error %q, got %v", tt.wantError, resp.Error)
+			}
+			if resp.StatusCode != tt.wantCode {
+				t.Errorf("expected status code %d, got %d", tt.wantCode, resp.StatusCode)
+			}
+			if resp.Families[FamilyIPv4] != tt.v4 || resp.Families[FamilyIPv6] != tt.v6 {
+				t.Error("expected both families' responses to be kept")
+			}
+		})
+	}
+}
+
+func TestHTTPCheckerDualStack(t *testing.T) {
+	// Listen on IPv4 only, so the IPv6 half of a dual-stack check can't connect
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.Listener.Close()
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+
+	checker := newTestChecker()
+	req := &CheckRequest{URL: server.URL, Timeout: 5 * time.Second, ExpectedStatus: 200}
+
+	if resp := checker.Execute(req); resp.Error != nil {
+		t.Fatalf("expected plain check to pass, got %v", resp.Error)
+	}
+
+	req.Family = FamilyIPv6
+	if resp := checker.Execute(req); resp.Error == nil {
+		t.Error("expected an IPv6-only request to an IPv4 listener to fail")
+	}
+
+	req.Family = ""
+	req.DualStack = true
+	resp := checker.Execute(req)
+	if resp.Error == nil || !strings.HasPrefix(resp.Error.Error(), "ipv6: ") {
+		t.Errorf("expected the IPv6 failure to be reported, got %v", resp.Error)
+	}
+	if v4 := resp.Families[FamilyIPv4]; v4 == nil || !v4.IsSuccess(200) {
+		t.Errorf("expected IPv4 to succeed, got %+v", v4)
+	}
+
+	req.Family = "ipx"
+	if resp := checker.Execute(req); resp.Error == nil {
+		t.Error("expected an unknown family to fail")
+	}
+}
+
+func TestProcessResultDualStack(t *testing.T) {
+	store := setupTestStorage(t)
+
+	check := &storage.Check{Name: "Dual", URL: "https://test.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true, DualStack: true}
+	if err := store.CreateCheck(check); err != nil {
+		t.Fatalf("failed to create check: %v", err)
+	}
+
+	response := executeDualStack(&CheckRequest{ExpectedStatus: 200}, func(req *CheckRequest) *CheckResponse {
+		if req.Family == FamilyIPv6 {
+			return &CheckResponse{Error: errors.New("connection refused")}
+		}
+		return &CheckResponse{StatusCode: 200}
+	})
+	if err := ProcessResult(store, nil, check, response, 2); err != nil {
+		t.Fatalf("ProcessResult failed: %v", err)
+	}
+
+	results, err := store.GetResultDetails(check.ID, 1)
+	if err != nil || len(results) != 1 {
+		t.Fatalf("expected one result, got %d (%v)", len(results), err)
+	}
+	result := results[0]
+	if result.Status != "down" {
+		t.Errorf("expected down, got %s", result.Status)
+	}
+	if result.IPv4Status != "up" || result.IPv6Status != "down" {
+		t.Errorf("expected ipv4 up and ipv6 down, got %q and %q", result.IPv4Status, result.IPv6Status)
+	}
+	if result.ErrorMessage != "ipv6: connection refused" {
+		t.Errorf("expected the failing family in the error, got %q", result.ErrorMessage)
+	}
+}
@@ -0,0 +1,210 @@
+package checker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+// Escalator re-alerts on incidents left open and unacknowledged. level
+// indexes the check's escalation rules; each level is sent at most once per
+// incident.
+type Escalator interface {
+	SendEscalationAlert(check *storage.Check, incident *storage.Incident, level int) error
+}
+
+// Reminder repeats the down alert for an incident still open; the alerter
+// decides which channels are due one
+type Reminder interface {
+	SendReminderAlert(check *storage.Check, incident *storage.Incident) error
+}
+
+// escalationScanInterval is how often open incidents are checked for due
+// escalations, which bounds how late one can fire
+const escalationScanInterval = time.Minute
+
+// escalationSeverities are the severities alert targets filter on
+var escalationSeverities = map[string]bool{"critical": true, "warning": true, "info": true}
+
+// alertProviders are the channel kinds a check can route alerts to
+var alertProviders = map[string]bool{"email": true, "slack": true, "discord": true, "webhook": true, "sms": true}
+
+// ValidateAlertChannels rejects routes naming an unknown provider. A route is
+// a provider ("slack") or a single target of one ("slack:oncall").
+func ValidateAlertChannels(channels []string) error {
+	for _, channel := range channels {
+		provider, _, _ := strings.Cut(channel, ":")
+		if !alertProviders[provider] {
+			return fmt.Errorf("invalid alert channel %q (use email, slack, discord, webhook, or sms)", channel)
+		}
+	}
+	return nil
+}
+
+// alertTypes are the alerts a check can route to their own channels
+var alertTypes = map[string]bool{"down": true, "recovery": true, "ssl_expiry": true, "stale": true, "escalation": true, "drift": true, "slow": true}
+
+// ValidateAlertRoutes rejects routes for unknown alert types, and routes
+// without channels or naming an unknown provider
+func ValidateAlertRoutes(routes map[string][]string) error {
+	types := make([]string, 0, len(routes))
+	for alertType := range routes {
+		types = append(types, alertType)
+	}
+	sort.Strings(types)
+
+	for _, alertType := range types {
+		if !alertTypes[alertType] {
+			return fmt.Errorf("invalid alert type %q (use down, recovery, ssl_expiry, stale, escalation, drift, or slow)", alertType)
+		}
+		if len(routes[alertType]) == 0 {
+			return fmt.Errorf("%s alerts must be routed to at least one channel", alertType)
+		}
+		if err := ValidateAlertChannels(routes[alertType]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseAlertRoute reads a route written as "<type> <channel>...", e.g.
+// "recovery slack" or "down slack:oncall email"
+func ParseAlertRoute(line string) (string, []string, error) {
+	fields := strings.Fields(strings.ReplaceAll(line, ",", " "))
+	if len(fields) < 2 {
+		return "", nil, fmt.Errorf("alert route %q must be \"<type> <channel>...\"", line)
+	}
+	return fields[0], fields[1:], ValidateAlertRoutes(map[string][]string{fields[0]: fields[1:]})
+}
+
+// ParseEscalation reads a rule written as "<after> [channel] [severity]",
+// e.g. "1h slack:oncall critical". A token naming a severity is the
+// severity; any other is the channel.
+func ParseEscalation(line string) (storage.EscalationRule, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || len(fields) > 3 {
+		return storage.EscalationRule{}, fmt.Errorf("escalation %q must be \"<after> [channel] [severity]\"", line)
+	}
+
+	after, err := time.ParseDuration(fields[0])
+	if err != nil {
+		return storage.EscalationRule{}, fmt.Errorf("escalation %q: invalid duration %q", line, fields[0])
+	}
+
+	rule := storage.EscalationRule{AfterSecs: int(after.Seconds())}
+	for _, field := range fields[1:] {
+		if escalationSeverities[field] {
+			rule.Severity = field
+		} else {
+			rule.Channel = field
+		}
+	}
+	return rule, ValidateEscalations([]storage.EscalationRule{rule})
+}
+
+// ValidateEscalations rejects rules that could never fire or route
+func ValidateEscalations(rules []storage.EscalationRule) error {
+	for _, rule := range rules {
+		if rule.AfterSecs < 60 {
+			return fmt.Errorf("escalation after %ds must be at least a minute", rule.AfterSecs)
+		}
+		if rule.Severity != "" && !escalationSeverities[rule.Severity] {
+			return fmt.Errorf("invalid escalation severity %q (use critical, warning, or info)", rule.Severity)
+		}
+		if rule.Channel != "" {
+			if err := ValidateAlertChannels([]string{rule.Channel}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Scheduler) runEscalationJob() {
+	ticker := time.NewTicker(escalationScanInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.doEscalations(time.Now())
+			s.doReminders(time.Now())
+		case <-s.stopChan:
+			return
+		}
+	}
+}
+
+// doEscalations sends every escalation that is due on an open incident
+// nobody has acknowledged. The escalator skips levels already sent.
+func (s *Scheduler) doEscalations(now time.Time) {
+	escalator, ok := s.alerter.(Escalator)
+	if !ok {
+		return
+	}
+
+	incidents, err := s.storage.ListActiveIncidents()
+	if err != nil {
+		fmt.Printf("escalation scan error: %v\n", err)
+		return
+	}
+
+	for _, incident := range incidents {
+		if incident.IsAcknowledged() || incident.IsResolved() {
+			continue
+		}
+
+		check, err := s.storage.GetCheck(incident.CheckID)
+		if err != nil || check == nil || len(check.Escalations) == 0 {
+			continue
+		}
+
+		open := now.Sub(incident.StartedAt)
+		for level, rule := range check.Escalations {
+			if open < time.Duration(rule.AfterSecs)*time.Second {
+				continue
+			}
+			if err := escalator.SendEscalationAlert(check, incident, level); err != nil {
+				fmt.Printf("failed to send escalation alert for %s: %v\n", check.Name, err)
+			}
+		}
+	}
+}
+
+// doReminders re-alerts on every open incident nobody has acknowledged, for
+// checks still running and outside maintenance
+func (s *Scheduler) doReminders(now time.Time) {
+	reminder, ok := s.alerter.(Reminder)
+	if !ok {
+		return
+	}
+
+	incidents, err := s.storage.ListActiveIncidents()
+	if err != nil {
+		fmt.Printf("reminder scan error: %v\n", err)
+		return
+	}
+	active, err := s.storage.ListActiveMaintenanceWindows(now)
+	if err != nil {
+		fmt.Printf("reminder scan error: %v\n", err)
+		return
+	}
+
+	for _, incident := range incidents {
+		if incident.IsAcknowledged() || incident.IsResolved() || storage.InMaintenance(active, incident.CheckID) {
+			continue
+		}
+
+		check, err := s.storage.GetCheck(incident.CheckID)
+		if err != nil || check == nil || !check.Enabled || check.IsPaused(now) {
+			continue
+		}
+
+		if err := reminder.SendReminderAlert(check, incident); err != nil {
+			fmt.Printf("failed to send reminder for %s: %v\n", check.Name, err)
+		}
+	}
+}
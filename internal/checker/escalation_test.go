@@ -0,0 +1,160 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+type mockEscalator struct {
+	mockAlerter
+	levels []int
+}
+
+func (m *mockEscalator) SendEscalationAlert(check *storage.Check, incident *storage.Incident, level int) error {
+	m.levels = append(m.levels, level)
+	return nil
+}
+
+type mockReminder struct {
+	mockAlerter
+	reminded []int64
+}
+
+func (m *mockReminder) SendReminderAlert(check *storage.Check, incident *storage.Incident) error {
+	m.reminded = append(m.reminded, incident.ID)
+	return nil
+}
+
+func TestParseEscalation(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    storage.EscalationRule
+		wantErr bool
+	}{
+		{line: "1h", want: storage.EscalationRule{AfterSecs: 3600}},
+		{line: "30m slack:oncall", want: storage.EscalationRule{AfterSecs: 1800, Channel: "slack:oncall"}},
+		{line: "2h email warning", want: storage.EscalationRule{AfterSecs: 7200, Channel: "email", Severity: "warning"}},
+		{line: "1h critical", want: storage.EscalationRule{AfterSecs: 3600, Severity: "critical"}},
+		{line: "soon", wantErr: true},
+		{line: "30s", wantErr: true},
+		{line: "1h email critical extra", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseEscalation(tt.line)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseEscalation(%q) expected error", tt.line)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseEscalation(%q) unexpected error: %v", tt.line, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseEscalation(%q) = %+v, want %+v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestParseAlertRoute(t *testing.T) {
+	tests := []struct {
+		line     string
+		wantType string
+		want     string
+		wantErr  bool
+	}{
+		{line: "recovery slack", wantType: "recovery", want: "slack"},
+		{line: "down slack:oncall, email", wantType: "down", want: "slack:oncall email"},
+		{line: "recovery", wantErr: true},
+		{line: "degraded slack", wantErr: true},
+		{line: "down pager", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		alertType, channels, err := ParseAlertRoute(tt.line)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseAlertRoute(%q) expected error", tt.line)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseAlertRoute(%q) unexpected error: %v", tt.line, err)
+			continue
+		}
+		if alertType != tt.wantType || strings.Join(channels, " ") != tt.want {
+			t.Errorf("ParseAlertRoute(%q) = %s %v, want %s %s", tt.line, alertType, channels, tt.wantType, tt.want)
+		}
+	}
+}
+
+func TestSchedulerDoEscalations(t *testing.T) {
+	store, _ := setupSchedulerTest(t)
+	escalator := &mockEscalator{}
+	scheduler := NewScheduler(store, escalator, SchedulerConfig{})
+
+	check := &storage.Check{
+		Name:           "Escalating",
+		URL:            "https://test.com",
+		IntervalSecs:   60,
+		TimeoutSecs:    10,
+		ExpectedStatus: 200,
+		Enabled:        true,
+		Escalations: []storage.EscalationRule{
+			{AfterSecs: 3600, Channel: "slack:oncall"},
+			{AfterSecs: 3 * 3600},
+		},
+	}
+	store.CreateCheck(check)
+
+	now := time.Now()
+	incident := &storage.Incident{CheckID: check.ID, StartedAt: now.Add(-2 * time.Hour)}
+	store.CreateIncident(incident)
+
+	// Two hours open: only the first level is due
+	scheduler.doEscalations(now)
+	if len(escalator.levels) != 1 || escalator.levels[0] != 0 {
+		t.Fatalf("expected level 0 escalated, got %v", escalator.levels)
+	}
+
+	// Acknowledged incidents stop escalating
+	store.UpdateIncidentStatus(incident.ID, storage.IncidentStatusIdentified)
+	scheduler.doEscalations(now.Add(2 * time.Hour))
+	if len(escalator.levels) != 1 {
+		t.Errorf("expected no escalation once acknowledged, got %v", escalator.levels)
+	}
+}
+
+func TestSchedulerDoReminders(t *testing.T) {
+	store, _ := setupSchedulerTest(t)
+	reminder := &mockReminder{}
+	scheduler := NewScheduler(store, reminder, SchedulerConfig{})
+
+	down := &storage.Check{Name: "Down", URL: "https://down.test.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	paused := &storage.Check{Name: "Paused", URL: "https://paused.test.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true, Paused: true}
+	store.CreateCheck(down)
+	store.CreateCheck(paused)
+
+	now := time.Now()
+	incident := &storage.Incident{CheckID: down.ID, StartedAt: now.Add(-time.Hour)}
+	store.CreateIncident(incident)
+	store.CreateIncident(&storage.Incident{CheckID: paused.ID, StartedAt: now.Add(-time.Hour)})
+
+	// Paused checks don't remind
+	scheduler.doReminders(now)
+	if len(reminder.reminded) != 1 || reminder.reminded[0] != incident.ID {
+		t.Fatalf("expected a reminder for the down check only, got %v", reminder.reminded)
+	}
+
+	// Nor do acknowledged incidents
+	store.UpdateIncidentStatus(incident.ID, storage.IncidentStatusIdentified)
+	scheduler.doReminders(now)
+	if len(reminder.reminded) != 1 {
+		t.Errorf("expected no reminder once acknowledged, got %v", reminder.reminded)
+	}
+}
@@ -0,0 +1,111 @@
+package checker
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/katieblackabee/sentinel/internal/config"
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+// CheckExport is the exported file: a config with only its checks section,
+// so it can be loaded or included as it is
+type CheckExport struct {
+	Checks []config.CheckConfig `yaml:"checks"`
+}
+
+// ExportCheck renders a stored check in the config file's shape, leaving out
+// settings at their defaults
+func ExportCheck(check *storage.Check) config.CheckConfig {
+	cfg := config.CheckConfig{
+		Name:                    check.Name,
+		URL:                     check.URL,
+		Interval:                seconds(check.IntervalSecs),
+		Cron:                    check.Cron,
+		Timeout:                 seconds(check.TimeoutSecs),
+		ExpectedStatuses:        check.ExpectedStatuses,
+		Tags:                    check.Tags,
+		Group:                   check.Group,
+		Regions:                 check.Regions,
+		Description:             check.Description,
+		RunbookURL:              check.RunbookURL,
+		Streaming:               check.Streaming,
+		SampleInterval:          seconds(check.SampleSecs),
+		CompressResults:         check.CompressResults,
+		AlertOnFirstCheck:       check.AlertOnFirstCheck,
+		JSONAssertions:          check.JSONAssertions,
+		BypassCache:             check.BypassCache,
+		DualStack:               check.DualStack,
+		InsecureSkipVerify:      check.InsecureSkipVerify,
+		RecordType:              check.RecordType,
+		ExpectedAnswer:          check.ExpectedAnswer,
+		ExpectedLocation:        check.ExpectedLocation,
+		ExpectedTrailer:         check.ExpectedTrailer,
+		MinBodyBytes:            check.MinBodyBytes,
+		ExpectedContentType:     check.ExpectedContentType,
+		ExpectedRedirects:       check.ExpectedRedirects,
+		Method:                  check.Method,
+		RequestBody:             check.RequestBody,
+		ExpectedCertFingerprint: check.ExpectedCertFingerprint,
+		BaselineURL:             check.BaselineURL,
+		CompareFields:           check.CompareFields,
+		LatencyTolerancePct:     check.LatencyTolerancePct,
+		DegradedThresholdMs:     check.DegradedThresholdMs,
+		SlowMultiplier:          check.SlowMultiplier,
+		ConsecutiveSuccesses:    check.ConsecutiveSuccesses,
+		ConsecutiveFailures:     check.ConsecutiveFailures,
+		BodyContains:            check.BodyContains,
+		BodyNotContains:         check.BodyNotContains,
+		AlertChannels:           check.AlertChannels,
+		AlertRoutes:             check.AlertRoutes,
+		Headers:                 check.Headers,
+		QueryParams:             check.QueryParams,
+		AssertionType:           check.AssertionType,
+		Assertions:              check.Assertions,
+	}
+	if check.Type != "" && check.Type != "http" {
+		cfg.Type = check.Type
+	}
+	// A status spec wins over the single code, so only one is written
+	if check.ExpectedStatuses == "" {
+		cfg.ExpectedStatus = check.ExpectedStatus
+	}
+	if !check.Enabled {
+		cfg.Enabled = new(bool)
+	}
+	if check.NoFollowRedirects {
+		cfg.FollowRedirects = new(bool)
+	}
+	for _, rule := range check.Escalations {
+		cfg.Escalations = append(cfg.Escalations, config.EscalationConfig{
+			After:    seconds(rule.AfterSecs),
+			Channel:  rule.Channel,
+			Severity: rule.Severity,
+		})
+	}
+	return cfg
+}
+
+// ExportChecks renders checks as a YAML config file
+func ExportChecks(checks []*storage.Check) ([]byte, error) {
+	export := CheckExport{Checks: make([]config.CheckConfig, len(checks))}
+	for i, check := range checks {
+		export.Checks[i] = ExportCheck(check)
+	}
+
+	data, err := yaml.Marshal(export)
+	if err != nil {
+		return nil, fmt.Errorf("exporting checks: %w", err)
+	}
+	return data, nil
+}
+
+// seconds writes a duration in seconds as the config does, e.g. "60s";
+// zero is left out
+func seconds(secs int) string {
+	if secs == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%ds", secs)
+}
@@ -0,0 +1,60 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/katieblackabee/sentinel/internal/config"
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+func TestExportChecks(t *testing.T) {
+	checks := []*storage.Check{
+		{
+			Name: "API", URL: "https://api.example.com", IntervalSecs: 60, TimeoutSecs: 5, ExpectedStatus: 200,
+			Enabled: true, Tags: []string{"prod", "api"},
+			Escalations: []storage.EscalationRule{{AfterSecs: 3600, Channel: "slack:oncall"}},
+		},
+		{
+			Name: "Legacy", URL: "https://legacy.example.com", IntervalSecs: 300, TimeoutSecs: 10,
+			ExpectedStatuses: "2xx,301", NoFollowRedirects: true, Type: "http",
+		},
+	}
+
+	data, err := ExportChecks(checks)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := string(data)
+	for _, want := range []string{"interval: 60s", "timeout: 5s", "- prod", "after: 3600s", "expected_statuses: 2xx,301"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in export:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "body_contains") || strings.Contains(out, "type:") {
+		t.Errorf("expected defaults to be left out:\n%s", out)
+	}
+
+	// The export loads back as a config with the same checks
+	path := filepath.Join(t.TempDir(), "checks.yaml")
+	os.WriteFile(path, data, 0644)
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("failed to load export: %v", err)
+	}
+	if len(cfg.Checks) != 2 {
+		t.Fatalf("expected 2 checks, got %d", len(cfg.Checks))
+	}
+	api, legacy := cfg.Checks[0], cfg.Checks[1]
+	if api.GetInterval().Seconds() != 60 || api.GetExpectedStatus() != 200 || !api.IsEnabled() || len(api.Tags) != 2 {
+		t.Errorf("expected API's settings back, got %+v", api)
+	}
+	if api.Escalations[0].GetAfter().Hours() != 1 || api.Escalations[0].Channel != "slack:oncall" {
+		t.Errorf("expected the escalation back, got %+v", api.Escalations)
+	}
+	if legacy.IsEnabled() || legacy.FollowsRedirects() || legacy.ExpectedStatuses != "2xx,301" {
+		t.Errorf("expected Legacy disabled, not following redirects, with its status spec, got %+v", legacy)
+	}
+}
@@ -0,0 +1,45 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CompareGolden diffs a response body against a recorded golden snapshot and
+// returns an error describing the first line that differs.
+func CompareGolden(golden string, body []byte) error {
+	expected := splitLines(golden)
+	actual := splitLines(string(body))
+
+	for i := 0; i < len(expected) || i < len(actual); i++ {
+		var want, got string
+		if i < len(expected) {
+			want = expected[i]
+		}
+		if i < len(actual) {
+			got = actual[i]
+		}
+
+		if i >= len(actual) {
+			return fmt.Errorf("response differs from golden snapshot at line %d: missing line %q", i+1, want)
+		}
+		if i >= len(expected) {
+			return fmt.Errorf("response differs from golden snapshot at line %d: unexpected line %q", i+1, got)
+		}
+		if want != got {
+			return fmt.Errorf("response differs from golden snapshot at line %d: expected %q, got %q", i+1, want, got)
+		}
+	}
+
+	return nil
+}
+
+// splitLines splits on newlines, ignoring CRLF differences and a trailing newline
+func splitLines(s string) []string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
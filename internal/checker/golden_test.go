@@ -0,0 +1,42 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompareGoldenMatch(t *testing.T) {
+	golden := "line one\nline two\n"
+
+	if err := CompareGolden(golden, []byte("line one\nline two\n")); err != nil {
+		t.Errorf("expected match, got %v", err)
+	}
+	if err := CompareGolden(golden, []byte("line one\r\nline two")); err != nil {
+		t.Errorf("expected CRLF and trailing newline to be ignored, got %v", err)
+	}
+}
+
+func TestCompareGoldenReportsFirstDifference(t *testing.T) {
+	tests := []struct {
+		name    string
+		golden  string
+		body    string
+		wantErr string
+	}{
+		{"changed line", "a\nb\nc", "a\nx\nc", "line 2: expected \"b\", got \"x\""},
+		{"missing line", "a\nb", "a", "line 2: missing line \"b\""},
+		{"extra line", "a", "a\nb", "line 2: unexpected line \"b\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CompareGolden(tt.golden, []byte(tt.body))
+			if err == nil {
+				t.Fatal("expected difference to be reported")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error to contain %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
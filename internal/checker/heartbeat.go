@@ -0,0 +1,52 @@
+package checker
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+// heartbeatSweep caps how long a missed heartbeat can go unnoticed, however
+// long the check's interval
+const heartbeatSweep = time.Minute
+
+// heartbeatDeadline is when a heartbeat check goes down without a push: its
+// interval plus its timeout as grace, counted from the last result. Pushes
+// that would have landed while Sentinel was stopped couldn't, so the count
+// starts no earlier than startedAt, nor than the check's last edit.
+func heartbeatDeadline(check *storage.Check, lastResult *storage.CheckResult, startedAt time.Time) time.Time {
+	since := startedAt
+	if check.UpdatedAt.After(since) {
+		since = check.UpdatedAt
+	}
+	if lastResult != nil && lastResult.CheckedAt.After(since) {
+		since = lastResult.CheckedAt
+	}
+	grace := CheckTimeout(TypeHeartbeat, check.TimeoutSecs)
+	return since.Add(time.Duration(check.IntervalSecs)*time.Second + grace)
+}
+
+// sweepHeartbeat marks a heartbeat check down once its deadline passes. One
+// missed deadline is already past the grace period, so it alerts without
+// waiting for consecutive failures, even for a job never heard from. Nothing
+// more is stored while it stays down; the next pushed up result recovers it.
+func (s *Scheduler) sweepHeartbeat(check *storage.Check, lastResult *storage.CheckResult, now time.Time) {
+	if check.Status == "down" {
+		return
+	}
+	if now.Before(heartbeatDeadline(check, lastResult, s.startedAt)) {
+		return
+	}
+
+	msg := "no heartbeat received since Sentinel started"
+	if lastResult != nil {
+		msg = fmt.Sprintf("no heartbeat received since %s", lastResult.CheckedAt.Format(time.RFC3339))
+	}
+	check.AlertOnFirstCheck = true
+	response := &CheckResponse{Error: errors.New(msg)}
+	if err := ProcessResultWithOptions(s.storage, s.alerter, check, response, 1, "", 0, s.events); err != nil {
+		fmt.Printf("error processing missed heartbeat for %s: %v\n", check.Name, err)
+	}
+}
@@ -0,0 +1,118 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+func TestHeartbeatDeadline(t *testing.T) {
+	started := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	check := &storage.Check{Type: TypeHeartbeat, IntervalSecs: 3600, TimeoutSecs: 300}
+
+	// Never heard from: counted from startup
+	if got, want := heartbeatDeadline(check, nil, started), started.Add(65*time.Minute); !got.Equal(want) {
+		t.Errorf("expected deadline %v, got %v", want, got)
+	}
+
+	// Counted from the last push once there is one
+	last := &storage.CheckResult{CheckedAt: started.Add(30 * time.Minute)}
+	if got, want := heartbeatDeadline(check, last, started), started.Add(95*time.Minute); !got.Equal(want) {
+		t.Errorf("expected deadline %v, got %v", want, got)
+	}
+
+	// An edit restarts the count
+	check.UpdatedAt = started.Add(time.Hour)
+	if got, want := heartbeatDeadline(check, last, started), started.Add(125*time.Minute); !got.Equal(want) {
+		t.Errorf("expected deadline %v, got %v", want, got)
+	}
+}
+
+func TestSchedulerSweepHeartbeat(t *testing.T) {
+	store := setupTestStorage(t)
+	alerter := &mockAlerter{}
+	scheduler := NewScheduler(store, alerter, SchedulerConfig{ConsecutiveFailures: 3})
+
+	check := &storage.Check{Name: "Nightly Backup", Type: TypeHeartbeat, URL: "nightly-backup", IntervalSecs: 60, TimeoutSecs: 30, Enabled: true}
+	if err := store.CreateCheck(check); err != nil {
+		t.Fatalf("failed to create check: %v", err)
+	}
+	check, _ = store.GetCheck(check.ID)
+
+	if _, err := scheduler.PushResult(check, &PushedResult{Status: "up"}); err != nil {
+		t.Fatalf("failed to push result: %v", err)
+	}
+	last, _ := store.GetLatestResult(check.ID)
+	check.Status = "up"
+
+	// Within interval plus grace nothing happens
+	scheduler.sweepHeartbeat(check, last, last.CheckedAt.Add(80*time.Second))
+	if latest, _ := store.GetLatestResult(check.ID); latest.ID != last.ID {
+		t.Fatalf("expected no result before the deadline, got %+v", latest)
+	}
+
+	// Past it the check goes down at once, despite the failure threshold
+	scheduler.sweepHeartbeat(check, last, last.CheckedAt.Add(91*time.Second))
+	latest, _ := store.GetLatestResult(check.ID)
+	if latest.Status != "down" {
+		t.Fatalf("expected a missed heartbeat to be down, got %+v", latest)
+	}
+	if incident, _ := store.GetActiveIncident(check.ID); incident == nil {
+		t.Fatal("expected a missed heartbeat to open an incident")
+	}
+	if alerter.downAlerts != 1 {
+		t.Errorf("expected 1 down alert, got %d", alerter.downAlerts)
+	}
+
+	// Nothing more is stored while down
+	check.Status = "down"
+	scheduler.sweepHeartbeat(check, latest, latest.CheckedAt.Add(time.Hour))
+	if again, _ := store.GetLatestResult(check.ID); again.ID != latest.ID {
+		t.Error("expected no further results while down")
+	}
+
+	// The next push recovers it
+	check, _ = store.GetCheck(check.ID)
+	if _, err := scheduler.PushResult(check, &PushedResult{Status: "up"}); err != nil {
+		t.Fatalf("failed to push result: %v", err)
+	}
+	if incident, _ := store.GetActiveIncident(check.ID); incident != nil {
+		t.Error("expected the push to close the incident")
+	}
+	if alerter.recoveryAlerts != 1 {
+		t.Errorf("expected 1 recovery alert, got %d", alerter.recoveryAlerts)
+	}
+}
+
+func TestSchedulerSweepHeartbeatNeverHeardFrom(t *testing.T) {
+	store := setupTestStorage(t)
+	alerter := &mockAlerter{}
+	scheduler := NewScheduler(store, alerter, SchedulerConfig{})
+
+	check := &storage.Check{Name: "Worker", Type: TypeHeartbeat, URL: "worker", IntervalSecs: 60, TimeoutSecs: 10, Enabled: true}
+	store.CreateCheck(check)
+	check, _ = store.GetCheck(check.ID)
+	check.Status = "pending"
+
+	scheduler.sweepHeartbeat(check, nil, time.Now().Add(2*time.Minute))
+	latest, _ := store.GetLatestResult(check.ID)
+	if latest == nil || latest.Status != "down" || latest.ErrorMessage != "no heartbeat received since Sentinel started" {
+		t.Fatalf("expected a down result for a job never heard from, got %+v", latest)
+	}
+	if alerter.downAlerts != 1 {
+		t.Errorf("expected the first miss to alert, got %d alerts", alerter.downAlerts)
+	}
+}
+
+func TestSchedulerTriggerHeartbeat(t *testing.T) {
+	store := setupTestStorage(t)
+	scheduler := NewScheduler(store, nil, SchedulerConfig{})
+
+	check := &storage.Check{Name: "Cron", Type: TypeHeartbeat, URL: "cron", IntervalSecs: 60, TimeoutSecs: 10, Enabled: true}
+	store.CreateCheck(check)
+
+	if _, err := scheduler.TriggerCheck(check.ID); err == nil {
+		t.Error("expected triggering a heartbeat check to be refused")
+	}
+}
@@ -1,21 +1,130 @@
 package checker
 
 import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
 )
 
+// maxBodyBytes bounds how much of a response body is read for comparisons
+const maxBodyBytes = 1 << 20
+
+// streamPeekBytes bounds the initial read from a streaming response
+const streamPeekBytes = 4 << 10
+
+// cacheBustParam carries a unique value on cache-bypassing requests so no
+// cache has seen the URL before
+const cacheBustParam = "_sentinel"
+
 type HTTPChecker struct {
-	client     *http.Client
+	httpClients
+	// insecure holds the same clients on a transport that skips certificate
+	// verification, for checks that opt out of it
+	insecure   httpClients
 	RetryDelay time.Duration
+	// RetryOn lists the failure categories worth a retry; nil means
+	// DefaultRetryOn
+	RetryOn []string
 }
 
 type CheckRequest struct {
+	// Type picks the checker: TypeHTTP (default), TypeTCP, whose URL is
+	// host:port, TypeDNS, whose URL is a hostname, or TypePing, whose URL is
+	// a hostname or IP address
+	Type           string
 	URL            string
 	Timeout        time.Duration
 	ExpectedStatus int
+	// ExpectedStatuses, when set, replaces ExpectedStatus with a spec like
+	// "200,204" or "2xx"
+	ExpectedStatuses string
+	// GoldenBody, when set, is compared line by line against the response body
+	GoldenBody string
+	// JSONAssertions are evaluated against the parsed body, e.g. "$.queue_depth < 10000"
+	JSONAssertions []string
+	// CaptureBody keeps the (bounded) response body on the CheckResponse
+	CaptureBody bool
+	// Streaming endpoints never finish their body, so only the status and a
+	// single bounded read are checked before the connection is closed
+	Streaming bool
+	// NoFollowRedirects returns the first redirect as the final response
+	NoFollowRedirects bool
+	// BypassCache asks caches and CDNs to go to the origin, with no-cache
+	// headers and a cache-busting query parameter
+	BypassCache bool
+	// ExpectedLocation is matched against a redirect's Location header:
+	// exactly, or as a regex when wrapped in slashes like /^https:/
+	ExpectedLocation string
+	// ExpectedCertFingerprint pins the leaf certificate's SHA-256 fingerprint
+	ExpectedCertFingerprint string
+	// InsecureSkipVerify accepts any certificate, for internal endpoints
+	// signed by a private CA. Expiry is still reported, and a pinned
+	// fingerprint still checked.
+	InsecureSkipVerify bool
+	// BaselineURL makes this a comparison: URL is the canary and fails when
+	// it diverges from the baseline on CompareFields
+	BaselineURL         string
+	CompareFields       []string
+	LatencyTolerancePct int
+	// BodyContains and BodyNotContains are plain substrings the body must and
+	// must not contain, for apps that serve error pages with a 200
+	BodyContains    string
+	BodyNotContains string
+	// Assertions are evaluated against the body by the matcher AssertionType
+	// names (json, xml or text), or by one picked from the Content-Type
+	AssertionType string
+	Assertions    []string
+	// DualStack runs the check over IPv4 and IPv6 separately and fails it
+	// unless both succeed
+	DualStack bool
+	// Family forces connections onto one address family (FamilyIPv4 or
+	// FamilyIPv6); empty leaves the choice to the resolver
+	Family string
+	// RecordType is the record a DNS check looks up (default A), and
+	// ExpectedAnswer a value that must be among the answers
+	RecordType     string
+	ExpectedAnswer string
+	// ExpectedTrailer, as "Name: value", must be among the trailers sent
+	// after the body; a bare "Name" only requires the trailer to be present.
+	// The body is read to its end (up to maxBodyBytes) to get them.
+	ExpectedTrailer string
+	// MinBodyBytes is the shortest body, once decoded, that passes, and
+	// ExpectedContentType the media type the Content-Type header must name;
+	// parameters such as charset are ignored. Streaming checks, whose body
+	// never ends, skip MinBodyBytes.
+	MinBodyBytes        int
+	ExpectedContentType string
+	// ExpectedRedirects is the chain of redirects that must be followed, one
+	// "status location" hop each; the location is exact or a /regex/, and may
+	// be left out to only check the status
+	ExpectedRedirects []string
+	// Method defaults to GET. Headers are set on top of the User-Agent, which
+	// they may replace, and Body is sent with the request when set.
+	Method  string
+	Headers map[string]string
+	Body    string
+	// QueryParams are Go templates rendered per request, e.g. a timestamp or
+	// a signature, and appended to the URL's query
+	QueryParams map[string]string
 }
 
 type CheckResponse struct {
@@ -23,9 +132,44 @@ type CheckResponse struct {
 	ResponseTimeMs int
 	Error          error
 	// SSL Certificate info
-	SSLExpiresAt *time.Time
-	SSLDaysLeft  int
-	SSLIssuer    string
+	SSLExpiresAt   *time.Time
+	SSLDaysLeft    int
+	SSLIssuer      string
+	SSLFingerprint string // SHA-256 of the leaf certificate, lowercase hex
+	// Body is only populated when the request asked for it
+	Body []byte
+	// BodyHash and Baseline are only set by comparison checks
+	BodyHash string
+	Baseline *CheckResponse
+	// Families holds each address family's response for dual-stack checks
+	Families map[string]*CheckResponse
+	// RedirectChain lists the redirects received, as "status location" hops
+	RedirectChain []string
+}
+
+// httpClients are the clients a checker picks from for one transport
+type httpClients struct {
+	client *http.Client
+	// noRedirectClient shares the transport but returns redirects as-is
+	noRedirectClient *http.Client
+	// families hold clients that only dial one address family
+	families map[string]familyClients
+}
+
+func newHTTPClients(transport *http.Transport) httpClients {
+	client, noRedirectClient := newClients(transport)
+	return httpClients{
+		client:           client,
+		noRedirectClient: noRedirectClient,
+		families:         newFamilyClients(transport),
+	}
+}
+
+// TransportLimits caps the connections a checker's transport keeps open.
+// Zero values fall back to the defaults (no per-host limit, 100 idle).
+type TransportLimits struct {
+	MaxConnsPerHost int // Open connections per target host, including in-use
+	MaxIdleConns    int // Idle connections kept across all hosts
 }
 
 func NewHTTPChecker() *HTTPChecker {
@@ -33,33 +177,100 @@ func NewHTTPChecker() *HTTPChecker {
 }
 
 func NewHTTPCheckerWithRetry(retryDelay time.Duration) *HTTPChecker {
+	return NewHTTPCheckerWithLimits(retryDelay, TransportLimits{})
+}
+
+// NewHTTPCheckerWithLimits creates a checker whose transport enforces limits.
+// Share one checker between checks so the limits apply to all of them.
+func NewHTTPCheckerWithLimits(retryDelay time.Duration, limits TransportLimits) *HTTPChecker {
+	return NewHTTPCheckerWithRootCAs(retryDelay, limits, nil)
+}
+
+// NewHTTPCheckerWithRootCAs creates a checker that verifies certificates
+// against rootCAs instead of the system pool; nil uses the system pool.
+func NewHTTPCheckerWithRootCAs(retryDelay time.Duration, limits TransportLimits, rootCAs *x509.CertPool) *HTTPChecker {
+	maxIdle := limits.MaxIdleConns
+	if maxIdle < 1 {
+		maxIdle = 100
+	}
+	maxIdlePerHost := 10
+	if limits.MaxConnsPerHost > 0 && limits.MaxConnsPerHost < maxIdlePerHost {
+		maxIdlePerHost = limits.MaxConnsPerHost
+	}
+
 	transport := &http.Transport{
 		TLSClientConfig: &tls.Config{
 			MinVersion: tls.VersionTLS12,
+			RootCAs:    rootCAs,
 		},
-		MaxIdleConns:        100,
-		MaxIdleConnsPerHost: 10,
+		MaxIdleConns:        maxIdle,
+		MaxIdleConnsPerHost: maxIdlePerHost,
+		MaxConnsPerHost:     limits.MaxConnsPerHost,
 		IdleConnTimeout:     90 * time.Second,
 	}
 
+	// A separate pool, so no connection verified one way is reused the other
+	insecure := transport.Clone()
+	insecure.TLSClientConfig.InsecureSkipVerify = true
+
+	return &HTTPChecker{
+		httpClients: newHTTPClients(transport),
+		insecure:    newHTTPClients(insecure),
+		RetryDelay:  retryDelay,
+	}
+}
+
+// LoadCABundle returns the system's CA pool with the PEM certificates in path
+// added, for checks against endpoints signed by an internal CA
+func LoadCABundle(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading CA bundle: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM certificates found in CA bundle %s", path)
+	}
+	return pool, nil
+}
+
+// newClients returns a client following up to 10 redirects and one returning
+// redirects as-is, both on transport
+func newClients(transport http.RoundTripper) (*http.Client, *http.Client) {
 	client := &http.Client{
 		Transport: transport,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			if len(via) >= 10 {
 				return http.ErrUseLastResponse
 			}
+			recordRedirect(req)
 			return nil
 		},
 	}
 
-	return &HTTPChecker{client: client, RetryDelay: retryDelay}
+	noRedirectClient := &http.Client{
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	return client, noRedirectClient
 }
 
 func (h *HTTPChecker) Execute(req *CheckRequest) *CheckResponse {
+	if req.DualStack && req.Family == "" {
+		return executeDualStack(req, h.Execute)
+	}
+
 	response := h.doRequest(req)
 
 	// Retry once after delay on failure (per spec: 1 retry after 5 seconds)
-	if !response.IsSuccess(req.ExpectedStatus) && h.RetryDelay > 0 {
+	if !response.IsSuccess(req.ExpectedStatus, req.ExpectedStatuses) && h.RetryDelay > 0 && Retryable(response, h.RetryOn) {
 		time.Sleep(h.RetryDelay)
 		response = h.doRequest(req)
 	}
@@ -71,15 +282,71 @@ func (h *HTTPChecker) doRequest(req *CheckRequest) *CheckResponse {
 	ctx, cancel := context.WithTimeout(context.Background(), req.Timeout)
 	defer cancel()
 
-	httpReq, err := http.NewRequestWithContext(ctx, "GET", req.URL, nil)
+	method := req.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	var body io.Reader
+	if req.Body != "" {
+		body = strings.NewReader(req.Body)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, req.URL, body)
 	if err != nil {
 		return &CheckResponse{Error: err}
 	}
 
 	httpReq.Header.Set("User-Agent", "Sentinel/1.0 (Uptime Monitor)")
+	for name, value := range req.Headers {
+		// Go sends the Host header from the request, not its header map
+		if strings.EqualFold(name, "Host") {
+			httpReq.Host = value
+			continue
+		}
+		httpReq.Header.Set(name, value)
+	}
+	if req.BypassCache {
+		httpReq.Header.Set("Cache-Control", "no-cache")
+		httpReq.Header.Set("Pragma", "no-cache")
+		// Appended rather than re-encoded so the URL's own query is untouched
+		bust := cacheBustParam + "=" + strconv.FormatInt(time.Now().UnixNano(), 10)
+		if httpReq.URL.RawQuery != "" {
+			bust = httpReq.URL.RawQuery + "&" + bust
+		}
+		httpReq.URL.RawQuery = bust
+	}
+	if len(req.QueryParams) > 0 {
+		params, err := renderQueryParams(req.QueryParams, method, httpReq.URL, time.Now())
+		if err != nil {
+			return &CheckResponse{Error: err}
+		}
+		if httpReq.URL.RawQuery != "" {
+			params = httpReq.URL.RawQuery + "&" + params
+		}
+		httpReq.URL.RawQuery = params
+	}
+
+	clients := h.httpClients
+	if req.InsecureSkipVerify {
+		clients = h.insecure
+	}
+	client, noRedirectClient := clients.client, clients.noRedirectClient
+	if req.Family != "" {
+		family, ok := clients.families[req.Family]
+		if !ok {
+			return &CheckResponse{Error: fmt.Errorf("unknown address family %q", req.Family)}
+		}
+		client, noRedirectClient = family.client, family.noRedirectClient
+	}
+	if req.NoFollowRedirects {
+		client = noRedirectClient
+	}
+
+	var chain []string
+	httpReq = httpReq.WithContext(context.WithValue(ctx, redirectChainKey{}, &chain))
 
 	start := time.Now()
-	resp, err := h.client.Do(httpReq)
+	resp, err := client.Do(httpReq)
 	elapsed := time.Since(start)
 
 	response := &CheckResponse{
@@ -94,6 +361,90 @@ func (h *HTTPChecker) doRequest(req *CheckRequest) *CheckResponse {
 
 	response.StatusCode = resp.StatusCode
 
+	// A redirect that wasn't followed is the chain's last hop
+	if isRedirect(resp) {
+		chain = append(chain, redirectHop(resp))
+	}
+	response.RedirectChain = chain
+
+	if len(req.ExpectedRedirects) > 0 && response.IsSuccess(req.ExpectedStatus, req.ExpectedStatuses) {
+		if err := CheckRedirectChain(req.ExpectedRedirects, chain); err != nil {
+			response.Error = err
+		}
+	}
+
+	if req.ExpectedLocation != "" && response.IsSuccess(req.ExpectedStatus, req.ExpectedStatuses) {
+		if err := CheckLocation(req.ExpectedLocation, resp.Header.Get("Location")); err != nil {
+			response.Error = err
+		}
+	}
+
+	assertBody := req.assertsBody()
+	contentType := resp.Header.Get("Content-Type")
+
+	if req.ExpectedContentType != "" && response.IsSuccess(req.ExpectedStatus, req.ExpectedStatuses) {
+		if err := CheckContentType(req.ExpectedContentType, contentType); err != nil {
+			response.Error = err
+		}
+	}
+
+	readBody := req.CaptureBody || req.GoldenBody != "" || assertBody || req.ExpectedTrailer != "" || req.MinBodyBytes > 0
+	var respBody io.Reader = resp.Body
+	if req.Streaming || readBody {
+		respBody, err = decodedBody(resp)
+		if err != nil {
+			response.Error = err
+			return response
+		}
+	}
+
+	if req.Streaming {
+		if req.CaptureBody || assertBody {
+			buf := make([]byte, streamPeekBytes)
+			n, _ := respBody.Read(buf)
+			if req.CaptureBody {
+				response.Body = buf[:n]
+			}
+			// A stream never ends, so assertions are matched against its first read
+			if assertBody && response.IsSuccess(req.ExpectedStatus, req.ExpectedStatuses) {
+				if err := req.checkBody(contentType, buf[:n]); err != nil {
+					response.Error = err
+				}
+			}
+		}
+	} else if readBody {
+		body, err := io.ReadAll(io.LimitReader(respBody, maxBodyBytes))
+		if err != nil {
+			response.Error = fmt.Errorf("reading response body: %w", err)
+			return response
+		}
+		// Trailers only arrive once the body has been read to EOF
+		if req.ExpectedTrailer != "" && response.IsSuccess(req.ExpectedStatus, req.ExpectedStatuses) {
+			if n, _ := io.Copy(io.Discard, io.LimitReader(respBody, 1)); n > 0 {
+				response.Error = fmt.Errorf("response body exceeds %d bytes, trailers not read", maxBodyBytes)
+			} else if err := CheckTrailer(req.ExpectedTrailer, resp.Trailer); err != nil {
+				response.Error = err
+			}
+		}
+		if req.MinBodyBytes > 0 && len(body) < req.MinBodyBytes && response.IsSuccess(req.ExpectedStatus, req.ExpectedStatuses) {
+			response.Error = fmt.Errorf("response body is %d bytes, expected at least %d", len(body), req.MinBodyBytes)
+		}
+		if req.CaptureBody {
+			response.Body = body
+		}
+		// Only diff bodies of otherwise healthy responses so a bad status stays the cause
+		if req.GoldenBody != "" && response.IsSuccess(req.ExpectedStatus, req.ExpectedStatuses) {
+			if err := CompareGolden(req.GoldenBody, body); err != nil {
+				response.Error = err
+			}
+		}
+		if assertBody && response.IsSuccess(req.ExpectedStatus, req.ExpectedStatuses) {
+			if err := req.checkBody(contentType, body); err != nil {
+				response.Error = err
+			}
+		}
+	}
+
 	// Extract SSL certificate info if available
 	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
 		cert := resp.TLS.PeerCertificates[0]
@@ -103,17 +454,367 @@ func (h *HTTPChecker) doRequest(req *CheckRequest) *CheckResponse {
 		if response.SSLIssuer == "" && len(cert.Issuer.Organization) > 0 {
 			response.SSLIssuer = cert.Issuer.Organization[0]
 		}
+		sum := sha256.Sum256(cert.Raw)
+		response.SSLFingerprint = hex.EncodeToString(sum[:])
+	}
+
+	if req.ExpectedCertFingerprint != "" && response.Error == nil {
+		if err := CheckFingerprint(req.ExpectedCertFingerprint, response.SSLFingerprint); err != nil {
+			response.Error = err
+		}
 	}
 
 	return response
 }
 
-func (r *CheckResponse) IsSuccess(expectedStatus int) bool {
+// decodedBody undoes the response's Content-Encoding. The transport only
+// decompresses gzip it asked for itself, so a server that compresses
+// regardless, or a check that sets its own Accept-Encoding, would otherwise
+// have its assertions matched against compressed bytes.
+func decodedBody(resp *http.Response) (io.Reader, error) {
+	if resp.Uncompressed {
+		return resp.Body, nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "gzip", "x-gzip":
+		zr, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("decoding gzip response body: %w", err)
+		}
+		return zr, nil
+	case "deflate":
+		// deflate is meant to be zlib-wrapped, but some servers send it raw
+		br := bufio.NewReader(resp.Body)
+		if header, err := br.Peek(2); err == nil && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+			zr, err := zlib.NewReader(br)
+			if err != nil {
+				return nil, fmt.Errorf("decoding deflate response body: %w", err)
+			}
+			return zr, nil
+		}
+		return flate.NewReader(br), nil
+	}
+	return resp.Body, nil
+}
+
+// assertsBody reports whether any assertion needs the response body
+func (r *CheckRequest) assertsBody() bool {
+	return len(r.JSONAssertions) > 0 || len(r.Assertions) > 0 || r.BodyContains != "" || r.BodyNotContains != ""
+}
+
+// checkBody runs the request's body assertions and returns the first
+// failure: JSON assertions, then keywords, then typed assertions
+func (r *CheckRequest) checkBody(contentType string, body []byte) error {
+	if len(r.JSONAssertions) > 0 {
+		if err := CheckJSONAssertions(r.JSONAssertions, body); err != nil {
+			return err
+		}
+	}
+	if err := CheckBodyKeywords(r.BodyContains, r.BodyNotContains, body); err != nil {
+		return err
+	}
+	if len(r.Assertions) > 0 {
+		return CheckBodyAssertions(r.AssertionType, contentType, r.Assertions, body)
+	}
+	return nil
+}
+
+// CheckBodyKeywords fails a body that is missing contains or includes
+// notContains; an empty keyword is not checked
+func CheckBodyKeywords(contains, notContains string, body []byte) error {
+	if contains != "" && !bytes.Contains(body, []byte(contains)) {
+		return fmt.Errorf("expected body to contain %q", contains)
+	}
+	if notContains != "" && bytes.Contains(body, []byte(notContains)) {
+		return fmt.Errorf("expected body not to contain %q", notContains)
+	}
+	return nil
+}
+
+// CheckFingerprint compares an observed certificate fingerprint against a
+// pinned one. An empty observed value means the connection wasn't TLS.
+func CheckFingerprint(expected, observed string) error {
+	pinned, err := ParseFingerprint(expected)
+	if err != nil {
+		return err
+	}
+	if observed == "" {
+		return fmt.Errorf("certificate fingerprint is pinned but the connection is not TLS")
+	}
+	if observed != pinned {
+		return fmt.Errorf("certificate fingerprint %s does not match pinned %s", observed, pinned)
+	}
+	return nil
+}
+
+// ParseFingerprint normalizes a SHA-256 fingerprint as printed by openssl
+// (AB:CD:...) or plain hex into lowercase hex
+func ParseFingerprint(fingerprint string) (string, error) {
+	normalized := strings.ToLower(strings.NewReplacer(":", "", " ", "").Replace(fingerprint))
+	if len(normalized) != sha256.Size*2 {
+		return "", fmt.Errorf("invalid certificate fingerprint %q: expected a SHA-256 hex digest", fingerprint)
+	}
+	if _, err := hex.DecodeString(normalized); err != nil {
+		return "", fmt.Errorf("invalid certificate fingerprint %q: %w", fingerprint, err)
+	}
+	return normalized, nil
+}
+
+// IsSuccess reports whether the response counts as up. A non-empty statuses
+// spec like "200,204" or "2xx" takes precedence over expectedStatus.
+func (r *CheckResponse) IsSuccess(expectedStatus int, statuses ...string) bool {
 	if r.Error != nil {
 		return false
 	}
+	for _, spec := range statuses {
+		if spec != "" {
+			return storage.StatusMatches(spec, r.StatusCode)
+		}
+	}
+	if expectedStatus == storage.AnyStatus {
+		return true
+	}
 	if expectedStatus == 0 {
 		expectedStatus = 200
 	}
 	return r.StatusCode == expectedStatus
 }
+
+// CheckLocation matches a redirect Location header against an expected
+// value, treating /slash-wrapped/ values as regular expressions
+func CheckLocation(expected, location string) error {
+	if location == "" {
+		return fmt.Errorf("expected redirect to %s, got no Location header", expected)
+	}
+
+	re, err := locationPattern(expected)
+	if err != nil {
+		return err
+	}
+	if re != nil {
+		if !re.MatchString(location) {
+			return fmt.Errorf("redirect location %s does not match %s", location, expected)
+		}
+		return nil
+	}
+
+	if location != expected {
+		return fmt.Errorf("redirect location %s, expected %s", location, expected)
+	}
+	return nil
+}
+
+// redirectChainKey carries a request's *[]string of redirect hops to the
+// CheckRedirect hook, which is shared by every request on a client
+type redirectChainKey struct{}
+
+// recordRedirect adds the response that caused req to its chain
+func recordRedirect(req *http.Request) {
+	chain, ok := req.Context().Value(redirectChainKey{}).(*[]string)
+	if ok && req.Response != nil {
+		*chain = append(*chain, redirectHop(req.Response))
+	}
+}
+
+func isRedirect(resp *http.Response) bool {
+	return resp.StatusCode >= 300 && resp.StatusCode < 400 && resp.Header.Get("Location") != ""
+}
+
+func redirectHop(resp *http.Response) string {
+	return fmt.Sprintf("%d %s", resp.StatusCode, resp.Header.Get("Location"))
+}
+
+// FormatRedirectChain renders hops as they are stored on results
+func FormatRedirectChain(chain []string) string {
+	return strings.Join(chain, " -> ")
+}
+
+// CheckRedirectChain compares observed "status location" hops against the
+// expected chain, hop by hop
+func CheckRedirectChain(expected, observed []string) error {
+	if len(observed) != len(expected) {
+		got := FormatRedirectChain(observed)
+		if got == "" {
+			got = "no redirects"
+		}
+		return fmt.Errorf("expected %d redirects, got %s", len(expected), got)
+	}
+
+	for i, hop := range expected {
+		status, location, err := ParseRedirectHop(hop)
+		if err != nil {
+			return err
+		}
+		gotStatus, gotLocation, _ := strings.Cut(observed[i], " ")
+		if gotStatus != strconv.Itoa(status) {
+			return fmt.Errorf("redirect %d: status %s, expected %d", i+1, gotStatus, status)
+		}
+		if location == "" {
+			continue
+		}
+		if err := CheckLocation(location, gotLocation); err != nil {
+			return fmt.Errorf("redirect %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// ParseRedirectHop splits an expected "status location" hop. The status must
+// be a 3xx code; the location is optional.
+func ParseRedirectHop(hop string) (int, string, error) {
+	code, location, _ := strings.Cut(strings.TrimSpace(hop), " ")
+	status, err := strconv.Atoi(code)
+	if err != nil || status < 300 || status > 399 {
+		return 0, "", fmt.Errorf("invalid expected redirect %q (use a 3xx status and location, e.g. 301 https://www.example.com/)", hop)
+	}
+	return status, strings.TrimSpace(location), nil
+}
+
+// ValidateExpectedRedirects rejects hops without a 3xx status or with a
+// /regex/ location that does not compile
+func ValidateExpectedRedirects(hops []string) error {
+	for _, hop := range hops {
+		_, location, err := ParseRedirectHop(hop)
+		if err != nil {
+			return err
+		}
+		if err := ValidateExpectedLocation(location); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckTrailer matches a "Name: value" spec against response trailers. A
+// spec without a value only requires the trailer to be present.
+func CheckTrailer(expected string, trailer http.Header) error {
+	name, value, err := ParseExpectedTrailer(expected)
+	if err != nil {
+		return err
+	}
+
+	values, ok := trailer[http.CanonicalHeaderKey(name)]
+	if !ok || len(values) == 0 {
+		return fmt.Errorf("expected trailer %s, got none", name)
+	}
+	if value != "" && !slices.Contains(values, value) {
+		return fmt.Errorf("trailer %s is %q, expected %q", name, strings.Join(values, ", "), value)
+	}
+	return nil
+}
+
+// ParseExpectedTrailer splits a "Name: value" spec, rejecting one without a
+// valid header name
+func ParseExpectedTrailer(expected string) (string, string, error) {
+	name, value, _ := strings.Cut(expected, ":")
+	name = strings.TrimSpace(name)
+	if name == "" || strings.ContainsAny(name, " \t\"(),/;<=>?@[\\]{}") {
+		return "", "", fmt.Errorf("invalid expected trailer %q (use Name: value)", expected)
+	}
+	return name, strings.TrimSpace(value), nil
+}
+
+// ValidateExpectedTrailer rejects a trailer spec without a valid name; an
+// empty spec is fine
+func ValidateExpectedTrailer(expected string) error {
+	if expected == "" {
+		return nil
+	}
+	_, _, err := ParseExpectedTrailer(expected)
+	return err
+}
+
+// CheckContentType matches a Content-Type header against the expected media
+// type, ignoring case and parameters such as charset
+func CheckContentType(expected, contentType string) error {
+	if strings.TrimSpace(contentType) == "" {
+		return fmt.Errorf("expected content type %s, got none", expected)
+	}
+	want, _, err := mime.ParseMediaType(expected)
+	if err != nil {
+		return fmt.Errorf("invalid expected content type %q", expected)
+	}
+	got, _, err := mime.ParseMediaType(contentType)
+	if err != nil || got != want {
+		return fmt.Errorf("content type is %q, expected %s", contentType, want)
+	}
+	return nil
+}
+
+// ValidateExpectedContentType rejects anything but a type/subtype media type;
+// an empty one is fine
+func ValidateExpectedContentType(expected string) error {
+	if expected == "" {
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(expected)
+	if err != nil || !strings.Contains(mediaType, "/") {
+		return fmt.Errorf("invalid expected content type %q (use a media type like application/json)", expected)
+	}
+	return nil
+}
+
+// ValidateMinBodyBytes rejects a negative length, or one longer than the
+// most of a body a check reads
+func ValidateMinBodyBytes(n int) error {
+	if n < 0 {
+		return fmt.Errorf("min_body_bytes cannot be negative")
+	}
+	if n > maxBodyBytes {
+		return fmt.Errorf("min_body_bytes cannot exceed %d, the most of a body a check reads", maxBodyBytes)
+	}
+	return nil
+}
+
+// requestMethods are the methods a check may send
+var requestMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+	http.MethodPatch, http.MethodDelete, http.MethodOptions,
+}
+
+// ValidateRequestOptions rejects an unknown method, and headers that could
+// not be sent; an empty method means GET
+func ValidateRequestOptions(method string, headers map[string]string) error {
+	if method != "" && !slices.Contains(requestMethods, method) {
+		return fmt.Errorf("invalid method %q (use %s)", method, strings.Join(requestMethods, ", "))
+	}
+	for name, value := range headers {
+		if name == "" || strings.ContainsAny(name, " \t\"(),/:;<=>?@[\\]{}") {
+			return fmt.Errorf("invalid header name %q", name)
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("header %s contains a line break", name)
+		}
+	}
+	return nil
+}
+
+// ParseHeader splits a "Name: value" line, as headers are entered in the form
+func ParseHeader(line string) (string, string, error) {
+	name, value, ok := strings.Cut(line, ":")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return "", "", fmt.Errorf("invalid header %q (use Name: value)", line)
+	}
+	return name, strings.TrimSpace(value), nil
+}
+
+// ValidateExpectedLocation rejects a /regex/ location that does not compile
+func ValidateExpectedLocation(expected string) error {
+	_, err := locationPattern(expected)
+	return err
+}
+
+// locationPattern compiles a /slash-wrapped/ expected location, returning
+// nil for values that are matched exactly
+func locationPattern(expected string) (*regexp.Regexp, error) {
+	if len(expected) < 2 || !strings.HasPrefix(expected, "/") || !strings.HasSuffix(expected, "/") {
+		return nil, nil
+	}
+	re, err := regexp.Compile(expected[1 : len(expected)-1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid expected location %s: %w", expected, err)
+	}
+	return re, nil
+}
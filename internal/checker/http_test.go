@@ -1,8 +1,20 @@
 package checker
 
 import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 )
@@ -156,6 +168,351 @@ func TestHTTPCheckerRedirects(t *testing.T) {
 	}
 }
 
+func TestHTTPCheckerNoFollowRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			http.Redirect(w, r, "https://example.com/login", http.StatusMovedPermanently)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		location string
+		wantErr  bool
+	}{
+		{"no assertion", "", false},
+		{"exact match", "https://example.com/login", false},
+		{"exact mismatch", "https://example.com/home", true},
+		{"regex match", "/^https://example\\.com/", false},
+		{"regex mismatch", "/^http://", true},
+	}
+
+	checker := newTestChecker()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := checker.Execute(&CheckRequest{
+				URL:               server.URL,
+				Timeout:           5 * time.Second,
+				ExpectedStatus:    301,
+				NoFollowRedirects: true,
+				ExpectedLocation:  tt.location,
+			})
+
+			if resp.StatusCode != 301 {
+				t.Fatalf("expected the redirect itself (301), got %d", resp.StatusCode)
+			}
+			if (resp.Error != nil) != tt.wantErr {
+				t.Errorf("expected error=%v, got %v", tt.wantErr, resp.Error)
+			}
+		})
+	}
+}
+
+func TestHTTPCheckerExpectedLocationWithoutRedirect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	checker := newTestChecker()
+	resp := checker.Execute(&CheckRequest{
+		URL:              server.URL,
+		Timeout:          5 * time.Second,
+		ExpectedStatus:   200,
+		ExpectedLocation: "https://example.com/",
+	})
+
+	if resp.Error == nil {
+		t.Error("expected an error when no Location header is returned")
+	}
+}
+
+func TestValidateExpectedLocation(t *testing.T) {
+	if err := ValidateExpectedLocation("https://example.com/"); err != nil {
+		t.Errorf("expected exact location to be valid, got %v", err)
+	}
+	if err := ValidateExpectedLocation("/^https://"); err != nil {
+		t.Errorf("expected regex location to be valid, got %v", err)
+	}
+	if err := ValidateExpectedLocation("/[/"); err == nil {
+		t.Error("expected invalid regex to be rejected")
+	}
+}
+
+func TestHTTPCheckerExpectedRedirects(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, server.URL+"/new", http.StatusMovedPermanently)
+		case "/new":
+			http.Redirect(w, r, "/home", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		expected []string
+		wantErr  string
+	}{
+		{"exact chain", []string{"301 " + server.URL + "/new", "302 /home"}, ""},
+		{"status only and regex", []string{"301", "302 /^/ho/"}, ""},
+		{"wrong status", []string{"301 " + server.URL + "/new", "301 /home"}, "redirect 2: status 302, expected 301"},
+		{"wrong location", []string{"301 https://www.example.com/", "302 /home"}, "redirect 1:"},
+		{"too short", []string{"301"}, "expected 1 redirects"},
+	}
+
+	checker := newTestChecker()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := checker.Execute(&CheckRequest{
+				URL:               server.URL + "/old",
+				Timeout:           5 * time.Second,
+				ExpectedStatus:    200,
+				ExpectedRedirects: tt.expected,
+			})
+
+			want := []string{"301 " + server.URL + "/new", "302 /home"}
+			if !slices.Equal(resp.RedirectChain, want) {
+				t.Errorf("expected chain %v, got %v", want, resp.RedirectChain)
+			}
+			if tt.wantErr == "" && resp.Error != nil {
+				t.Errorf("unexpected error: %v", resp.Error)
+			}
+			if tt.wantErr != "" && (resp.Error == nil || !strings.Contains(resp.Error.Error(), tt.wantErr)) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, resp.Error)
+			}
+		})
+	}
+}
+
+func TestHTTPCheckerRedirectChainNotFollowed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://example.com/login", http.StatusMovedPermanently)
+	}))
+	defer server.Close()
+
+	resp := newTestChecker().Execute(&CheckRequest{
+		URL:               server.URL,
+		Timeout:           5 * time.Second,
+		ExpectedStatus:    301,
+		NoFollowRedirects: true,
+		ExpectedRedirects: []string{"301 https://example.com/login"},
+	})
+	if resp.Error != nil {
+		t.Errorf("expected the unfollowed redirect as the chain's only hop, got %v", resp.Error)
+	}
+}
+
+func TestValidateExpectedRedirects(t *testing.T) {
+	if err := ValidateExpectedRedirects([]string{"301 https://example.com/", "302", "308 /^https:/"}); err != nil {
+		t.Errorf("expected valid hops, got %v", err)
+	}
+	for _, invalid := range []string{"200 https://example.com/", "moved https://example.com/", "301 /[/"} {
+		if err := ValidateExpectedRedirects([]string{invalid}); err == nil {
+			t.Errorf("expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestHTTPCheckerExpectedTrailer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Trailer", "Grpc-Status")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("payload"))
+		w.Header().Set("Grpc-Status", r.URL.Query().Get("status"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+		wantErr  bool
+	}{
+		{"matching value", "?status=0", "Grpc-Status: 0", false},
+		{"wrong value", "?status=14", "Grpc-Status: 0", true},
+		{"presence only", "?status=14", "grpc-status", false},
+		{"missing trailer", "?status=0", "Grpc-Message: ok", true},
+	}
+
+	checker := newTestChecker()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := checker.Execute(&CheckRequest{
+				URL:             server.URL + tt.query,
+				Timeout:         5 * time.Second,
+				ExpectedStatus:  200,
+				ExpectedTrailer: tt.expected,
+			})
+			if (resp.Error != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, resp.Error)
+			}
+		})
+	}
+}
+
+func TestHTTPCheckerExpectedTrailerBodyTooLarge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Trailer", "Grpc-Status")
+		w.Write(make([]byte, maxBodyBytes+1))
+		w.Header().Set("Grpc-Status", "0")
+	}))
+	defer server.Close()
+
+	checker := newTestChecker()
+	resp := checker.Execute(&CheckRequest{
+		URL:             server.URL,
+		Timeout:         5 * time.Second,
+		ExpectedStatus:  200,
+		ExpectedTrailer: "Grpc-Status: 0",
+	})
+
+	if resp.Error == nil || !strings.Contains(resp.Error.Error(), "trailers not read") {
+		t.Errorf("expected an oversized body to fail, got %v", resp.Error)
+	}
+}
+
+func TestValidateExpectedTrailer(t *testing.T) {
+	for _, valid := range []string{"", "Grpc-Status: 0", "X-Checksum"} {
+		if err := ValidateExpectedTrailer(valid); err != nil {
+			t.Errorf("expected %q to be valid, got %v", valid, err)
+		}
+	}
+	for _, invalid := range []string{": 0", "Grpc Status: 0"} {
+		if err := ValidateExpectedTrailer(invalid); err == nil {
+			t.Errorf("expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestHTTPCheckerBodySizeAndContentType(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gateway" {
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html>Bad Gateway</html>"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		path        string
+		minBytes    int
+		contentType string
+		wantErr     string
+	}{
+		{"matching type with charset", "/", 0, "application/json", ""},
+		{"type is case-insensitive", "/", 0, "Application/JSON", ""},
+		{"long enough body", "/", 15, "", ""},
+		{"truncated body", "/", 16, "", "response body is 15 bytes, expected at least 16"},
+		{"html error page", "/gateway", 0, "application/json", `content type is "text/html", expected application/json`},
+	}
+
+	checker := newTestChecker()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := checker.Execute(&CheckRequest{
+				URL:                 server.URL + tt.path,
+				Timeout:             5 * time.Second,
+				ExpectedStatus:      200,
+				MinBodyBytes:        tt.minBytes,
+				ExpectedContentType: tt.contentType,
+			})
+			if tt.wantErr == "" && resp.Error != nil {
+				t.Errorf("unexpected error: %v", resp.Error)
+			}
+			if tt.wantErr != "" && (resp.Error == nil || resp.Error.Error() != tt.wantErr) {
+				t.Errorf("expected error %q, got %v", tt.wantErr, resp.Error)
+			}
+		})
+	}
+}
+
+func TestCheckContentTypeMissing(t *testing.T) {
+	if err := CheckContentType("application/json", ""); err == nil {
+		t.Error("expected a response without a Content-Type to fail")
+	}
+}
+
+func TestValidateExpectedContentType(t *testing.T) {
+	for _, valid := range []string{"", "application/json", "text/html; charset=utf-8"} {
+		if err := ValidateExpectedContentType(valid); err != nil {
+			t.Errorf("expected %q to be valid, got %v", valid, err)
+		}
+	}
+	for _, invalid := range []string{"json", "application/json;;"} {
+		if err := ValidateExpectedContentType(invalid); err == nil {
+			t.Errorf("expected %q to be rejected", invalid)
+		}
+	}
+	if err := ValidateMinBodyBytes(-1); err == nil {
+		t.Error("expected a negative min_body_bytes to be rejected")
+	}
+	if err := ValidateMinBodyBytes(maxBodyBytes + 1); err == nil {
+		t.Error("expected a min_body_bytes past the read limit to be rejected")
+	}
+}
+
+func TestHTTPCheckerRequestOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPost || r.Header.Get("Authorization") != "Bearer token" || string(body) != `{"ping":true}` {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	checker := newTestChecker()
+
+	// The default GET without headers is turned away
+	resp := checker.Execute(&CheckRequest{URL: server.URL, Timeout: 5 * time.Second, ExpectedStatus: 200})
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 for a plain GET, got %d", resp.StatusCode)
+	}
+
+	resp = checker.Execute(&CheckRequest{
+		URL:            server.URL,
+		Timeout:        5 * time.Second,
+		ExpectedStatus: 200,
+		Method:         http.MethodPost,
+		Headers:        map[string]string{"Authorization": "Bearer token"},
+		Body:           `{"ping":true}`,
+	})
+	if resp.Error != nil || resp.StatusCode != http.StatusOK {
+		t.Errorf("expected the POST to succeed, got %d (%v)", resp.StatusCode, resp.Error)
+	}
+}
+
+func TestValidateRequestOptions(t *testing.T) {
+	if err := ValidateRequestOptions("", nil); err != nil {
+		t.Errorf("expected the defaults to be valid, got %v", err)
+	}
+	if err := ValidateRequestOptions("POST", map[string]string{"Authorization": "Bearer token", "X-Api-Key": "abc"}); err != nil {
+		t.Errorf("expected POST with headers to be valid, got %v", err)
+	}
+	if err := ValidateRequestOptions("FETCH", nil); err == nil {
+		t.Error("expected an unknown method to be rejected")
+	}
+	if err := ValidateRequestOptions("GET", map[string]string{"Bad Name": "x"}); err == nil {
+		t.Error("expected an invalid header name to be rejected")
+	}
+	if err := ValidateRequestOptions("GET", map[string]string{"X-Injected": "a\r\nHost: evil"}); err == nil {
+		t.Error("expected a header value with a line break to be rejected")
+	}
+}
+
 func TestHTTPCheckerResponseTime(t *testing.T) {
 	delay := 100 * time.Millisecond
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -201,6 +558,53 @@ func TestHTTPCheckerUserAgent(t *testing.T) {
 	}
 }
 
+func TestHTTPCheckerBypassCache(t *testing.T) {
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	checker := newTestChecker()
+	checker.Execute(&CheckRequest{URL: server.URL + "/health?b=2&a=1", Timeout: 5 * time.Second, ExpectedStatus: 200})
+	checker.Execute(&CheckRequest{URL: server.URL + "/health?b=2&a=1", Timeout: 5 * time.Second, ExpectedStatus: 200, BypassCache: true})
+
+	plain, bypass := requests[0], requests[1]
+	if plain.Header.Get("Cache-Control") != "" || plain.URL.Query().Has(cacheBustParam) {
+		t.Error("expected no cache bypass unless enabled")
+	}
+	if bypass.Header.Get("Cache-Control") != "no-cache" || bypass.Header.Get("Pragma") != "no-cache" {
+		t.Errorf("expected no-cache headers, got %v", bypass.Header)
+	}
+	if !strings.HasPrefix(bypass.URL.RawQuery, "b=2&a=1&"+cacheBustParam+"=") {
+		t.Errorf("expected cache-busting param appended to the original query, got %q", bypass.URL.RawQuery)
+	}
+}
+
+func TestNewHTTPCheckerWithLimits(t *testing.T) {
+	checker := NewHTTPCheckerWithLimits(0, TransportLimits{MaxConnsPerHost: 4, MaxIdleConns: 20})
+	transport := checker.client.Transport.(*http.Transport)
+
+	if transport.MaxConnsPerHost != 4 {
+		t.Errorf("expected MaxConnsPerHost 4, got %d", transport.MaxConnsPerHost)
+	}
+	if transport.MaxIdleConns != 20 {
+		t.Errorf("expected MaxIdleConns 20, got %d", transport.MaxIdleConns)
+	}
+	if transport.MaxIdleConnsPerHost != 4 {
+		t.Errorf("expected idle per host capped at 4, got %d", transport.MaxIdleConnsPerHost)
+	}
+	if checker.noRedirectClient.Transport != transport {
+		t.Error("expected both clients to share the limited transport")
+	}
+
+	defaults := newTestChecker().client.Transport.(*http.Transport)
+	if defaults.MaxConnsPerHost != 0 || defaults.MaxIdleConns != 100 {
+		t.Errorf("expected unlimited conns and 100 idle by default, got %d and %d", defaults.MaxConnsPerHost, defaults.MaxIdleConns)
+	}
+}
+
 func TestIsSuccessDefaultStatus(t *testing.T) {
 	resp := &CheckResponse{StatusCode: 200}
 
@@ -226,6 +630,8 @@ func TestHTTPCheckerRetryOnFailure(t *testing.T) {
 
 	// Use short retry delay for testing
 	checker := NewHTTPCheckerWithRetry(10 * time.Millisecond)
+	// Only transport errors are retried by default
+	checker.RetryOn = []string{CauseHTTP}
 	resp := checker.Execute(&CheckRequest{
 		URL:            server.URL,
 		Timeout:        5 * time.Second,
@@ -338,3 +744,403 @@ func TestHTTPCheckerSSLFieldsForHTTPS(t *testing.T) {
 		t.Error("expected SSLIssuer to be set for HTTPS")
 	}
 }
+
+func TestHTTPCheckerInsecureSkipVerify(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	checker := newTestChecker()
+	req := &CheckRequest{URL: server.URL, Timeout: 5 * time.Second, ExpectedStatus: 200}
+
+	// The test server's certificate is self-signed, so verification fails
+	if resp := checker.Execute(req); resp.Error == nil {
+		t.Fatal("expected an untrusted certificate to fail by default")
+	}
+
+	req.InsecureSkipVerify = true
+	resp := checker.Execute(req)
+	if resp.Error != nil || resp.StatusCode != 200 {
+		t.Fatalf("expected the check to pass without verification, got %d, %v", resp.StatusCode, resp.Error)
+	}
+	if resp.SSLExpiresAt == nil {
+		t.Error("expected certificate expiry to still be reported")
+	}
+
+	// The secure transport is untouched
+	req.InsecureSkipVerify = false
+	if resp := checker.Execute(req); resp.Error == nil {
+		t.Error("expected verification to stay on for other checks")
+	}
+}
+
+func TestHTTPCheckerCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	pemCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundle, pemCert, 0o600); err != nil {
+		t.Fatalf("failed to write bundle: %v", err)
+	}
+
+	rootCAs, err := LoadCABundle(bundle)
+	if err != nil {
+		t.Fatalf("failed to load bundle: %v", err)
+	}
+	checker := NewHTTPCheckerWithRootCAs(0, TransportLimits{}, rootCAs)
+	resp := checker.Execute(&CheckRequest{URL: server.URL, Timeout: 5 * time.Second, ExpectedStatus: 200})
+	if resp.Error != nil {
+		t.Errorf("expected the bundle's CA to be trusted, got %v", resp.Error)
+	}
+
+	if err := os.WriteFile(bundle, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("failed to write bundle: %v", err)
+	}
+	if _, err := LoadCABundle(bundle); err == nil {
+		t.Error("expected a bundle without certificates to be rejected")
+	}
+}
+
+func TestHTTPCheckerCertFingerprint(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	checker := newTestChecker()
+	// Trust the test server's self-signed certificate
+	checker.client.Transport.(*http.Transport).TLSClientConfig = server.Client().Transport.(*http.Transport).TLSClientConfig
+
+	sum := sha256.Sum256(server.Certificate().Raw)
+	fingerprint := hex.EncodeToString(sum[:])
+
+	// openssl prints fingerprints as uppercase, colon-separated pairs
+	var pairs []string
+	for i := 0; i < len(fingerprint); i += 2 {
+		pairs = append(pairs, strings.ToUpper(fingerprint[i:i+2]))
+	}
+
+	tests := []struct {
+		name     string
+		expected string
+		wantErr  bool
+	}{
+		{"not pinned", "", false},
+		{"matching hex", fingerprint, false},
+		{"matching openssl format", strings.Join(pairs, ":"), false},
+		{"mismatch", strings.Repeat("0", 64), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := checker.Execute(&CheckRequest{
+				URL:                     server.URL,
+				Timeout:                 5 * time.Second,
+				ExpectedStatus:          200,
+				ExpectedCertFingerprint: tt.expected,
+			})
+
+			if resp.SSLFingerprint != fingerprint {
+				t.Errorf("expected observed fingerprint %s, got %s", fingerprint, resp.SSLFingerprint)
+			}
+			if (resp.Error != nil) != tt.wantErr {
+				t.Errorf("expected error=%v, got %v", tt.wantErr, resp.Error)
+			}
+		})
+	}
+}
+
+func TestHTTPCheckerCertFingerprintWithoutTLS(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	resp := newTestChecker().Execute(&CheckRequest{
+		URL:                     server.URL,
+		Timeout:                 5 * time.Second,
+		ExpectedStatus:          200,
+		ExpectedCertFingerprint: strings.Repeat("ab", 32),
+	})
+
+	if resp.Error == nil {
+		t.Error("expected a pinned check over plain HTTP to fail")
+	}
+}
+
+func TestParseFingerprint(t *testing.T) {
+	got, err := ParseFingerprint(strings.Repeat("AB:", 31) + "AB")
+	if err != nil || got != strings.Repeat("ab", 32) {
+		t.Errorf("expected normalized fingerprint, got %q (%v)", got, err)
+	}
+
+	for _, bad := range []string{"abc", strings.Repeat("zz", 32)} {
+		if _, err := ParseFingerprint(bad); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestHTTPCheckerGoldenBody(t *testing.T) {
+	body := "version: 1\nmode: primary\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	checker := newTestChecker()
+
+	resp := checker.Execute(&CheckRequest{
+		URL:            server.URL,
+		Timeout:        5 * time.Second,
+		ExpectedStatus: 200,
+		CaptureBody:    true,
+	})
+	if string(resp.Body) != body {
+		t.Errorf("expected captured body %q, got %q", body, string(resp.Body))
+	}
+
+	resp = checker.Execute(&CheckRequest{
+		URL:            server.URL,
+		Timeout:        5 * time.Second,
+		ExpectedStatus: 200,
+		GoldenBody:     body,
+	})
+	if resp.Error != nil {
+		t.Errorf("expected matching golden body to pass, got %v", resp.Error)
+	}
+	if resp.Body != nil {
+		t.Error("expected body not to be kept unless requested")
+	}
+
+	resp = checker.Execute(&CheckRequest{
+		URL:            server.URL,
+		Timeout:        5 * time.Second,
+		ExpectedStatus: 200,
+		GoldenBody:     "version: 1\nmode: standby\n",
+	})
+	if resp.Error == nil {
+		t.Fatal("expected golden mismatch to fail the check")
+	}
+	if DetermineStatus(resp, 200) != "down" {
+		t.Error("expected golden mismatch to mark check down")
+	}
+}
+
+func TestHTTPCheckerStreaming(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("data: hello\n\n"))
+		w.(http.Flusher).Flush()
+		// Never finish the body
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	defer server.Close()
+	defer close(done)
+
+	checker := newTestChecker()
+
+	start := time.Now()
+	resp := checker.Execute(&CheckRequest{
+		URL:            server.URL,
+		Timeout:        2 * time.Second,
+		ExpectedStatus: 200,
+		CaptureBody:    true,
+		Streaming:      true,
+	})
+
+	if !resp.IsSuccess(200) {
+		t.Fatalf("expected streaming check to succeed, got status %d, error %v", resp.StatusCode, resp.Error)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("expected streaming check not to wait for the body to end, took %v", time.Since(start))
+	}
+	if string(resp.Body) != "data: hello\n\n" {
+		t.Errorf("expected initial chunk to be captured, got %q", string(resp.Body))
+	}
+}
+
+func TestHTTPCheckerJSONAssertions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"queue_depth": 12000, "status": "ok"}`))
+	}))
+	defer server.Close()
+
+	checker := newTestChecker()
+
+	resp := checker.Execute(&CheckRequest{
+		URL:            server.URL,
+		Timeout:        2 * time.Second,
+		ExpectedStatus: 200,
+		JSONAssertions: []string{"$.status == ok"},
+	})
+	if !resp.IsSuccess(200) {
+		t.Errorf("expected passing assertions to succeed, got %v", resp.Error)
+	}
+
+	resp = checker.Execute(&CheckRequest{
+		URL:            server.URL,
+		Timeout:        2 * time.Second,
+		ExpectedStatus: 200,
+		JSONAssertions: []string{"$.status == ok", "$.queue_depth <= 10000"},
+	})
+	if resp.IsSuccess(200) {
+		t.Fatal("expected failing assertion to mark the check down")
+	}
+	if !strings.Contains(resp.Error.Error(), "$.queue_depth is 12000") {
+		t.Errorf("expected descriptive cause, got %v", resp.Error)
+	}
+}
+
+func TestHTTPCheckerBodyKeywords(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<h1>Backend unavailable</h1>`))
+	}))
+	defer server.Close()
+
+	checker := newTestChecker()
+
+	resp := checker.Execute(&CheckRequest{
+		URL:            server.URL,
+		Timeout:        2 * time.Second,
+		ExpectedStatus: 200,
+		BodyContains:   "healthy",
+	})
+	if resp.IsSuccess(200) {
+		t.Fatal("expected a 200 without the keyword to be down")
+	}
+	if resp.Error.Error() != `expected body to contain "healthy"` {
+		t.Errorf("expected descriptive cause, got %v", resp.Error)
+	}
+
+	resp = checker.Execute(&CheckRequest{
+		URL:             server.URL,
+		Timeout:         2 * time.Second,
+		ExpectedStatus:  200,
+		BodyContains:    "<h1>",
+		BodyNotContains: "unavailable",
+	})
+	if resp.IsSuccess(200) || !strings.Contains(resp.Error.Error(), "not to contain") {
+		t.Errorf("expected forbidden keyword to mark the check down, got %v", resp.Error)
+	}
+
+	resp = checker.Execute(&CheckRequest{
+		URL:             server.URL,
+		Timeout:         2 * time.Second,
+		ExpectedStatus:  200,
+		BodyContains:    "Backend",
+		BodyNotContains: "healthy",
+	})
+	if !resp.IsSuccess(200) {
+		t.Errorf("expected matching keywords to succeed, got %v", resp.Error)
+	}
+	if resp.Body != nil {
+		t.Error("expected body not to be kept without CaptureBody")
+	}
+}
+
+func TestHTTPCheckerDecodesCompressedBody(t *testing.T) {
+	const page = "<h1>All systems healthy</h1>"
+	tests := []struct {
+		name     string
+		encoding string
+		encode   func(io.Writer) io.WriteCloser
+	}{
+		{"gzip", "gzip", func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }},
+		{"zlib deflate", "deflate", func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) }},
+		{"raw deflate", "deflate", func(w io.Writer) io.WriteCloser {
+			fw, _ := flate.NewWriter(w, flate.DefaultCompression)
+			return fw
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var compressed bytes.Buffer
+			zw := tt.encode(&compressed)
+			zw.Write([]byte(page))
+			zw.Close()
+
+			// The check asked for compression itself, so the transport leaves it
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Encoding", tt.encoding)
+				w.Write(compressed.Bytes())
+			}))
+			defer server.Close()
+
+			resp := newTestChecker().Execute(&CheckRequest{
+				URL:            server.URL,
+				Timeout:        2 * time.Second,
+				ExpectedStatus: 200,
+				Headers:        map[string]string{"Accept-Encoding": "gzip, deflate"},
+				BodyContains:   "healthy",
+				CaptureBody:    true,
+			})
+			if !resp.IsSuccess(200) {
+				t.Fatalf("expected the decoded body to match, got %v", resp.Error)
+			}
+			if string(resp.Body) != page {
+				t.Errorf("expected the captured body decoded, got %q", resp.Body)
+			}
+		})
+	}
+}
+
+func TestHTTPCheckerCorruptGzipBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write([]byte("not gzip at all"))
+	}))
+	defer server.Close()
+
+	resp := newTestChecker().Execute(&CheckRequest{
+		URL:            server.URL,
+		Timeout:        2 * time.Second,
+		ExpectedStatus: 200,
+		Headers:        map[string]string{"Accept-Encoding": "gzip"},
+		BodyContains:   "healthy",
+	})
+	if resp.Error == nil || !strings.Contains(resp.Error.Error(), "decoding gzip") {
+		t.Errorf("expected a decoding error, got %v", resp.Error)
+	}
+}
+
+func TestHTTPCheckerBodyAssertionsByContentType(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(`<health><status>degraded</status></health>`))
+	}))
+	defer server.Close()
+
+	checker := newTestChecker()
+
+	resp := checker.Execute(&CheckRequest{
+		URL:            server.URL,
+		Timeout:        2 * time.Second,
+		ExpectedStatus: 200,
+		Assertions:     []string{"/health/status == ok"},
+	})
+	if resp.IsSuccess(200) || !strings.Contains(resp.Error.Error(), `/health/status is "degraded"`) {
+		t.Errorf("expected the XML matcher to fail the check, got %v", resp.Error)
+	}
+
+	resp = checker.Execute(&CheckRequest{
+		URL:            server.URL,
+		Timeout:        2 * time.Second,
+		ExpectedStatus: 200,
+		AssertionType:  AssertionText,
+		Assertions:     []string{"contains degraded"},
+	})
+	if !resp.IsSuccess(200) {
+		t.Errorf("expected the configured text matcher to pass, got %v", resp.Error)
+	}
+}
@@ -0,0 +1,190 @@
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+// ValidateCheckInput rejects a new check the scheduler could not run, and
+// normalizes its certificate fingerprint
+func ValidateCheckInput(input *storage.CreateCheckInput) error {
+	if input.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if input.URL == "" {
+		return fmt.Errorf("url is required")
+	}
+	if err := ValidateTarget(input.Type, input.URL); err != nil {
+		return err
+	}
+	if err := ValidateDNSExpectation(input.RecordType, input.ExpectedAnswer); err != nil {
+		return err
+	}
+	if err := ValidateCron(input.Type, input.Cron); err != nil {
+		return err
+	}
+	if input.ExpectedStatuses != "" {
+		if err := storage.ValidateStatusSpec(input.ExpectedStatuses); err != nil {
+			return err
+		}
+	}
+	for _, a := range input.JSONAssertions {
+		if _, err := ParseJSONAssertion(a); err != nil {
+			return err
+		}
+	}
+	if err := ValidateExpectedLocation(input.ExpectedLocation); err != nil {
+		return err
+	}
+	if err := ValidateExpectedTrailer(input.ExpectedTrailer); err != nil {
+		return err
+	}
+	if err := ValidateExpectedContentType(input.ExpectedContentType); err != nil {
+		return err
+	}
+	if err := ValidateMinBodyBytes(input.MinBodyBytes); err != nil {
+		return err
+	}
+	if err := ValidateExpectedRedirects(input.ExpectedRedirects); err != nil {
+		return err
+	}
+	input.Method = strings.ToUpper(input.Method)
+	if err := ValidateRequestOptions(input.Method, input.Headers); err != nil {
+		return err
+	}
+	if err := ValidateQueryParams(input.QueryParams); err != nil {
+		return err
+	}
+	if input.ExpectedCertFingerprint != "" {
+		fingerprint, err := ParseFingerprint(input.ExpectedCertFingerprint)
+		if err != nil {
+			return err
+		}
+		input.ExpectedCertFingerprint = fingerprint
+	}
+	if err := ValidateCompareFields(input.CompareFields); err != nil {
+		return err
+	}
+	if err := ValidateEscalations(input.Escalations); err != nil {
+		return err
+	}
+	if err := ValidateAlertChannels(input.AlertChannels); err != nil {
+		return err
+	}
+	if err := ValidateAlertRoutes(input.AlertRoutes); err != nil {
+		return err
+	}
+	if err := ValidateBodyAssertions(input.AssertionType, input.Assertions); err != nil {
+		return err
+	}
+	if input.LatencyTolerancePct < 0 {
+		return fmt.Errorf("latency_tolerance_pct cannot be negative")
+	}
+	if input.DegradedThresholdMs < 0 {
+		return fmt.Errorf("degraded_threshold_ms cannot be negative")
+	}
+	if input.SlowMultiplier != 0 && input.SlowMultiplier <= 1 {
+		return fmt.Errorf("slow_multiplier must be more than 1")
+	}
+	if input.ConsecutiveSuccesses < 0 {
+		return fmt.Errorf("consecutive_successes cannot be negative")
+	}
+	if input.ConsecutiveFailures < 0 {
+		return fmt.Errorf("consecutive_failures cannot be negative")
+	}
+	return nil
+}
+
+// ParseCheckImport reads a JSON or YAML list of checks with the API's field
+// names. YAML is converted to JSON first so both decode the same way.
+func ParseCheckImport(data []byte) ([]*storage.CreateCheckInput, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing checks: %w", err)
+	}
+	if _, ok := raw.([]interface{}); !ok {
+		return nil, fmt.Errorf("parsing checks: expected a list of checks")
+	}
+
+	converted, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parsing checks: %w", err)
+	}
+	var inputs []*storage.CreateCheckInput
+	if err := json.Unmarshal(converted, &inputs); err != nil {
+		return nil, fmt.Errorf("parsing checks: %w", err)
+	}
+	return inputs, nil
+}
+
+// ImportedCheck is one entry of an import, identified by its position in the
+// list
+type ImportedCheck struct {
+	Index int    `json:"index"`
+	Name  string `json:"name"`
+	URL   string `json:"url"`
+	ID    int64  `json:"id,omitempty"`    // The new check, or the existing one it was skipped for
+	Error string `json:"error,omitempty"` // Why it failed
+}
+
+// ImportSummary sorts an import's entries by outcome
+type ImportSummary struct {
+	Created []ImportedCheck `json:"created"`
+	Skipped []ImportedCheck `json:"skipped"`
+	Failed  []ImportedCheck `json:"failed"`
+}
+
+// ImportChecks creates each valid check whose URL isn't monitored yet,
+// including by an earlier entry of the same list. It returns the summary and
+// the created checks, for the caller to schedule.
+func ImportChecks(store storage.Storage, inputs []*storage.CreateCheckInput) (*ImportSummary, []*storage.Check) {
+	summary := &ImportSummary{
+		Created: []ImportedCheck{},
+		Skipped: []ImportedCheck{},
+		Failed:  []ImportedCheck{},
+	}
+	var created []*storage.Check
+
+	for i, input := range inputs {
+		if input == nil {
+			summary.Failed = append(summary.Failed, ImportedCheck{Index: i, Error: "empty entry"})
+			continue
+		}
+		entry := ImportedCheck{Index: i, Name: input.Name, URL: input.URL}
+
+		if err := ValidateCheckInput(input); err != nil {
+			entry.Error = err.Error()
+			summary.Failed = append(summary.Failed, entry)
+			continue
+		}
+
+		existing, err := store.GetCheckByURL(input.URL)
+		if err != nil {
+			entry.Error = err.Error()
+			summary.Failed = append(summary.Failed, entry)
+			continue
+		}
+		if existing != nil {
+			entry.ID = existing.ID
+			summary.Skipped = append(summary.Skipped, entry)
+			continue
+		}
+
+		check := input.ToCheck()
+		if err := store.CreateCheck(check); err != nil {
+			entry.Error = err.Error()
+			summary.Failed = append(summary.Failed, entry)
+			continue
+		}
+		entry.ID = check.ID
+		summary.Created = append(summary.Created, entry)
+		created = append(created, check)
+	}
+
+	return summary, created
+}
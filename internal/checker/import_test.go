@@ -0,0 +1,63 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+func TestParseCheckImport(t *testing.T) {
+	yamlList := `
+- name: API
+  url: https://api.example.com
+  interval_seconds: 60
+  expected_status: "2xx"
+`
+	jsonList := `[{"name":"API","url":"https://api.example.com","interval_seconds":60,"expected_status":"2xx"}]`
+
+	for _, data := range []string{yamlList, jsonList} {
+		inputs, err := ParseCheckImport([]byte(data))
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", data, err)
+		}
+		if len(inputs) != 1 || inputs[0].IntervalSecs != 60 || inputs[0].ExpectedStatuses != "2xx" {
+			t.Errorf("expected one check with its fields, got %+v", inputs[0])
+		}
+	}
+
+	if _, err := ParseCheckImport([]byte(`{"name":"not a list"}`)); err == nil {
+		t.Error("expected an error for a single object")
+	}
+}
+
+func TestImportChecks(t *testing.T) {
+	store := setupTestStorage(t)
+
+	existing := &storage.Check{Name: "Existing", URL: "https://existing.example.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(existing)
+
+	inputs := []*storage.CreateCheckInput{
+		{Name: "New", URL: "https://new.example.com"},
+		{Name: "Existing again", URL: "https://existing.example.com"},
+		{Name: "New twice", URL: "https://new.example.com"},
+		{URL: "https://nameless.example.com"},
+		{Name: "Bad assertion", URL: "https://bad.example.com", JSONAssertions: []string{"nonsense"}},
+	}
+
+	summary, created := ImportChecks(store, inputs)
+
+	if len(summary.Created) != 1 || len(created) != 1 || created[0].URL != "https://new.example.com" {
+		t.Errorf("expected one created check, got %+v", summary.Created)
+	}
+	if len(summary.Skipped) != 2 || summary.Skipped[0].ID != existing.ID || summary.Skipped[1].ID != created[0].ID {
+		t.Errorf("expected the existing and repeated URLs to be skipped, got %+v", summary.Skipped)
+	}
+	if len(summary.Failed) != 2 || summary.Failed[0].Index != 3 || summary.Failed[0].Error != "name is required" {
+		t.Errorf("expected two failures with reasons, got %+v", summary.Failed)
+	}
+
+	checks, _ := store.ListChecks()
+	if len(checks) != 2 {
+		t.Errorf("expected 2 checks stored, got %d", len(checks))
+	}
+}
@@ -0,0 +1,185 @@
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonAssertionOps are checked longest first so ">=" isn't read as ">"
+var jsonAssertionOps = []string{">=", "<=", "==", "!=", ">", "<"}
+
+// JSONAssertion compares one field of a JSON response body against a value,
+// e.g. "$.queue_depth < 10000" or "$.status == ok"
+type JSONAssertion struct {
+	Path  string
+	Op    string
+	Value string
+}
+
+// ParseJSONAssertion parses "<path> <op> <value>". Paths use dot notation
+// with optional array indexes, like $.data.items[0].count.
+func ParseJSONAssertion(s string) (*JSONAssertion, error) {
+	path, op, value, err := parseComparison(s, "$")
+	if err != nil {
+		return nil, err
+	}
+	return &JSONAssertion{Path: path, Op: op, Value: value}, nil
+}
+
+// parseComparison splits "<path> <op> <value>", requiring the path to start
+// with prefix and ordering operators to have a numeric value
+func parseComparison(s, prefix string) (path, op, value string, err error) {
+	for _, op := range jsonAssertionOps {
+		i := strings.Index(s, op)
+		if i < 0 {
+			continue
+		}
+		path, value = strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+len(op):])
+		if !strings.HasPrefix(path, prefix) {
+			return "", "", "", fmt.Errorf("invalid assertion %q: path must start with %s", s, prefix)
+		}
+		if value == "" {
+			return "", "", "", fmt.Errorf("invalid assertion %q: missing value", s)
+		}
+		if op != "==" && op != "!=" {
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return "", "", "", fmt.Errorf("invalid assertion %q: %s needs a number", s, op)
+			}
+		}
+		return path, op, value, nil
+	}
+	return "", "", "", fmt.Errorf("invalid assertion %q: expected one of %s", s, strings.Join(jsonAssertionOps, " "))
+}
+
+func (a *JSONAssertion) String() string {
+	return fmt.Sprintf("%s %s %s", a.Path, a.Op, a.Value)
+}
+
+// Evaluate checks the assertion against a decoded JSON document
+func (a *JSONAssertion) Evaluate(doc interface{}) error {
+	field, err := lookupJSONPath(doc, a.Path)
+	if err != nil {
+		return err
+	}
+	return compareField(a.Path, a.Op, a.Value, field)
+}
+
+// compareField checks a looked-up field against an assertion's value.
+// Numbers support every operator; anything else is compared as text.
+func compareField(path, op, value string, field interface{}) error {
+	if num, ok := field.(float64); ok {
+		want, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%s is %s, expected %s %s", path, formatJSONNumber(num), op, value)
+		}
+		var pass bool
+		switch op {
+		case ">":
+			pass = num > want
+		case "<":
+			pass = num < want
+		case ">=":
+			pass = num >= want
+		case "<=":
+			pass = num <= want
+		case "==":
+			pass = num == want
+		case "!=":
+			pass = num != want
+		}
+		if !pass {
+			return fmt.Errorf("%s is %s, expected %s %s", path, formatJSONNumber(num), op, value)
+		}
+		return nil
+	}
+
+	// Non-numeric fields only support equality, compared as text
+	got := fmt.Sprint(field)
+	switch op {
+	case "==":
+		if got != value {
+			return fmt.Errorf("%s is %q, expected %q", path, got, value)
+		}
+	case "!=":
+		if got == value {
+			return fmt.Errorf("%s is %q, expected anything else", path, got)
+		}
+	default:
+		return fmt.Errorf("%s is %q, not a number", path, got)
+	}
+	return nil
+}
+
+// CheckJSONAssertions parses the body as JSON and returns an error for the
+// first assertion that fails
+func CheckJSONAssertions(assertions []string, body []byte) error {
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return fmt.Errorf("response is not valid JSON: %w", err)
+	}
+
+	for _, s := range assertions {
+		a, err := ParseJSONAssertion(s)
+		if err != nil {
+			return err
+		}
+		if err := a.Evaluate(doc); err != nil {
+			return fmt.Errorf("assertion failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// lookupJSONPath walks a path like $.data.items[0].count
+func lookupJSONPath(doc interface{}, path string) (interface{}, error) {
+	rest := strings.TrimPrefix(path, "$")
+	current := doc
+
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			key := rest[:end]
+			rest = rest[end:]
+			obj, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s: not an object at %q", path, key)
+			}
+			if current, ok = obj[key]; !ok {
+				return nil, fmt.Errorf("%s: field %q not found", path, key)
+			}
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("%s: unclosed [", path)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid index %q", path, rest[1:end])
+			}
+			rest = rest[end+1:]
+			arr, ok := current.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s: not an array at [%d]", path, index)
+			}
+			if index < 0 || index >= len(arr) {
+				return nil, fmt.Errorf("%s: index %d out of range", path, index)
+			}
+			current = arr[index]
+		default:
+			return nil, fmt.Errorf("%s: unexpected %q", path, rest[0])
+		}
+	}
+
+	return current, nil
+}
+
+func formatJSONNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,63 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseJSONAssertion(t *testing.T) {
+	a, err := ParseJSONAssertion("$.queue_depth >= 10000")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.Path != "$.queue_depth" || a.Op != ">=" || a.Value != "10000" {
+		t.Errorf("unexpected assertion: %+v", a)
+	}
+
+	for _, s := range []string{
+		"queue_depth > 5",    // no $
+		"$.queue_depth >",    // no value
+		"$.status > ok",      // ordering needs a number
+		"$.queue_depth ~ 10", // unknown operator
+	} {
+		if _, err := ParseJSONAssertion(s); err == nil {
+			t.Errorf("expected error for %q", s)
+		}
+	}
+}
+
+func TestCheckJSONAssertions(t *testing.T) {
+	body := []byte(`{"queue_depth": 12000, "status": "ok", "workers": [{"busy": 3}, {"busy": 8}]}`)
+
+	tests := []struct {
+		assertion string
+		wantErr   string
+	}{
+		{"$.queue_depth > 10000", ""},
+		{"$.queue_depth < 10000", "$.queue_depth is 12000, expected < 10000"},
+		{"$.queue_depth == 12000", ""},
+		{"$.status == ok", ""},
+		{"$.status != ok", "expected anything else"},
+		{"$.status == degraded", `$.status is "ok", expected "degraded"`},
+		{"$.workers[1].busy <= 8", ""},
+		{"$.workers[2].busy < 5", "index 2 out of range"},
+		{"$.missing > 1", `field "missing" not found`},
+	}
+
+	for _, tt := range tests {
+		err := CheckJSONAssertions([]string{tt.assertion}, body)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tt.assertion, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.assertion, tt.wantErr, err)
+		}
+	}
+
+	if err := CheckJSONAssertions([]string{"$.a > 1"}, []byte("not json")); err == nil {
+		t.Error("expected error for non-JSON body")
+	}
+}
@@ -0,0 +1,228 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+// pingCount is how many echo requests one ping check sends; losing any of
+// them is down
+const pingCount = 3
+
+// ErrPingUnavailable means neither kind of ICMP socket could be opened.
+// Unprivileged ping sockets need the process's group inside the
+// net.ipv4.ping_group_range sysctl on Linux; raw sockets need CAP_NET_RAW
+// or root.
+var ErrPingUnavailable = errors.New("ICMP ping unavailable: grant CAP_NET_RAW or allow this group in net.ipv4.ping_group_range")
+
+// PingChecker checks hosts without an HTTP endpoint, such as network gear,
+// with ICMP echo requests. The average round trip is reported as the
+// response time; there is no status code, and a lost echo is down.
+type PingChecker struct {
+	RetryDelay time.Duration
+	RetryOn    []string // Failure categories worth a retry; nil means DefaultRetryOn
+}
+
+func NewPingChecker() *PingChecker {
+	return NewPingCheckerWithRetry(5 * time.Second)
+}
+
+func NewPingCheckerWithRetry(retryDelay time.Duration) *PingChecker {
+	return &PingChecker{RetryDelay: retryDelay}
+}
+
+func (p *PingChecker) Execute(req *CheckRequest) *CheckResponse {
+	if req.DualStack && req.Family == "" {
+		return executeDualStack(req, p.Execute)
+	}
+
+	response := p.ping(req)
+
+	// Same single retry as HTTP checks; a missing privilege won't change
+	if response.Error != nil && !errors.Is(response.Error, ErrPingUnavailable) && p.RetryDelay > 0 && Retryable(response, p.RetryOn) {
+		time.Sleep(p.RetryDelay)
+		response = p.ping(req)
+	}
+
+	return response
+}
+
+func (p *PingChecker) ping(req *CheckRequest) *CheckResponse {
+	host, err := PingHost(req.URL)
+	if err != nil {
+		return &CheckResponse{Error: err}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), req.Timeout)
+	defer cancel()
+	ip, err := pingAddress(ctx, host, req.Family)
+	if err != nil {
+		return &CheckResponse{Error: err}
+	}
+
+	conn, raw, err := listenICMP(ip.To4() != nil)
+	if err != nil {
+		return &CheckResponse{Error: err}
+	}
+	defer conn.Close()
+
+	// Each echo gets an equal share of the timeout
+	wait := req.Timeout / pingCount
+	id := rand.IntN(0xffff) + 1
+	var total time.Duration
+	lost := 0
+	for seq := 1; seq <= pingCount; seq++ {
+		rtt, err := echo(conn, ip, raw, id, seq, wait)
+		if err != nil {
+			var netErr net.Error
+			if !errors.As(err, &netErr) || !netErr.Timeout() {
+				return &CheckResponse{Error: fmt.Errorf("ping %s: %w", host, err)}
+			}
+			lost++
+			continue
+		}
+		total += rtt
+	}
+
+	if lost == pingCount {
+		return &CheckResponse{
+			ResponseTimeMs: int(req.Timeout.Milliseconds()),
+			Error:          fmt.Errorf("ping %s: timeout, all %d echo requests lost", host, pingCount),
+		}
+	}
+	response := &CheckResponse{
+		ResponseTimeMs: int((total / time.Duration(pingCount-lost)).Milliseconds()),
+	}
+	if lost > 0 {
+		response.Error = fmt.Errorf("ping %s: %d of %d echo requests lost", host, lost, pingCount)
+	}
+	return response
+}
+
+// pingAddress resolves the host to one address, IPv4 first unless a family
+// is forced
+func pingAddress(ctx context.Context, host, family string) (net.IP, error) {
+	network := "ip"
+	switch family {
+	case "":
+	case FamilyIPv4:
+		network = "ip4"
+	case FamilyIPv6:
+		network = "ip6"
+	default:
+		return nil, fmt.Errorf("unknown address family %q", family)
+	}
+
+	ips, err := net.DefaultResolver.LookupIP(ctx, network, host)
+	if err != nil {
+		return nil, err
+	}
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			return ip, nil
+		}
+	}
+	return ips[0], nil
+}
+
+// listenICMP opens an unprivileged ping socket, falling back to a raw one.
+// raw reports which: the kernel matches replies to an unprivileged socket
+// itself, rewriting the echo ID, while a raw socket sees every reply.
+func listenICMP(v4 bool) (*icmp.PacketConn, bool, error) {
+	dgram, rawNet, addr := "udp4", "ip4:icmp", "0.0.0.0"
+	if !v4 {
+		dgram, rawNet, addr = "udp6", "ip6:ipv6-icmp", "::"
+	}
+
+	if conn, err := icmp.ListenPacket(dgram, addr); err == nil {
+		return conn, false, nil
+	}
+	conn, err := icmp.ListenPacket(rawNet, addr)
+	if err != nil {
+		if errors.Is(err, os.ErrPermission) {
+			return nil, false, ErrPingUnavailable
+		}
+		return nil, false, fmt.Errorf("opening ICMP socket: %w", err)
+	}
+	return conn, true, nil
+}
+
+// echo sends one echo request and waits up to wait for its reply
+func echo(conn *icmp.PacketConn, ip net.IP, raw bool, id, seq int, wait time.Duration) (time.Duration, error) {
+	var request icmp.Type = ipv4.ICMPTypeEcho
+	var reply icmp.Type = ipv4.ICMPTypeEchoReply
+	proto := 1 // ICMP
+	if ip.To4() == nil {
+		request, reply, proto = ipv6.ICMPTypeEchoRequest, ipv6.ICMPTypeEchoReply, 58 // ICMPv6
+	}
+
+	msg := icmp.Message{
+		Type: request,
+		Body: &icmp.Echo{ID: id, Seq: seq, Data: []byte("sentinel")},
+	}
+	b, err := msg.Marshal(nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var dst net.Addr = &net.UDPAddr{IP: ip}
+	if raw {
+		dst = &net.IPAddr{IP: ip}
+	}
+
+	start := time.Now()
+	if err := conn.SetDeadline(start.Add(wait)); err != nil {
+		return 0, err
+	}
+	if _, err := conn.WriteTo(b, dst); err != nil {
+		return 0, err
+	}
+
+	buf := make([]byte, 1500)
+	for {
+		n, from, err := conn.ReadFrom(buf)
+		if err != nil {
+			return 0, err
+		}
+		parsed, err := icmp.ParseMessage(proto, buf[:n])
+		if err != nil || parsed.Type != reply {
+			continue
+		}
+		body, ok := parsed.Body.(*icmp.Echo)
+		if !ok || body.Seq != seq || (raw && body.ID != id) || !sameIP(from, ip) {
+			continue
+		}
+		return time.Since(start), nil
+	}
+}
+
+func sameIP(addr net.Addr, ip net.IP) bool {
+	switch a := addr.(type) {
+	case *net.UDPAddr:
+		return a.IP.Equal(ip)
+	case *net.IPAddr:
+		return a.IP.Equal(ip)
+	}
+	return false
+}
+
+// PingHost extracts the host from a ping check target, written either as
+// ping://host or a bare hostname or IP address
+func PingHost(target string) (string, error) {
+	host := strings.TrimSuffix(strings.TrimPrefix(target, "ping://"), ".")
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if host == "" || strings.ContainsAny(host, "/ ") || (strings.Contains(host, ":") && net.ParseIP(host) == nil) {
+		return "", fmt.Errorf("ping check target %q must be a hostname or IP address", target)
+	}
+	return host, nil
+}
@@ -0,0 +1,63 @@
+package checker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPingCheckerLoopback(t *testing.T) {
+	resp := NewPingCheckerWithRetry(0).Execute(&CheckRequest{
+		Type:    TypePing,
+		URL:     "127.0.0.1",
+		Timeout: 3 * time.Second,
+	})
+	if errors.Is(resp.Error, ErrPingUnavailable) {
+		t.Skip("no ICMP socket available here:", resp.Error)
+	}
+
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	if resp.ResponseTimeMs < 0 || resp.StatusCode != 0 {
+		t.Errorf("expected a round trip and no status code, got %dms, %d", resp.ResponseTimeMs, resp.StatusCode)
+	}
+}
+
+func TestPingCheckerUnresolvable(t *testing.T) {
+	resp := NewPingCheckerWithRetry(0).Execute(&CheckRequest{
+		Type:    TypePing,
+		URL:     "ping://nonexistent.invalid",
+		Timeout: 2 * time.Second,
+	})
+	if resp.Error == nil {
+		t.Fatal("expected an error for a host that doesn't resolve")
+	}
+}
+
+func TestPingHost(t *testing.T) {
+	tests := []struct {
+		target  string
+		want    string
+		wantErr bool
+	}{
+		{"router.internal", "router.internal", false},
+		{"ping://10.0.0.1", "10.0.0.1", false},
+		{"ping://[fe80::1]", "fe80::1", false},
+		{"::1", "::1", false},
+		{"switch.internal:22", "", true},
+		{"https://example.com", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := PingHost(tt.target)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("PingHost(%q) error = %v, wantErr %v", tt.target, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("PingHost(%q) = %q, want %q", tt.target, got, tt.want)
+		}
+	}
+}
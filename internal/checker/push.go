@@ -0,0 +1,65 @@
+package checker
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+// PushedResult is a result a service reports for itself, for checks
+// Sentinel can't reach but that can reach Sentinel
+type PushedResult struct {
+	Status         string `json:"status"` // "up" or "down"
+	ResponseTimeMs int    `json:"response_time_ms"`
+	Message        string `json:"message,omitempty"` // Recorded as the cause of a down result
+}
+
+// Validate rejects a push with no usable status
+func (p *PushedResult) Validate() error {
+	if p.Status != "up" && p.Status != "down" {
+		return fmt.Errorf("status must be up or down, got %q", p.Status)
+	}
+	if p.ResponseTimeMs < 0 {
+		return fmt.Errorf("response_time_ms must not be negative")
+	}
+	return nil
+}
+
+// PushResult records a pushed result for the check, with the same incident
+// and alert handling as a scheduled run, and returns the stored status
+func (s *Scheduler) PushResult(check *storage.Check, pushed *PushedResult) (string, error) {
+	if err := pushed.Validate(); err != nil {
+		return "", err
+	}
+
+	lastResult, _ := s.storage.GetLatestResult(check.ID)
+	if lastResult != nil {
+		check.Status = lastResult.Status
+	} else {
+		check.Status = "pending"
+	}
+	storage.SettleStatus(s.storage, check)
+	if s.config.AlertOnFirstCheck {
+		check.AlertOnFirstCheck = true
+	}
+
+	// The pushed status is the verdict, so the check's expected status
+	// doesn't apply; only the degraded threshold still does
+	check.ExpectedStatus = storage.AnyStatus
+	check.ExpectedStatuses = ""
+
+	response := &CheckResponse{ResponseTimeMs: pushed.ResponseTimeMs}
+	if pushed.Status == "down" {
+		message := pushed.Message
+		if message == "" {
+			message = "reported down"
+		}
+		response.Error = errors.New(message)
+	}
+
+	if err := ProcessResultWithOptions(s.storage, s.alerter, check, response, s.failureThreshold(check), "", 0, s.events); err != nil {
+		return "", fmt.Errorf("processing result: %w", err)
+	}
+	return DetermineCheckStatus(response, check), nil
+}
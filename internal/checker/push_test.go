@@ -0,0 +1,68 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+func TestPushedResultValidate(t *testing.T) {
+	tests := []struct {
+		pushed  PushedResult
+		wantErr bool
+	}{
+		{PushedResult{Status: "up", ResponseTimeMs: 12}, false},
+		{PushedResult{Status: "down", Message: "backup failed"}, false},
+		{PushedResult{Status: "degraded"}, true},
+		{PushedResult{}, true},
+		{PushedResult{Status: "up", ResponseTimeMs: -1}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.pushed.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%+v) error = %v, wantErr %v", tt.pushed, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSchedulerPushResult(t *testing.T) {
+	store := setupTestStorage(t)
+	alerter := &mockAlerter{}
+	scheduler := NewScheduler(store, alerter, SchedulerConfig{ConsecutiveFailures: 1})
+
+	// The expected status doesn't apply to pushes, only the pushed verdict
+	check := &storage.Check{Name: "Nightly Backup", URL: "https://backup.internal", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 204, Enabled: true}
+	if err := store.CreateCheck(check); err != nil {
+		t.Fatalf("failed to create check: %v", err)
+	}
+
+	status, err := scheduler.PushResult(check, &PushedResult{Status: "up", ResponseTimeMs: 42})
+	if err != nil {
+		t.Fatalf("failed to push result: %v", err)
+	}
+	if status != "up" {
+		t.Errorf("expected up, got %q", status)
+	}
+	latest, _ := store.GetLatestResult(check.ID)
+	if latest == nil || latest.Status != "up" || latest.ResponseTimeMs != 42 {
+		t.Fatalf("expected the pushed result stored, got %+v", latest)
+	}
+
+	if _, err := scheduler.PushResult(check, &PushedResult{Status: "down", Message: "backup failed"}); err != nil {
+		t.Fatalf("failed to push result: %v", err)
+	}
+
+	latest, _ = store.GetLatestResult(check.ID)
+	if latest.Status != "down" || latest.ErrorMessage != "backup failed" {
+		t.Errorf("expected a down result with the pushed message, got %+v", latest)
+	}
+	if incident, _ := store.GetActiveIncident(check.ID); incident == nil {
+		t.Error("expected pushed failures to open an incident")
+	}
+	if alerter.downAlerts != 1 {
+		t.Errorf("expected 1 down alert, got %d", alerter.downAlerts)
+	}
+
+	if _, err := scheduler.PushResult(check, &PushedResult{Status: "sideways"}); err == nil {
+		t.Error("expected an invalid status to be rejected")
+	}
+}
@@ -0,0 +1,105 @@
+package checker
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// queryData is what a query parameter template is rendered with, fresh for
+// every request: e.g. {{.Now.Unix}} for a timestamp, or
+// {{hmac (secret "API_KEY") .Path}} to sign the path
+type queryData struct {
+	Now    time.Time
+	Method string
+	Path   string
+	Query  string // The URL's own query, before any parameters are added
+}
+
+// querySecretPrefix limits which environment variables templates can read.
+// Anyone who can edit a check writes its templates, so a plain env would
+// let them send any of the server's secrets to a URL of their choosing.
+const querySecretPrefix = "SENTINEL_QUERY_"
+
+// queryFuncs are available to query parameter templates. hmac is the hex
+// HMAC-SHA256 of a message under a key, and secret "NAME" reads the
+// variable SENTINEL_QUERY_NAME so keys can stay out of the config.
+var queryFuncs = template.FuncMap{
+	"hmac": func(key, message string) string {
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write([]byte(message))
+		return hex.EncodeToString(mac.Sum(nil))
+	},
+	"secret": func(name string) string {
+		return os.Getenv(querySecretPrefix + name)
+	},
+}
+
+func parseQueryParam(name, value string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(queryFuncs).Option("missingkey=error").Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query parameter %s: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// ValidateQueryParams rejects an unnamed parameter or a template that does
+// not parse
+func ValidateQueryParams(params map[string]string) error {
+	for name, value := range params {
+		if name == "" {
+			return fmt.Errorf("query parameter name is required")
+		}
+		if _, err := parseQueryParam(name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseQueryParam splits a "name=template" line, as query parameters are
+// entered in the form
+func ParseQueryParam(line string) (string, string, error) {
+	name, value, ok := strings.Cut(line, "=")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return "", "", fmt.Errorf("invalid query parameter %q (use name=value)", line)
+	}
+	return name, strings.TrimSpace(value), nil
+}
+
+// renderQueryParams renders each parameter for this request and encodes
+// them, in name order, to be appended to the URL's query
+func renderQueryParams(params map[string]string, method string, u *url.URL, now time.Time) (string, error) {
+	data := queryData{Now: now, Method: method, Path: u.EscapedPath(), Query: u.RawQuery}
+	if data.Path == "" {
+		data.Path = "/"
+	}
+
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var parts []string
+	for _, name := range names {
+		tmpl, err := parseQueryParam(name, params[name])
+		if err != nil {
+			return "", err
+		}
+		var value strings.Builder
+		if err := tmpl.Execute(&value, data); err != nil {
+			return "", fmt.Errorf("rendering query parameter %s: %w", name, err)
+		}
+		parts = append(parts, url.QueryEscape(name)+"="+url.QueryEscape(value.String()))
+	}
+	return strings.Join(parts, "&"), nil
+}
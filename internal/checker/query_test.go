@@ -0,0 +1,98 @@
+package checker
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestHTTPCheckerQueryParams(t *testing.T) {
+	t.Setenv("SENTINEL_QUERY_TEST_SECRET", "s3cret")
+	t.Setenv("TEST_SECRET", "wrong")
+
+	var timestamps []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		mac := hmac.New(sha256.New, []byte("s3cret"))
+		mac.Write([]byte(r.URL.Path + q.Get("ts")))
+		if q.Get("region") != "eu" || q.Get("sig") != hex.EncodeToString(mac.Sum(nil)) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		timestamps = append(timestamps, q.Get("ts"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	req := &CheckRequest{
+		URL:            server.URL + "/health?region=eu",
+		Timeout:        5 * time.Second,
+		ExpectedStatus: 200,
+		QueryParams: map[string]string{
+			"ts":  "{{.Now.UnixNano}}",
+			"sig": `{{hmac (secret "TEST_SECRET") (print .Path .Now.UnixNano)}}`,
+		},
+	}
+	checker := newTestChecker()
+	for range 2 {
+		if resp := checker.Execute(req); resp.Error != nil || resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected the signed request to succeed, got %d (%v)", resp.StatusCode, resp.Error)
+		}
+	}
+
+	// Rendered afresh for every request
+	if len(timestamps) != 2 || timestamps[0] == timestamps[1] {
+		t.Errorf("expected a new timestamp per request, got %v", timestamps)
+	}
+	if _, err := strconv.ParseInt(timestamps[0], 10, 64); err != nil {
+		t.Errorf("expected a numeric timestamp, got %q", timestamps[0])
+	}
+}
+
+func TestHTTPCheckerQueryParamsRenderError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	resp := newTestChecker().Execute(&CheckRequest{
+		URL:            server.URL,
+		Timeout:        5 * time.Second,
+		ExpectedStatus: 200,
+		QueryParams:    map[string]string{"ts": "{{.Missing}}"},
+	})
+	if resp.Error == nil {
+		t.Error("expected a template that fails to render to fail the check")
+	}
+}
+
+func TestValidateQueryParams(t *testing.T) {
+	if err := ValidateQueryParams(map[string]string{"ts": "{{.Now.Unix}}", "v": "2"}); err != nil {
+		t.Errorf("expected valid parameters, got %v", err)
+	}
+	if err := ValidateQueryParams(map[string]string{"ts": "{{.Now.Unix"}); err == nil {
+		t.Error("expected an unclosed action to be rejected")
+	}
+	if err := ValidateQueryParams(map[string]string{"sig": "{{sha1 .Path}}"}); err == nil {
+		t.Error("expected an unknown function to be rejected")
+	}
+	if err := ValidateQueryParams(map[string]string{"key": `{{env "SENTINEL_DB_KEY"}}`}); err == nil {
+		t.Error("expected env to be unavailable, so any variable can't be read")
+	}
+	if err := ValidateQueryParams(map[string]string{"": "x"}); err == nil {
+		t.Error("expected an unnamed parameter to be rejected")
+	}
+
+	name, value, err := ParseQueryParam(" ts = {{.Now.Unix}} ")
+	if err != nil || name != "ts" || value != "{{.Now.Unix}}" {
+		t.Errorf("expected ts={{.Now.Unix}}, got %q=%q (%v)", name, value, err)
+	}
+	if _, _, err := ParseQueryParam("no-equals"); err == nil {
+		t.Error("expected a line without = to be rejected")
+	}
+}
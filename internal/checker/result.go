@@ -13,20 +13,70 @@ type Alerter interface {
 	SendRecoveryAlert(check *storage.Check, incident *storage.Incident) error
 }
 
+// IncidentSyncer mirrors incidents into an external incident management
+// system as they open and close
+type IncidentSyncer interface {
+	SyncIncident(check *storage.Check, incident *storage.Incident, event string) error
+}
+
+// EventSink receives every up/down transition, bypassing alert thresholds and cooldowns
+type EventSink interface {
+	SendStateChange(check *storage.Check, from, to string, result *storage.CheckResult) error
+}
+
+// MetricsRecorder is an EventSink that also sees every result and every
+// alert ProcessResult sends, e.g. to push metrics
+type MetricsRecorder interface {
+	RecordResult(check *storage.Check, result *storage.CheckResult)
+	RecordAlert(check *storage.Check, alertType string)
+}
+
+// EventSinks passes each state change to every sink in turn, returning the last error
+type EventSinks []EventSink
+
+// RecordResult passes the result to every sink that records metrics
+func (e EventSinks) RecordResult(check *storage.Check, result *storage.CheckResult) {
+	for _, sink := range e {
+		if m, ok := sink.(MetricsRecorder); ok {
+			m.RecordResult(check, result)
+		}
+	}
+}
+
+// RecordAlert passes the alert to every sink that records metrics
+func (e EventSinks) RecordAlert(check *storage.Check, alertType string) {
+	for _, sink := range e {
+		if m, ok := sink.(MetricsRecorder); ok {
+			m.RecordAlert(check, alertType)
+		}
+	}
+}
+
+func (e EventSinks) SendStateChange(check *storage.Check, from, to string, result *storage.CheckResult) error {
+	var lastErr error
+	for _, sink := range e {
+		if err := sink.SendStateChange(check, from, to, result); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
 // ProcessResult handles a check response: saves result, detects state changes, manages incidents
 func ProcessResult(store storage.Storage, alerter Alerter, check *storage.Check, response *CheckResponse, consecutiveFailures int) error {
-	return ProcessResultWithOptions(store, alerter, check, response, consecutiveFailures, "", 0)
+	return ProcessResultWithOptions(store, alerter, check, response, consecutiveFailures, "", 0, nil)
 }
 
 // ProcessResultWithRegion handles a check response with optional region tag
 func ProcessResultWithRegion(store storage.Storage, alerter Alerter, check *storage.Check, response *CheckResponse, consecutiveFailures int, region string) error {
-	return ProcessResultWithOptions(store, alerter, check, response, consecutiveFailures, region, 0)
+	return ProcessResultWithOptions(store, alerter, check, response, consecutiveFailures, region, 0, nil)
 }
 
-// ProcessResultWithOptions handles a check response with all options including multi-region threshold
-func ProcessResultWithOptions(store storage.Storage, alerter Alerter, check *storage.Check, response *CheckResponse, consecutiveFailures int, region string, multiRegionThreshold int) error {
+// ProcessResultWithOptions handles a check response with all options including multi-region
+// threshold and an optional sink for raw state change events
+func ProcessResultWithOptions(store storage.Storage, alerter Alerter, check *storage.Check, response *CheckResponse, consecutiveFailures int, region string, multiRegionThreshold int, events EventSink) error {
 	// Determine status
-	status := DetermineStatus(response, check.ExpectedStatus)
+	status := DetermineCheckStatus(response, check)
 
 	// Build result
 	result := &storage.CheckResult{
@@ -38,26 +88,114 @@ func ProcessResultWithOptions(store storage.Storage, alerter Alerter, check *sto
 		SSLExpiresAt:   response.SSLExpiresAt,
 		SSLDaysLeft:    response.SSLDaysLeft,
 		SSLIssuer:      response.SSLIssuer,
+		SSLFingerprint: response.SSLFingerprint,
+		BodyHash:       response.BodyHash,
+		RedirectChain:  FormatRedirectChain(response.RedirectChain),
 	}
 	if response.Error != nil {
 		result.ErrorMessage = response.Error.Error()
 	}
+	if response.Baseline != nil {
+		result.BaselineStatusCode = response.Baseline.StatusCode
+		result.BaselineResponseMs = response.Baseline.ResponseTimeMs
+		result.BaselineBodyHash = response.Baseline.BodyHash
+	}
+	if v4, ok := response.Families[FamilyIPv4]; ok {
+		result.IPv4Status = DetermineStatus(v4, check.SuccessStatus(), check.SuccessStatuses())
+	}
+	if v6, ok := response.Families[FamilyIPv6]; ok {
+		result.IPv6Status = DetermineStatus(v6, check.SuccessStatus(), check.SuccessStatuses())
+	}
+
+	// Metrics count every execution, including results folded away below
+	metrics, _ := events.(MetricsRecorder)
+	if metrics != nil {
+		metrics.RecordResult(check, result)
+	}
+
+	// Compressed checks fold a stable up result identical to the last one into
+	// that row. Failures are always stored since alert thresholds count them.
+	if check.CompressResults && region == "" && status == "up" && check.Status == "up" {
+		last, err := store.GetLatestResult(check.ID)
+		if err != nil {
+			return fmt.Errorf("getting latest result: %w", err)
+		}
+		if last != nil && sameRun(last, result, time.Now()) {
+			result.ID = last.ID
+			if err := store.ExtendResult(result); err != nil {
+				return fmt.Errorf("extending result: %w", err)
+			}
+			return nil
+		}
+	} else if check.SampleSecs > 0 && region == "" && status == "up" && check.Status == "up" {
+		// Sampled checks only store stable up results as a periodic heartbeat
+		last, err := store.GetLatestResult(check.ID)
+		if err != nil {
+			return fmt.Errorf("getting latest result: %w", err)
+		}
+		if last != nil {
+			elapsed := time.Since(last.CheckedAt)
+			if elapsed < time.Duration(check.SampleSecs)*time.Second {
+				return nil
+			}
+			result.Weight = sampleWeight(elapsed, check.IntervalSecs)
+		}
+	}
 
 	// Save result
 	if err := store.SaveResult(result); err != nil {
 		return fmt.Errorf("saving result: %w", err)
 	}
 
-	// Get previous status to detect state change
-	previousStatus := check.Status
-	if previousStatus == "" || previousStatus == "pending" {
-		// First check, no state change detection needed
-		return nil
+	// Get previous status to detect state change. Degraded results count as
+	// up from here on, so slowness alone never opens or closes an incident.
+	state := alertState(status)
+	previousStatus := alertState(check.Status)
+	firstCheck := previousStatus == "" || previousStatus == "pending"
+	if firstCheck {
+		if !check.AlertOnFirstCheck {
+			// First check, no state change detection needed
+			return nil
+		}
+		// Treat the service as known up so a first failure alerts right away
+		previousStatus = "up"
+		consecutiveFailures = 1
+	}
+
+	// A check needing several successes in a row stays down until the last of
+	// them, so a lone up result during a flapping restart changes nothing
+	if state == "up" && previousStatus == "down" {
+		recovered, err := ShouldRecover(store, check.ID, check.ConsecutiveSuccesses)
+		if err != nil {
+			return fmt.Errorf("checking recovery threshold: %w", err)
+		}
+		if !recovered {
+			return nil
+		}
 	}
 
+	// Raw events fire on every transition, before any alert threshold applies
+	if events != nil && !firstCheck && state != previousStatus {
+		if err := events.SendStateChange(check, previousStatus, state, result); err != nil {
+			fmt.Printf("failed to send state change event: %v\n", err)
+		}
+	}
+
+	// Maintenance windows keep results but hold back incidents and alerts. A
+	// failure still going when its window ends has no incident, so it opens
+	// one then.
+	active, err := store.ListActiveMaintenanceWindows(time.Now())
+	if err != nil {
+		return fmt.Errorf("listing maintenance windows: %w", err)
+	}
+	inMaintenance := storage.InMaintenance(active, check.ID)
+
 	// Detect state changes
-	if status == "down" && previousStatus == "up" {
-		// UP -> DOWN transition
+	if state == "down" && !inMaintenance {
+		// UP -> DOWN transition. It's judged on every failure rather than
+		// only the first, since a threshold above 1 is reached with the
+		// previous result already down; ShouldAlert skips a check that
+		// already has an incident.
 		shouldAlert, err := ShouldAlert(store, check.ID, consecutiveFailures)
 		if err != nil {
 			return fmt.Errorf("checking alert threshold: %w", err)
@@ -81,20 +219,25 @@ func ProcessResultWithOptions(store storage.Storage, alerter Alerter, check *sto
 				CheckID:   check.ID,
 				StartedAt: time.Now(),
 				Cause:     result.ErrorMessage,
+				Category:  ClassifyCause(result.ErrorMessage, result.StatusCode),
 			}
 			if err := store.CreateIncident(incident); err != nil {
 				return fmt.Errorf("creating incident: %w", err)
 			}
+			// Synced first so the alert can carry the ticket ID
+			syncIncident(alerter, check, incident, "opened")
 
 			// Send alert
 			if alerter != nil {
 				if err := alerter.SendDownAlert(check, incident, result.ErrorMessage); err != nil {
 					// Log but don't fail - alert failure shouldn't stop monitoring
 					fmt.Printf("failed to send down alert: %v\n", err)
+				} else if metrics != nil {
+					metrics.RecordAlert(check, "down")
 				}
 			}
 		}
-	} else if status == "up" && previousStatus == "down" {
+	} else if state == "up" && previousStatus == "down" {
 		// DOWN -> UP transition (recovery)
 		incident, err := store.GetActiveIncident(check.ID)
 		if err != nil {
@@ -109,11 +252,16 @@ func ProcessResultWithOptions(store storage.Storage, alerter Alerter, check *sto
 
 			// Reload to get duration
 			incident, _ = store.GetIncident(incident.ID)
+			if incident != nil {
+				syncIncident(alerter, check, incident, "closed")
+			}
 
-			// Send recovery alert
-			if alerter != nil {
+			// Send recovery alert; one closed during maintenance stays quiet
+			if alerter != nil && !inMaintenance {
 				if err := alerter.SendRecoveryAlert(check, incident); err != nil {
 					fmt.Printf("failed to send recovery alert: %v\n", err)
+				} else if metrics != nil {
+					metrics.RecordAlert(check, "recovery")
 				}
 			}
 		}
@@ -122,20 +270,96 @@ func ProcessResultWithOptions(store storage.Storage, alerter Alerter, check *sto
 	return nil
 }
 
-// DetermineStatus returns "up" or "down" based on the check response
-func DetermineStatus(response *CheckResponse, expectedStatus int) string {
-	if response.Error != nil {
-		return "down"
+// syncIncident passes an incident event on when the alerter syncs incidents.
+// A failed sync is logged, like a failed alert.
+func syncIncident(alerter Alerter, check *storage.Check, incident *storage.Incident, event string) {
+	syncer, ok := alerter.(IncidentSyncer)
+	if !ok {
+		return
+	}
+	if err := syncer.SyncIncident(check, incident, event); err != nil {
+		fmt.Printf("failed to sync %s incident for %s: %v\n", event, check.Name, err)
 	}
-	if expectedStatus == 0 {
-		expectedStatus = 200
+}
+
+// compressBucketMs is how close response times must be for results to count
+// as identical, so jitter doesn't break up a run
+const compressBucketMs = 50
+
+// sameRun reports whether result can be folded into the stored row last.
+// Runs end at each hour boundary so hourly charts and aggregates stay exact.
+func sameRun(last, result *storage.CheckResult, now time.Time) bool {
+	return last.Region == "" &&
+		last.Status == result.Status &&
+		last.StatusCode == result.StatusCode &&
+		last.ErrorMessage == result.ErrorMessage &&
+		last.ResponseTimeMs/compressBucketMs == result.ResponseTimeMs/compressBucketMs &&
+		last.CheckedAt.UTC().Truncate(time.Hour).Equal(now.UTC().Truncate(time.Hour))
+}
+
+// sampleWeight estimates how many evaluations a heartbeat result stands for
+func sampleWeight(elapsed time.Duration, intervalSecs int) int {
+	if intervalSecs < 1 {
+		return 1
 	}
-	if response.StatusCode != expectedStatus {
+	interval := time.Duration(intervalSecs) * time.Second
+	weight := int((elapsed + interval/2) / interval)
+	if weight < 1 {
+		return 1
+	}
+	return weight
+}
+
+// DetermineStatus returns "up" or "down" based on the check response, judged
+// like IsSuccess against expectedStatus or a statuses spec
+func DetermineStatus(response *CheckResponse, expectedStatus int, statuses ...string) string {
+	if !response.IsSuccess(expectedStatus, statuses...) {
 		return "down"
 	}
 	return "up"
 }
 
+// DetermineCheckStatus is DetermineStatus for a stored check, returning
+// "degraded" for a success slower than the check's DegradedThresholdMs
+func DetermineCheckStatus(response *CheckResponse, check *storage.Check) string {
+	status := DetermineStatus(response, check.SuccessStatus(), check.SuccessStatuses())
+	if status == "up" && check.DegradedThresholdMs > 0 && response.ResponseTimeMs > check.DegradedThresholdMs {
+		return "degraded"
+	}
+	return status
+}
+
+// alertState maps a degraded status to up, the state incidents and alerts
+// are driven by
+func alertState(status string) string {
+	if status == "degraded" {
+		return "up"
+	}
+	return status
+}
+
+// ShouldRecover checks if we have enough consecutive successes for a down
+// check to count as up again; a threshold below 2 needs only the latest
+func ShouldRecover(store storage.Storage, checkID int64, threshold int) (bool, error) {
+	if threshold <= 1 {
+		return true, nil
+	}
+
+	results, err := store.GetRecentResults(checkID, threshold)
+	if err != nil {
+		return false, err
+	}
+	if len(results) < threshold {
+		return false, nil
+	}
+	for _, r := range results {
+		if !r.IsUp() {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 // ShouldAlert checks if we have enough consecutive failures to trigger an alert
 func ShouldAlert(store storage.Storage, checkID int64, threshold int) (bool, error) {
 	if threshold < 1 {
@@ -155,7 +379,7 @@ func ShouldAlert(store storage.Storage, checkID int64, threshold int) (bool, err
 
 	// Check if all recent results are down
 	for _, r := range results {
-		if r.Status == "up" {
+		if r.IsUp() {
 			return false, nil
 		}
 	}
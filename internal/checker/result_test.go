@@ -2,8 +2,6 @@ package checker
 
 import (
 	"errors"
-	"os"
-	"path/filepath"
 	"testing"
 	"time"
 
@@ -31,18 +29,23 @@ func (m *mockAlerter) SendRecoveryAlert(check *storage.Check, incident *storage.
 	return nil
 }
 
-func setupTestStorage(t *testing.T) storage.Storage {
-	tmpDir := t.TempDir()
-	dbPath := filepath.Join(tmpDir, "test.db")
+type mockEventSink struct {
+	transitions []string
+}
+
+func (m *mockEventSink) SendStateChange(check *storage.Check, from, to string, result *storage.CheckResult) error {
+	m.transitions = append(m.transitions, from+"->"+to)
+	return nil
+}
 
-	store, err := storage.NewSQLiteStorage(dbPath)
+func setupTestStorage(t *testing.T) storage.Storage {
+	store, err := storage.NewMemoryStorage()
 	if err != nil {
 		t.Fatalf("failed to create storage: %v", err)
 	}
 
 	t.Cleanup(func() {
 		store.Close()
-		os.Remove(dbPath)
 	})
 
 	return store
@@ -53,6 +56,7 @@ func TestDetermineStatus(t *testing.T) {
 		name           string
 		response       *CheckResponse
 		expectedStatus int
+		statuses       string
 		want           string
 	}{
 		{
@@ -85,11 +89,55 @@ func TestDetermineStatus(t *testing.T) {
 			expectedStatus: 0, // Should default to 200
 			want:           "up",
 		},
+		{
+			name:           "any status accepts 500",
+			response:       &CheckResponse{StatusCode: 500},
+			expectedStatus: storage.AnyStatus,
+			want:           "up",
+		},
+		{
+			name:           "any status still fails on transport error",
+			response:       &CheckResponse{Error: errors.New("connection refused")},
+			expectedStatus: storage.AnyStatus,
+			want:           "down",
+		},
+		{
+			name:           "class accepts 204",
+			response:       &CheckResponse{StatusCode: 204},
+			expectedStatus: 200,
+			statuses:       "2xx",
+			want:           "up",
+		},
+		{
+			name:     "class rejects 301",
+			response: &CheckResponse{StatusCode: 301},
+			statuses: "2xx",
+			want:     "down",
+		},
+		{
+			name:     "list accepts listed code",
+			response: &CheckResponse{StatusCode: 204},
+			statuses: "200, 204",
+			want:     "up",
+		},
+		{
+			name:           "list mismatch is down",
+			response:       &CheckResponse{StatusCode: 202},
+			expectedStatus: 202,
+			statuses:       "200,204",
+			want:           "down",
+		},
+		{
+			name:     "mixed list accepts code outside class",
+			response: &CheckResponse{StatusCode: 304},
+			statuses: "2xx,304",
+			want:     "up",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := DetermineStatus(tt.response, tt.expectedStatus)
+			got := DetermineStatus(tt.response, tt.expectedStatus, tt.statuses)
 			if got != tt.want {
 				t.Errorf("DetermineStatus() = %v, want %v", got, tt.want)
 			}
@@ -259,6 +307,9 @@ func TestProcessResultCreatesIncident(t *testing.T) {
 	if incident == nil {
 		t.Fatal("expected incident to be created")
 	}
+	if incident.Category != CauseConnectionRefused {
+		t.Errorf("expected cause category %s, got %q", CauseConnectionRefused, incident.Category)
+	}
 
 	// Verify alert was sent
 	if alerter.downAlerts != 1 {
@@ -314,6 +365,152 @@ func TestProcessResultClosesIncident(t *testing.T) {
 	}
 }
 
+type mockSyncAlerter struct {
+	mockAlerter
+	events []string
+}
+
+func (m *mockSyncAlerter) SyncIncident(check *storage.Check, incident *storage.Incident, event string) error {
+	m.events = append(m.events, event)
+	return nil
+}
+
+func TestProcessResultSyncsIncident(t *testing.T) {
+	store := setupTestStorage(t)
+	alerter := &mockSyncAlerter{}
+
+	check := &storage.Check{
+		Name:           "Test",
+		URL:            "https://test.com",
+		IntervalSecs:   60,
+		TimeoutSecs:    10,
+		ExpectedStatus: 200,
+		Enabled:        true,
+		Status:         "up",
+	}
+	if err := store.CreateCheck(check); err != nil {
+		t.Fatalf("failed to create check: %v", err)
+	}
+
+	if err := ProcessResult(store, alerter, check, &CheckResponse{Error: errors.New("connection refused")}, 1); err != nil {
+		t.Fatalf("ProcessResult failed: %v", err)
+	}
+	check.Status = "down"
+	if err := ProcessResult(store, alerter, check, &CheckResponse{StatusCode: 200, ResponseTimeMs: 50}, 1); err != nil {
+		t.Fatalf("ProcessResult failed: %v", err)
+	}
+
+	if len(alerter.events) != 2 || alerter.events[0] != "opened" || alerter.events[1] != "closed" {
+		t.Errorf("expected the incident synced when opened and closed, got %v", alerter.events)
+	}
+}
+
+func TestProcessResultConsecutiveSuccesses(t *testing.T) {
+	store := setupTestStorage(t)
+	alerter := &mockAlerter{}
+
+	check := &storage.Check{
+		Name:                 "Flapping",
+		URL:                  "https://test.com",
+		IntervalSecs:         60,
+		TimeoutSecs:          10,
+		ExpectedStatus:       200,
+		Enabled:              true,
+		ConsecutiveSuccesses: 3,
+	}
+	if err := store.CreateCheck(check); err != nil {
+		t.Fatalf("failed to create check: %v", err)
+	}
+	store.SaveResult(&storage.CheckResult{CheckID: check.ID, Status: "down", StatusCode: 503, CheckedAt: time.Now().Add(-time.Minute)})
+	store.CreateIncident(&storage.Incident{CheckID: check.ID, StartedAt: time.Now().Add(-5 * time.Minute)})
+
+	// Each run settles the previous status from the stored results, as the
+	// scheduler does
+	process := func(code int) {
+		t.Helper()
+		latest, _ := store.GetLatestResult(check.ID)
+		check.Status = latest.Status
+		storage.SettleStatus(store, check)
+		if err := ProcessResult(store, alerter, check, &CheckResponse{StatusCode: code, ResponseTimeMs: 50}, 1); err != nil {
+			t.Fatalf("ProcessResult failed: %v", err)
+		}
+	}
+
+	// One good response during a restart, then failing again
+	process(200)
+	process(503)
+	process(200)
+	process(200)
+	if active, _ := store.GetActiveIncident(check.ID); active == nil || alerter.recoveryAlerts != 0 {
+		t.Fatalf("expected the incident to stay open short of 3 successes, got %d recovery alerts", alerter.recoveryAlerts)
+	}
+	if alerter.downAlerts != 0 {
+		t.Errorf("expected no new down alert while flapping, got %d", alerter.downAlerts)
+	}
+	check.Status = "up"
+	if storage.SettleStatus(store, check); check.Status != "down" {
+		t.Errorf("expected the check still shown down, got %s", check.Status)
+	}
+
+	process(200)
+	if active, _ := store.GetActiveIncident(check.ID); active != nil || alerter.recoveryAlerts != 1 {
+		t.Errorf("expected recovery on the third success in a row, got %d recovery alerts", alerter.recoveryAlerts)
+	}
+	check.Status = "up"
+	if storage.SettleStatus(store, check); check.Status != "up" {
+		t.Errorf("expected the check shown up, got %s", check.Status)
+	}
+}
+
+func TestProcessResultMaintenanceWindow(t *testing.T) {
+	store := setupTestStorage(t)
+	alerter := &mockAlerter{}
+
+	check := &storage.Check{
+		Name:           "Deploying",
+		URL:            "https://test.com",
+		IntervalSecs:   60,
+		TimeoutSecs:    10,
+		ExpectedStatus: 200,
+		Enabled:        true,
+		Status:         "up",
+	}
+	store.CreateCheck(check)
+
+	window := &storage.MaintenanceWindow{CheckID: check.ID, StartsAt: time.Now().Add(-time.Minute), DurationSecs: 3600, Recurrence: storage.MaintenanceOnce}
+	store.CreateMaintenanceWindow(window)
+
+	// Failures inside the window are recorded but open no incident
+	store.SaveResult(&storage.CheckResult{CheckID: check.ID, Status: "down"})
+	time.Sleep(10 * time.Millisecond)
+	if err := ProcessResult(store, alerter, check, &CheckResponse{Error: errors.New("connection refused")}, 2); err != nil {
+		t.Fatalf("ProcessResult failed: %v", err)
+	}
+
+	results, _ := store.GetResults(check.ID, 10, 0)
+	if len(results) != 2 {
+		t.Errorf("expected results recorded during maintenance, got %d", len(results))
+	}
+	if incident, _ := store.GetActiveIncident(check.ID); incident != nil || alerter.downAlerts != 0 {
+		t.Fatalf("expected no incident or alert during maintenance, got %v and %d alerts", incident, alerter.downAlerts)
+	}
+
+	// Once the window is gone, a failure that outlasted it is alerted on
+	store.DeleteMaintenanceWindow(window.ID)
+	lastChecked := time.Now()
+	check.Status = "down"
+	check.LastCheckedAt = &lastChecked
+	store.CreateMaintenanceWindow(&storage.MaintenanceWindow{CheckID: check.ID, StartsAt: lastChecked.Add(-time.Minute), DurationSecs: 61, Recurrence: storage.MaintenanceOnce})
+	time.Sleep(1100 * time.Millisecond)
+
+	if err := ProcessResult(store, alerter, check, &CheckResponse{Error: errors.New("connection refused")}, 2); err != nil {
+		t.Fatalf("ProcessResult failed: %v", err)
+	}
+	if incident, _ := store.GetActiveIncident(check.ID); incident == nil || alerter.downAlerts != 1 {
+		t.Errorf("expected an incident once maintenance ended, got %v and %d alerts", incident, alerter.downAlerts)
+	}
+}
+
 func TestProcessResultNoAlertOnFirstCheck(t *testing.T) {
 	store := setupTestStorage(t)
 	alerter := &mockAlerter{}
@@ -354,6 +551,60 @@ func TestProcessResultNoAlertOnFirstCheck(t *testing.T) {
 	}
 }
 
+func TestProcessResultAlertOnFirstCheck(t *testing.T) {
+	store := setupTestStorage(t)
+	alerter := &mockAlerter{}
+	events := &mockEventSink{}
+
+	check := &storage.Check{
+		Name:              "Test",
+		URL:               "https://test.com",
+		IntervalSecs:      60,
+		TimeoutSecs:       10,
+		ExpectedStatus:    200,
+		Enabled:           true,
+		AlertOnFirstCheck: true,
+		Status:            "pending", // First check
+	}
+	if err := store.CreateCheck(check); err != nil {
+		t.Fatalf("failed to create check: %v", err)
+	}
+
+	// A first failure alerts immediately, regardless of the consecutive threshold
+	response := &CheckResponse{
+		Error: errors.New("connection refused"),
+	}
+	if err := ProcessResultWithOptions(store, alerter, check, response, 3, "", 0, events); err != nil {
+		t.Fatalf("ProcessResult failed: %v", err)
+	}
+
+	incident, err := store.GetActiveIncident(check.ID)
+	if err != nil {
+		t.Fatalf("failed to get incident: %v", err)
+	}
+	if incident == nil {
+		t.Error("expected incident on failing first check")
+	}
+	if alerter.downAlerts != 1 {
+		t.Errorf("expected 1 down alert, got %d", alerter.downAlerts)
+	}
+
+	// Leaving pending is not a state change event
+	if len(events.transitions) != 0 {
+		t.Errorf("expected no state change events, got %v", events.transitions)
+	}
+
+	// A healthy first result does nothing
+	other := &storage.Check{Name: "Healthy", URL: "https://healthy.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true, AlertOnFirstCheck: true, Status: "pending"}
+	store.CreateCheck(other)
+	if err := ProcessResult(store, alerter, other, &CheckResponse{StatusCode: 200}, 2); err != nil {
+		t.Fatalf("ProcessResult failed: %v", err)
+	}
+	if alerter.downAlerts != 1 || alerter.recoveryAlerts != 0 {
+		t.Errorf("expected no alerts for healthy first check, got %d down %d recovery", alerter.downAlerts, alerter.recoveryAlerts)
+	}
+}
+
 func TestProcessResultMultiRegionThreshold(t *testing.T) {
 	store := setupTestStorage(t)
 	alerter := &mockAlerter{}
@@ -393,7 +644,7 @@ func TestProcessResultMultiRegionThreshold(t *testing.T) {
 	}
 
 	// Process result for "us" region going down - with threshold=2, should NOT alert
-	if err := ProcessResultWithOptions(store, alerter, check, response, 1, "us", 2); err != nil {
+	if err := ProcessResultWithOptions(store, alerter, check, response, 1, "us", 2, nil); err != nil {
 		t.Fatalf("ProcessResultWithOptions failed: %v", err)
 	}
 
@@ -402,7 +653,7 @@ func TestProcessResultMultiRegionThreshold(t *testing.T) {
 	}
 
 	// Now simulate second region going down
-	if err := ProcessResultWithOptions(store, alerter, check, response, 1, "eu", 2); err != nil {
+	if err := ProcessResultWithOptions(store, alerter, check, response, 1, "eu", 2, nil); err != nil {
 		t.Fatalf("ProcessResultWithOptions failed: %v", err)
 	}
 
@@ -450,7 +701,7 @@ func TestProcessResultMultiRegionThresholdZero(t *testing.T) {
 		Error: errors.New("timeout"),
 	}
 
-	if err := ProcessResultWithOptions(store, alerter, check, response, 1, "us", 0); err != nil {
+	if err := ProcessResultWithOptions(store, alerter, check, response, 1, "us", 0, nil); err != nil {
 		t.Fatalf("ProcessResultWithOptions failed: %v", err)
 	}
 
@@ -458,3 +709,316 @@ func TestProcessResultMultiRegionThresholdZero(t *testing.T) {
 		t.Errorf("expected 1 alert with threshold 0, got %d", alerter.downAlerts)
 	}
 }
+
+func TestProcessResultSendsEveryStateChange(t *testing.T) {
+	store := setupTestStorage(t)
+	alerter := &mockAlerter{}
+	events := &mockEventSink{}
+
+	check := &storage.Check{
+		Name:           "Events",
+		URL:            "https://test.com",
+		IntervalSecs:   60,
+		TimeoutSecs:    10,
+		ExpectedStatus: 200,
+		Enabled:        true,
+		Status:         "up",
+	}
+	if err := store.CreateCheck(check); err != nil {
+		t.Fatalf("failed to create check: %v", err)
+	}
+
+	// A single failure is below the alert threshold but still an event
+	down := &CheckResponse{Error: errors.New("connection refused")}
+	if err := ProcessResultWithOptions(store, alerter, check, down, 3, "", 0, events); err != nil {
+		t.Fatalf("ProcessResultWithOptions failed: %v", err)
+	}
+	if alerter.downAlerts != 0 {
+		t.Errorf("expected no down alert below threshold, got %d", alerter.downAlerts)
+	}
+
+	check.Status = "down"
+	up := &CheckResponse{StatusCode: 200}
+	if err := ProcessResultWithOptions(store, alerter, check, up, 3, "", 0, events); err != nil {
+		t.Fatalf("ProcessResultWithOptions failed: %v", err)
+	}
+
+	// No transition, no event
+	check.Status = "up"
+	if err := ProcessResultWithOptions(store, alerter, check, up, 3, "", 0, events); err != nil {
+		t.Fatalf("ProcessResultWithOptions failed: %v", err)
+	}
+
+	if len(events.transitions) != 2 || events.transitions[0] != "up->down" || events.transitions[1] != "down->up" {
+		t.Errorf("expected up->down and down->up events, got %v", events.transitions)
+	}
+}
+
+func TestProcessResultSamplesStableResults(t *testing.T) {
+	store := setupTestStorage(t)
+
+	check := &storage.Check{
+		Name:           "Sampled",
+		URL:            "https://test.com",
+		IntervalSecs:   5,
+		TimeoutSecs:    10,
+		ExpectedStatus: 200,
+		Enabled:        true,
+		SampleSecs:     60,
+		Status:         "up",
+	}
+	if err := store.CreateCheck(check); err != nil {
+		t.Fatalf("failed to create check: %v", err)
+	}
+	store.SaveResult(&storage.CheckResult{CheckID: check.ID, Status: "up", StatusCode: 200})
+
+	// Stable and within the heartbeat interval: evaluated but not stored
+	up := &CheckResponse{StatusCode: 200, ResponseTimeMs: 50}
+	if err := ProcessResult(store, nil, check, up, 2); err != nil {
+		t.Fatalf("ProcessResult failed: %v", err)
+	}
+
+	results, _ := store.GetResults(check.ID, 10, 0)
+	if len(results) != 1 {
+		t.Errorf("expected stable result to be skipped, got %d results", len(results))
+	}
+
+	// Failures are always stored
+	down := &CheckResponse{Error: errors.New("connection refused")}
+	if err := ProcessResult(store, nil, check, down, 2); err != nil {
+		t.Fatalf("ProcessResult failed: %v", err)
+	}
+
+	results, _ = store.GetResults(check.ID, 10, 0)
+	if len(results) != 2 {
+		t.Errorf("expected failure to be stored, got %d results", len(results))
+	}
+}
+
+func TestProcessResultCompressesIdenticalResults(t *testing.T) {
+	store := setupTestStorage(t)
+
+	check := &storage.Check{
+		Name:            "Compressed",
+		URL:             "https://test.com",
+		IntervalSecs:    5,
+		TimeoutSecs:     10,
+		ExpectedStatus:  200,
+		Enabled:         true,
+		CompressResults: true,
+		Status:          "up",
+	}
+	if err := store.CreateCheck(check); err != nil {
+		t.Fatalf("failed to create check: %v", err)
+	}
+	store.SaveResult(&storage.CheckResult{CheckID: check.ID, Status: "up", StatusCode: 200, ResponseTimeMs: 100})
+
+	// Identical within the response-time bucket: folded into the stored row
+	for _, ms := range []int{110, 120} {
+		if err := ProcessResult(store, nil, check, &CheckResponse{StatusCode: 200, ResponseTimeMs: ms}, 0); err != nil {
+			t.Fatalf("ProcessResult failed: %v", err)
+		}
+	}
+
+	results, _ := store.GetResultDetails(check.ID, 10)
+	if len(results) != 1 {
+		t.Fatalf("expected identical results to share one row, got %d", len(results))
+	}
+	if results[0].Weight != 3 || results[0].RunStartedAt == nil {
+		t.Errorf("expected a run of 3 with a start time, got weight %d start %v", results[0].Weight, results[0].RunStartedAt)
+	}
+	if results[0].ResponseTimeMs != 110 {
+		t.Errorf("expected averaged response time 110ms, got %d", results[0].ResponseTimeMs)
+	}
+
+	// A different status code starts a new row
+	if err := ProcessResult(store, nil, check, &CheckResponse{StatusCode: 204, ResponseTimeMs: 110}, 0); err != nil {
+		t.Fatalf("ProcessResult failed: %v", err)
+	}
+	results, _ = store.GetResultDetails(check.ID, 10)
+	if len(results) != 2 {
+		t.Errorf("expected a changed result to be stored, got %d rows", len(results))
+	}
+}
+
+func TestSameRun(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC)
+	last := &storage.CheckResult{Status: "up", StatusCode: 200, ResponseTimeMs: 120, CheckedAt: now.Add(-time.Minute)}
+
+	tests := []struct {
+		name   string
+		result storage.CheckResult
+		now    time.Time
+		want   bool
+	}{
+		{"identical", storage.CheckResult{Status: "up", StatusCode: 200, ResponseTimeMs: 140}, now, true},
+		{"other bucket", storage.CheckResult{Status: "up", StatusCode: 200, ResponseTimeMs: 150}, now, false},
+		{"other status code", storage.CheckResult{Status: "up", StatusCode: 204, ResponseTimeMs: 120}, now, false},
+		{"next hour", storage.CheckResult{Status: "up", StatusCode: 200, ResponseTimeMs: 120}, now.Add(30 * time.Minute), false},
+	}
+
+	for _, tt := range tests {
+		if got := sameRun(last, &tt.result, tt.now); got != tt.want {
+			t.Errorf("%s: sameRun = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSampleWeight(t *testing.T) {
+	tests := []struct {
+		elapsed  time.Duration
+		interval int
+		want     int
+	}{
+		{60 * time.Second, 5, 12},
+		{62 * time.Second, 5, 12},
+		{3 * time.Second, 5, 1},
+		{time.Minute, 0, 1},
+	}
+
+	for _, tt := range tests {
+		if got := sampleWeight(tt.elapsed, tt.interval); got != tt.want {
+			t.Errorf("sampleWeight(%v, %d) = %d, want %d", tt.elapsed, tt.interval, got, tt.want)
+		}
+	}
+}
+
+func TestEventSinksFanOut(t *testing.T) {
+	first := &mockEventSink{}
+	second := &mockEventSink{}
+	sinks := EventSinks{first, second}
+
+	check := &storage.Check{ID: 1, Name: "Fan"}
+	if err := sinks.SendStateChange(check, "up", "down", &storage.CheckResult{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(first.transitions) != 1 || len(second.transitions) != 1 || second.transitions[0] != "up->down" {
+		t.Errorf("expected both sinks to get up->down, got %v and %v", first.transitions, second.transitions)
+	}
+}
+
+func TestDetermineCheckStatus(t *testing.T) {
+	check := &storage.Check{ExpectedStatus: 200, DegradedThresholdMs: 500}
+
+	tests := []struct {
+		name     string
+		response *CheckResponse
+		want     string
+	}{
+		{"fast success", &CheckResponse{StatusCode: 200, ResponseTimeMs: 120}, "up"},
+		{"at threshold", &CheckResponse{StatusCode: 200, ResponseTimeMs: 500}, "up"},
+		{"slow success", &CheckResponse{StatusCode: 200, ResponseTimeMs: 900}, "degraded"},
+		{"slow failure", &CheckResponse{StatusCode: 500, ResponseTimeMs: 900}, "down"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetermineCheckStatus(tt.response, check); got != tt.want {
+				t.Errorf("DetermineCheckStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	check.DegradedThresholdMs = 0
+	if got := DetermineCheckStatus(&CheckResponse{StatusCode: 200, ResponseTimeMs: 90000}, check); got != "up" {
+		t.Errorf("expected up without a threshold, got %v", got)
+	}
+}
+
+func TestProcessResultDegradedIsNotAnIncident(t *testing.T) {
+	store := setupTestStorage(t)
+	alerter := &mockAlerter{}
+	events := &mockEventSink{}
+
+	check := &storage.Check{
+		Name:                "Slow",
+		URL:                 "https://slow.com",
+		IntervalSecs:        60,
+		TimeoutSecs:         10,
+		ExpectedStatus:      200,
+		Enabled:             true,
+		Status:              "up",
+		DegradedThresholdMs: 500,
+	}
+	if err := store.CreateCheck(check); err != nil {
+		t.Fatalf("failed to create check: %v", err)
+	}
+
+	response := &CheckResponse{StatusCode: 200, ResponseTimeMs: 2000}
+	if err := ProcessResultWithOptions(store, alerter, check, response, 1, "", 0, events); err != nil {
+		t.Fatalf("ProcessResult failed: %v", err)
+	}
+
+	latest, err := store.GetLatestResult(check.ID)
+	if err != nil || latest == nil {
+		t.Fatalf("expected a saved result, got %v", err)
+	}
+	if latest.Status != "degraded" {
+		t.Errorf("expected a degraded result, got %q", latest.Status)
+	}
+	if incident, _ := store.GetActiveIncident(check.ID); incident != nil {
+		t.Error("expected no incident for a degraded result")
+	}
+	if alerter.downAlerts != 0 || len(events.transitions) != 0 {
+		t.Errorf("expected no alerts or events, got %d alerts and %v", alerter.downAlerts, events.transitions)
+	}
+
+	// A slow success still recovers a down check
+	check.Status = "down"
+	incident := &storage.Incident{CheckID: check.ID, StartedAt: time.Now().Add(-time.Minute)}
+	store.CreateIncident(incident)
+	if err := ProcessResult(store, alerter, check, response, 1); err != nil {
+		t.Fatalf("ProcessResult failed: %v", err)
+	}
+	if active, _ := store.GetActiveIncident(check.ID); active != nil {
+		t.Error("expected a degraded result to close the incident")
+	}
+	if alerter.recoveryAlerts != 1 {
+		t.Errorf("expected 1 recovery alert, got %d", alerter.recoveryAlerts)
+	}
+}
+
+type mockMetrics struct {
+	mockEventSink
+	results []string
+	alerts  []string
+}
+
+func (m *mockMetrics) RecordResult(check *storage.Check, result *storage.CheckResult) {
+	m.results = append(m.results, result.Status)
+}
+
+func (m *mockMetrics) RecordAlert(check *storage.Check, alertType string) {
+	m.alerts = append(m.alerts, alertType)
+}
+
+func TestProcessResultRecordsMetrics(t *testing.T) {
+	store := setupTestStorage(t)
+	alerter := &mockAlerter{}
+	metrics := &mockMetrics{}
+	events := EventSinks{&mockEventSink{}, metrics}
+
+	check := &storage.Check{Name: "Metrics", URL: "https://metrics.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true, Status: "up"}
+	if err := store.CreateCheck(check); err != nil {
+		t.Fatalf("failed to create check: %v", err)
+	}
+
+	if err := ProcessResultWithOptions(store, alerter, check, &CheckResponse{Error: errors.New("refused")}, 1, "", 0, events); err != nil {
+		t.Fatalf("ProcessResult failed: %v", err)
+	}
+	check.Status = "down"
+	if err := ProcessResultWithOptions(store, alerter, check, &CheckResponse{StatusCode: 200}, 1, "", 0, events); err != nil {
+		t.Fatalf("ProcessResult failed: %v", err)
+	}
+
+	if len(metrics.results) != 2 || metrics.results[0] != "down" || metrics.results[1] != "up" {
+		t.Errorf("expected both results recorded, got %v", metrics.results)
+	}
+	if len(metrics.alerts) != 2 || metrics.alerts[0] != "down" || metrics.alerts[1] != "recovery" {
+		t.Errorf("expected down and recovery alerts recorded, got %v", metrics.alerts)
+	}
+	if len(metrics.transitions) != 2 {
+		t.Errorf("expected the recorder to also get state changes, got %v", metrics.transitions)
+	}
+}
@@ -1,73 +1,189 @@
 package checker
 
 import (
+	"crypto/x509"
 	"fmt"
 	"math/rand"
 	"sync"
 	"time"
 
+	"github.com/katieblackabee/sentinel/internal/config"
+	"github.com/katieblackabee/sentinel/internal/cron"
 	"github.com/katieblackabee/sentinel/internal/storage"
 )
 
 type Scheduler struct {
 	storage storage.Storage
 	alerter Alerter
+	events  EventSink
 	config  SchedulerConfig
 
+	// One checker shared by every check so the transport limits are global,
+	// and a semaphore capping how many checks execute at once
+	http     *HTTPChecker
+	tcp      *TCPChecker
+	dns      *DNSChecker
+	ping     *PingChecker
+	inflight chan struct{}
+
 	checks      map[int64]*scheduledCheck
 	mu          sync.RWMutex
 	stopChan    chan struct{}
 	wg          sync.WaitGroup
 	cleanupStop chan struct{}
+	// Background jobs start with the first Start, not again on reloads
+	jobsOnce sync.Once
+
+	// Checks the watchdog has flagged for missing results
+	startedAt time.Time
+	stale     map[int64]bool
+	staleMu   sync.Mutex
+
+	// Changed fields last alerted on per drifted check, so each drift alerts once
+	drifted map[int64]string
+
+	// Expiry of the certificate last warned about per check, so each
+	// certificate's expiry alerts once
+	sslAlerted map[int64]time.Time
+	sslMu      sync.Mutex
+
+	// Slow alert state per check, so each slow spell alerts once
+	slow   map[int64]*slowState
+	slowMu sync.Mutex
 }
 
 type SchedulerConfig struct {
 	ConsecutiveFailures       int
 	RetentionDays             int
 	AggregatesDays            int
+	ArchiveDays               int // Keep results past RetentionDays in day archives this long (0 = delete them)
 	SSLExpiryDays             int
-	MultiRegionAlertThreshold int // Min failing regions to alert (0 = alert on any)
+	MultiRegionAlertThreshold int           // Min failing regions to alert (0 = alert on any)
+	AlertOnFirstCheck         bool          // Default for checks that don't opt in themselves
+	MaxConcurrentChecks       int           // Checks executing at once across the scheduler (default 50)
+	StaleIntervals            int           // Intervals without a result before a check is stale (default 3, negative disables)
+	StartupRamp               time.Duration // Spread startup's first runs evenly over this long (0 = within a second)
+	RetryOn                   []string      // Failure categories retried once before a result is stored (nil = DefaultRetryOn)
+	Transport                 TransportLimits
+	RootCAs                   *x509.CertPool // CAs HTTPS checks verify against (nil = the system's)
+	SlowMultiplier            float64        // Slow alert when an up response takes this many times the 24h average (0 = off); checks may set their own
+	SlowCooldown              time.Duration  // Least time between slow alerts for one check
+
+	// Checks defined in the config file, compared with their stored copies
+	// every DriftInterval (nil = off); DriftCorrect restores drifted checks
+	Drift         []config.CheckConfig
+	DriftInterval time.Duration
+	DriftCorrect  bool
 }
 
 type scheduledCheck struct {
-	check  *storage.Check
-	ticker *time.Ticker
-	stop   chan struct{}
+	check    *storage.Check
+	interval time.Duration
+	ticker   *time.Ticker
+	// cron, when set, replaces the ticker: the check runs at each of its times
+	cron *cron.Schedule
+	stop chan struct{}
+	// delay holds back the first run, for checks placed on the startup ramp
+	delay time.Duration
 }
 
 func NewScheduler(store storage.Storage, alerter Alerter, config SchedulerConfig) *Scheduler {
 	if config.ConsecutiveFailures < 1 {
 		config.ConsecutiveFailures = 2
 	}
+	if config.MaxConcurrentChecks < 1 {
+		config.MaxConcurrentChecks = 50
+	}
+	if config.StaleIntervals == 0 {
+		config.StaleIntervals = storage.DefaultStaleIntervals
+	}
+	if config.DriftInterval <= 0 {
+		config.DriftInterval = 5 * time.Minute
+	}
+
+	httpChecker := NewHTTPCheckerWithRootCAs(5*time.Second, config.Transport, config.RootCAs)
+	httpChecker.RetryOn = config.RetryOn
+	tcpChecker := NewTCPChecker()
+	tcpChecker.RetryOn = config.RetryOn
+	dnsChecker := NewDNSChecker()
+	dnsChecker.RetryOn = config.RetryOn
+	pingChecker := NewPingChecker()
+	pingChecker.RetryOn = config.RetryOn
 
 	return &Scheduler{
 		storage:     store,
 		alerter:     alerter,
 		config:      config,
+		http:        httpChecker,
+		tcp:         tcpChecker,
+		dns:         dnsChecker,
+		ping:        pingChecker,
+		inflight:    make(chan struct{}, config.MaxConcurrentChecks),
 		checks:      make(map[int64]*scheduledCheck),
 		stopChan:    make(chan struct{}),
 		cleanupStop: make(chan struct{}),
+		startedAt:   time.Now(),
+		stale:       make(map[int64]bool),
+		drifted:     make(map[int64]string),
+		sslAlerted:  make(map[int64]time.Time),
+		slow:        make(map[int64]*slowState),
 	}
 }
 
+// SetEventSink registers a receiver for every check state change
+func (s *Scheduler) SetEventSink(events EventSink) {
+	s.events = events
+}
+
 func (s *Scheduler) Start() error {
-	// Load all enabled checks
+	s.startedAt = time.Now()
+
+	if err := s.loadChecks(); err != nil {
+		return err
+	}
+	s.jobsOnce.Do(s.startJobs)
+
+	fmt.Printf("Scheduler started with %d checks\n", s.GetCheckCount())
+	return nil
+}
+
+// loadChecks schedules every enabled check
+func (s *Scheduler) loadChecks() error {
 	checks, err := s.storage.ListEnabledChecks()
 	if err != nil {
 		return fmt.Errorf("loading checks: %w", err)
 	}
 
-	for _, check := range checks {
-		if err := s.scheduleCheck(check); err != nil {
+	for i, check := range checks {
+		// Check i of n starts i/n of the way through the ramp
+		var delay time.Duration
+		if s.config.StartupRamp > 0 {
+			delay = s.config.StartupRamp * time.Duration(i) / time.Duration(len(checks))
+		}
+		if err := s.scheduleCheck(check, delay); err != nil {
 			fmt.Printf("failed to schedule check %s: %v\n", check.Name, err)
 		}
 	}
+	return nil
+}
 
+// startJobs starts the jobs that run alongside the checks until Stop
+func (s *Scheduler) startJobs() {
 	// Start daily cleanup job
 	go s.runCleanupJob()
 
-	fmt.Printf("Scheduler started with %d checks\n", len(s.checks))
-	return nil
+	// Re-alert on incidents left open too long
+	go s.runEscalationJob()
+
+	// Flag checks that stop producing results
+	if s.config.StaleIntervals > 0 {
+		go s.runWatchdogJob()
+	}
+
+	// Catch edits that stray from the config file's checks
+	if len(s.config.Drift) > 0 {
+		go s.runDriftJob()
+	}
 }
 
 func (s *Scheduler) runCleanupJob() {
@@ -111,8 +227,21 @@ func (s *Scheduler) doCleanup() {
 		fmt.Printf("Aggregated results older than %d days into hourly summaries\n", retentionDays)
 	}
 
-	// Then delete the old results
-	if err := s.storage.CleanupOldResults(resultsCutoff); err != nil {
+	// Then archive or delete the old results
+	if s.config.ArchiveDays > 0 {
+		if err := s.storage.ArchiveResults(resultsCutoff); err != nil {
+			fmt.Printf("archive error: %v\n", err)
+		} else {
+			fmt.Printf("Archived results older than %d days\n", retentionDays)
+		}
+
+		archiveCutoff := time.Now().Add(-time.Duration(s.config.ArchiveDays) * 24 * time.Hour)
+		if err := s.storage.CleanupOldArchives(archiveCutoff); err != nil {
+			fmt.Printf("archive cleanup error: %v\n", err)
+		} else {
+			fmt.Printf("Cleaned up archives older than %d days\n", s.config.ArchiveDays)
+		}
+	} else if err := s.storage.CleanupOldResults(resultsCutoff); err != nil {
 		fmt.Printf("cleanup error: %v\n", err)
 	} else {
 		fmt.Printf("Cleaned up results older than %d days\n", retentionDays)
@@ -139,7 +268,7 @@ func (s *Scheduler) Stop() {
 	fmt.Println("Scheduler stopped")
 }
 
-func (s *Scheduler) scheduleCheck(check *storage.Check) error {
+func (s *Scheduler) scheduleCheck(check *storage.Check, delay time.Duration) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -152,11 +281,25 @@ func (s *Scheduler) scheduleCheck(check *storage.Check) error {
 	if interval < time.Second {
 		interval = time.Minute // Minimum 1 second, default 1 minute
 	}
+	if check.Type == TypeHeartbeat && interval > heartbeatSweep {
+		interval = heartbeatSweep
+	}
 
 	sc := &scheduledCheck{
-		check:  check,
-		ticker: time.NewTicker(interval),
-		stop:   make(chan struct{}),
+		check:    check,
+		interval: interval,
+		stop:     make(chan struct{}),
+		delay:    delay,
+	}
+	// Heartbeat checks are swept, not run, so a schedule means nothing to them
+	if check.Cron != "" && check.Type != TypeHeartbeat {
+		schedule, err := cron.Parse(check.Cron)
+		if err != nil {
+			return fmt.Errorf("check %s: %w", check.Name, err)
+		}
+		sc.cron = schedule
+	} else {
+		sc.ticker = time.NewTicker(interval)
 	}
 
 	s.checks[check.ID] = sc
@@ -170,19 +313,37 @@ func (s *Scheduler) scheduleCheck(check *storage.Check) error {
 func (s *Scheduler) runCheck(sc *scheduledCheck) {
 	defer s.wg.Done()
 
-	checker := NewHTTPChecker()
+	if sc.cron != nil {
+		s.runCron(sc)
+		return
+	}
 
-	// Add small jitter to prevent thundering herd
-	jitter := time.Duration(rand.Intn(1000)) * time.Millisecond
-	time.Sleep(jitter)
+	if sc.delay > 0 {
+		// Wait for this check's slot on the startup ramp, then restart the
+		// ticker so later runs keep the same spacing
+		select {
+		case <-time.After(sc.delay):
+			sc.ticker.Reset(sc.interval)
+		case <-sc.stop:
+			sc.ticker.Stop()
+			return
+		case <-s.stopChan:
+			sc.ticker.Stop()
+			return
+		}
+	} else {
+		// Add small jitter to prevent thundering herd
+		jitter := time.Duration(rand.Intn(1000)) * time.Millisecond
+		time.Sleep(jitter)
+	}
 
 	// Run immediately on start
-	s.executeCheck(sc.check, checker)
+	s.executeCheck(sc.check)
 
 	for {
 		select {
 		case <-sc.ticker.C:
-			s.executeCheck(sc.check, checker)
+			s.executeCheck(sc.check)
 		case <-sc.stop:
 			sc.ticker.Stop()
 			return
@@ -193,7 +354,60 @@ func (s *Scheduler) runCheck(sc *scheduledCheck) {
 	}
 }
 
-func (s *Scheduler) executeCheck(check *storage.Check, checker *HTTPChecker) {
+// ValidateCron rejects a cron expression that doesn't parse or never fires,
+// and any on a heartbeat check, which waits for pushes instead of running
+func ValidateCron(checkType, spec string) error {
+	if spec == "" {
+		return nil
+	}
+	if checkType == TypeHeartbeat {
+		return fmt.Errorf("heartbeat checks can't use cron, they wait for pushes")
+	}
+	_, err := cron.Parse(spec)
+	return err
+}
+
+// runCron runs a check at each time its cron expression gives, without the
+// startup ramp or an immediate first run: the schedule decides when it runs
+func (s *Scheduler) runCron(sc *scheduledCheck) {
+	for {
+		next := sc.cron.Next(time.Now())
+		if next.IsZero() {
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-timer.C:
+			s.executeCheck(sc.check)
+		case <-sc.stop:
+			timer.Stop()
+			return
+		case <-s.stopChan:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// execute runs a request once a slot under max_concurrent_checks is free
+func (s *Scheduler) execute(req *CheckRequest) *CheckResponse {
+	s.inflight <- struct{}{}
+	defer func() { <-s.inflight }()
+	switch req.Type {
+	case TypeTCP:
+		return s.tcp.Execute(req)
+	case TypeDNS:
+		return s.dns.Execute(req)
+	case TypePing:
+		return s.ping.Execute(req)
+	}
+	if req.BaselineURL != "" {
+		return s.http.ExecuteComparison(req)
+	}
+	return s.http.Execute(req)
+}
+
+func (s *Scheduler) executeCheck(check *storage.Check) {
 	// Reload check from storage to get latest status
 	current, err := s.storage.GetCheck(check.ID)
 	if err != nil || current == nil {
@@ -201,6 +415,21 @@ func (s *Scheduler) executeCheck(check *storage.Check, checker *HTTPChecker) {
 		return
 	}
 
+	// Paused checks keep their schedule and last status but don't run. A
+	// timed pause that has ended is cleared here, so it resumes on its own.
+	if current.IsPaused(time.Now()) {
+		return
+	}
+	if current.Paused {
+		if err := s.storage.SetCheckPaused(current.ID, false); err != nil {
+			fmt.Printf("failed to resume check %s: %v\n", current.Name, err)
+		} else {
+			fmt.Printf("check %s resumed after timed pause\n", current.Name)
+		}
+		current.Paused = false
+		current.PausedUntil = nil
+	}
+
 	// Get previous result to determine current status
 	lastResult, _ := s.storage.GetLatestResult(check.ID)
 	if lastResult != nil {
@@ -210,49 +439,132 @@ func (s *Scheduler) executeCheck(check *storage.Check, checker *HTTPChecker) {
 	} else {
 		current.Status = "pending"
 	}
+	storage.SettleStatus(s.storage, current)
+	if s.config.AlertOnFirstCheck {
+		current.AlertOnFirstCheck = true
+	}
 
-	// Build the check request
-	req := &CheckRequest{
-		URL:            current.URL,
-		Timeout:        time.Duration(current.TimeoutSecs) * time.Second,
-		ExpectedStatus: current.ExpectedStatus,
+	// Heartbeat checks only wait for pushes
+	if current.Type == TypeHeartbeat {
+		s.sweepHeartbeat(current, lastResult, time.Now())
+		return
 	}
 
+	// Build the check request
+	req := s.buildRequest(current)
+
+	// The average slow alerts compare against, taken before this run's
+	// results count towards it
+	average := s.slowBaseline(current)
+
 	// If check has regions configured, execute once per region
 	if len(current.Regions) > 0 {
 		for _, region := range current.Regions {
-			response := checker.Execute(req)
-			if err := ProcessResultWithOptions(s.storage, s.alerter, current, response, s.config.ConsecutiveFailures, region, s.config.MultiRegionAlertThreshold); err != nil {
+			response := s.execute(req)
+			if err := ProcessResultWithOptions(s.storage, s.alerter, current, response, s.failureThreshold(current), region, s.config.MultiRegionAlertThreshold, s.events); err != nil {
 				fmt.Printf("error processing result for %s (region %s): %v\n", current.Name, region, err)
 			}
 			s.handleSSLAlert(current, response)
+			s.handleSlowAlert(current, response, average)
 		}
 	} else {
 		// No regions configured, execute once without region tag
-		response := checker.Execute(req)
-		if err := ProcessResult(s.storage, s.alerter, current, response, s.config.ConsecutiveFailures); err != nil {
+		response := s.execute(req)
+		if err := ProcessResultWithOptions(s.storage, s.alerter, current, response, s.failureThreshold(current), "", 0, s.events); err != nil {
 			fmt.Printf("error processing result for %s: %v\n", current.Name, err)
 		}
 		s.handleSSLAlert(current, response)
+		s.handleSlowAlert(current, response, average)
+	}
+}
+
+// failureThreshold is how many failures in a row open an incident for a
+// check: its own consecutive_failures, or the global setting
+func (s *Scheduler) failureThreshold(check *storage.Check) int {
+	if check.ConsecutiveFailures > 0 {
+		return check.ConsecutiveFailures
 	}
+	return s.config.ConsecutiveFailures
 }
 
+// buildRequest creates the check request for a stored check
+func (s *Scheduler) buildRequest(check *storage.Check) *CheckRequest {
+	req := &CheckRequest{
+		Type:                    check.Type,
+		URL:                     check.URL,
+		Timeout:                 CheckTimeout(check.Type, check.TimeoutSecs),
+		Method:                  check.Method,
+		Headers:                 check.Headers,
+		Body:                    check.RequestBody,
+		QueryParams:             check.QueryParams,
+		ExpectedStatus:          check.SuccessStatus(),
+		ExpectedStatuses:        check.SuccessStatuses(),
+		Streaming:               check.Streaming,
+		JSONAssertions:          check.JSONAssertions,
+		NoFollowRedirects:       check.NoFollowRedirects,
+		BypassCache:             check.BypassCache,
+		ExpectedLocation:        check.ExpectedLocation,
+		ExpectedCertFingerprint: check.ExpectedCertFingerprint,
+		BaselineURL:             check.BaselineURL,
+		CompareFields:           check.CompareFields,
+		LatencyTolerancePct:     check.LatencyTolerancePct,
+		BodyContains:            check.BodyContains,
+		BodyNotContains:         check.BodyNotContains,
+		AssertionType:           check.AssertionType,
+		Assertions:              check.Assertions,
+		DualStack:               check.DualStack,
+		InsecureSkipVerify:      check.InsecureSkipVerify,
+		RecordType:              check.RecordType,
+		ExpectedAnswer:          check.ExpectedAnswer,
+		ExpectedTrailer:         check.ExpectedTrailer,
+		MinBodyBytes:            check.MinBodyBytes,
+		ExpectedContentType:     check.ExpectedContentType,
+		ExpectedRedirects:       check.ExpectedRedirects,
+	}
+
+	if golden, err := s.storage.GetGoldenSnapshot(check.ID); err == nil && golden != nil {
+		req.GoldenBody = golden.Body
+	}
+
+	return req
+}
+
+// handleSSLAlert warns once per certificate that it expires within
+// SSLExpiryDays, rather than on every run. A renewed certificate has a new
+// expiry, so it is warned about afresh when its own time comes. What was
+// sent is kept in memory, so a restart warns once more.
 func (s *Scheduler) handleSSLAlert(check *storage.Check, response *CheckResponse) {
-	if s.config.SSLExpiryDays > 0 && response.SSLExpiresAt != nil {
-		if response.SSLDaysLeft <= s.config.SSLExpiryDays {
-			if sslAlerter, ok := s.alerter.(interface {
-				SendSSLExpiryAlert(*storage.Check, int, time.Time) error
-			}); ok {
-				if err := sslAlerter.SendSSLExpiryAlert(check, response.SSLDaysLeft, *response.SSLExpiresAt); err != nil {
-					fmt.Printf("error sending SSL expiry alert for %s: %v\n", check.Name, err)
-				}
-			}
-		}
+	if s.config.SSLExpiryDays <= 0 || response.SSLExpiresAt == nil || response.SSLDaysLeft > s.config.SSLExpiryDays {
+		return
+	}
+	sslAlerter, ok := s.alerter.(interface {
+		SendSSLExpiryAlert(*storage.Check, int, time.Time) error
+	})
+	if !ok {
+		return
+	}
+
+	expiresAt := *response.SSLExpiresAt
+	s.sslMu.Lock()
+	if s.sslAlerted[check.ID].Equal(expiresAt) {
+		s.sslMu.Unlock()
+		return
+	}
+	s.sslAlerted[check.ID] = expiresAt
+	s.sslMu.Unlock()
+
+	fmt.Printf("SSL certificate for %s expires in %d days\n", check.Name, response.SSLDaysLeft)
+	if err := sslAlerter.SendSSLExpiryAlert(check, response.SSLDaysLeft, expiresAt); err != nil {
+		fmt.Printf("error sending SSL expiry alert for %s: %v\n", check.Name, err)
+		// Try again on the next run
+		s.sslMu.Lock()
+		delete(s.sslAlerted, check.ID)
+		s.sslMu.Unlock()
 	}
 }
 
 func (s *Scheduler) AddCheck(check *storage.Check) error {
-	return s.scheduleCheck(check)
+	return s.scheduleCheck(check, 0)
 }
 
 func (s *Scheduler) RemoveCheck(checkID int64) {
@@ -271,7 +583,7 @@ func (s *Scheduler) UpdateCheck(check *storage.Check) error {
 
 	// Re-schedule with new settings
 	if check.Enabled {
-		return s.scheduleCheck(check)
+		return s.scheduleCheck(check, 0)
 	}
 	return nil
 }
@@ -284,6 +596,9 @@ func (s *Scheduler) TriggerCheck(checkID int64) (*CheckResponse, error) {
 	if check == nil {
 		return nil, fmt.Errorf("check not found")
 	}
+	if check.Type == TypeHeartbeat {
+		return nil, fmt.Errorf("heartbeat checks can't be triggered; they record pushed results")
+	}
 
 	// Get current status
 	lastResult, _ := s.storage.GetLatestResult(check.ID)
@@ -292,35 +607,75 @@ func (s *Scheduler) TriggerCheck(checkID int64) (*CheckResponse, error) {
 	} else {
 		check.Status = "pending"
 	}
-
-	checker := NewHTTPChecker()
-	req := &CheckRequest{
-		URL:            check.URL,
-		Timeout:        time.Duration(check.TimeoutSecs) * time.Second,
-		ExpectedStatus: check.ExpectedStatus,
+	storage.SettleStatus(s.storage, check)
+	if s.config.AlertOnFirstCheck {
+		check.AlertOnFirstCheck = true
 	}
 
+	req := s.buildRequest(check)
+
 	var lastResponse *CheckResponse
 
 	// If check has regions configured, execute once per region
 	if len(check.Regions) > 0 {
 		for _, region := range check.Regions {
-			response := checker.Execute(req)
-			if err := ProcessResultWithOptions(s.storage, s.alerter, check, response, s.config.ConsecutiveFailures, region, s.config.MultiRegionAlertThreshold); err != nil {
+			response := s.execute(req)
+			if err := ProcessResultWithOptions(s.storage, s.alerter, check, response, s.failureThreshold(check), region, s.config.MultiRegionAlertThreshold, s.events); err != nil {
 				return nil, fmt.Errorf("processing result for region %s: %w", region, err)
 			}
 			lastResponse = response
 		}
 	} else {
-		lastResponse = checker.Execute(req)
-		if err := ProcessResult(s.storage, s.alerter, check, lastResponse, s.config.ConsecutiveFailures); err != nil {
+		lastResponse = s.execute(req)
+		if err := ProcessResultWithOptions(s.storage, s.alerter, check, lastResponse, s.failureThreshold(check), "", 0, s.events); err != nil {
 			return nil, fmt.Errorf("processing result: %w", err)
 		}
 	}
+	s.handleSSLAlert(check, lastResponse)
 
 	return lastResponse, nil
 }
 
+// RecordGolden fetches the check's URL and stores the response body as the
+// golden snapshot that subsequent responses are compared against
+func (s *Scheduler) RecordGolden(checkID int64) (*storage.GoldenSnapshot, error) {
+	check, err := s.storage.GetCheck(checkID)
+	if err != nil {
+		return nil, fmt.Errorf("getting check: %w", err)
+	}
+	if check == nil {
+		return nil, fmt.Errorf("check not found")
+	}
+	if check.Streaming {
+		return nil, fmt.Errorf("golden snapshots are not supported for streaming checks")
+	}
+	if check.Type == TypeTCP || check.Type == TypeDNS || check.Type == TypePing || check.Type == TypeHeartbeat {
+		return nil, fmt.Errorf("golden snapshots are not supported for %s checks", check.Type)
+	}
+
+	req := s.buildRequest(check)
+	req.GoldenBody = ""
+	req.CaptureBody = true
+
+	response := s.execute(req)
+	if !response.IsSuccess(req.ExpectedStatus, req.ExpectedStatuses) {
+		if response.Error != nil {
+			return nil, fmt.Errorf("recording golden snapshot: %w", response.Error)
+		}
+		return nil, fmt.Errorf("recording golden snapshot: unexpected status %d", response.StatusCode)
+	}
+
+	snapshot := &storage.GoldenSnapshot{
+		CheckID: check.ID,
+		Body:    string(response.Body),
+	}
+	if err := s.storage.SaveGoldenSnapshot(snapshot); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
 func (s *Scheduler) ReloadChecks() error {
 	// Stop all current checks
 	s.mu.Lock()
@@ -333,8 +688,8 @@ func (s *Scheduler) ReloadChecks() error {
 	// Wait for all goroutines to stop
 	s.wg.Wait()
 
-	// Reload
-	return s.Start()
+	// Reload the checks; the background jobs carry on as they were
+	return s.loadChecks()
 }
 
 func (s *Scheduler) GetCheckCount() int {
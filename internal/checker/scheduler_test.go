@@ -1,10 +1,11 @@
 package checker
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/httptest"
-	"os"
-	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -12,10 +13,7 @@ import (
 )
 
 func setupSchedulerTest(t *testing.T) (storage.Storage, *httptest.Server) {
-	tmpDir := t.TempDir()
-	dbPath := filepath.Join(tmpDir, "test.db")
-
-	store, err := storage.NewSQLiteStorage(dbPath)
+	store, err := storage.NewMemoryStorage()
 	if err != nil {
 		t.Fatalf("failed to create storage: %v", err)
 	}
@@ -27,7 +25,6 @@ func setupSchedulerTest(t *testing.T) (storage.Storage, *httptest.Server) {
 	t.Cleanup(func() {
 		store.Close()
 		server.Close()
-		os.Remove(dbPath)
 	})
 
 	return store, server
@@ -74,6 +71,106 @@ func TestSchedulerStartStop(t *testing.T) {
 	}
 }
 
+func TestSchedulerStartupRamp(t *testing.T) {
+	store, server := setupSchedulerTest(t)
+
+	for i := 0; i < 3; i++ {
+		check := &storage.Check{
+			Name:           fmt.Sprintf("Check %d", i),
+			URL:            server.URL,
+			IntervalSecs:   3600,
+			TimeoutSecs:    5,
+			ExpectedStatus: 200,
+			Enabled:        true,
+		}
+		if err := store.CreateCheck(check); err != nil {
+			t.Fatalf("failed to create check: %v", err)
+		}
+	}
+
+	scheduler := NewScheduler(store, nil, SchedulerConfig{ConsecutiveFailures: 2, StartupRamp: time.Minute})
+	if err := scheduler.Start(); err != nil {
+		t.Fatalf("failed to start scheduler: %v", err)
+	}
+
+	scheduler.mu.RLock()
+	delays := make(map[time.Duration]bool)
+	for _, sc := range scheduler.checks {
+		delays[sc.delay] = true
+	}
+	scheduler.mu.RUnlock()
+
+	for _, want := range []time.Duration{0, 20 * time.Second, 40 * time.Second} {
+		if !delays[want] {
+			t.Errorf("expected a check starting after %v, got %v", want, delays)
+		}
+	}
+
+	// Checks still waiting for their slot must not hold up shutdown
+	done := make(chan struct{})
+	go func() {
+		scheduler.Stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Stop to return while checks wait on the ramp")
+	}
+}
+
+func TestSchedulerCronCheck(t *testing.T) {
+	store, server := setupSchedulerTest(t)
+
+	check := &storage.Check{
+		Name:           "Business Hours",
+		URL:            server.URL,
+		IntervalSecs:   1,
+		Cron:           "@yearly",
+		TimeoutSecs:    5,
+		ExpectedStatus: 200,
+		Enabled:        true,
+	}
+	if err := store.CreateCheck(check); err != nil {
+		t.Fatalf("failed to create check: %v", err)
+	}
+
+	scheduler := NewScheduler(store, nil, SchedulerConfig{ConsecutiveFailures: 2})
+	if err := scheduler.Start(); err != nil {
+		t.Fatalf("failed to start scheduler: %v", err)
+	}
+
+	scheduler.mu.RLock()
+	sc := scheduler.checks[check.ID]
+	scheduler.mu.RUnlock()
+	if sc == nil || sc.cron == nil || sc.ticker != nil {
+		t.Fatal("expected the check scheduled by its cron expression, not a ticker")
+	}
+
+	// The interval is ignored and nothing runs before the next scheduled time
+	time.Sleep(1500 * time.Millisecond)
+	scheduler.Stop()
+
+	if results, _ := store.GetResults(check.ID, 10, 0); len(results) != 0 {
+		t.Errorf("expected no runs before the scheduled time, got %d", len(results))
+	}
+}
+
+func TestValidateCron(t *testing.T) {
+	if err := ValidateCron(TypeHTTP, ""); err != nil {
+		t.Errorf("expected no cron to be valid, got %v", err)
+	}
+	if err := ValidateCron(TypeHTTP, "*/5 * * * *"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ValidateCron(TypeHTTP, "every hour"); err == nil {
+		t.Error("expected an error for an invalid expression")
+	}
+	if err := ValidateCron(TypeHeartbeat, "@daily"); err == nil {
+		t.Error("expected an error for a heartbeat check")
+	}
+}
+
 func TestSchedulerDisabledCheck(t *testing.T) {
 	store, server := setupSchedulerTest(t)
 
@@ -103,6 +200,93 @@ func TestSchedulerDisabledCheck(t *testing.T) {
 	scheduler.Stop()
 }
 
+func TestSchedulerPausedCheck(t *testing.T) {
+	store, server := setupSchedulerTest(t)
+
+	check := &storage.Check{
+		Name:           "Paused Check",
+		URL:            server.URL,
+		IntervalSecs:   1,
+		TimeoutSecs:    5,
+		ExpectedStatus: 200,
+		Enabled:        true,
+		Paused:         true,
+	}
+	if err := store.CreateCheck(check); err != nil {
+		t.Fatalf("failed to create check: %v", err)
+	}
+
+	scheduler := NewScheduler(store, nil, SchedulerConfig{ConsecutiveFailures: 2})
+
+	if err := scheduler.Start(); err != nil {
+		t.Fatalf("failed to start scheduler: %v", err)
+	}
+	defer scheduler.Stop()
+
+	// Paused checks stay registered
+	if scheduler.GetCheckCount() != 1 {
+		t.Errorf("expected paused check to stay scheduled, got %d checks", scheduler.GetCheckCount())
+	}
+
+	time.Sleep(1500 * time.Millisecond)
+
+	results, _ := store.GetResults(check.ID, 10, 0)
+	if len(results) != 0 {
+		t.Errorf("expected no results while paused, got %d", len(results))
+	}
+
+	// Resuming takes effect on the next tick without re-adding
+	if err := store.SetCheckPaused(check.ID, false); err != nil {
+		t.Fatalf("failed to resume check: %v", err)
+	}
+
+	time.Sleep(1500 * time.Millisecond)
+
+	results, _ = store.GetResults(check.ID, 10, 0)
+	if len(results) == 0 {
+		t.Error("expected results after resuming")
+	}
+}
+
+func TestSchedulerTimedPauseResumes(t *testing.T) {
+	store, server := setupSchedulerTest(t)
+
+	check := &storage.Check{
+		Name:           "Timed Pause",
+		URL:            server.URL,
+		IntervalSecs:   1,
+		TimeoutSecs:    5,
+		ExpectedStatus: 200,
+		Enabled:        true,
+	}
+	if err := store.CreateCheck(check); err != nil {
+		t.Fatalf("failed to create check: %v", err)
+	}
+	if err := store.PauseCheckUntil(check.ID, time.Now().Add(1200*time.Millisecond)); err != nil {
+		t.Fatalf("failed to pause check: %v", err)
+	}
+
+	scheduler := NewScheduler(store, nil, SchedulerConfig{ConsecutiveFailures: 2})
+	if err := scheduler.Start(); err != nil {
+		t.Fatalf("failed to start scheduler: %v", err)
+	}
+	defer scheduler.Stop()
+
+	time.Sleep(800 * time.Millisecond)
+	if results, _ := store.GetResults(check.ID, 10, 0); len(results) != 0 {
+		t.Errorf("expected no results before the pause ends, got %d", len(results))
+	}
+
+	time.Sleep(1700 * time.Millisecond)
+	if results, _ := store.GetResults(check.ID, 10, 0); len(results) == 0 {
+		t.Error("expected results once the pause ended")
+	}
+	got, _ := store.GetCheck(check.ID)
+	if got.Paused || got.PausedUntil != nil {
+		t.Errorf("expected the check resumed in storage, got paused=%v until=%v", got.Paused, got.PausedUntil)
+	}
+}
+
 func TestSchedulerAddRemoveCheck(t *testing.T) {
 	store, server := setupSchedulerTest(t)
 
@@ -189,6 +373,202 @@ func TestSchedulerTriggerCheck(t *testing.T) {
 	}
 }
 
+func TestSchedulerPerCheckFailureThreshold(t *testing.T) {
+	store, _ := setupSchedulerTest(t)
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	alerter := &mockAlerter{}
+	scheduler := NewScheduler(store, alerter, SchedulerConfig{ConsecutiveFailures: 5})
+
+	newCheck := func(name string, failures int) *storage.Check {
+		t.Helper()
+		check := &storage.Check{Name: name, URL: failing.URL, IntervalSecs: 3600, TimeoutSecs: 5, ExpectedStatus: 200, Enabled: true, ConsecutiveFailures: failures}
+		if err := store.CreateCheck(check); err != nil {
+			t.Fatalf("failed to create check: %v", err)
+		}
+		store.SaveResult(&storage.CheckResult{CheckID: check.ID, Status: "up", StatusCode: 200, CheckedAt: time.Now().Add(-time.Minute)})
+		return check
+	}
+
+	// The global threshold of 5 holds the first failure back
+	global := newCheck("Global", 0)
+	if _, err := scheduler.TriggerCheck(global.ID); err != nil {
+		t.Fatalf("failed to trigger check: %v", err)
+	}
+	if incident, _ := store.GetActiveIncident(global.ID); incident != nil || alerter.downAlerts != 0 {
+		t.Fatal("expected no incident below the global threshold")
+	}
+
+	// A check's own threshold of 1 opens one right away
+	critical := newCheck("Critical", 1)
+	if _, err := scheduler.TriggerCheck(critical.ID); err != nil {
+		t.Fatalf("failed to trigger check: %v", err)
+	}
+	if incident, _ := store.GetActiveIncident(critical.ID); incident == nil || alerter.downAlerts != 1 {
+		t.Errorf("expected an incident under the check's own threshold, got %d alerts", alerter.downAlerts)
+	}
+
+	// A threshold of 3 waits out two failures, opens one incident on the
+	// third and no more after it
+	flaky := newCheck("Flaky", 3)
+	for i := 1; i <= 5; i++ {
+		if _, err := scheduler.TriggerCheck(flaky.ID); err != nil {
+			t.Fatalf("failed to trigger check: %v", err)
+		}
+		incident, _ := store.GetActiveIncident(flaky.ID)
+		if i < 3 && incident != nil {
+			t.Fatalf("expected no incident after %d failures", i)
+		}
+		if i >= 3 && incident == nil {
+			t.Fatalf("expected an incident after %d failures", i)
+		}
+	}
+	if alerter.downAlerts != 2 {
+		t.Errorf("expected one down alert for the flaky check, got %d", alerter.downAlerts-1)
+	}
+	incidents, _ := store.ListIncidentsForCheck(flaky.ID, 10)
+	if len(incidents) != 1 {
+		t.Errorf("expected exactly one incident, got %d", len(incidents))
+	}
+}
+
+type mockSSLAlerter struct {
+	mockAlerter
+	sslAlerts []int
+}
+
+func (m *mockSSLAlerter) SendSSLExpiryAlert(check *storage.Check, daysLeft int, expiresAt time.Time) error {
+	m.sslAlerts = append(m.sslAlerts, daysLeft)
+	return nil
+}
+
+func TestSchedulerSSLExpiryAlertOncePerCert(t *testing.T) {
+	store, _ := setupSchedulerTest(t)
+	alerter := &mockSSLAlerter{}
+	scheduler := NewScheduler(store, alerter, SchedulerConfig{SSLExpiryDays: 14})
+	check := &storage.Check{ID: 1, Name: "API"}
+
+	response := func(expiresAt time.Time) *CheckResponse {
+		return &CheckResponse{StatusCode: 200, SSLExpiresAt: &expiresAt, SSLDaysLeft: int(time.Until(expiresAt).Hours() / 24)}
+	}
+
+	// Far from expiry: nothing
+	scheduler.handleSSLAlert(check, response(time.Now().Add(60*24*time.Hour)))
+	if len(alerter.sslAlerts) != 0 {
+		t.Fatalf("expected no alert outside the threshold, got %v", alerter.sslAlerts)
+	}
+
+	// Near expiry: one alert however many runs see it
+	expiring := time.Now().Add(10*24*time.Hour + time.Hour)
+	for range 3 {
+		scheduler.handleSSLAlert(check, response(expiring))
+	}
+	if len(alerter.sslAlerts) != 1 || alerter.sslAlerts[0] != 10 {
+		t.Fatalf("expected one alert at 10 days, got %v", alerter.sslAlerts)
+	}
+
+	// A renewed certificate that also nears expiry is a new window
+	scheduler.handleSSLAlert(check, response(expiring.Add(time.Hour)))
+	if len(alerter.sslAlerts) != 2 {
+		t.Errorf("expected a new certificate to alert again, got %v", alerter.sslAlerts)
+	}
+}
+
+func TestSchedulerTriggerComparisonCheck(t *testing.T) {
+	store, server := setupSchedulerTest(t)
+
+	canary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer canary.Close()
+
+	check := &storage.Check{Name: "Canary", URL: canary.URL, BaselineURL: server.URL, CompareFields: []string{"status"}, IntervalSecs: 3600, TimeoutSecs: 5, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(check)
+	if stored, _ := store.GetCheck(check.ID); stored.BaselineURL != server.URL || len(stored.CompareFields) != 1 {
+		t.Fatalf("expected comparison settings to be stored, got %+v", stored)
+	}
+
+	scheduler := NewScheduler(store, nil, SchedulerConfig{ConsecutiveFailures: 2})
+	if _, err := scheduler.TriggerCheck(check.ID); err != nil {
+		t.Fatalf("failed to trigger check: %v", err)
+	}
+
+	results, _ := store.GetResultDetails(check.ID, 1)
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	result := results[0]
+	if result.Status != "down" || result.StatusCode != 500 {
+		t.Errorf("expected canary down with 500, got %s %d", result.Status, result.StatusCode)
+	}
+	if result.BaselineStatusCode != 200 {
+		t.Errorf("expected baseline status 200 stored, got %d", result.BaselineStatusCode)
+	}
+}
+
+func TestSchedulerTriggerTCPCheck(t *testing.T) {
+	store, server := setupSchedulerTest(t)
+
+	addr := server.Listener.Addr().String()
+	check := &storage.Check{Name: "Port", Type: storage.CheckTypeTCP, URL: addr, IntervalSecs: 3600, TimeoutSecs: 5, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(check)
+	if stored, _ := store.GetCheck(check.ID); stored.Type != storage.CheckTypeTCP {
+		t.Fatalf("expected type tcp to be stored, got %q", stored.Type)
+	}
+
+	scheduler := NewScheduler(store, nil, SchedulerConfig{ConsecutiveFailures: 2})
+	if _, err := scheduler.TriggerCheck(check.ID); err != nil {
+		t.Fatalf("failed to trigger check: %v", err)
+	}
+
+	latest, _ := store.GetLatestResult(check.ID)
+	if latest == nil || latest.Status != "up" || latest.StatusCode != 0 {
+		t.Errorf("expected tcp check up with no status code, got %+v", latest)
+	}
+}
+
+func TestSchedulerTriggerDNSCheck(t *testing.T) {
+	store, _ := setupSchedulerTest(t)
+
+	check := &storage.Check{Name: "Resolver", Type: storage.CheckTypeDNS, URL: "localhost", ExpectedAnswer: "127.0.0.1", IntervalSecs: 3600, TimeoutSecs: 2, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(check)
+
+	scheduler := NewScheduler(store, nil, SchedulerConfig{ConsecutiveFailures: 2})
+	if _, err := scheduler.TriggerCheck(check.ID); err != nil {
+		t.Fatalf("failed to trigger check: %v", err)
+	}
+
+	latest, _ := store.GetLatestResult(check.ID)
+	if latest == nil || latest.Status != "up" || latest.StatusCode != 0 {
+		t.Errorf("expected dns check up with no status code, got %+v", latest)
+	}
+}
+
+func TestSchedulerTriggerCheckInvalidatesStats(t *testing.T) {
+	store, server := setupSchedulerTest(t)
+	cache := storage.NewStatsCache(store, time.Hour)
+
+	check := &storage.Check{Name: "Cached", URL: server.URL, IntervalSecs: 3600, TimeoutSecs: 5, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(check)
+	store.SaveResult(&storage.CheckResult{CheckID: check.ID, Status: "down"})
+
+	if stats, _ := cache.GetStats(check.ID); stats.UptimePercent24h != 0 {
+		t.Fatalf("expected 0%% uptime before trigger, got %v", stats.UptimePercent24h)
+	}
+
+	scheduler := NewScheduler(cache, nil, SchedulerConfig{ConsecutiveFailures: 2})
+	if _, err := scheduler.TriggerCheck(check.ID); err != nil {
+		t.Fatalf("failed to trigger check: %v", err)
+	}
+
+	if stats, _ := cache.GetStats(check.ID); stats.UptimePercent24h != 50 {
+		t.Errorf("expected triggered result to refresh stats to 50%%, got %v", stats.UptimePercent24h)
+	}
+}
+
 func TestSchedulerTriggerCheckNotFound(t *testing.T) {
 	store, _ := setupSchedulerTest(t)
 
@@ -200,6 +580,96 @@ func TestSchedulerTriggerCheckNotFound(t *testing.T) {
 	}
 }
 
+func TestSchedulerMaxConcurrentChecks(t *testing.T) {
+	store, _ := setupSchedulerTest(t)
+
+	var mu sync.Mutex
+	running, peak := 0, 0
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		running++
+		if running > peak {
+			peak = running
+		}
+		mu.Unlock()
+
+		time.Sleep(50 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer slow.Close()
+
+	scheduler := NewScheduler(store, nil, SchedulerConfig{ConsecutiveFailures: 2, MaxConcurrentChecks: 2})
+
+	var ids []int64
+	for i := 0; i < 5; i++ {
+		check := &storage.Check{Name: fmt.Sprintf("Limited %d", i), URL: fmt.Sprintf("%s/%d", slow.URL, i), IntervalSecs: 60, TimeoutSecs: 5, ExpectedStatus: 200, Enabled: true}
+		store.CreateCheck(check)
+		ids = append(ids, check.ID)
+	}
+
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id int64) {
+			defer wg.Done()
+			scheduler.TriggerCheck(id)
+		}(id)
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("expected at most 2 checks in flight, got %d", peak)
+	}
+	if peak < 1 {
+		t.Error("expected checks to run")
+	}
+}
+
+// countingStore counts the startup cleanup, which each cleanup job runs once
+type countingStore struct {
+	storage.Storage
+	aggregations atomic.Int32
+}
+
+func (c *countingStore) AggregateResults(olderThan time.Time) error {
+	c.aggregations.Add(1)
+	return c.Storage.AggregateResults(olderThan)
+}
+
+func TestSchedulerReloadKeepsJobs(t *testing.T) {
+	store, server := setupSchedulerTest(t)
+	counting := &countingStore{Storage: store}
+
+	check := &storage.Check{Name: "Reloaded", URL: server.URL, IntervalSecs: 3600, TimeoutSecs: 5, ExpectedStatus: 200, Enabled: true}
+	if err := store.CreateCheck(check); err != nil {
+		t.Fatalf("failed to create check: %v", err)
+	}
+
+	scheduler := NewScheduler(counting, nil, SchedulerConfig{ConsecutiveFailures: 2})
+	if err := scheduler.Start(); err != nil {
+		t.Fatalf("failed to start scheduler: %v", err)
+	}
+	defer scheduler.Stop()
+
+	for i := 0; i < 2; i++ {
+		if err := scheduler.ReloadChecks(); err != nil {
+			t.Fatalf("failed to reload checks: %v", err)
+		}
+	}
+	if scheduler.GetCheckCount() != 1 {
+		t.Errorf("expected 1 check after reloading, got %d", scheduler.GetCheckCount())
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	if n := counting.aggregations.Load(); n != 1 {
+		t.Errorf("expected the background jobs started once, got %d cleanup jobs", n)
+	}
+}
+
 func TestSchedulerUpdateCheck(t *testing.T) {
 	store, server := setupSchedulerTest(t)
 
@@ -0,0 +1,98 @@
+package checker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+// slowMinResults is how many results a check needs before its average is
+// trusted; a new check's first few runs are all the history there is
+const slowMinResults = 10
+
+// SlowAlerter is implemented by alerters that can warn about a check
+// answering far slower than usual
+type SlowAlerter interface {
+	SendSlowAlert(check *storage.Check, responseMs, averageMs int) error
+}
+
+// slowState tracks one check's slow spells: whether it's in one, and when it
+// was last alerted about
+type slowState struct {
+	active bool
+	sentAt time.Time
+}
+
+// slowMultiplier is the check's own multiplier, or the global one
+func (s *Scheduler) slowMultiplier(check *storage.Check) float64 {
+	if check.SlowMultiplier > 0 {
+		return check.SlowMultiplier
+	}
+	return s.config.SlowMultiplier
+}
+
+// slowBaseline returns the check's 24h average response time, or 0 when
+// slow alerts are off for it or there is no average yet
+func (s *Scheduler) slowBaseline(check *storage.Check) int {
+	if s.slowMultiplier(check) <= 0 {
+		return 0
+	}
+	if _, ok := s.alerter.(SlowAlerter); !ok {
+		return 0
+	}
+	stats, err := s.storage.GetStats(check.ID)
+	if err != nil || stats == nil {
+		return 0
+	}
+	return stats.AvgResponseMs24h
+}
+
+// handleSlowAlert sends a slow alert when an up response takes the check's
+// multiplier times average or longer. A slow spell alerts once, when it
+// starts, and spells closer together than SlowCooldown share one alert.
+func (s *Scheduler) handleSlowAlert(check *storage.Check, response *CheckResponse, average int) {
+	if average <= 0 || response.Error != nil || response.ResponseTimeMs <= 0 {
+		return
+	}
+	slowAlerter, ok := s.alerter.(SlowAlerter)
+	if !ok {
+		return
+	}
+
+	slow := float64(response.ResponseTimeMs) >= s.slowMultiplier(check)*float64(average)
+	if slow {
+		if n, err := s.storage.CountResults(check.ID); err != nil || n < slowMinResults {
+			return
+		}
+	}
+
+	s.slowMu.Lock()
+	state := s.slow[check.ID]
+	if state == nil {
+		state = &slowState{}
+		s.slow[check.ID] = state
+	}
+	if !slow || state.active {
+		state.active = slow
+		s.slowMu.Unlock()
+		return
+	}
+	state.active = true
+	if !state.sentAt.IsZero() && time.Since(state.sentAt) < s.config.SlowCooldown {
+		s.slowMu.Unlock()
+		return
+	}
+	state.sentAt = time.Now()
+	s.slowMu.Unlock()
+
+	fmt.Printf("check %s is slow: %dms against a %dms average\n", check.Name, response.ResponseTimeMs, average)
+	if err := slowAlerter.SendSlowAlert(check, response.ResponseTimeMs, average); err != nil {
+		fmt.Printf("error sending slow alert for %s: %v\n", check.Name, err)
+		// Try again on the next slow run
+		s.slowMu.Lock()
+		state.active = false
+		state.sentAt = time.Time{}
+		s.slowMu.Unlock()
+	}
+}
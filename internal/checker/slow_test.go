@@ -0,0 +1,92 @@
+package checker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+type mockSlowAlerter struct {
+	mockAlerter
+	slowAlerts []int
+}
+
+func (m *mockSlowAlerter) SendSlowAlert(check *storage.Check, responseMs, averageMs int) error {
+	m.slowAlerts = append(m.slowAlerts, responseMs)
+	return nil
+}
+
+func TestSchedulerSlowAlert(t *testing.T) {
+	store, _ := setupSchedulerTest(t)
+	alerter := &mockSlowAlerter{}
+	scheduler := NewScheduler(store, alerter, SchedulerConfig{SlowMultiplier: 5, SlowCooldown: time.Hour})
+
+	check := &storage.Check{Name: "API", URL: "https://api.example.com", IntervalSecs: 60, TimeoutSecs: 5, ExpectedStatus: 200, Enabled: true}
+	if err := store.CreateCheck(check); err != nil {
+		t.Fatalf("failed to create check: %v", err)
+	}
+
+	// Too little history to trust the average
+	store.SaveResult(&storage.CheckResult{CheckID: check.ID, Status: "up", StatusCode: 200, ResponseTimeMs: 100, CheckedAt: time.Now().Add(-time.Hour)})
+	scheduler.handleSlowAlert(check, &CheckResponse{StatusCode: 200, ResponseTimeMs: 600}, scheduler.slowBaseline(check))
+	if len(alerter.slowAlerts) != 0 {
+		t.Fatalf("expected no alert on a new check, got %v", alerter.slowAlerts)
+	}
+
+	for i := 0; i < slowMinResults; i++ {
+		store.SaveResult(&storage.CheckResult{CheckID: check.ID, Status: "up", StatusCode: 200, ResponseTimeMs: 100, CheckedAt: time.Now().Add(-time.Duration(i+2) * time.Minute)})
+	}
+	average := scheduler.slowBaseline(check)
+	if average != 100 {
+		t.Fatalf("expected a 100ms average, got %d", average)
+	}
+
+	// Under the multiplier, or down: nothing
+	scheduler.handleSlowAlert(check, &CheckResponse{StatusCode: 200, ResponseTimeMs: 400}, average)
+	scheduler.handleSlowAlert(check, &CheckResponse{StatusCode: 500, ResponseTimeMs: 900, Error: errors.New("status 500")}, average)
+	if len(alerter.slowAlerts) != 0 {
+		t.Fatalf("expected no alert, got %v", alerter.slowAlerts)
+	}
+
+	// One alert for a slow spell, however many runs it lasts
+	for range 3 {
+		scheduler.handleSlowAlert(check, &CheckResponse{StatusCode: 200, ResponseTimeMs: 600}, average)
+	}
+	if len(alerter.slowAlerts) != 1 || alerter.slowAlerts[0] != 600 {
+		t.Fatalf("expected one alert at 600ms, got %v", alerter.slowAlerts)
+	}
+
+	// A new spell within the cooldown doesn't alert again
+	scheduler.handleSlowAlert(check, &CheckResponse{StatusCode: 200, ResponseTimeMs: 100}, average)
+	scheduler.handleSlowAlert(check, &CheckResponse{StatusCode: 200, ResponseTimeMs: 700}, average)
+	if len(alerter.slowAlerts) != 1 {
+		t.Fatalf("expected the cooldown to hold back a second alert, got %v", alerter.slowAlerts)
+	}
+
+	// After it, a new spell does
+	scheduler.slow[check.ID].sentAt = time.Now().Add(-2 * time.Hour)
+	scheduler.handleSlowAlert(check, &CheckResponse{StatusCode: 200, ResponseTimeMs: 100}, average)
+	scheduler.handleSlowAlert(check, &CheckResponse{StatusCode: 200, ResponseTimeMs: 800}, average)
+	if len(alerter.slowAlerts) != 2 {
+		t.Errorf("expected a second alert after the cooldown, got %v", alerter.slowAlerts)
+	}
+}
+
+func TestSlowMultiplier(t *testing.T) {
+	scheduler := NewScheduler(nil, &mockSlowAlerter{}, SchedulerConfig{SlowMultiplier: 5})
+
+	if m := scheduler.slowMultiplier(&storage.Check{}); m != 5 {
+		t.Errorf("expected the global multiplier, got %v", m)
+	}
+	if m := scheduler.slowMultiplier(&storage.Check{SlowMultiplier: 3}); m != 3 {
+		t.Errorf("expected the check's own multiplier, got %v", m)
+	}
+
+	// Off globally, no baseline is looked up
+	off := NewScheduler(nil, &mockSlowAlerter{}, SchedulerConfig{})
+	if avg := off.slowBaseline(&storage.Check{ID: 1}); avg != 0 {
+		t.Errorf("expected no baseline with slow alerts off, got %d", avg)
+	}
+}
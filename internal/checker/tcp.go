@@ -0,0 +1,102 @@
+package checker
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// TCPChecker checks raw TCP services like Postgres, Redis, or SMTP by opening
+// a connection. There is no response to inspect, so only the connect time and
+// any dial error are reported; SSL fields stay nil.
+type TCPChecker struct {
+	RetryDelay time.Duration
+	RetryOn    []string // Failure categories worth a retry; nil means DefaultRetryOn
+}
+
+func NewTCPChecker() *TCPChecker {
+	return NewTCPCheckerWithRetry(5 * time.Second)
+}
+
+func NewTCPCheckerWithRetry(retryDelay time.Duration) *TCPChecker {
+	return &TCPChecker{RetryDelay: retryDelay}
+}
+
+func (t *TCPChecker) Execute(req *CheckRequest) *CheckResponse {
+	if req.DualStack && req.Family == "" {
+		return executeDualStack(req, t.Execute)
+	}
+
+	response := t.dial(req)
+
+	// Same single retry as HTTP checks
+	if response.Error != nil && t.RetryDelay > 0 && Retryable(response, t.RetryOn) {
+		time.Sleep(t.RetryDelay)
+		response = t.dial(req)
+	}
+
+	return response
+}
+
+func (t *TCPChecker) dial(req *CheckRequest) *CheckResponse {
+	addr, err := TCPAddress(req.URL)
+	if err != nil {
+		return &CheckResponse{Error: err}
+	}
+
+	network := "tcp"
+	if req.Family != "" {
+		var ok bool
+		if network, ok = familyNetworks[req.Family]; !ok {
+			return &CheckResponse{Error: fmt.Errorf("unknown address family %q", req.Family)}
+		}
+	}
+
+	start := time.Now()
+	conn, err := net.DialTimeout(network, addr, req.Timeout)
+	response := &CheckResponse{
+		ResponseTimeMs: int(time.Since(start).Milliseconds()),
+	}
+	if err != nil {
+		response.Error = err
+		return response
+	}
+	conn.Close()
+
+	return response
+}
+
+// TCPAddress extracts host:port from a TCP check target, written either as
+// tcp://host:port or a bare host:port
+func TCPAddress(target string) (string, error) {
+	addr := strings.TrimPrefix(target, "tcp://")
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || host == "" || port == "" || strings.Contains(addr, "/") {
+		return "", fmt.Errorf("tcp check target %q must be host:port", target)
+	}
+	return addr, nil
+}
+
+// ValidateTarget checks that a check's type is one the scheduler can run and,
+// for TCP, DNS and ping checks, that its target is a host:port, hostname or
+// IP address. A heartbeat check's URL is only a label.
+func ValidateTarget(checkType, target string) error {
+	switch checkType {
+	case "", TypeHTTP:
+		return nil
+	case TypeTCP:
+		_, err := TCPAddress(target)
+		return err
+	case TypeDNS:
+		_, err := DNSHost(target)
+		return err
+	case TypePing:
+		_, err := PingHost(target)
+		return err
+	case TypeHeartbeat:
+		return nil
+	default:
+		return fmt.Errorf("invalid check type %q (use http, tcp, dns, ping, or heartbeat)", checkType)
+	}
+}
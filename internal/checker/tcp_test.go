@@ -0,0 +1,110 @@
+package checker
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestTCPCheckerSuccess(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	resp := NewTCPCheckerWithRetry(0).Execute(&CheckRequest{
+		Type:    TypeTCP,
+		URL:     listener.Addr().String(),
+		Timeout: 5 * time.Second,
+	})
+
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	if resp.ResponseTimeMs < 0 {
+		t.Error("expected non-negative response time")
+	}
+	if resp.SSLExpiresAt != nil {
+		t.Error("expected no SSL info for a tcp check")
+	}
+}
+
+func TestTCPCheckerRefused(t *testing.T) {
+	// Grab a free port, then close it so nothing is listening
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	resp := NewTCPCheckerWithRetry(0).Execute(&CheckRequest{
+		Type:    TypeTCP,
+		URL:     "tcp://" + addr,
+		Timeout: 2 * time.Second,
+	})
+
+	if resp.Error == nil {
+		t.Fatal("expected an error for a closed port")
+	}
+	if got := ClassifyCause(resp.Error.Error(), 0); got != CauseConnectionRefused {
+		t.Errorf("expected cause %s, got %s (%v)", CauseConnectionRefused, got, resp.Error)
+	}
+}
+
+func TestTCPAddress(t *testing.T) {
+	tests := []struct {
+		target  string
+		want    string
+		wantErr bool
+	}{
+		{"db.internal:5432", "db.internal:5432", false},
+		{"tcp://redis:6379", "redis:6379", false},
+		{"[::1]:25", "[::1]:25", false},
+		{"db.internal", "", true},
+		{":5432", "", true},
+		{"https://example.com", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := TCPAddress(tt.target)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("TCPAddress(%q) error = %v, wantErr %v", tt.target, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("TCPAddress(%q) = %q, want %q", tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestValidateTarget(t *testing.T) {
+	if err := ValidateTarget("", "https://example.com"); err != nil {
+		t.Errorf("expected untyped check to be valid, got %v", err)
+	}
+	if err := ValidateTarget(TypeTCP, "db.internal:5432"); err != nil {
+		t.Errorf("expected tcp host:port to be valid, got %v", err)
+	}
+	if err := ValidateTarget(TypeTCP, "https://example.com"); err == nil {
+		t.Error("expected tcp check with a URL target to be rejected")
+	}
+	if err := ValidateTarget(TypeDNS, "example.com"); err != nil {
+		t.Errorf("expected dns hostname to be valid, got %v", err)
+	}
+	if err := ValidateTarget(TypeDNS, "https://example.com"); err == nil {
+		t.Error("expected dns check with a URL target to be rejected")
+	}
+	if err := ValidateTarget(TypePing, "10.0.0.1"); err != nil {
+		t.Errorf("expected ping IP address to be valid, got %v", err)
+	}
+	if err := ValidateTarget(TypePing, "https://example.com"); err == nil {
+		t.Error("expected ping check with a URL target to be rejected")
+	}
+	if err := ValidateTarget(TypeHeartbeat, "nightly-backup"); err != nil {
+		t.Errorf("expected any heartbeat label to be valid, got %v", err)
+	}
+	if err := ValidateTarget("icmp", "example.com"); err == nil {
+		t.Error("expected unknown type to be rejected")
+	}
+}
@@ -0,0 +1,40 @@
+package checker
+
+import "time"
+
+// Check types, each with its own timeout default
+const (
+	TypeHTTP = "http"
+	TypeTCP  = "tcp"
+	TypeDNS  = "dns"
+	TypePing = "ping"
+
+	// TypeHeartbeat checks make no requests; they go down when pushes stop.
+	// Their timeout is the grace period allowed past the interval.
+	TypeHeartbeat = "heartbeat"
+)
+
+var defaultTimeouts = map[string]time.Duration{
+	TypeHTTP: 10 * time.Second,
+	TypeTCP:  5 * time.Second,
+	TypeDNS:  2 * time.Second,
+	TypePing: 5 * time.Second,
+}
+
+// DefaultTimeout returns the timeout used for a check type when the check
+// does not set one. Unknown types get the HTTP default.
+func DefaultTimeout(checkType string) time.Duration {
+	if d, ok := defaultTimeouts[checkType]; ok {
+		return d
+	}
+	return defaultTimeouts[TypeHTTP]
+}
+
+// CheckTimeout returns the configured timeout for a check of the given type,
+// falling back to the type default when timeoutSecs is not positive
+func CheckTimeout(checkType string, timeoutSecs int) time.Duration {
+	if timeoutSecs <= 0 {
+		return DefaultTimeout(checkType)
+	}
+	return time.Duration(timeoutSecs) * time.Second
+}
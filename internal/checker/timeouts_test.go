@@ -0,0 +1,36 @@
+package checker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDefaultTimeout(t *testing.T) {
+	tests := []struct {
+		checkType string
+		want      time.Duration
+	}{
+		{TypeHTTP, 10 * time.Second},
+		{TypeTCP, 5 * time.Second},
+		{TypeDNS, 2 * time.Second},
+		{"unknown", 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := DefaultTimeout(tt.checkType); got != tt.want {
+			t.Errorf("DefaultTimeout(%q) = %v, want %v", tt.checkType, got, tt.want)
+		}
+	}
+}
+
+func TestCheckTimeout(t *testing.T) {
+	if got := CheckTimeout(TypeDNS, 0); got != 2*time.Second {
+		t.Errorf("expected DNS default for unset timeout, got %v", got)
+	}
+	if got := CheckTimeout(TypeTCP, -1); got != 5*time.Second {
+		t.Errorf("expected TCP default for negative timeout, got %v", got)
+	}
+	if got := CheckTimeout(TypeDNS, 7); got != 7*time.Second {
+		t.Errorf("expected explicit timeout to win, got %v", got)
+	}
+}
@@ -0,0 +1,84 @@
+package checker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+// StaleAlerter warns when a check stops producing results. That points at
+// Sentinel's own scheduling for the check, not the target, so it is reported
+// separately from down alerts and opens no incident.
+type StaleAlerter interface {
+	SendStaleAlert(check *storage.Check, lastResult time.Time) error
+}
+
+// watchdogInterval is how often checks are scanned for missing results
+const watchdogInterval = time.Minute
+
+func (s *Scheduler) runWatchdogJob() {
+	ticker := time.NewTicker(watchdogInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.doWatchdog(time.Now())
+		case <-s.stopChan:
+			return
+		}
+	}
+}
+
+// doWatchdog flags enabled checks whose last result is older than their
+// stale window, alerting once when a check goes stale. Results from before
+// the scheduler started, or before the check was last edited, don't count
+// against it since it was never scheduled to run then.
+func (s *Scheduler) doWatchdog(now time.Time) {
+	checks, err := s.storage.ListEnabledChecks()
+	if err != nil {
+		fmt.Printf("watchdog scan error: %v\n", err)
+		return
+	}
+
+	for _, check := range checks {
+		if check.IsPaused(now) || check.Type == TypeHeartbeat {
+			continue
+		}
+
+		since := s.startedAt
+		if check.UpdatedAt.After(since) {
+			since = check.UpdatedAt
+		}
+		var lastResult time.Time
+		if result, _ := s.storage.GetLatestResult(check.ID); result != nil {
+			lastResult = result.CheckedAt
+			if lastResult.After(since) {
+				since = lastResult
+			}
+		}
+
+		stale := now.After(check.StaleAt(since, s.config.StaleIntervals))
+
+		s.staleMu.Lock()
+		wasStale := s.stale[check.ID]
+		if stale {
+			s.stale[check.ID] = true
+		} else {
+			delete(s.stale, check.ID)
+		}
+		s.staleMu.Unlock()
+
+		if stale && !wasStale {
+			fmt.Printf("check %s is stale: no result since %s\n", check.Name, since.Format(time.RFC3339))
+			if alerter, ok := s.alerter.(StaleAlerter); ok {
+				if err := alerter.SendStaleAlert(check, lastResult); err != nil {
+					fmt.Printf("failed to send stale alert for %s: %v\n", check.Name, err)
+				}
+			}
+		} else if !stale && wasStale {
+			fmt.Printf("check %s is producing results again\n", check.Name)
+		}
+	}
+}
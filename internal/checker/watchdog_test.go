@@ -0,0 +1,52 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+type mockStaleAlerter struct {
+	mockAlerter
+	stale []string
+}
+
+func (m *mockStaleAlerter) SendStaleAlert(check *storage.Check, lastResult time.Time) error {
+	m.stale = append(m.stale, check.Name)
+	return nil
+}
+
+func TestSchedulerDoWatchdog(t *testing.T) {
+	store, _ := setupSchedulerTest(t)
+	alerter := &mockStaleAlerter{}
+	scheduler := NewScheduler(store, alerter, SchedulerConfig{})
+
+	check := &storage.Check{Name: "Stuck", URL: "https://test.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(check)
+	paused := &storage.Check{Name: "Paused", URL: "https://paused.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true, Paused: true}
+	store.CreateCheck(paused)
+	store.SaveResult(&storage.CheckResult{CheckID: check.ID, Status: "up", StatusCode: 200})
+
+	// Within three intervals nothing is stale
+	now := time.Now()
+	scheduler.doWatchdog(now.Add(2 * time.Minute))
+	if len(alerter.stale) != 0 {
+		t.Fatalf("expected no stale alert yet, got %v", alerter.stale)
+	}
+
+	// Past three intervals the check is flagged once; paused checks never are
+	scheduler.doWatchdog(now.Add(4 * time.Minute))
+	scheduler.doWatchdog(now.Add(5 * time.Minute))
+	if len(alerter.stale) != 1 || alerter.stale[0] != "Stuck" {
+		t.Fatalf("expected one stale alert for Stuck, got %v", alerter.stale)
+	}
+
+	// A fresh result clears it, so the next gap alerts again
+	store.SaveResult(&storage.CheckResult{CheckID: check.ID, Status: "up", StatusCode: 200})
+	scheduler.doWatchdog(time.Now())
+	scheduler.doWatchdog(time.Now().Add(10 * time.Minute))
+	if len(alerter.stale) != 2 {
+		t.Errorf("expected a second stale alert after recovering, got %v", alerter.stale)
+	}
+}
@@ -0,0 +1,163 @@
+package checker
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// XMLAssertion compares one element or attribute of an XML response body
+// against a value, e.g. "/health/status == ok" or "/health/queue/@depth < 100"
+type XMLAssertion struct {
+	Path  string
+	Op    string
+	Value string
+}
+
+// ParseXMLAssertion parses "<path> <op> <value>". Paths are a small XPath
+// subset: absolute element steps with optional 1-based indexes, optionally
+// ending in an attribute, like /feed/entry[2]/@id.
+func ParseXMLAssertion(s string) (*XMLAssertion, error) {
+	path, op, value, err := parseComparison(s, "/")
+	if err != nil {
+		return nil, err
+	}
+	return &XMLAssertion{Path: path, Op: op, Value: value}, nil
+}
+
+func (a *XMLAssertion) String() string {
+	return fmt.Sprintf("%s %s %s", a.Path, a.Op, a.Value)
+}
+
+// Evaluate checks the assertion against a parsed XML document. Text that
+// parses as a number is compared as one.
+func (a *XMLAssertion) Evaluate(doc *xmlNode) error {
+	text, err := lookupXMLPath(doc, a.Path)
+	if err != nil {
+		return err
+	}
+	var field interface{} = text
+	if num, err := strconv.ParseFloat(text, 64); err == nil {
+		field = num
+	}
+	return compareField(a.Path, a.Op, a.Value, field)
+}
+
+// CheckXMLAssertions parses the body as XML and returns an error for the
+// first assertion that fails
+func CheckXMLAssertions(assertions []string, body []byte) error {
+	doc, err := parseXML(body)
+	if err != nil {
+		return fmt.Errorf("response is not valid XML: %w", err)
+	}
+
+	for _, s := range assertions {
+		a, err := ParseXMLAssertion(s)
+		if err != nil {
+			return err
+		}
+		if err := a.Evaluate(doc); err != nil {
+			return fmt.Errorf("assertion failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// xmlNode is an element with its attributes, direct text and children.
+// Namespaces are dropped, so paths use local names.
+type xmlNode struct {
+	name     string
+	attrs    map[string]string
+	text     strings.Builder
+	children []*xmlNode
+}
+
+// parseXML builds the element tree under a synthetic root, so the document
+// element is the root's only child
+func parseXML(body []byte) (*xmlNode, error) {
+	root := &xmlNode{}
+	stack := []*xmlNode{root}
+
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		current := stack[len(stack)-1]
+		switch t := tok.(type) {
+		case xml.StartElement:
+			node := &xmlNode{name: t.Name.Local, attrs: make(map[string]string, len(t.Attr))}
+			for _, attr := range t.Attr {
+				node.attrs[attr.Name.Local] = attr.Value
+			}
+			current.children = append(current.children, node)
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			current.text.Write(t)
+		}
+	}
+
+	if len(root.children) == 0 {
+		return nil, fmt.Errorf("no root element")
+	}
+	return root, nil
+}
+
+// lookupXMLPath walks a path like /feed/entry[2]/title or /feed/@version
+// and returns the element's trimmed text or the attribute's value
+func lookupXMLPath(doc *xmlNode, path string) (string, error) {
+	current := doc
+	steps := strings.Split(strings.TrimPrefix(path, "/"), "/")
+
+	for i, step := range steps {
+		if strings.HasPrefix(step, "@") {
+			if i != len(steps)-1 {
+				return "", fmt.Errorf("%s: attribute %q must be the last step", path, step)
+			}
+			value, ok := current.attrs[step[1:]]
+			if !ok {
+				return "", fmt.Errorf("%s: attribute %q not found", path, step[1:])
+			}
+			return value, nil
+		}
+
+		name, index := step, 1
+		if open := strings.IndexByte(step, '['); open >= 0 {
+			if !strings.HasSuffix(step, "]") {
+				return "", fmt.Errorf("%s: unclosed [", path)
+			}
+			n, err := strconv.Atoi(step[open+1 : len(step)-1])
+			if err != nil || n < 1 {
+				return "", fmt.Errorf("%s: invalid index %q", path, step[open+1:len(step)-1])
+			}
+			name, index = step[:open], n
+		}
+
+		var next *xmlNode
+		seen := 0
+		for _, child := range current.children {
+			if child.name == name {
+				if seen++; seen == index {
+					next = child
+					break
+				}
+			}
+		}
+		if next == nil {
+			return "", fmt.Errorf("%s: element %q not found", path, step)
+		}
+		current = next
+	}
+
+	return strings.TrimSpace(current.text.String()), nil
+}
@@ -0,0 +1,68 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseXMLAssertion(t *testing.T) {
+	a, err := ParseXMLAssertion("/health/queue/@depth < 100")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.Path != "/health/queue/@depth" || a.Op != "<" || a.Value != "100" {
+		t.Errorf("unexpected assertion: %+v", a)
+	}
+
+	for _, s := range []string{
+		"health/status == ok",   // not absolute
+		"/health/status ==",     // no value
+		"/health/status > fine", // ordering needs a number
+	} {
+		if _, err := ParseXMLAssertion(s); err == nil {
+			t.Errorf("expected error for %q", s)
+		}
+	}
+}
+
+func TestCheckXMLAssertions(t *testing.T) {
+	body := []byte(`<?xml version="1.0"?>
+<health xmlns="urn:example" version="2">
+  <status>ok</status>
+  <queue depth="42"/>
+  <worker><busy>3</busy></worker>
+  <worker><busy>8</busy></worker>
+</health>`)
+
+	passing := []string{
+		"/health/status == ok",
+		"/health/@version == 2",
+		"/health/queue/@depth < 100",
+		"/health/worker[2]/busy >= 8",
+		"/health/worker/busy == 3",
+	}
+	if err := CheckXMLAssertions(passing, body); err != nil {
+		t.Errorf("expected assertions to pass, got %v", err)
+	}
+
+	tests := []struct {
+		assertion string
+		want      string
+	}{
+		{"/health/queue/@depth > 100", "is 42, expected > 100"},
+		{"/health/status != ok", "expected anything else"},
+		{"/health/missing == x", `element "missing" not found`},
+		{"/health/worker[3]/busy == 1", `element "worker[3]" not found`},
+		{"/health/queue/@size == 1", `attribute "size" not found`},
+	}
+	for _, tt := range tests {
+		err := CheckXMLAssertions([]string{tt.assertion}, body)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.assertion, tt.want, err)
+		}
+	}
+
+	if err := CheckXMLAssertions(passing, []byte("<health><status>")); err == nil || !strings.Contains(err.Error(), "not valid XML") {
+		t.Errorf("expected invalid XML error, got %v", err)
+	}
+}
@@ -1,22 +1,40 @@
 package config
 
 import (
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"mime"
+	"net"
 	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/katieblackabee/sentinel/internal/cron"
 )
 
 type Config struct {
-	Server    ServerConfig    `yaml:"server"`
-	Database  DatabaseConfig  `yaml:"database"`
-	Alerts    AlertsConfig    `yaml:"alerts"`
-	Retention RetentionConfig `yaml:"retention"`
-	Regions   []RegionConfig  `yaml:"regions"` // Optional probe regions for multi-region checks
-	Checks    []CheckConfig   `yaml:"checks"`
+	Include    []string         `yaml:"include"` // More config files merged over this one, relative to it
+	Server     ServerConfig     `yaml:"server"`
+	Database   DatabaseConfig   `yaml:"database"`
+	Alerts     AlertsConfig     `yaml:"alerts"`
+	Events     EventsConfig     `yaml:"events"` // Raw state change feed for data pipelines
+	StatsD     StatsDConfig     `yaml:"statsd"` // Push check metrics to a StatsD or Datadog agent
+	Retention  RetentionConfig  `yaml:"retention"`
+	Regions    []RegionConfig   `yaml:"regions"` // Optional probe regions for multi-region checks
+	Checks     []CheckConfig    `yaml:"checks"`
+	Drift      DriftConfig      `yaml:"drift"`       // Watch stored checks for edits that stray from the checks above
+	StatusPage StatusPageConfig `yaml:"status_page"` // Branding for public status pages
+	Limits     LimitsConfig     `yaml:"limits"`      // Outbound load caps for large check lists
+	Checker    CheckerConfig    `yaml:"checker"`     // How HTTP checks connect
 }
 
 type ServerConfig struct {
@@ -24,58 +42,687 @@ type ServerConfig struct {
 	Port    int               `yaml:"port"`
 	BaseURL string            `yaml:"base_url"`
 	Users   map[string]string `yaml:"users"` // username -> password
+
+	// SHA-256 hex digests of keys accepted as "Authorization: Bearer <key>"
+	// on the API, for automation that can't log in through the form
+	APIKeys []string `yaml:"api_keys"`
+
+	BulkConcurrency int    `yaml:"bulk_concurrency"` // Max checks run at once by bulk actions (default 5)
+	BulkTimeout     string `yaml:"bulk_timeout"`     // Overall deadline for bulk actions (default 60s)
+
+	DashboardIncidents int    `yaml:"dashboard_incidents"` // Recent incidents shown on the dashboard (default 5, max 100)
+	DashboardRefresh   string `yaml:"dashboard_refresh"`   // How often open dashboards reload (default 30s, min 5s)
+
+	ConnectivityCheckURL string `yaml:"connectivity_check_url"` // Fetched once at startup to warn about blocked egress (empty disables)
+
+	GraphQL bool `yaml:"graphql"` // Serve the read-only GraphQL endpoint at /graphql
+
+	// Origins such as "https://app.example.com" whose pages may call /api
+	// from the browser; empty keeps the API same-origin
+	AllowedOrigins []string `yaml:"allowed_origins"`
 }
 
+// MaxDashboardIncidents caps the dashboard's incident list, from config or
+// the ?incidents= query param, so the page stays fast
+const MaxDashboardIncidents = 100
+
+// MinDashboardRefresh keeps a room full of dashboards from hammering the
+// server
+const MinDashboardRefresh = 5 * time.Second
+
+// Database drivers
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
 type DatabaseConfig struct {
-	Path string `yaml:"path"`
+	Driver        string `yaml:"driver"`          // sqlite (default) or postgres
+	Path          string `yaml:"path"`            // SQLite database file
+	DSN           string `yaml:"dsn"`             // PostgreSQL connection string
+	StatsCacheTTL string `yaml:"stats_cache_ttl"` // How long per-check stats are cached (default 30s, "0s" disables)
 }
 
 type AlertsConfig struct {
-	ConsecutiveFailures      int           `yaml:"consecutive_failures"`
-	RecoveryNotification     bool          `yaml:"recovery_notification"`
-	CooldownMinutes          int           `yaml:"cooldown_minutes"`
-	SSLExpiryDays            int           `yaml:"ssl_expiry_days"`            // Alert when SSL cert expires within X days (0 = disabled)
-	MultiRegionAlertThreshold int          `yaml:"multi_region_alert_threshold"` // Min failing regions to alert (0 = alert on any, default)
-	Email                    EmailConfig   `yaml:"email"`
-	Slack                    SlackConfig   `yaml:"slack"`
-	Discord                  DiscordConfig `yaml:"discord"`
+	ConsecutiveFailures       int                 `yaml:"consecutive_failures"`
+	RecoveryNotification      bool                `yaml:"recovery_notification"`
+	CooldownMinutes           int                 `yaml:"cooldown_minutes"`
+	RenotifyMinutes           int                 `yaml:"renotify_minutes"`             // Remind each channel this often while an incident stays open (0 = off)
+	SSLExpiryDays             int                 `yaml:"ssl_expiry_days"`              // Alert when SSL cert expires within X days (0 = disabled)
+	MultiRegionAlertThreshold int                 `yaml:"multi_region_alert_threshold"` // Min failing regions to alert (0 = alert on any, default)
+	AlertOnFirstCheck         bool                `yaml:"alert_on_first_check"`         // Default for checks: alert if the very first result is down
+	BreakerFailures           int                 `yaml:"breaker_failures"`             // Consecutive failures before a channel is skipped (default 5)
+	BreakerCooldown           string              `yaml:"breaker_cooldown"`             // How long a failing channel is skipped before a probe (default 10m)
+	Timezone                  string              `yaml:"timezone"`                     // IANA zone for times in alert bodies, e.g. Europe/Berlin (default: server zone)
+	TimeFormat                string              `yaml:"time_format"`                  // Go time layout for alert bodies (default RFC1123)
+	CauseRules                []CauseRule         `yaml:"cause_rules"`                  // Extra incident cause categories, tried before the built-ins
+	StaleIntervals            int                 `yaml:"stale_intervals"`              // Intervals without a result before a check is stale (default 3, -1 = off)
+	SlowMultiplier            float64             `yaml:"slow_multiplier"`              // Alert when an up response takes this many times its 24h average (0 = off)
+	Routes                    map[string][]string `yaml:"routes"`                       // Channels per alert type, e.g. recovery: [slack], for checks without alert_channels
+	BusinessHours             BusinessHoursConfig `yaml:"business_hours"`               // Hold back less severe alerts outside working hours
+	Email                     EmailConfig         `yaml:"email"`
+	Slack                     SlackConfig         `yaml:"slack"`
+	Discord                   DiscordConfig       `yaml:"discord"`
+	Webhook                   WebhookConfig       `yaml:"webhook"`
+	Twilio                    TwilioConfig        `yaml:"twilio"`
+	IncidentSync              IncidentSyncConfig  `yaml:"incident_sync"`
+}
+
+// CauseRule files incidents whose error contains Match (case-insensitive)
+// under Category, e.g. a WAF's block page as "waf"
+type CauseRule struct {
+	Match    string `yaml:"match"`
+	Category string `yaml:"category"`
 }
 
 type SlackConfig struct {
-	Enabled    bool   `yaml:"enabled"`
-	WebhookURL string `yaml:"webhook_url"`
+	Enabled    bool            `yaml:"enabled"`
+	WebhookURL string          `yaml:"webhook_url"`
+	Targets    []WebhookTarget `yaml:"targets"`
 }
 
 type DiscordConfig struct {
+	Enabled    bool            `yaml:"enabled"`
+	WebhookURL string          `yaml:"webhook_url"`
+	Targets    []WebhookTarget `yaml:"targets"`
+}
+
+// WebhookConfig sends alerts to any HTTP endpoint, such as an in-house alert
+// router, with the body rendered from a Go template given the alert
+type WebhookConfig struct {
+	Enabled      bool              `yaml:"enabled"`
+	URL          string            `yaml:"url"`
+	Method       string            `yaml:"method"`        // Default POST
+	Headers      map[string]string `yaml:"headers"`       // e.g. Authorization; Content-Type defaults to application/json
+	BodyTemplate string            `yaml:"body_template"` // Default DefaultWebhookTemplate
+}
+
+// DefaultWebhookTemplate renders an alert as flat JSON
+const DefaultWebhookTemplate = `{"type":{{json .Type}},"severity":{{json .Severity}},` +
+	`"check":{"id":{{.Check.ID}},"name":{{json .Check.Name}},"url":{{json .Check.URL}},"tags":{{json .Check.Tags}}},` +
+	`"incident_id":{{if .Incident}}{{.Incident.ID}}{{else}}null{{end}},` +
+	`"error":{{json .Error}},"timestamp":{{json .Timestamp}}}`
+
+// webhookFuncs are available to body templates. json renders any value as
+// JSON, which also quotes and escapes strings.
+var webhookFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// GetMethod returns the HTTP method, defaulting to POST
+func (c *WebhookConfig) GetMethod() string {
+	if c.Method == "" {
+		return "POST"
+	}
+	return strings.ToUpper(c.Method)
+}
+
+// Template parses the body template, or the default when none is set
+func (c *WebhookConfig) Template() (*template.Template, error) {
+	body := c.BodyTemplate
+	if body == "" {
+		body = DefaultWebhookTemplate
+	}
+	return template.New("webhook").Funcs(webhookFuncs).Parse(body)
+}
+
+// validateRequest checks the URL and method
+func (c *WebhookConfig) validateRequest() error {
+	if !strings.HasPrefix(c.URL, "http://") && !strings.HasPrefix(c.URL, "https://") {
+		return fmt.Errorf("url must start with http:// or https://")
+	}
+	switch c.GetMethod() {
+	case "POST", "PUT", "PATCH":
+	default:
+		return fmt.Errorf("invalid method %q (use POST, PUT, or PATCH)", c.Method)
+	}
+	return nil
+}
+
+// TwilioConfig sends short down and recovery texts through Twilio, for when
+// nobody is at a screen to see the other channels
+type TwilioConfig struct {
+	Enabled    bool     `yaml:"enabled"`
+	AccountSID string   `yaml:"account_sid"`
+	AuthToken  string   `yaml:"auth_token"`
+	FromNumber string   `yaml:"from_number"` // A Twilio number in E.164 form, e.g. +15005550006
+	ToNumbers  []string `yaml:"to_numbers"`  // E.164 numbers to text
+}
+
+// IncidentSyncConfig mirrors incidents into an external incident management
+// or ticketing system. Each incident is sent like a webhook alert when it
+// opens and again when it closes, and a ticket ID found in the response is
+// kept on the incident for the next request.
+type IncidentSyncConfig struct {
+	WebhookConfig `yaml:",inline"`
+	IDField       string `yaml:"id_field"` // Dotted path to the ticket ID in the JSON response, e.g. "id" or "result.sys_id"
+}
+
+// DefaultIncidentSyncTemplate renders an incident event as flat JSON
+const DefaultIncidentSyncTemplate = `{"event":{{json .Event}},"external_id":{{json .Incident.ExternalID}},` +
+	`"incident":{"id":{{.Incident.ID}},"title":{{json .Incident.Title}},"cause":{{json .Incident.Cause}},"category":{{json .Incident.Category}},` +
+	`"started_at":{{json .Incident.StartedAt}},"ended_at":{{json .Incident.EndedAt}},"duration_seconds":{{.Incident.DurationSeconds}}},` +
+	`"check":{"id":{{.Check.ID}},"name":{{json .Check.Name}},"url":{{json .Check.URL}},"tags":{{json .Check.Tags}}},` +
+	`"timestamp":{{json .Timestamp}}}`
+
+// Template parses the body template, or the default when none is set
+func (c *IncidentSyncConfig) Template() (*template.Template, error) {
+	body := c.BodyTemplate
+	if body == "" {
+		body = DefaultIncidentSyncTemplate
+	}
+	return template.New("incident_sync").Funcs(webhookFuncs).Parse(body)
+}
+
+// WebhookTarget is one Slack or Discord webhook, optionally limited to
+// alerts of certain severities or checks with certain tags
+type WebhookTarget struct {
+	Name       string   `yaml:"name"`
+	URL        string   `yaml:"url"`
+	Severities []string `yaml:"severities"` // critical, warning, info; empty means all
+	Tags       []string `yaml:"tags"`       // any of these check tags; empty means all
+}
+
+// Alert severities that webhook targets can filter on
+var validSeverities = map[string]bool{"critical": true, "warning": true, "info": true}
+
+// BusinessHoursConfig lets only severe alerts through outside working hours,
+// in alerts.timezone. The rest wait for a digest sent when hours start again,
+// or are dropped.
+type BusinessHoursConfig struct {
+	Enabled     bool     `yaml:"enabled"`
+	Days        []string `yaml:"days"`         // mon, tue, ... sun (default mon-fri)
+	Start       string   `yaml:"start"`        // HH:MM (default 09:00)
+	End         string   `yaml:"end"`          // HH:MM (default 17:00); before start wraps past midnight
+	MinSeverity string   `yaml:"min_severity"` // Least severe alert sent outside hours (default critical)
+	OffHours    string   `yaml:"off_hours"`    // digest (default) or drop, for alerts held outside hours
+}
+
+// Day names business hours are given in
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// Contains reports whether t, already in the alerts zone, is within business
+// hours. A range wrapping past midnight belongs to the day it starts on.
+func (b *BusinessHoursConfig) Contains(t time.Time) bool {
+	start, end := b.minutes(b.Start, 9*60), b.minutes(b.End, 17*60)
+	now := t.Hour()*60 + t.Minute()
+
+	day := t.Weekday()
+	if start <= end {
+		return b.onDay(day) && now >= start && now < end
+	}
+	if now >= start {
+		return b.onDay(day)
+	}
+	return now < end && b.onDay((day+6)%7)
+}
+
+func (b *BusinessHoursConfig) onDay(day time.Weekday) bool {
+	if len(b.Days) == 0 {
+		return day >= time.Monday && day <= time.Friday
+	}
+	for _, name := range b.Days {
+		if d, ok := weekdays[strings.ToLower(name)]; ok && d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// minutes parses an HH:MM time of day as minutes after midnight
+func (b *BusinessHoursConfig) minutes(clock string, def int) int {
+	if clock == "" {
+		return def
+	}
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return def
+	}
+	return t.Hour()*60 + t.Minute()
+}
+
+func (b *BusinessHoursConfig) GetMinSeverity() string {
+	if b.MinSeverity == "" {
+		return "critical"
+	}
+	return b.MinSeverity
+}
+
+// Drops reports whether alerts held outside hours are discarded rather
+// than sent as a digest
+func (b *BusinessHoursConfig) Drops() bool {
+	return b.OffHours == "drop"
+}
+
+func (b *BusinessHoursConfig) validate() error {
+	for _, name := range b.Days {
+		if _, ok := weekdays[strings.ToLower(name)]; !ok {
+			return fmt.Errorf("business_hours: invalid day %q (use mon, tue, wed, thu, fri, sat, or sun)", name)
+		}
+	}
+	for _, clock := range []string{b.Start, b.End} {
+		if clock == "" {
+			continue
+		}
+		if _, err := time.Parse("15:04", clock); err != nil {
+			return fmt.Errorf("business_hours: invalid time %q (use HH:MM)", clock)
+		}
+	}
+	if b.MinSeverity != "" && !validSeverities[b.MinSeverity] {
+		return fmt.Errorf("business_hours: invalid min_severity %q (use critical, warning, or info)", b.MinSeverity)
+	}
+	if b.OffHours != "" && b.OffHours != "digest" && b.OffHours != "drop" {
+		return fmt.Errorf("business_hours: invalid off_hours %q (use digest or drop)", b.OffHours)
+	}
+	return nil
+}
+
+// Alert providers a check can route to, alone or as "provider:target"
+var alertProviders = map[string]bool{"email": true, "slack": true, "discord": true, "webhook": true, "sms": true}
+
+// Alert types that can be routed to their own channels
+var alertTypes = map[string]bool{"down": true, "recovery": true, "ssl_expiry": true, "stale": true, "escalation": true, "digest": true, "drift": true, "slow": true}
+
+// Matchers a check's body assertions can be written for
+var assertionTypes = map[string]bool{"auto": true, "json": true, "xml": true, "text": true}
+
+// retryCategories are the built-in failure categories retry_on accepts
+var retryCategories = map[string]bool{
+	"transport": true, "timeout": true, "connection_refused": true, "connection_reset": true,
+	"dns": true, "tls": true, "http": true, "other": true,
+}
+
+var requestMethods = map[string]bool{"GET": true, "HEAD": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true, "OPTIONS": true}
+
+// Record types a DNS check can look up
+var recordTypes = map[string]bool{"A": true, "AAAA": true, "CNAME": true, "MX": true, "NS": true, "TXT": true}
+
+// Matches reports whether an alert with the given severity, for a check with
+// the given tags, should go to this target
+func (t *WebhookTarget) Matches(severity string, tags []string) bool {
+	return routeMatches(t.Severities, t.Tags, severity, tags)
+}
+
+// routeMatches applies a severity and tag filter, where an empty filter
+// matches everything
+func routeMatches(severities, routeTags []string, severity string, tags []string) bool {
+	if len(severities) > 0 && !containsString(severities, severity) {
+		return false
+	}
+	if len(routeTags) == 0 {
+		return true
+	}
+	for _, tag := range tags {
+		if containsString(routeTags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTargets returns the configured targets, with webhook_url (if set) as
+// an unfiltered target first
+func (c *SlackConfig) GetTargets() []WebhookTarget {
+	return webhookTargets(c.WebhookURL, c.Targets)
+}
+
+// GetTargets returns the configured targets, with webhook_url (if set) as
+// an unfiltered target first
+func (c *DiscordConfig) GetTargets() []WebhookTarget {
+	return webhookTargets(c.WebhookURL, c.Targets)
+}
+
+// TargetChannel names a target as checks route to it and alert_log records
+// it. The unnamed first target is the bare provider so single-webhook setups
+// look the same as before.
+func TargetChannel(provider string, i int, target WebhookTarget) string {
+	if target.Name != "" {
+		return provider + ":" + target.Name
+	}
+	if i == 0 {
+		return provider
+	}
+	return fmt.Sprintf("%s:%d", provider, i+1)
+}
+
+// Channels returns the channels of every enabled provider
+func (c *AlertsConfig) Channels() []string {
+	var channels []string
+	if c.Email.Enabled {
+		channels = append(channels, "email")
+	}
+	if c.Slack.Enabled {
+		for i, target := range c.Slack.GetTargets() {
+			channels = append(channels, TargetChannel("slack", i, target))
+		}
+	}
+	if c.Discord.Enabled {
+		for i, target := range c.Discord.GetTargets() {
+			channels = append(channels, TargetChannel("discord", i, target))
+		}
+	}
+	if c.Webhook.Enabled {
+		channels = append(channels, "webhook")
+	}
+	if c.Twilio.Enabled {
+		channels = append(channels, "sms")
+	}
+	return channels
+}
+
+// validateRoutes checks that routes are for alert types that exist and only
+// name enabled channels. A route naming a provider ("slack") covers all of
+// its targets, so it is valid if any target is enabled.
+func (c *AlertsConfig) validateRoutes(routes map[string][]string) error {
+	types := make([]string, 0, len(routes))
+	for alertType := range routes {
+		types = append(types, alertType)
+	}
+	sort.Strings(types)
+
+	channels := c.Channels()
+	for _, alertType := range types {
+		if !alertTypes[alertType] {
+			return fmt.Errorf("invalid alert type %q (use down, recovery, ssl_expiry, stale, escalation, drift, slow, or digest)", alertType)
+		}
+		if len(routes[alertType]) == 0 {
+			return fmt.Errorf("%s: at least one channel is required", alertType)
+		}
+		for _, route := range routes[alertType] {
+			found := false
+			for _, channel := range channels {
+				if channel == route || strings.HasPrefix(channel, route+":") {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf("%s: channel %q is not configured or not enabled", alertType, route)
+			}
+		}
+	}
+	return nil
+}
+
+func webhookTargets(url string, targets []WebhookTarget) []WebhookTarget {
+	if url == "" {
+		return targets
+	}
+	return append([]WebhookTarget{{URL: url}}, targets...)
+}
+
+func validateTargets(provider string, targets []WebhookTarget) error {
+	for i, target := range targets {
+		if target.URL == "" {
+			return fmt.Errorf("%s target[%d]: url is required", provider, i)
+		}
+		for _, severity := range target.Severities {
+			if !validSeverities[severity] {
+				return fmt.Errorf("%s target[%d]: invalid severity %q (expected critical, warning or info)", provider, i, severity)
+			}
+		}
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusPageConfig brands the public status pages. Pages overrides any of
+// the defaults for a single status page slug.
+type StatusPageConfig struct {
+	Branding `yaml:",inline"`
+	Pages    map[string]Branding `yaml:"pages"`
+}
+
+type Branding struct {
+	Title        string `yaml:"title"`
+	LogoURL      string `yaml:"logo_url"`
+	PrimaryColor string `yaml:"primary_color"` // Hex color, e.g. "#0066ff"
+	Footer       string `yaml:"footer"`
+}
+
+// statusSpecPattern matches expected status specs like "200,204" or "2xx"
+var statusSpecPattern = regexp.MustCompile(`^\s*([1-5][0-9][0-9]|[1-5]xx)(\s*,\s*([1-5][0-9][0-9]|[1-5]xx))*\s*$`)
+
+var hexColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// phoneNumberPattern matches E.164 numbers, which Twilio wants
+var phoneNumberPattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// BrandingFor returns the branding for a status page, with per-page values
+// taking precedence over the defaults
+func (c *StatusPageConfig) BrandingFor(slug string) Branding {
+	b := c.Branding
+	page, ok := c.Pages[slug]
+	if !ok {
+		return b
+	}
+	if page.Title != "" {
+		b.Title = page.Title
+	}
+	if page.LogoURL != "" {
+		b.LogoURL = page.LogoURL
+	}
+	if page.PrimaryColor != "" {
+		b.PrimaryColor = page.PrimaryColor
+	}
+	if page.Footer != "" {
+		b.Footer = page.Footer
+	}
+	return b
+}
+
+func (b *Branding) validate() error {
+	if b.PrimaryColor != "" && !hexColorPattern.MatchString(b.PrimaryColor) {
+		return fmt.Errorf("invalid primary_color %q (expected a hex color like #0066ff)", b.PrimaryColor)
+	}
+	return nil
+}
+
+// EventsConfig configures a webhook that receives every state change,
+// unaffected by alert thresholds and cooldowns.
+type EventsConfig struct {
 	Enabled    bool   `yaml:"enabled"`
 	WebhookURL string `yaml:"webhook_url"`
 }
 
+// StatsDConfig pushes response times and up/down and alert counters to a
+// StatsD agent over UDP, batched into few packets
+type StatsDConfig struct {
+	Enabled       bool   `yaml:"enabled"`
+	Host          string `yaml:"host"`           // Default localhost
+	Port          int    `yaml:"port"`           // Default 8125
+	Prefix        string `yaml:"prefix"`         // Default "sentinel"
+	Tags          bool   `yaml:"tags"`           // DogStatsD tags (check:<name>) instead of the name in the metric path
+	FlushInterval string `yaml:"flush_interval"` // How long metrics are batched (default 1s)
+}
+
+// DriftConfig periodically compares the checks defined in the config file
+// with their stored copies, which the UI and API can edit
+type DriftConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	Interval string `yaml:"interval"` // How often checks are compared (default 5m)
+	Action   string `yaml:"action"`   // "warn" (default) alerts on drift; "correct" also restores the file's definition
+}
+
 type EmailConfig struct {
-	Enabled      bool     `yaml:"enabled"`
-	SMTPHost     string   `yaml:"smtp_host"`
-	SMTPPort     int      `yaml:"smtp_port"`
-	SMTPUser     string   `yaml:"smtp_user"`
-	SMTPPassword string   `yaml:"smtp_password"`
-	SMTPTLS      bool     `yaml:"smtp_tls"`
-	FromAddress  string   `yaml:"from_address"`
-	ToAddresses  []string `yaml:"to_addresses"`
+	Enabled      bool              `yaml:"enabled"`
+	SMTPHost     string            `yaml:"smtp_host"`
+	SMTPPort     int               `yaml:"smtp_port"`
+	SMTPUser     string            `yaml:"smtp_user"`
+	SMTPPassword string            `yaml:"smtp_password"`
+	SMTPTLS      bool              `yaml:"smtp_tls"`
+	FromAddress  string            `yaml:"from_address"`
+	ToAddresses  []string          `yaml:"to_addresses"` // Receive every alert
+	Recipients   []EmailRecipients `yaml:"recipients"`   // Extra addresses for matching alerts
+}
+
+// EmailRecipients is a set of addresses that receive alerts of certain
+// severities or for checks with certain tags, e.g. critical alerts to the
+// on-call list
+type EmailRecipients struct {
+	Name       string   `yaml:"name"`
+	To         []string `yaml:"to"`
+	Severities []string `yaml:"severities"` // critical, warning, info; empty means all
+	Tags       []string `yaml:"tags"`       // any of these check tags; empty means all
+}
+
+// RecipientsFor returns every address an alert with the given severity, for a
+// check with the given tags, should go to: to_addresses plus each matching
+// recipient set, without duplicates
+func (c *EmailConfig) RecipientsFor(severity string, tags []string) []string {
+	var to []string
+	add := func(addrs []string) {
+		for _, addr := range addrs {
+			if !containsString(to, addr) {
+				to = append(to, addr)
+			}
+		}
+	}
+
+	add(c.ToAddresses)
+	for _, set := range c.Recipients {
+		if routeMatches(set.Severities, set.Tags, severity, tags) {
+			add(set.To)
+		}
+	}
+	return to
+}
+
+func validateRecipients(sets []EmailRecipients) error {
+	for i, set := range sets {
+		if len(set.To) == 0 {
+			return fmt.Errorf("email recipients[%d]: to is required", i)
+		}
+		for _, addr := range set.To {
+			if !strings.Contains(addr, "@") {
+				return fmt.Errorf("email recipients[%d]: invalid address %q", i, addr)
+			}
+		}
+		for _, severity := range set.Severities {
+			if !validSeverities[severity] {
+				return fmt.Errorf("email recipients[%d]: invalid severity %q (expected critical, warning or info)", i, severity)
+			}
+		}
+	}
+	return nil
+}
+
+// CheckerConfig tunes how HTTP checks connect
+type CheckerConfig struct {
+	// PEM file of CA certificates trusted on top of the system's, so
+	// endpoints signed by an internal CA validate with verification on
+	CABundle string `yaml:"ca_bundle"`
+}
+
+// LimitsConfig caps outbound checks so thousands of them can't exhaust the
+// host's sockets or file descriptors
+type LimitsConfig struct {
+	MaxConcurrentChecks int    `yaml:"max_concurrent_checks"` // Checks executing at once (default 50)
+	MaxConnsPerHost     int    `yaml:"max_conns_per_host"`    // Open connections per target host (default 10)
+	MaxIdleConns        int    `yaml:"max_idle_conns"`        // Idle connections kept across all hosts (default 100)
+	StartupRamp         string `yaml:"startup_ramp"`          // Spread first runs at startup over this long, e.g. "60s" (default off)
+
+	// Failure categories retried once before a check counts as failed:
+	// transport (no response at all), timeout, connection_refused,
+	// connection_reset, dns, tls, http, other, or a cause_rules category.
+	// Default [transport]; [] never retries.
+	RetryOn []string `yaml:"retry_on"`
 }
 
 type RetentionConfig struct {
 	ResultsDays    int `yaml:"results_days"`
 	AggregatesDays int `yaml:"aggregates_days"`
+	ArchiveDays    int `yaml:"archive_days"` // Pack results past results_days into day archives kept this long (0 = off)
 }
 
 type CheckConfig struct {
-	Name           string   `yaml:"name"`
-	URL            string   `yaml:"url"`
-	Interval       string   `yaml:"interval"`
-	Timeout        string   `yaml:"timeout"`
-	ExpectedStatus int      `yaml:"expected_status"`
-	Enabled        *bool    `yaml:"enabled"`
-	Tags           []string `yaml:"tags"`
-	Regions        []string `yaml:"regions"` // Optional: run check from multiple regions (us, eu, apac)
+	Name                    string   `yaml:"name,omitempty"`
+	URL                     string   `yaml:"url,omitempty"`
+	Type                    string   `yaml:"type,omitempty"` // http (default), tcp, whose url is host:port, dns, whose url is a hostname, or ping, a hostname or IP
+	Interval                string   `yaml:"interval,omitempty"`
+	Cron                    string   `yaml:"cron,omitempty"` // Run at these times instead of every interval, e.g. "0 9-17 * * mon-fri" (server time zone)
+	Timeout                 string   `yaml:"timeout,omitempty"`
+	ExpectedStatus          int      `yaml:"expected_status,omitempty"` // -1 accepts any response; "200,204" or "2xx" set ExpectedStatuses
+	ExpectedStatuses        string   `yaml:"expected_statuses,omitempty"`
+	Enabled                 *bool    `yaml:"enabled,omitempty"`
+	Tags                    []string `yaml:"tags,omitempty"`
+	Group                   string   `yaml:"group,omitempty"`
+	Regions                 []string `yaml:"regions,omitempty"` // Optional: run check from multiple regions (us, eu, apac)
+	Description             string   `yaml:"description,omitempty"`
+	RunbookURL              string   `yaml:"runbook_url,omitempty"`               // Linked from alerts
+	Streaming               bool     `yaml:"streaming,omitempty"`                 // Endpoint streams indefinitely; succeed on headers
+	SampleInterval          string   `yaml:"sample_interval,omitempty"`           // Store stable results at most this often (e.g. "1m")
+	CompressResults         bool     `yaml:"compress_results,omitempty"`          // Fold identical stable results into one row per run
+	AlertOnFirstCheck       bool     `yaml:"alert_on_first_check,omitempty"`      // Alert if the very first result is down
+	JSONAssertions          []string `yaml:"json_assertions,omitempty"`           // e.g. "$.queue_depth < 10000"
+	FollowRedirects         *bool    `yaml:"follow_redirects,omitempty"`          // Default true; false checks the redirect itself
+	BypassCache             bool     `yaml:"bypass_cache,omitempty"`              // Skip CDN caches to reach the origin
+	DualStack               bool     `yaml:"dual_stack,omitempty"`                // Check over IPv4 and IPv6; down unless both are up
+	InsecureSkipVerify      bool     `yaml:"insecure_skip_verify,omitempty"`      // Accept any TLS certificate; prefer checker.ca_bundle for a private CA
+	RecordType              string   `yaml:"record_type,omitempty"`               // DNS checks: A (default), AAAA, CNAME, MX, NS, or TXT
+	ExpectedAnswer          string   `yaml:"expected_answer,omitempty"`           // DNS checks: down unless this is among the answers
+	ExpectedLocation        string   `yaml:"expected_location,omitempty"`         // Redirect target, exact or /regex/
+	ExpectedTrailer         string   `yaml:"expected_trailer,omitempty"`          // "Name: value" trailer sent after the body, e.g. "Grpc-Status: 0"
+	MinBodyBytes            int      `yaml:"min_body_bytes,omitempty"`            // Down if the body is shorter, e.g. truncated
+	ExpectedContentType     string   `yaml:"expected_content_type,omitempty"`     // Down unless the Content-Type is this media type, e.g. application/json
+	ExpectedRedirects       []string `yaml:"expected_redirects,omitempty"`        // Redirect chain, one "status location" hop each
+	Method                  string   `yaml:"method,omitempty"`                    // GET (default), HEAD, POST, PUT, PATCH, DELETE, or OPTIONS
+	RequestBody             string   `yaml:"request_body,omitempty"`              // Sent with the request, e.g. a JSON payload for POST
+	ExpectedCertFingerprint string   `yaml:"expected_cert_fingerprint,omitempty"` // Pinned leaf certificate SHA-256
+	BaselineURL             string   `yaml:"baseline_url,omitempty"`              // Compare url (the canary) against this
+	CompareFields           []string `yaml:"compare_fields,omitempty"`            // status, latency, body (default status and body)
+	LatencyTolerancePct     int      `yaml:"latency_tolerance_pct,omitempty"`     // Canary may be this % slower (default 50)
+	DegradedThresholdMs     int      `yaml:"degraded_threshold_ms,omitempty"`     // Up but slower than this is degraded; 0 disables
+	SlowMultiplier          float64  `yaml:"slow_multiplier,omitempty"`           // Slow alert at this many times the 24h average (default alerts.slow_multiplier)
+	ConsecutiveSuccesses    int      `yaml:"consecutive_successes,omitempty"`     // Up results in a row before a down check counts as up (default 1)
+	ConsecutiveFailures     int      `yaml:"consecutive_failures,omitempty"`      // Down results in a row before an incident (default alerts.consecutive_failures)
+	BodyContains            string   `yaml:"body_contains,omitempty"`             // Down unless the body contains this
+	BodyNotContains         string   `yaml:"body_not_contains,omitempty"`         // Down if the body contains this
+	AlertChannels           []string `yaml:"alert_channels,omitempty"`            // e.g. [slack] or [slack:oncall, email]; default all
+	AssertionType           string   `yaml:"assertion_type,omitempty"`            // json, xml, text, or auto (default: by Content-Type)
+	Assertions              []string `yaml:"assertions,omitempty"`                // e.g. "/health/status == ok" or "contains ready"
+
+	// Channels per alert type, e.g. recovery: [slack]; overrides alerts.routes
+	// and alert_channels for that type
+	AlertRoutes map[string][]string `yaml:"alert_routes,omitempty"`
+
+	// Extra request headers, e.g. Authorization: "Bearer ..."
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// Query parameters rendered per request from Go templates, e.g.
+	// ts: "{{.Now.Unix}}" or sig: '{{hmac (secret "API_KEY") .Path}}'
+	QueryParams map[string]string `yaml:"query_params,omitempty"`
+
+	// Re-alert while an incident stays open and unacknowledged
+	Escalations []EscalationConfig `yaml:"escalations,omitempty"`
+}
+
+// EscalationConfig re-alerts on an incident still open and unacknowledged
+// (status investigating) once After has passed since it started
+type EscalationConfig struct {
+	After    string `yaml:"after,omitempty"`    // e.g. "1h"
+	Channel  string `yaml:"channel,omitempty"`  // Alert channel like "email" or "slack:oncall"; empty means all
+	Severity string `yaml:"severity,omitempty"` // Severity targets filter on (default critical)
+}
+
+// GetAfter returns how long an incident must be open before escalating
+func (e *EscalationConfig) GetAfter() time.Duration {
+	d, _ := time.ParseDuration(e.After)
+	return d
 }
 
 // RegionConfig defines a probe region.
@@ -113,22 +760,59 @@ func DefaultConfig() *Config {
 }
 
 func Load(path string) (*Config, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return DefaultConfig(), nil
-		}
-		return nil, fmt.Errorf("reading config file: %w", err)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return DefaultConfig(), nil
 	}
 
 	config := DefaultConfig()
-	if err := yaml.Unmarshal(data, config); err != nil {
-		return nil, fmt.Errorf("parsing config file: %w", err)
+	if err := config.load(path, nil); err != nil {
+		return nil, err
 	}
+	config.Include = nil
 
 	return config, nil
 }
 
+// load merges one file into c, then the files it includes, in order. Later
+// files override the values of earlier ones, except checks, which accumulate
+// so they can be split across files. stack holds the files being loaded, to
+// catch include cycles.
+func (c *Config) load(path string, stack []string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("resolving config file: %w", err)
+	}
+	if slices.Contains(stack, abs) {
+		return fmt.Errorf("config include cycle: %s", strings.Join(append(stack, abs), " -> "))
+	}
+	stack = append(stack, abs)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+
+	checks := c.Checks
+	c.Checks, c.Include = nil, nil
+	if err := yaml.Unmarshal(data, c); err != nil {
+		if len(stack) > 1 {
+			return fmt.Errorf("parsing config file %s: %w", path, err)
+		}
+		return fmt.Errorf("parsing config file: %w", err)
+	}
+	c.Checks = append(checks, c.Checks...)
+
+	for _, include := range c.Include {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(path), include)
+		}
+		if err := c.load(include, stack); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func LoadWithEnv(path string) (*Config, error) {
 	config, err := Load(path)
 	if err != nil {
@@ -159,6 +843,12 @@ func applyEnvOverrides(c *Config) {
 	if v := os.Getenv("SENTINEL_DB_PATH"); v != "" {
 		c.Database.Path = v
 	}
+	if v := os.Getenv("SENTINEL_DB_DRIVER"); v != "" {
+		c.Database.Driver = v
+	}
+	if v := os.Getenv("SENTINEL_DB_DSN"); v != "" {
+		c.Database.DSN = v
+	}
 	if v := os.Getenv("SENTINEL_SMTP_HOST"); v != "" {
 		c.Alerts.Email.SMTPHost = v
 	}
@@ -182,15 +872,132 @@ func applyEnvOverrides(c *Config) {
 	if v := os.Getenv("SENTINEL_EMAIL_ENABLED"); v != "" {
 		c.Alerts.Email.Enabled = v == "true" || v == "1"
 	}
+	if v := os.Getenv("SENTINEL_TWILIO_AUTH_TOKEN"); v != "" {
+		c.Alerts.Twilio.AuthToken = v
+	}
+}
+
+// ValidationResult separates config problems that must stop startup from
+// ones worth logging but safe to run with
+type ValidationResult struct {
+	Errors   []error
+	Warnings []string
 }
 
+// Err returns the first error, or nil when there are only warnings
+func (r *ValidationResult) Err() error {
+	if len(r.Errors) == 0 {
+		return nil
+	}
+	return r.Errors[0]
+}
+
+// minCheckInterval is the shortest interval that doesn't draw a warning
+const minCheckInterval = 10 * time.Second
+
+// ValidateAll reports both hard errors and warnings
+func (c *Config) ValidateAll() *ValidationResult {
+	result := &ValidationResult{Warnings: c.warnings()}
+	if err := c.Validate(); err != nil {
+		result.Errors = append(result.Errors, err)
+	}
+	return result
+}
+
+// Validate returns the first problem that makes the config unusable
 func (c *Config) Validate() error {
 	if c.Server.Port < 1 || c.Server.Port > 65535 {
 		return fmt.Errorf("invalid port: %d", c.Server.Port)
 	}
 
-	if c.Database.Path == "" {
-		return fmt.Errorf("database path is required")
+	switch c.Database.GetDriver() {
+	case DriverSQLite:
+		if c.Database.Path == "" {
+			return fmt.Errorf("database path is required")
+		}
+	case DriverPostgres:
+		if c.Database.DSN == "" {
+			return fmt.Errorf("database dsn is required for the postgres driver")
+		}
+	default:
+		return fmt.Errorf("invalid database driver %q (use sqlite or postgres)", c.Database.Driver)
+	}
+
+	if c.Database.StatsCacheTTL != "" {
+		d, err := time.ParseDuration(c.Database.StatsCacheTTL)
+		if err != nil {
+			return fmt.Errorf("invalid stats_cache_ttl %q: %w", c.Database.StatsCacheTTL, err)
+		}
+		if d < 0 {
+			return fmt.Errorf("stats_cache_ttl cannot be negative")
+		}
+	}
+
+	for i, digest := range c.Server.APIKeys {
+		if b, err := hex.DecodeString(digest); err != nil || len(b) != 32 {
+			return fmt.Errorf("api_keys[%d]: expected a SHA-256 hex digest of the key", i)
+		}
+	}
+
+	if c.Server.BulkConcurrency < 0 {
+		return fmt.Errorf("bulk_concurrency cannot be negative")
+	}
+
+	if c.Server.DashboardIncidents < 0 {
+		return fmt.Errorf("dashboard_incidents cannot be negative")
+	}
+
+	if c.Server.DashboardRefresh != "" {
+		d, err := time.ParseDuration(c.Server.DashboardRefresh)
+		if err != nil {
+			return fmt.Errorf("invalid dashboard_refresh %q: %w", c.Server.DashboardRefresh, err)
+		}
+		if d < MinDashboardRefresh {
+			return fmt.Errorf("dashboard_refresh must be at least %s", MinDashboardRefresh)
+		}
+	}
+
+	if c.Server.BulkTimeout != "" {
+		if _, err := time.ParseDuration(c.Server.BulkTimeout); err != nil {
+			return fmt.Errorf("invalid bulk_timeout %q: %w", c.Server.BulkTimeout, err)
+		}
+	}
+
+	if u := c.Server.ConnectivityCheckURL; u != "" && !strings.HasPrefix(u, "http://") && !strings.HasPrefix(u, "https://") {
+		return fmt.Errorf("connectivity_check_url %q must be an http or https URL", u)
+	}
+
+	for i, origin := range c.Server.AllowedOrigins {
+		if origin == "*" {
+			continue
+		}
+		// Browsers send the bare origin, so a path would never match
+		rest, ok := strings.CutPrefix(origin, "https://")
+		if !ok {
+			rest, ok = strings.CutPrefix(origin, "http://")
+		}
+		if !ok || rest == "" || strings.Contains(rest, "/") {
+			return fmt.Errorf("allowed_origins[%d] %q must be * or a scheme and host such as https://app.example.com", i, origin)
+		}
+	}
+
+	if c.Limits.MaxConcurrentChecks < 0 || c.Limits.MaxConnsPerHost < 0 || c.Limits.MaxIdleConns < 0 {
+		return fmt.Errorf("limits cannot be negative")
+	}
+	if c.Checker.CABundle != "" {
+		if _, err := os.Stat(c.Checker.CABundle); err != nil {
+			return fmt.Errorf("checker.ca_bundle: %w", err)
+		}
+	}
+
+	if c.Limits.StartupRamp != "" {
+		d, err := time.ParseDuration(c.Limits.StartupRamp)
+		if err != nil {
+			return fmt.Errorf("invalid startup_ramp %q: %w", c.Limits.StartupRamp, err)
+		}
+		if d < 0 {
+			return fmt.Errorf("startup_ramp cannot be negative")
+		}
 	}
 
 	if c.Alerts.ConsecutiveFailures < 1 {
@@ -201,6 +1008,28 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("cooldown_minutes cannot be negative")
 	}
 
+	if c.Alerts.RenotifyMinutes < 0 {
+		return fmt.Errorf("renotify_minutes cannot be negative")
+	}
+
+	if c.Alerts.Timezone != "" {
+		if _, err := time.LoadLocation(c.Alerts.Timezone); err != nil {
+			return fmt.Errorf("invalid alerts timezone %q: %w", c.Alerts.Timezone, err)
+		}
+	}
+
+	for i, rule := range c.Alerts.CauseRules {
+		if rule.Match == "" || rule.Category == "" {
+			return fmt.Errorf("cause_rules[%d]: match and category are required", i)
+		}
+	}
+
+	for _, category := range c.Limits.RetryOn {
+		if !retryCategories[category] && !slices.ContainsFunc(c.Alerts.CauseRules, func(r CauseRule) bool { return r.Category == category }) {
+			return fmt.Errorf("invalid retry_on category %q (use transport, timeout, connection_refused, connection_reset, dns, tls, http, other, or a cause_rules category)", category)
+		}
+	}
+
 	if c.Alerts.Email.Enabled {
 		if c.Alerts.Email.SMTPHost == "" {
 			return fmt.Errorf("smtp_host is required when email is enabled")
@@ -211,11 +1040,106 @@ func (c *Config) Validate() error {
 		if c.Alerts.Email.FromAddress == "" {
 			return fmt.Errorf("from_address is required when email is enabled")
 		}
-		if len(c.Alerts.Email.ToAddresses) == 0 {
-			return fmt.Errorf("to_addresses is required when email is enabled")
+		if len(c.Alerts.Email.ToAddresses) == 0 && len(c.Alerts.Email.Recipients) == 0 {
+			return fmt.Errorf("to_addresses or recipients is required when email is enabled")
+		}
+		if err := validateRecipients(c.Alerts.Email.Recipients); err != nil {
+			return err
+		}
+	}
+
+	if c.Alerts.SlowMultiplier != 0 && c.Alerts.SlowMultiplier <= 1 {
+		return fmt.Errorf("slow_multiplier must be more than 1")
+	}
+
+	if c.Alerts.BreakerCooldown != "" {
+		if _, err := time.ParseDuration(c.Alerts.BreakerCooldown); err != nil {
+			return fmt.Errorf("invalid breaker_cooldown %q: %w", c.Alerts.BreakerCooldown, err)
 		}
 	}
 
+	if err := validateTargets("slack", c.Alerts.Slack.Targets); err != nil {
+		return err
+	}
+	if err := validateTargets("discord", c.Alerts.Discord.Targets); err != nil {
+		return err
+	}
+	if err := c.Alerts.validateRoutes(c.Alerts.Routes); err != nil {
+		return fmt.Errorf("alert routes: %w", err)
+	}
+	if err := c.Alerts.BusinessHours.validate(); err != nil {
+		return err
+	}
+
+	if c.Alerts.Webhook.Enabled {
+		webhook := c.Alerts.Webhook
+		if err := webhook.validateRequest(); err != nil {
+			return fmt.Errorf("webhook: %w", err)
+		}
+		if _, err := webhook.Template(); err != nil {
+			return fmt.Errorf("webhook: invalid body_template: %w", err)
+		}
+	}
+
+	if c.Alerts.Twilio.Enabled {
+		twilio := c.Alerts.Twilio
+		if twilio.AccountSID == "" || twilio.AuthToken == "" {
+			return fmt.Errorf("twilio: account_sid and auth_token are required when twilio is enabled")
+		}
+		if !phoneNumberPattern.MatchString(twilio.FromNumber) {
+			return fmt.Errorf("twilio: from_number %q must be in E.164 form, e.g. +15005550006", twilio.FromNumber)
+		}
+		if len(twilio.ToNumbers) == 0 {
+			return fmt.Errorf("twilio: to_numbers is required when twilio is enabled")
+		}
+		for _, number := range twilio.ToNumbers {
+			if !phoneNumberPattern.MatchString(number) {
+				return fmt.Errorf("twilio: to_numbers entry %q must be in E.164 form, e.g. +15005550006", number)
+			}
+		}
+	}
+
+	if c.Alerts.IncidentSync.Enabled {
+		sync := c.Alerts.IncidentSync
+		if err := sync.validateRequest(); err != nil {
+			return fmt.Errorf("incident_sync: %w", err)
+		}
+		if _, err := sync.Template(); err != nil {
+			return fmt.Errorf("incident_sync: invalid body_template: %w", err)
+		}
+	}
+
+	if err := c.StatusPage.validate(); err != nil {
+		return fmt.Errorf("status_page: %w", err)
+	}
+	for slug, page := range c.StatusPage.Pages {
+		if err := page.validate(); err != nil {
+			return fmt.Errorf("status_page %s: %w", slug, err)
+		}
+	}
+
+	if c.Events.Enabled && c.Events.WebhookURL == "" {
+		return fmt.Errorf("events webhook_url is required when events are enabled")
+	}
+
+	if c.StatsD.Port < 0 || c.StatsD.Port > 65535 {
+		return fmt.Errorf("invalid statsd port: %d", c.StatsD.Port)
+	}
+	if c.StatsD.FlushInterval != "" {
+		if d, err := time.ParseDuration(c.StatsD.FlushInterval); err != nil || d <= 0 {
+			return fmt.Errorf("invalid statsd flush_interval %q", c.StatsD.FlushInterval)
+		}
+	}
+
+	if c.Drift.Interval != "" {
+		if d, err := time.ParseDuration(c.Drift.Interval); err != nil || d <= 0 {
+			return fmt.Errorf("invalid drift interval %q", c.Drift.Interval)
+		}
+	}
+	if c.Drift.Action != "" && c.Drift.Action != "warn" && c.Drift.Action != "correct" {
+		return fmt.Errorf("invalid drift action %q (use warn or correct)", c.Drift.Action)
+	}
+
 	for i, check := range c.Checks {
 		if check.Name == "" {
 			return fmt.Errorf("check[%d]: name is required", i)
@@ -223,25 +1147,324 @@ func (c *Config) Validate() error {
 		if check.URL == "" {
 			return fmt.Errorf("check[%d]: url is required", i)
 		}
+		if check.ExpectedStatuses != "" && !statusSpecPattern.MatchString(strings.ToLower(check.ExpectedStatuses)) {
+			return fmt.Errorf("check[%d]: invalid expected_status %q (use codes like 204 or classes like 2xx, separated by commas)", i, check.ExpectedStatuses)
+		}
+		if check.Type != "" && check.Type != "http" && check.Type != "tcp" && check.Type != "dns" && check.Type != "ping" && check.Type != "heartbeat" {
+			return fmt.Errorf("check[%d]: invalid type %q (use http, tcp, dns, ping, or heartbeat)", i, check.Type)
+		}
+		if check.RecordType != "" && !recordTypes[strings.ToUpper(check.RecordType)] {
+			return fmt.Errorf("check[%d]: invalid record_type %q (use A, AAAA, CNAME, MX, NS, or TXT)", i, check.RecordType)
+		}
 		if check.Interval != "" {
 			if _, err := time.ParseDuration(check.Interval); err != nil {
 				return fmt.Errorf("check[%d]: invalid interval %q: %w", i, check.Interval, err)
 			}
 		}
+		if check.Cron != "" {
+			if check.Type == "heartbeat" {
+				return fmt.Errorf("check[%d]: heartbeat checks can't use cron, they wait for pushes", i)
+			}
+			if _, err := cron.Parse(check.Cron); err != nil {
+				return fmt.Errorf("check[%d]: %w", i, err)
+			}
+		}
 		if check.Timeout != "" {
-			if _, err := time.ParseDuration(check.Timeout); err != nil {
+			d, err := time.ParseDuration(check.Timeout)
+			if err != nil {
 				return fmt.Errorf("check[%d]: invalid timeout %q: %w", i, check.Timeout, err)
 			}
+			if d <= 0 {
+				return fmt.Errorf("check[%d]: timeout must be positive", i)
+			}
+		}
+		if check.SampleInterval != "" {
+			if _, err := time.ParseDuration(check.SampleInterval); err != nil {
+				return fmt.Errorf("check[%d]: invalid sample_interval %q: %w", i, check.SampleInterval, err)
+			}
+		}
+		for _, channel := range check.AlertChannels {
+			if provider, _, _ := strings.Cut(channel, ":"); !alertProviders[provider] {
+				return fmt.Errorf("check[%d]: invalid alert channel %q (use email, slack, discord, webhook, or sms)", i, channel)
+			}
+		}
+		if err := c.Alerts.validateRoutes(check.AlertRoutes); err != nil {
+			return fmt.Errorf("check[%d]: alert_routes: %w", i, err)
+		}
+		if check.Method != "" && !requestMethods[strings.ToUpper(check.Method)] {
+			return fmt.Errorf("check[%d]: invalid method %q (use GET, HEAD, POST, PUT, PATCH, DELETE, or OPTIONS)", i, check.Method)
+		}
+		if check.DegradedThresholdMs < 0 {
+			return fmt.Errorf("check[%d]: degraded_threshold_ms cannot be negative", i)
+		}
+		if check.SlowMultiplier != 0 && check.SlowMultiplier <= 1 {
+			return fmt.Errorf("check[%d]: slow_multiplier must be more than 1", i)
+		}
+		if check.ConsecutiveSuccesses < 0 {
+			return fmt.Errorf("check[%d]: consecutive_successes cannot be negative", i)
+		}
+		if check.ConsecutiveFailures < 0 {
+			return fmt.Errorf("check[%d]: consecutive_failures cannot be negative", i)
+		}
+		if check.MinBodyBytes < 0 {
+			return fmt.Errorf("check[%d]: min_body_bytes cannot be negative", i)
+		}
+		if check.ExpectedContentType != "" {
+			if mediaType, _, err := mime.ParseMediaType(check.ExpectedContentType); err != nil || !strings.Contains(mediaType, "/") {
+				return fmt.Errorf("check[%d]: invalid expected_content_type %q (use a media type like application/json)", i, check.ExpectedContentType)
+			}
+		}
+		if _, ok := check.QueryParams[""]; ok {
+			return fmt.Errorf("check[%d]: query_params: parameter name is required", i)
+		}
+		if check.AssertionType != "" && !assertionTypes[check.AssertionType] {
+			return fmt.Errorf("check[%d]: invalid assertion_type %q (use auto, json, text, or xml)", i, check.AssertionType)
+		}
+		for j, esc := range check.Escalations {
+			d, err := time.ParseDuration(esc.After)
+			if err != nil {
+				return fmt.Errorf("check[%d]: escalation[%d]: invalid after %q: %w", i, j, esc.After, err)
+			}
+			if d < time.Minute {
+				return fmt.Errorf("check[%d]: escalation[%d]: after must be at least 1m", i, j)
+			}
+			if esc.Severity != "" && !validSeverities[esc.Severity] {
+				return fmt.Errorf("check[%d]: escalation[%d]: invalid severity %q (use critical, warning, or info)", i, j, esc.Severity)
+			}
 		}
 	}
 
 	if c.Retention.ResultsDays < 1 {
 		return fmt.Errorf("results_days must be at least 1")
 	}
+	if c.Retention.ArchiveDays < 0 {
+		return fmt.Errorf("archive_days cannot be negative")
+	}
+	if c.Retention.ArchiveDays > 0 && c.Retention.ArchiveDays <= c.Retention.ResultsDays {
+		return fmt.Errorf("archive_days must be longer than results_days")
+	}
 
 	return nil
 }
 
+// warnings finds suspicious but runnable settings
+func (c *Config) warnings() []string {
+	var warnings []string
+
+	// A disabled channel is still checked so enabling it later doesn't surprise
+	email := c.Alerts.Email
+	if !email.Enabled && email.SMTPHost != "" {
+		if strings.ContainsAny(email.SMTPHost, " /:") {
+			warnings = append(warnings, fmt.Sprintf("email (disabled): smtp_host %q does not look like a hostname", email.SMTPHost))
+		}
+		if email.SMTPPort < 1 || email.SMTPPort > 65535 {
+			warnings = append(warnings, fmt.Sprintf("email (disabled): invalid smtp_port %d", email.SMTPPort))
+		}
+		if email.FromAddress == "" {
+			warnings = append(warnings, "email (disabled): from_address is not set")
+		}
+	}
+
+	// API keys turn auth on for the pages too, and only users can log in
+	if len(c.Server.APIKeys) > 0 && len(c.Server.Users) == 0 {
+		warnings = append(warnings, "server.api_keys is set without server.users: the dashboard will ask for a login no one can give")
+	}
+
+	if c.Alerts.Slack.Enabled && len(c.Alerts.Slack.GetTargets()) == 0 {
+		warnings = append(warnings, "slack is enabled but has no webhook_url or targets")
+	}
+	if c.Alerts.Discord.Enabled && len(c.Alerts.Discord.GetTargets()) == 0 {
+		warnings = append(warnings, "discord is enabled but has no webhook_url or targets")
+	}
+
+	for i, check := range c.Checks {
+		if check.Interval == "" {
+			continue
+		}
+		interval, err := time.ParseDuration(check.Interval)
+		if err != nil {
+			continue // Reported by Validate
+		}
+		if interval < minCheckInterval {
+			warnings = append(warnings, fmt.Sprintf("check[%d] %s: interval %s is very short", i, check.Name, interval))
+		}
+		// A heartbeat's timeout is its grace period, which may well be longer
+		if check.Timeout != "" && check.Type != "heartbeat" {
+			if timeout, err := time.ParseDuration(check.Timeout); err == nil && timeout >= interval {
+				warnings = append(warnings, fmt.Sprintf("check[%d] %s: timeout %s is not shorter than interval %s", i, check.Name, timeout, interval))
+			}
+		}
+	}
+
+	return warnings
+}
+
+func (c *LimitsConfig) GetMaxConcurrentChecks() int {
+	if c.MaxConcurrentChecks < 1 {
+		return 50
+	}
+	return c.MaxConcurrentChecks
+}
+
+func (c *LimitsConfig) GetMaxConnsPerHost() int {
+	if c.MaxConnsPerHost < 1 {
+		return 10
+	}
+	return c.MaxConnsPerHost
+}
+
+func (c *LimitsConfig) GetMaxIdleConns() int {
+	if c.MaxIdleConns < 1 {
+		return 100
+	}
+	return c.MaxIdleConns
+}
+
+// GetStartupRamp returns how long to spread startup's first runs over; zero
+// when unset or invalid
+func (c *LimitsConfig) GetStartupRamp() time.Duration {
+	d, err := time.ParseDuration(c.StartupRamp)
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
+}
+
+// GetAddr returns the agent's host:port
+func (c *StatsDConfig) GetAddr() string {
+	host := c.Host
+	if host == "" {
+		host = "localhost"
+	}
+	port := c.Port
+	if port == 0 {
+		port = 8125
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+func (c *StatsDConfig) GetPrefix() string {
+	if c.Prefix == "" {
+		return "sentinel"
+	}
+	return strings.TrimSuffix(c.Prefix, ".")
+}
+
+func (c *StatsDConfig) GetFlushInterval() time.Duration {
+	d, err := time.ParseDuration(c.FlushInterval)
+	if err != nil || d <= 0 {
+		return time.Second
+	}
+	return d
+}
+
+func (c *DriftConfig) GetInterval() time.Duration {
+	d, err := time.ParseDuration(c.Interval)
+	if err != nil || d <= 0 {
+		return 5 * time.Minute
+	}
+	return d
+}
+
+// Corrects reports whether drifted checks are restored, not just reported
+func (c *DriftConfig) Corrects() bool {
+	return c.Action == "correct"
+}
+
+func (c *ServerConfig) GetBulkConcurrency() int {
+	if c.BulkConcurrency < 1 {
+		return 5
+	}
+	return c.BulkConcurrency
+}
+
+func (c *ServerConfig) GetDashboardIncidents() int {
+	if c.DashboardIncidents < 1 {
+		return 5
+	}
+	return min(c.DashboardIncidents, MaxDashboardIncidents)
+}
+
+func (c *ServerConfig) GetDashboardRefresh() time.Duration {
+	d, err := time.ParseDuration(c.DashboardRefresh)
+	if err != nil || d <= 0 {
+		return 30 * time.Second
+	}
+	return max(d, MinDashboardRefresh)
+}
+
+func (c *ServerConfig) GetBulkTimeout() time.Duration {
+	if c.BulkTimeout == "" {
+		return time.Minute
+	}
+	d, err := time.ParseDuration(c.BulkTimeout)
+	if err != nil || d <= 0 {
+		return time.Minute
+	}
+	return d
+}
+
+func (c *AlertsConfig) GetBreakerFailures() int {
+	if c.BreakerFailures < 1 {
+		return 5
+	}
+	return c.BreakerFailures
+}
+
+func (c *AlertsConfig) GetBreakerCooldown() time.Duration {
+	if c.BreakerCooldown == "" {
+		return 10 * time.Minute
+	}
+	d, err := time.ParseDuration(c.BreakerCooldown)
+	if err != nil || d <= 0 {
+		return 10 * time.Minute
+	}
+	return d
+}
+
+// GetStaleIntervals returns how many intervals a check may go without a
+// result before it is flagged stale, or -1 when the watchdog is off
+func (c *AlertsConfig) GetStaleIntervals() int {
+	if c.StaleIntervals < 0 {
+		return -1
+	}
+	if c.StaleIntervals == 0 {
+		return 3
+	}
+	return c.StaleIntervals
+}
+
+// GetLocation returns the zone alert times are shown in, or nil to keep
+// each timestamp's own zone
+func (c *AlertsConfig) GetLocation() *time.Location {
+	if c.Timezone == "" {
+		return nil
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil
+	}
+	return loc
+}
+
+func (c *DatabaseConfig) GetDriver() string {
+	if c.Driver == "" {
+		return DriverSQLite
+	}
+	return c.Driver
+}
+
+func (c *DatabaseConfig) GetStatsCacheTTL() time.Duration {
+	if c.StatsCacheTTL == "" {
+		return 30 * time.Second
+	}
+	d, err := time.ParseDuration(c.StatsCacheTTL)
+	if err != nil || d < 0 {
+		return 30 * time.Second
+	}
+	return d
+}
+
 func (c *CheckConfig) GetInterval() time.Duration {
 	if c.Interval == "" {
 		return time.Hour
@@ -264,6 +1487,39 @@ func (c *CheckConfig) GetTimeout() time.Duration {
 	return d
 }
 
+func (c *CheckConfig) GetSampleInterval() time.Duration {
+	if c.SampleInterval == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(c.SampleInterval)
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
+}
+
+// GetMethod returns the request method in upper case; empty means GET
+func (c *CheckConfig) GetMethod() string {
+	return strings.ToUpper(c.Method)
+}
+
+// UnmarshalYAML accepts expected_status as a plain code or as a spec like
+// "200,204" or "2xx", which is kept in ExpectedStatuses
+func (c *CheckConfig) UnmarshalYAML(value *yaml.Node) error {
+	type plain CheckConfig
+	if value.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			key, val := value.Content[i], value.Content[i+1]
+			if key.Value == "expected_status" && val.Kind == yaml.ScalarNode {
+				if _, err := strconv.Atoi(val.Value); err != nil {
+					key.Value = "expected_statuses"
+				}
+			}
+		}
+	}
+	return value.Decode((*plain)(c))
+}
+
 func (c *CheckConfig) GetExpectedStatus() int {
 	if c.ExpectedStatus == 0 {
 		return 200
@@ -277,3 +1533,11 @@ func (c *CheckConfig) IsEnabled() bool {
 	}
 	return *c.Enabled
 }
+
+// FollowsRedirects reports whether redirects are followed (the default)
+func (c *CheckConfig) FollowsRedirects() bool {
+	if c.FollowRedirects == nil {
+		return true
+	}
+	return *c.FollowRedirects
+}
@@ -3,8 +3,11 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -88,6 +91,86 @@ checks:
 	}
 }
 
+func TestLoadEscalations(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "sentinel.yaml")
+
+	content := `
+checks:
+  - name: API
+    url: https://example.com
+    escalations:
+      - after: 1h
+        channel: slack:oncall
+      - after: 4h
+        severity: critical
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	c, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	escalations := c.Checks[0].Escalations
+	if len(escalations) != 2 || escalations[0].GetAfter() != time.Hour || escalations[0].Channel != "slack:oncall" {
+		t.Fatalf("unexpected escalations: %+v", escalations)
+	}
+
+	escalations[1].Severity = "urgent"
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for invalid escalation severity")
+	}
+	escalations[1].Severity = ""
+	escalations[1].After = "30s"
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for escalation under a minute")
+	}
+}
+
+func TestLoadExpectedStatusSpec(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "sentinel.yaml")
+
+	content := `
+checks:
+  - name: Plain
+    url: https://example.com
+    expected_status: 204
+  - name: Class
+    url: https://example.com/api
+    expected_status: 2xx
+  - name: List
+    url: https://example.com/upload
+    expected_status: "200,201"
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	c, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if c.Checks[0].GetExpectedStatus() != 204 || c.Checks[0].ExpectedStatuses != "" {
+		t.Errorf("expected plain 204, got %d %q", c.Checks[0].ExpectedStatus, c.Checks[0].ExpectedStatuses)
+	}
+	if c.Checks[1].ExpectedStatuses != "2xx" {
+		t.Errorf("expected spec 2xx, got %q", c.Checks[1].ExpectedStatuses)
+	}
+	if c.Checks[2].ExpectedStatuses != "200,201" {
+		t.Errorf("expected spec 200,201, got %q", c.Checks[2].ExpectedStatuses)
+	}
+
+	c.Checks[1].ExpectedStatuses = "2xz"
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for invalid expected_status spec")
+	}
+}
+
 func TestLoadInvalidYAML(t *testing.T) {
 	tmpDir := t.TempDir()
 	configPath := filepath.Join(tmpDir, "sentinel.yaml")
@@ -106,6 +189,84 @@ server:
 	}
 }
 
+func TestLoadIncludes(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"sentinel.yaml": `
+include:
+  - conf.d/checks.yaml
+  - prod.yaml
+server:
+  port: 8080
+  host: 127.0.0.1
+  users:
+    admin: secret
+checks:
+  - name: Main
+    url: https://main.example.com
+`,
+		"conf.d/checks.yaml": `
+include:
+  - more.yaml
+checks:
+  - name: Split
+    url: https://split.example.com
+`,
+		"conf.d/more.yaml": `
+checks:
+  - name: Nested
+    url: https://nested.example.com
+`,
+		"prod.yaml": `
+server:
+  port: 9090
+  users:
+    ops: hunter2
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	c, err := Load(filepath.Join(tmpDir, "sentinel.yaml"))
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	// The later file overrides the port but leaves the host alone
+	if c.Server.Port != 9090 || c.Server.Host != "127.0.0.1" {
+		t.Errorf("expected port 9090 on 127.0.0.1, got %d on %s", c.Server.Port, c.Server.Host)
+	}
+	if len(c.Server.Users) != 2 {
+		t.Errorf("expected users from both files, got %v", c.Server.Users)
+	}
+	var names []string
+	for _, check := range c.Checks {
+		names = append(names, check.Name)
+	}
+	if strings.Join(names, ",") != "Main,Split,Nested" {
+		t.Errorf("expected checks from every file in order, got %v", names)
+	}
+}
+
+func TestLoadIncludeErrors(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.WriteFile(filepath.Join(tmpDir, "a.yaml"), []byte("include: [b.yaml]\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "b.yaml"), []byte("include: [a.yaml]\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "missing.yaml"), []byte("include: [nope.yaml]\n"), 0644)
+
+	if _, err := Load(filepath.Join(tmpDir, "a.yaml")); err == nil || !strings.Contains(err.Error(), "include cycle") {
+		t.Errorf("expected an include cycle error, got %v", err)
+	}
+	if _, err := Load(filepath.Join(tmpDir, "missing.yaml")); err == nil {
+		t.Error("expected an error for a missing include")
+	}
+}
+
 func TestEnvOverrides(t *testing.T) {
 	tmpDir := t.TempDir()
 	configPath := filepath.Join(tmpDir, "sentinel.yaml")
@@ -205,6 +366,63 @@ func TestValidateCheckConfig(t *testing.T) {
 	if err := c.Validate(); err == nil {
 		t.Error("expected error for check with invalid interval")
 	}
+
+	c.Checks = []CheckConfig{
+		{Name: "Test", URL: "https://example.com", Cron: "0 9-17 * * mon-fri"},
+	}
+	if err := c.Validate(); err != nil {
+		t.Errorf("expected a cron schedule to be valid, got %v", err)
+	}
+
+	c.Checks = []CheckConfig{
+		{Name: "Test", URL: "https://example.com", Cron: "0 25 * * *"},
+	}
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for check with invalid cron")
+	}
+
+	c.Checks = []CheckConfig{
+		{Name: "Test", URL: "nightly-backup", Type: "heartbeat", Cron: "@daily"},
+	}
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for heartbeat check with cron")
+	}
+
+	c.Checks = []CheckConfig{
+		{Name: "Test", URL: "https://example.com", Timeout: "0s"},
+	}
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for check with non-positive timeout")
+	}
+
+	c.Checks = []CheckConfig{
+		{Name: "Test", URL: "https://example.com", ExpectedContentType: "json"},
+	}
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for check with an expected_content_type that isn't a media type")
+	}
+
+	c.Checks = []CheckConfig{
+		{Name: "Test", URL: "https://example.com", MinBodyBytes: -1},
+	}
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for check with negative min_body_bytes")
+	}
+}
+
+func TestValidateCABundle(t *testing.T) {
+	c := DefaultConfig()
+	c.Checker.CABundle = filepath.Join(t.TempDir(), "missing.pem")
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for a ca_bundle that doesn't exist")
+	}
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	os.WriteFile(bundle, []byte("-----BEGIN CERTIFICATE-----"), 0o600)
+	c.Checker.CABundle = bundle
+	if err := c.Validate(); err != nil {
+		t.Errorf("unexpected error for an existing ca_bundle: %v", err)
+	}
 }
 
 func TestCheckConfigHelpers(t *testing.T) {
@@ -226,11 +444,15 @@ func TestCheckConfigHelpers(t *testing.T) {
 	if !check.IsEnabled() {
 		t.Error("expected check to be enabled by default")
 	}
+	if check.GetSampleInterval() != 0 {
+		t.Errorf("expected sampling off by default, got %v", check.GetSampleInterval())
+	}
 
 	// Test custom values
 	check.Interval = "30s"
 	check.Timeout = "5s"
 	check.ExpectedStatus = 201
+	check.SampleInterval = "1m"
 	enabled := false
 	check.Enabled = &enabled
 
@@ -246,4 +468,734 @@ func TestCheckConfigHelpers(t *testing.T) {
 	if check.IsEnabled() {
 		t.Error("expected check to be disabled")
 	}
+	if check.GetSampleInterval() != time.Minute {
+		t.Errorf("expected sample interval 1m, got %v", check.GetSampleInterval())
+	}
+}
+
+func TestValidateAllWarnings(t *testing.T) {
+	c := DefaultConfig()
+	c.Alerts.Email.SMTPHost = "smtp example.com"
+	c.Alerts.Email.FromAddress = "sentinel@example.com"
+	c.Checks = []CheckConfig{
+		{Name: "Fast", URL: "https://fast.example.com", Interval: "2s"},
+		{Name: "Slow", URL: "https://slow.example.com", Interval: "30s", Timeout: "45s"},
+		{Name: "Fine", URL: "https://fine.example.com", Interval: "1m", Timeout: "10s"},
+	}
+
+	result := c.ValidateAll()
+	if err := result.Err(); err != nil {
+		t.Fatalf("expected warnings only, got error: %v", err)
+	}
+	if err := c.Validate(); err != nil {
+		t.Errorf("expected Validate to ignore warnings, got %v", err)
+	}
+
+	// Disabled email host, a short interval and a timeout longer than its interval
+	if len(result.Warnings) != 3 {
+		t.Errorf("expected 3 warnings, got %d: %v", len(result.Warnings), result.Warnings)
+	}
+	for _, w := range result.Warnings {
+		if strings.Contains(w, "Fine") {
+			t.Errorf("unexpected warning for a sane check: %s", w)
+		}
+	}
+}
+
+func TestValidateAllWarnsAPIKeysWithoutUsers(t *testing.T) {
+	c := DefaultConfig()
+	c.Server.APIKeys = []string{strings.Repeat("a", 64)}
+
+	result := c.ValidateAll()
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "server.users") {
+		t.Errorf("expected a warning about the dashboard login, got %v", result.Warnings)
+	}
+
+	c.Server.Users = map[string]string{"admin": "secret"}
+	if result := c.ValidateAll(); len(result.Warnings) != 0 {
+		t.Errorf("expected no warning with users set, got %v", result.Warnings)
+	}
+}
+
+func TestValidateAllErrors(t *testing.T) {
+	c := DefaultConfig()
+	c.Server.Port = 0
+	c.Checks = []CheckConfig{{Name: "Fast", URL: "https://fast.example.com", Interval: "1s"}}
+
+	result := c.ValidateAll()
+	if result.Err() == nil {
+		t.Error("expected an error for a bad port")
+	}
+	if len(result.Warnings) != 1 {
+		t.Errorf("expected warnings alongside errors, got %v", result.Warnings)
+	}
+}
+
+func TestLimitsHelpers(t *testing.T) {
+	limits := LimitsConfig{}
+
+	if limits.GetMaxConcurrentChecks() != 50 {
+		t.Errorf("expected default max concurrent checks 50, got %d", limits.GetMaxConcurrentChecks())
+	}
+	if limits.GetMaxConnsPerHost() != 10 {
+		t.Errorf("expected default max conns per host 10, got %d", limits.GetMaxConnsPerHost())
+	}
+	if limits.GetMaxIdleConns() != 100 {
+		t.Errorf("expected default max idle conns 100, got %d", limits.GetMaxIdleConns())
+	}
+	if limits.GetStartupRamp() != 0 {
+		t.Errorf("expected no startup ramp by default, got %v", limits.GetStartupRamp())
+	}
+
+	limits = LimitsConfig{MaxConcurrentChecks: 200, MaxConnsPerHost: 2, MaxIdleConns: 500, StartupRamp: "90s"}
+	if limits.GetMaxConcurrentChecks() != 200 || limits.GetMaxConnsPerHost() != 2 || limits.GetMaxIdleConns() != 500 {
+		t.Errorf("expected configured limits, got %+v", limits)
+	}
+	if limits.GetStartupRamp() != 90*time.Second {
+		t.Errorf("expected startup ramp 90s, got %v", limits.GetStartupRamp())
+	}
+}
+
+func TestValidateLimits(t *testing.T) {
+	c := DefaultConfig()
+	c.Limits.MaxConnsPerHost = -1
+
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for negative limit")
+	}
+
+	c = DefaultConfig()
+	c.Limits.StartupRamp = "soon"
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for invalid startup_ramp")
+	}
+}
+
+func TestServerBulkHelpers(t *testing.T) {
+	server := ServerConfig{}
+
+	if server.GetBulkConcurrency() != 5 {
+		t.Errorf("expected default bulk concurrency 5, got %d", server.GetBulkConcurrency())
+	}
+	if server.GetBulkTimeout() != time.Minute {
+		t.Errorf("expected default bulk timeout 1m, got %v", server.GetBulkTimeout())
+	}
+
+	server.BulkConcurrency = 2
+	server.BulkTimeout = "15s"
+
+	if server.GetBulkConcurrency() != 2 {
+		t.Errorf("expected bulk concurrency 2, got %d", server.GetBulkConcurrency())
+	}
+	if server.GetBulkTimeout() != 15*time.Second {
+		t.Errorf("expected bulk timeout 15s, got %v", server.GetBulkTimeout())
+	}
+}
+
+func TestValidateBulkTimeout(t *testing.T) {
+	c := DefaultConfig()
+	c.Server.BulkTimeout = "soon"
+
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for invalid bulk_timeout")
+	}
+}
+
+func TestValidateAPIKeys(t *testing.T) {
+	c := DefaultConfig()
+	c.Server.APIKeys = []string{"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("expected a SHA-256 digest to be valid, got %v", err)
+	}
+
+	c.Server.APIKeys = []string{"plaintext-key"}
+	if err := c.Validate(); err == nil {
+		t.Error("expected a plaintext key to be rejected")
+	}
+}
+
+func TestValidateEventsWebhook(t *testing.T) {
+	c := DefaultConfig()
+	c.Events.Enabled = true
+
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for events enabled without webhook_url")
+	}
+
+	c.Events.WebhookURL = "https://events.example.com/sentinel"
+	if err := c.Validate(); err != nil {
+		t.Errorf("expected valid config, got %v", err)
+	}
+}
+
+func TestValidateWebhook(t *testing.T) {
+	c := DefaultConfig()
+	c.Alerts.Webhook = WebhookConfig{Enabled: true, URL: "https://alerts.example.com/hook"}
+
+	if err := c.Validate(); err != nil {
+		t.Errorf("expected default template to be valid, got %v", err)
+	}
+
+	c.Alerts.Webhook.BodyTemplate = `{"name":{{json .Check.Name}`
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for unparseable body_template")
+	}
+
+	c.Alerts.Webhook.BodyTemplate = ""
+	c.Alerts.Webhook.Method = "DELETE"
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for invalid method")
+	}
+
+	c.Alerts.Webhook.Method = ""
+	c.Alerts.Webhook.URL = "alerts.example.com"
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for url without scheme")
+	}
+}
+
+func TestValidateTwilio(t *testing.T) {
+	c := DefaultConfig()
+	c.Alerts.Twilio = TwilioConfig{
+		Enabled:    true,
+		AccountSID: "AC123",
+		AuthToken:  "token",
+		FromNumber: "+15005550006",
+		ToNumbers:  []string{"+14155550100"},
+	}
+	if err := c.Validate(); err != nil {
+		t.Errorf("expected valid twilio config, got %v", err)
+	}
+
+	c.Alerts.Twilio.ToNumbers = []string{"415-555-0100"}
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for a number not in E.164 form")
+	}
+
+	c.Alerts.Twilio.ToNumbers = nil
+	if err := c.Validate(); err == nil {
+		t.Error("expected error without to_numbers")
+	}
+
+	c.Alerts.Twilio.ToNumbers = []string{"+14155550100"}
+	c.Alerts.Twilio.AuthToken = ""
+	if err := c.Validate(); err == nil {
+		t.Error("expected error without auth_token")
+	}
+
+	// sms is a channel checks can route to once enabled
+	c.Alerts.Twilio.AuthToken = "token"
+	c.Alerts.Routes = map[string][]string{"down": {"sms"}}
+	if err := c.Validate(); err != nil {
+		t.Errorf("expected a route to sms to be valid, got %v", err)
+	}
+}
+
+func TestValidateSlowMultiplier(t *testing.T) {
+	c := DefaultConfig()
+	c.Alerts.SlowMultiplier = 5
+	c.Checks = []CheckConfig{{Name: "API", URL: "https://api.example.com", SlowMultiplier: 2.5}}
+	if err := c.Validate(); err != nil {
+		t.Errorf("expected multipliers to be valid, got %v", err)
+	}
+
+	c.Checks[0].SlowMultiplier = 0.5
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for a check multiplier under 1")
+	}
+
+	c.Checks[0].SlowMultiplier = 0
+	c.Alerts.SlowMultiplier = 1
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for a global multiplier of 1")
+	}
+}
+
+func TestValidateIncidentSync(t *testing.T) {
+	var alerts AlertsConfig
+	data := `
+incident_sync:
+  enabled: true
+  url: https://example.service-now.com/api/now/table/incident
+  headers:
+    Authorization: Basic abc
+  id_field: result.sys_id
+`
+	if err := yaml.Unmarshal([]byte(data), &alerts); err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	sync := alerts.IncidentSync
+	if !sync.Enabled || sync.URL == "" || sync.Headers["Authorization"] != "Basic abc" || sync.IDField != "result.sys_id" {
+		t.Fatalf("expected the webhook fields inline, got %+v", sync)
+	}
+
+	c := DefaultConfig()
+	c.Alerts.IncidentSync = sync
+	if err := c.Validate(); err != nil {
+		t.Errorf("expected default template to be valid, got %v", err)
+	}
+
+	c.Alerts.IncidentSync.BodyTemplate = `{"event":{{json .Event}`
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for unparseable body_template")
+	}
+
+	c.Alerts.IncidentSync.BodyTemplate = ""
+	c.Alerts.IncidentSync.URL = "example.service-now.com"
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for url without scheme")
+	}
+}
+
+func TestWebhookTargets(t *testing.T) {
+	slack := SlackConfig{
+		WebhookURL: "https://hooks.slack.com/general",
+		Targets: []WebhookTarget{
+			{Name: "oncall", URL: "https://hooks.slack.com/oncall", Severities: []string{"critical"}},
+		},
+	}
+
+	targets := slack.GetTargets()
+	if len(targets) != 2 || targets[0].URL != "https://hooks.slack.com/general" {
+		t.Fatalf("expected webhook_url as the first target, got %+v", targets)
+	}
+
+	oncall := targets[1]
+	if !oncall.Matches("critical", nil) {
+		t.Error("expected critical alert to match")
+	}
+	if oncall.Matches("info", nil) {
+		t.Error("expected info alert not to match critical-only target")
+	}
+
+	tagged := WebhookTarget{URL: "https://example.com", Tags: []string{"payments"}}
+	if !tagged.Matches("info", []string{"api", "payments"}) {
+		t.Error("expected check with matching tag to match")
+	}
+	if tagged.Matches("info", []string{"api"}) {
+		t.Error("expected check without matching tag not to match")
+	}
+}
+
+func TestValidateWebhookTargets(t *testing.T) {
+	c := DefaultConfig()
+	c.Alerts.Discord.Targets = []WebhookTarget{{Name: "no-url"}}
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for target without url")
+	}
+
+	c.Alerts.Discord.Targets = []WebhookTarget{{URL: "https://example.com", Severities: []string{"urgent"}}}
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for invalid severity")
+	}
+}
+
+func TestEmailRecipientsFor(t *testing.T) {
+	email := EmailConfig{
+		ToAddresses: []string{"ops@example.com"},
+		Recipients: []EmailRecipients{
+			{Name: "oncall", To: []string{"oncall@example.com", "ops@example.com"}, Severities: []string{"critical"}},
+			{Name: "payments", To: []string{"payments@example.com"}, Tags: []string{"payments"}},
+		},
+	}
+
+	got := email.RecipientsFor("critical", []string{"api"})
+	if strings.Join(got, ",") != "ops@example.com,oncall@example.com" {
+		t.Errorf("expected ops and oncall without duplicates, got %v", got)
+	}
+
+	got = email.RecipientsFor("info", []string{"payments"})
+	if strings.Join(got, ",") != "ops@example.com,payments@example.com" {
+		t.Errorf("expected ops and payments, got %v", got)
+	}
+
+	email.ToAddresses = nil
+	if got := email.RecipientsFor("info", nil); len(got) != 0 {
+		t.Errorf("expected no recipients for an unrouted alert, got %v", got)
+	}
+}
+
+func TestValidateEmailRecipients(t *testing.T) {
+	c := DefaultConfig()
+	c.Alerts.Email.Enabled = true
+	c.Alerts.Email.SMTPHost = "smtp.example.com"
+	c.Alerts.Email.FromAddress = "sentinel@example.com"
+	c.Alerts.Email.Recipients = []EmailRecipients{{Name: "oncall", To: []string{"oncall@example.com"}, Severities: []string{"critical"}}}
+	if err := c.Validate(); err != nil {
+		t.Errorf("expected recipient sets to stand in for to_addresses, got %v", err)
+	}
+
+	c.Alerts.Email.Recipients = append(c.Alerts.Email.Recipients, EmailRecipients{Name: "empty"})
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for recipient set without addresses")
+	}
+
+	c.Alerts.Email.Recipients[1] = EmailRecipients{To: []string{"not-an-address"}}
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for invalid address")
+	}
+
+	c.Alerts.Email.Recipients[1] = EmailRecipients{To: []string{"info@example.com"}, Severities: []string{"urgent"}}
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for invalid severity")
+	}
+}
+
+func TestValidateConnectivityCheckURL(t *testing.T) {
+	c := DefaultConfig()
+	c.Server.ConnectivityCheckURL = "https://example.com"
+	if err := c.Validate(); err != nil {
+		t.Errorf("expected https URL to be valid, got %v", err)
+	}
+
+	c.Server.ConnectivityCheckURL = "example.com"
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for URL without scheme")
+	}
+}
+
+func TestValidateAllowedOrigins(t *testing.T) {
+	c := DefaultConfig()
+	c.Server.AllowedOrigins = []string{"https://app.example.com", "http://localhost:5173", "*"}
+	if err := c.Validate(); err != nil {
+		t.Errorf("expected origins to be valid, got %v", err)
+	}
+
+	for _, origin := range []string{"app.example.com", "https://app.example.com/", "https://", ""} {
+		c.Server.AllowedOrigins = []string{origin}
+		if err := c.Validate(); err == nil {
+			t.Errorf("expected error for origin %q", origin)
+		}
+	}
+}
+
+func TestStatsCacheTTL(t *testing.T) {
+	db := DatabaseConfig{}
+	if db.GetStatsCacheTTL() != 30*time.Second {
+		t.Errorf("expected default 30s, got %v", db.GetStatsCacheTTL())
+	}
+
+	db.StatsCacheTTL = "0s"
+	if db.GetStatsCacheTTL() != 0 {
+		t.Errorf("expected 0 to disable the cache, got %v", db.GetStatsCacheTTL())
+	}
+
+	c := DefaultConfig()
+	c.Database.StatsCacheTTL = "-5s"
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for negative stats_cache_ttl")
+	}
+}
+
+func TestStatusPageBranding(t *testing.T) {
+	sp := StatusPageConfig{
+		Branding: Branding{Title: "Acme Status", PrimaryColor: "#0066ff"},
+		Pages: map[string]Branding{
+			"internal": {Title: "Acme Internal"},
+		},
+	}
+
+	if b := sp.BrandingFor("public"); b.Title != "Acme Status" {
+		t.Errorf("expected default title, got %q", b.Title)
+	}
+	b := sp.BrandingFor("internal")
+	if b.Title != "Acme Internal" || b.PrimaryColor != "#0066ff" {
+		t.Errorf("expected page title over default color, got %+v", b)
+	}
+
+	c := DefaultConfig()
+	c.StatusPage.Pages = map[string]Branding{"public": {PrimaryColor: "red; background: url(x)"}}
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for non-hex primary_color")
+	}
+}
+
+func TestAlertsTimezone(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.Alerts.GetLocation() != nil {
+		t.Error("expected no display zone by default")
+	}
+
+	cfg.Alerts.Timezone = "Europe/Berlin"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid timezone, got %v", err)
+	}
+	if loc := cfg.Alerts.GetLocation(); loc == nil || loc.String() != "Europe/Berlin" {
+		t.Errorf("expected Europe/Berlin, got %v", loc)
+	}
+
+	cfg.Alerts.Timezone = "Mars/Olympus"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown timezone")
+	}
+}
+
+func TestValidateCauseRules(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Alerts.CauseRules = []CauseRule{{Match: "Request Blocked", Category: "waf"}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid cause rule, got %v", err)
+	}
+
+	cfg.Alerts.CauseRules = append(cfg.Alerts.CauseRules, CauseRule{Match: "maintenance"})
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for cause rule without a category")
+	}
+}
+
+func TestDashboardIncidents(t *testing.T) {
+	cfg := &ServerConfig{}
+	if got := cfg.GetDashboardIncidents(); got != 5 {
+		t.Errorf("expected default 5, got %d", got)
+	}
+	cfg.DashboardIncidents = 20
+	if got := cfg.GetDashboardIncidents(); got != 20 {
+		t.Errorf("expected 20, got %d", got)
+	}
+	cfg.DashboardIncidents = 5000
+	if got := cfg.GetDashboardIncidents(); got != MaxDashboardIncidents {
+		t.Errorf("expected clamp to %d, got %d", MaxDashboardIncidents, got)
+	}
+}
+
+func TestDashboardRefresh(t *testing.T) {
+	cfg := &ServerConfig{}
+	if got := cfg.GetDashboardRefresh(); got != 30*time.Second {
+		t.Errorf("expected default 30s, got %v", got)
+	}
+	cfg.DashboardRefresh = "10s"
+	if got := cfg.GetDashboardRefresh(); got != 10*time.Second {
+		t.Errorf("expected 10s, got %v", got)
+	}
+
+	c := DefaultConfig()
+	c.Server.DashboardRefresh = "2m"
+	if err := c.Validate(); err != nil {
+		t.Errorf("expected 2m to be valid, got %v", err)
+	}
+	c.Server.DashboardRefresh = "1s"
+	if err := c.Validate(); err == nil {
+		t.Error("expected an interval below the minimum to be rejected")
+	}
+	c.Server.DashboardRefresh = "often"
+	if err := c.Validate(); err == nil {
+		t.Error("expected an unparseable interval to be rejected")
+	}
+}
+
+func TestValidateArchiveDays(t *testing.T) {
+	c := DefaultConfig()
+	c.Retention.ArchiveDays = 730
+	if err := c.Validate(); err != nil {
+		t.Errorf("expected archive_days to be valid, got %v", err)
+	}
+
+	c.Retention.ArchiveDays = c.Retention.ResultsDays
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for archive_days not longer than results_days")
+	}
+}
+
+func TestGetStaleIntervals(t *testing.T) {
+	tests := []struct {
+		value int
+		want  int
+	}{
+		{0, 3},
+		{5, 5},
+		{-1, -1},
+		{-7, -1},
+	}
+	for _, tt := range tests {
+		c := AlertsConfig{StaleIntervals: tt.value}
+		if got := c.GetStaleIntervals(); got != tt.want {
+			t.Errorf("GetStaleIntervals() with %d = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestValidateDatabaseDriver(t *testing.T) {
+	c := DefaultConfig()
+	if c.Database.GetDriver() != DriverSQLite {
+		t.Errorf("expected sqlite by default, got %q", c.Database.GetDriver())
+	}
+
+	c.Database.Driver = DriverPostgres
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for postgres without a dsn")
+	}
+
+	c.Database.DSN = "postgres://sentinel@db/sentinel"
+	if err := c.Validate(); err != nil {
+		t.Errorf("expected postgres with a dsn to be valid, got %v", err)
+	}
+
+	c.Database.Driver = "mysql"
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for an unknown driver")
+	}
+}
+
+func TestValidateAssertionType(t *testing.T) {
+	c := DefaultConfig()
+	c.Checks = []CheckConfig{{Name: "Feed", URL: "https://feed.example.com", AssertionType: "xml"}}
+	if err := c.Validate(); err != nil {
+		t.Errorf("expected xml assertion_type to be valid, got %v", err)
+	}
+
+	c.Checks[0].AssertionType = "yaml"
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for an unknown assertion_type")
+	}
+}
+
+func TestValidateDNSCheck(t *testing.T) {
+	c := DefaultConfig()
+	c.Checks = []CheckConfig{{Name: "Apex DNS", URL: "example.com", Type: "dns", RecordType: "aaaa"}}
+	if err := c.Validate(); err != nil {
+		t.Errorf("expected dns check to be valid, got %v", err)
+	}
+
+	c.Checks[0].RecordType = "SRV"
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for an unknown record_type")
+	}
+}
+
+func TestValidateAlertRoutes(t *testing.T) {
+	c := DefaultConfig()
+	c.Alerts.Slack = SlackConfig{Enabled: true, WebhookURL: "https://hooks.slack.com/x", Targets: []WebhookTarget{{Name: "oncall", URL: "https://hooks.slack.com/y"}}}
+	c.Alerts.Routes = map[string][]string{"down": {"slack:oncall"}, "recovery": {"slack"}}
+	c.Checks = []CheckConfig{{Name: "API", URL: "https://api.example.com", AlertRoutes: map[string][]string{"ssl_expiry": {"slack"}}}}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("expected routes to enabled channels to be valid, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		routes map[string][]string
+	}{
+		{"unknown type", map[string][]string{"degraded": {"slack"}}},
+		{"no channels", map[string][]string{"down": {}}},
+		{"disabled provider", map[string][]string{"down": {"email"}}},
+		{"unknown target", map[string][]string{"down": {"slack:payments"}}},
+	}
+	for _, tt := range tests {
+		c.Alerts.Routes = tt.routes
+		if err := c.Validate(); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+
+	c.Alerts.Routes = nil
+	c.Checks[0].AlertRoutes = map[string][]string{"recovery": {"discord"}}
+	if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "check[0]") {
+		t.Errorf("expected error for a check routing to a disabled channel, got %v", err)
+	}
+}
+
+func TestBusinessHoursContains(t *testing.T) {
+	day := BusinessHoursConfig{Start: "09:00", End: "17:30"}
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"weekday morning", time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC), true}, // Monday
+		{"weekday before start", time.Date(2026, 3, 2, 8, 59, 0, 0, time.UTC), false},
+		{"weekday at end", time.Date(2026, 3, 6, 17, 30, 0, 0, time.UTC), false}, // Friday
+		{"weekend", time.Date(2026, 3, 7, 12, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		if got := day.Contains(tt.at); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+
+	// A night shift wraps past midnight and belongs to the day it starts on
+	night := BusinessHoursConfig{Days: []string{"fri"}, Start: "22:00", End: "06:00"}
+	if !night.Contains(time.Date(2026, 3, 7, 3, 0, 0, 0, time.UTC)) {
+		t.Error("expected early Saturday to be within Friday's night shift")
+	}
+	if night.Contains(time.Date(2026, 3, 6, 3, 0, 0, 0, time.UTC)) {
+		t.Error("expected early Friday to be outside Thursday's unscheduled shift")
+	}
+}
+
+func TestValidateBusinessHours(t *testing.T) {
+	c := DefaultConfig()
+	c.Alerts.BusinessHours = BusinessHoursConfig{Enabled: true, Days: []string{"Mon", "tue"}, Start: "08:00", End: "18:00", MinSeverity: "warning", OffHours: "drop"}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("expected valid business hours, got %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		hours BusinessHoursConfig
+	}{
+		{"bad day", BusinessHoursConfig{Days: []string{"someday"}}},
+		{"bad time", BusinessHoursConfig{Start: "9am"}},
+		{"bad severity", BusinessHoursConfig{MinSeverity: "urgent"}},
+		{"bad off_hours", BusinessHoursConfig{OffHours: "queue"}},
+	}
+	for _, tt := range tests {
+		c.Alerts.BusinessHours = tt.hours
+		if err := c.Validate(); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestValidateRetryOn(t *testing.T) {
+	c := DefaultConfig()
+	c.Limits.RetryOn = []string{"transport", "http"}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("expected valid retry_on, got %v", err)
+	}
+
+	c.Limits.RetryOn = []string{"waf"}
+	if err := c.Validate(); err == nil {
+		t.Error("expected an unknown category to be rejected")
+	}
+	c.Alerts.CauseRules = []CauseRule{{Match: "Request Blocked", Category: "waf"}}
+	if err := c.Validate(); err != nil {
+		t.Errorf("expected a cause_rules category to be accepted, got %v", err)
+	}
+}
+
+func TestStatsDDefaults(t *testing.T) {
+	c := &StatsDConfig{}
+	if c.GetAddr() != "localhost:8125" || c.GetPrefix() != "sentinel" || c.GetFlushInterval() != time.Second {
+		t.Errorf("unexpected defaults: %s %s %s", c.GetAddr(), c.GetPrefix(), c.GetFlushInterval())
+	}
+
+	cfg := DefaultConfig()
+	cfg.StatsD = StatsDConfig{Enabled: true, FlushInterval: "never"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for invalid flush_interval")
+	}
+}
+
+func TestDriftConfig(t *testing.T) {
+	c := &DriftConfig{}
+	if c.GetInterval() != 5*time.Minute || c.Corrects() {
+		t.Errorf("unexpected defaults: %s corrects=%v", c.GetInterval(), c.Corrects())
+	}
+
+	tests := []struct {
+		drift   DriftConfig
+		wantErr bool
+	}{
+		{DriftConfig{Enabled: true}, false},
+		{DriftConfig{Enabled: true, Interval: "1m", Action: "correct"}, false},
+		{DriftConfig{Enabled: true, Action: "revert"}, true},
+		{DriftConfig{Enabled: true, Interval: "-1m"}, true},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		cfg.Drift = tt.drift
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%+v) error = %v, wantErr %v", tt.drift, err, tt.wantErr)
+		}
+	}
 }
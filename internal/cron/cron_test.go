@@ -0,0 +1,81 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"10-5 * * * *",
+		"a * * * *",
+		"@often",
+		"0 0 30 2 *",
+	} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q): expected an error", spec)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	// A Wednesday
+	from := time.Date(2026, 1, 14, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 1, 14, 10, 8, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 1, 14, 11, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 1, 14, 10, 15, 0, 0, time.UTC)},
+		{"5/20 * * * *", time.Date(2026, 1, 14, 10, 25, 0, 0, time.UTC)},
+		{"0 9-17 * * mon-fri", time.Date(2026, 1, 14, 11, 0, 0, 0, time.UTC)},
+		{"0 9 * * sat,sun", time.Date(2026, 1, 17, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * 7", time.Date(2026, 1, 18, 9, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"30 6 1 jul *", time.Date(2026, 7, 1, 6, 30, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// With both day fields set, either one matches
+		{"0 12 20 * fri", time.Date(2026, 1, 16, 12, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		s, err := Parse(tt.spec)
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error: %v", tt.spec, err)
+			continue
+		}
+		if got := s.Next(from); !got.Equal(tt.want) {
+			t.Errorf("%q: expected %v, got %v", tt.spec, tt.want, got)
+		}
+	}
+}
+
+func TestNextInLocation(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("no time zone data")
+	}
+
+	s, _ := Parse("0 9 * * *")
+	from := time.Date(2026, 3, 28, 12, 0, 0, 0, loc)
+	// 9:00 local on both sides of the spring DST change
+	next := s.Next(from)
+	if want := time.Date(2026, 3, 29, 9, 0, 0, 0, loc); !next.Equal(want) {
+		t.Errorf("expected %v, got %v", want, next)
+	}
+	if want := time.Date(2026, 3, 30, 9, 0, 0, 0, loc); !s.Next(next).Equal(want) {
+		t.Errorf("expected %v, got %v", want, s.Next(next))
+	}
+}
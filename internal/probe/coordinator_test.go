@@ -75,6 +75,16 @@ func (m *mockStorage) ListChecksByTag(tag string) ([]*storage.Check, error) {
 	return result, nil
 }
 
+func (m *mockStorage) GetChecksModifiedSince(t time.Time) ([]*storage.Check, error) {
+	var result []*storage.Check
+	for _, c := range m.checks {
+		if c.UpdatedAt.After(t) {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
 func (m *mockStorage) UpdateCheck(check *storage.Check) error {
 	for i, c := range m.checks {
 		if c.ID == check.ID {
@@ -85,6 +95,14 @@ func (m *mockStorage) UpdateCheck(check *storage.Check) error {
 	return nil
 }
 
+func (m *mockStorage) SetCheckPaused(id int64, paused bool) error {
+	return nil
+}
+
+func (m *mockStorage) PauseCheckUntil(id int64, until time.Time) error {
+	return nil
+}
+
 func (m *mockStorage) DeleteCheck(id int64) error {
 	for i, c := range m.checks {
 		if c.ID == id {
@@ -99,14 +117,42 @@ func (m *mockStorage) SaveResult(result *storage.CheckResult) error {
 	return nil
 }
 
+func (m *mockStorage) ResetCheckHistory(checkID int64) error {
+	return nil
+}
+
+func (m *mockStorage) ExtendResult(result *storage.CheckResult) error {
+	return nil
+}
+
+func (m *mockStorage) ImportResults(results []*storage.CheckResult) (int, int, error) {
+	return 0, 0, nil
+}
+
 func (m *mockStorage) GetResults(checkID int64, limit int, offset int) ([]*storage.CheckResult, error) {
 	return nil, nil
 }
 
+func (m *mockStorage) CountResults(checkID int64) (int, error) {
+	return 0, nil
+}
+
 func (m *mockStorage) GetLatestResult(checkID int64) (*storage.CheckResult, error) {
 	return nil, nil
 }
 
+func (m *mockStorage) GetLatestResults() (map[int64][]*storage.CheckResult, error) {
+	return nil, nil
+}
+
+func (m *mockStorage) ListChecksByStatus(status string) ([]*storage.Check, error) {
+	return nil, nil
+}
+
+func (m *mockStorage) FilterChecks(filter storage.CheckFilter) ([]*storage.Check, error) {
+	return nil, nil
+}
+
 func (m *mockStorage) GetLatestResultsByRegion(checkID int64) (map[string]*storage.CheckResult, error) {
 	return nil, nil
 }
@@ -123,10 +169,26 @@ func (m *mockStorage) GetRecentResults(checkID int64, count int) ([]*storage.Che
 	return nil, nil
 }
 
+func (m *mockStorage) GetResultDetails(checkID int64, limit int) ([]*storage.CheckResult, error) {
+	return nil, nil
+}
+
 func (m *mockStorage) GetStats(checkID int64) (*storage.CheckStats, error) {
 	return nil, nil
 }
 
+func (m *mockStorage) SaveGoldenSnapshot(snapshot *storage.GoldenSnapshot) error {
+	return nil
+}
+
+func (m *mockStorage) GetGoldenSnapshot(checkID int64) (*storage.GoldenSnapshot, error) {
+	return nil, nil
+}
+
+func (m *mockStorage) DeleteGoldenSnapshot(checkID int64) error {
+	return nil
+}
+
 func (m *mockStorage) CreateIncident(incident *storage.Incident) error {
 	return nil
 }
@@ -155,10 +217,18 @@ func (m *mockStorage) UpdateIncidentTitle(id int64, title string) error {
 	return nil
 }
 
+func (m *mockStorage) SetIncidentExternalID(id int64, externalID string) error {
+	return nil
+}
+
 func (m *mockStorage) ListIncidents(limit int, offset int) ([]*storage.Incident, error) {
 	return nil, nil
 }
 
+func (m *mockStorage) CountIncidents() (int, error) {
+	return 0, nil
+}
+
 func (m *mockStorage) ListIncidentsForCheck(checkID int64, limit int) ([]*storage.Incident, error) {
 	return nil, nil
 }
@@ -167,6 +237,14 @@ func (m *mockStorage) ListActiveIncidents() ([]*storage.Incident, error) {
 	return nil, nil
 }
 
+func (m *mockStorage) GetUptimeSeries(checkID int64, start, end time.Time, resolution time.Duration) ([]*storage.UptimePoint, error) {
+	return nil, nil
+}
+
+func (m *mockStorage) GetIncidentStats(checkID int64, start, end time.Time) (*storage.IncidentStats, error) {
+	return nil, nil
+}
+
 func (m *mockStorage) AddIncidentNote(note *storage.IncidentNote) error {
 	return nil
 }
@@ -179,6 +257,34 @@ func (m *mockStorage) DeleteIncidentNote(id int64) error {
 	return nil
 }
 
+func (m *mockStorage) CreateMaintenanceWindow(window *storage.MaintenanceWindow) error {
+	return nil
+}
+
+func (m *mockStorage) ListMaintenanceWindows() ([]*storage.MaintenanceWindow, error) {
+	return nil, nil
+}
+
+func (m *mockStorage) ListActiveMaintenanceWindows(at time.Time) ([]*storage.MaintenanceWindow, error) {
+	return nil, nil
+}
+
+func (m *mockStorage) DeleteMaintenanceWindow(id int64) error {
+	return nil
+}
+
+func (m *mockStorage) GetSetting(key string) (string, error) {
+	return "", nil
+}
+
+func (m *mockStorage) SetSetting(key, value string) error {
+	return nil
+}
+
+func (m *mockStorage) DeleteSetting(key string) error {
+	return nil
+}
+
 func (m *mockStorage) LogAlert(log *storage.AlertLog) error {
 	return nil
 }
@@ -207,6 +313,18 @@ func (m *mockStorage) CleanupOldAggregates(olderThan time.Time) error {
 	return nil
 }
 
+func (m *mockStorage) ArchiveResults(olderThan time.Time) error {
+	return nil
+}
+
+func (m *mockStorage) CleanupOldArchives(olderThan time.Time) error {
+	return nil
+}
+
+func (m *mockStorage) Backup(path string) error {
+	return nil
+}
+
 func (m *mockStorage) Close() error {
 	return nil
 }
@@ -0,0 +1,235 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Result archives keep history past results retention as one blob per check
+// per UTC day. A result packs into a handful of bytes: its offset from the
+// previous one, status, status code, latency, weight and region. Error
+// messages, SSL details and the like stay with the raw rows.
+
+// archiveVersion prefixes every blob so the layout can change later
+const archiveVersion = 1
+
+// encodeArchive packs a day's results, oldest first, for storage
+func encodeArchive(day time.Time, results []*CheckResult) []byte {
+	buf := []byte{archiveVersion}
+	prev := day
+	for _, r := range results {
+		delta := r.CheckedAt.Sub(prev).Milliseconds()
+		if delta < 0 {
+			delta = 0
+		}
+		prev = prev.Add(time.Duration(delta) * time.Millisecond)
+
+		// Results are up, down or degraded; anything else counts as down
+		var status byte = 1
+		switch r.Status {
+		case "up":
+			status = 0
+		case "degraded":
+			status = 2
+		}
+
+		buf = binary.AppendUvarint(buf, uint64(delta))
+		buf = append(buf, status)
+		buf = binary.AppendUvarint(buf, uint64(max(r.StatusCode, 0)))
+		buf = binary.AppendUvarint(buf, uint64(max(r.ResponseTimeMs, 0)))
+		buf = binary.AppendUvarint(buf, uint64(max(r.Weight, 1)))
+		buf = binary.AppendUvarint(buf, uint64(len(r.Region)))
+		buf = append(buf, r.Region...)
+	}
+	return buf
+}
+
+// decodeArchive unpacks a blob written by encodeArchive
+func decodeArchive(checkID int64, day time.Time, data []byte) ([]*CheckResult, error) {
+	if len(data) == 0 || data[0] != archiveVersion {
+		return nil, fmt.Errorf("unsupported archive version")
+	}
+	data = data[1:]
+
+	uvarint := func() (int, error) {
+		v, n := binary.Uvarint(data)
+		if n <= 0 {
+			return 0, fmt.Errorf("truncated archive")
+		}
+		data = data[n:]
+		return int(v), nil
+	}
+
+	var results []*CheckResult
+	at := day
+	for len(data) > 0 {
+		delta, err := uvarint()
+		if err != nil {
+			return nil, err
+		}
+		if len(data) == 0 {
+			return nil, fmt.Errorf("truncated archive")
+		}
+		var status string
+		switch data[0] {
+		case 0:
+			status = "up"
+		case 2:
+			status = "degraded"
+		default:
+			status = "down"
+		}
+		data = data[1:]
+
+		var fields [4]int
+		for i := range fields {
+			if fields[i], err = uvarint(); err != nil {
+				return nil, err
+			}
+		}
+		regionLen := fields[3]
+		if regionLen > len(data) {
+			return nil, fmt.Errorf("truncated archive")
+		}
+
+		at = at.Add(time.Duration(delta) * time.Millisecond)
+		results = append(results, &CheckResult{
+			CheckID:        checkID,
+			Region:         string(data[:regionLen]),
+			Status:         status,
+			StatusCode:     fields[0],
+			ResponseTimeMs: fields[1],
+			Weight:         fields[2],
+			CheckedAt:      at,
+		})
+		data = data[regionLen:]
+	}
+	return results, nil
+}
+
+// ArchiveResults moves raw results older than the cutoff into day archives,
+// merging with any archive already written for that day
+func (s *SQLiteStorage) ArchiveResults(olderThan time.Time) error {
+	checks, err := s.ListChecks()
+	if err != nil {
+		return fmt.Errorf("listing checks for archiving: %w", err)
+	}
+
+	for _, check := range checks {
+		if err := s.archiveCheckResults(check.ID, olderThan); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SQLiteStorage) archiveCheckResults(checkID int64, olderThan time.Time) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting archive: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT COALESCE(region, ''), status, COALESCE(status_code, 0), COALESCE(response_time_ms, 0), weight, checked_at
+		FROM check_results WHERE check_id = ? AND checked_at < ?
+	`, checkID, olderThan)
+	if err != nil {
+		return fmt.Errorf("querying results to archive: %w", err)
+	}
+
+	days := make(map[time.Time][]*CheckResult)
+	for rows.Next() {
+		r := &CheckResult{CheckID: checkID}
+		if err := rows.Scan(&r.Region, &r.Status, &r.StatusCode, &r.ResponseTimeMs, &r.Weight, &r.CheckedAt); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning result to archive: %w", err)
+		}
+		r.CheckedAt = r.CheckedAt.UTC()
+		day := r.CheckedAt.Truncate(24 * time.Hour)
+		days[day] = append(days[day], r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("reading results to archive: %w", err)
+	}
+	if len(days) == 0 {
+		return nil
+	}
+
+	for day, results := range days {
+		var existing []byte
+		err := tx.QueryRow(`SELECT data FROM result_archives WHERE check_id = ? AND day = ?`, checkID, day).Scan(&existing)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("reading archive: %w", err)
+		}
+		if len(existing) > 0 {
+			archived, err := decodeArchive(checkID, day, existing)
+			if err != nil {
+				return fmt.Errorf("decoding archive for %s: %w", day.Format("2006-01-02"), err)
+			}
+			results = append(archived, results...)
+		}
+		sort.SliceStable(results, func(i, j int) bool { return results[i].CheckedAt.Before(results[j].CheckedAt) })
+
+		if _, err := tx.Exec(`
+			INSERT INTO result_archives (check_id, day, data) VALUES (?, ?, ?)
+			ON CONFLICT (check_id, day) DO UPDATE SET data = excluded.data
+		`, checkID, day, encodeArchive(day, results)); err != nil {
+			return fmt.Errorf("writing archive: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM check_results WHERE check_id = ? AND checked_at < ?`, checkID, olderThan); err != nil {
+		return fmt.Errorf("deleting archived results: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing archive: %w", err)
+	}
+	return nil
+}
+
+// CleanupOldArchives deletes day archives that ended before the cutoff
+func (s *SQLiteStorage) CleanupOldArchives(olderThan time.Time) error {
+	_, err := s.db.Exec("DELETE FROM result_archives WHERE day <= ?", olderThan.UTC().Add(-24*time.Hour))
+	if err != nil {
+		return fmt.Errorf("cleaning up old archives: %w", err)
+	}
+	return nil
+}
+
+// archivedResults returns a check's archived results between start and end,
+// oldest first
+func (s *SQLiteStorage) archivedResults(checkID int64, start, end time.Time) ([]*CheckResult, error) {
+	rows, err := s.db.Query(`
+		SELECT day, data FROM result_archives
+		WHERE check_id = ? AND day BETWEEN ? AND ? ORDER BY day
+	`, checkID, start.UTC().Truncate(24*time.Hour), end.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("querying archives: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*CheckResult
+	for rows.Next() {
+		var day time.Time
+		var data []byte
+		if err := rows.Scan(&day, &data); err != nil {
+			return nil, fmt.Errorf("scanning archive: %w", err)
+		}
+		archived, err := decodeArchive(checkID, day.UTC(), data)
+		if err != nil {
+			return nil, fmt.Errorf("decoding archive for %s: %w", day.Format("2006-01-02"), err)
+		}
+		for _, r := range archived {
+			if !r.CheckedAt.Before(start) && !r.CheckedAt.After(end) {
+				results = append(results, r)
+			}
+		}
+	}
+	return results, rows.Err()
+}
@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestArchiveEncodeDecode(t *testing.T) {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	results := []*CheckResult{
+		{Status: "up", StatusCode: 200, ResponseTimeMs: 120, Weight: 1, CheckedAt: day.Add(90 * time.Second)},
+		{Status: "down", StatusCode: 503, ResponseTimeMs: 0, Weight: 1, CheckedAt: day.Add(2*time.Minute + 250*time.Millisecond)},
+		{Status: "up", StatusCode: 200, ResponseTimeMs: 95, Weight: 12, Region: "eu-west", CheckedAt: day.Add(23 * time.Hour)},
+		{Status: "degraded", StatusCode: 200, ResponseTimeMs: 2400, Weight: 1, CheckedAt: day.Add(23*time.Hour + time.Minute)},
+	}
+
+	data := encodeArchive(day, results)
+	decoded, err := decodeArchive(7, day, data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(decoded) != len(results) {
+		t.Fatalf("expected %d results, got %d", len(results), len(decoded))
+	}
+	for i, want := range results {
+		got := decoded[i]
+		if got.CheckID != 7 || got.Status != want.Status || got.StatusCode != want.StatusCode ||
+			got.ResponseTimeMs != want.ResponseTimeMs || got.Weight != want.Weight || got.Region != want.Region ||
+			!got.CheckedAt.Equal(want.CheckedAt) {
+			t.Errorf("result %d: got %+v, want %+v", i, got, want)
+		}
+	}
+
+	if _, err := decodeArchive(7, day, data[:len(data)-3]); err == nil {
+		t.Error("expected error for a truncated archive")
+	}
+}
+
+func TestArchiveResults(t *testing.T) {
+	s := setupTestDB(t)
+
+	check := &Check{Name: "Archive", URL: "https://archive.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	s.CreateCheck(check)
+
+	now := time.Now()
+	old := now.Add(-10 * 24 * time.Hour)
+	s.SaveResult(&CheckResult{CheckID: check.ID, Status: "up", StatusCode: 200, ResponseTimeMs: 100, CheckedAt: old})
+	s.SaveResult(&CheckResult{CheckID: check.ID, Status: "down", StatusCode: 500, ErrorMessage: "boom", CheckedAt: old.Add(time.Minute)})
+	s.SaveResult(&CheckResult{CheckID: check.ID, Status: "up", StatusCode: 200, ResponseTimeMs: 300, Weight: 2, CheckedAt: old.Add(2 * time.Minute)})
+	s.SaveResult(&CheckResult{CheckID: check.ID, Status: "up", StatusCode: 200, ResponseTimeMs: 50, CheckedAt: now.Add(-time.Hour)})
+
+	before, _ := s.GetStats(check.ID)
+
+	if err := s.ArchiveResults(now.Add(-7 * 24 * time.Hour)); err != nil {
+		t.Fatalf("failed to archive: %v", err)
+	}
+
+	// Only the recent result stays a raw row
+	raw, _ := s.GetResults(check.ID, 10, 0)
+	if len(raw) != 1 {
+		t.Errorf("expected 1 raw result after archiving, got %d", len(raw))
+	}
+
+	// Stats and ranges read archives like raw rows
+	after, _ := s.GetStats(check.ID)
+	if *after != *before {
+		t.Errorf("expected stats unchanged by archiving, got %+v, want %+v", after, before)
+	}
+
+	results, err := s.GetResultsInRange(check.ID, now.Add(-30*24*time.Hour), now)
+	if err != nil {
+		t.Fatalf("failed to get range: %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results in range, got %d", len(results))
+	}
+	if results[1].Status != "down" || results[1].StatusCode != 500 || results[2].Weight != 2 || results[3].ResponseTimeMs != 50 {
+		t.Errorf("unexpected archived results: %+v %+v %+v", results[1], results[2], results[3])
+	}
+
+	// A later pass merges into the day already archived
+	s.SaveResult(&CheckResult{CheckID: check.ID, Status: "up", StatusCode: 200, ResponseTimeMs: 80, CheckedAt: old.Add(3 * time.Minute)})
+	if err := s.ArchiveResults(now.Add(-7 * 24 * time.Hour)); err != nil {
+		t.Fatalf("failed to archive again: %v", err)
+	}
+	results, _ = s.GetResultsInRange(check.ID, now.Add(-30*24*time.Hour), now)
+	if len(results) != 5 || results[3].ResponseTimeMs != 80 {
+		t.Errorf("expected the new result merged in order, got %d results", len(results))
+	}
+
+	points, err := s.GetUptimeSeries(check.ID, now.Add(-30*24*time.Hour), now, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("failed to get uptime series: %v", err)
+	}
+	total := 0
+	for _, p := range points {
+		total += p.TotalChecks
+	}
+	if total != 6 {
+		t.Errorf("expected archived results in the uptime series, got %d checks", total)
+	}
+}
+
+func TestCleanupOldArchives(t *testing.T) {
+	s := setupTestDB(t)
+
+	check := &Check{Name: "Archive Cleanup", URL: "https://archivecleanup.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	s.CreateCheck(check)
+
+	now := time.Now()
+	s.SaveResult(&CheckResult{CheckID: check.ID, Status: "up", StatusCode: 200, CheckedAt: now.Add(-400 * 24 * time.Hour)})
+	s.SaveResult(&CheckResult{CheckID: check.ID, Status: "up", StatusCode: 200, CheckedAt: now.Add(-100 * 24 * time.Hour)})
+	s.ArchiveResults(now.Add(-7 * 24 * time.Hour))
+
+	if err := s.CleanupOldArchives(now.Add(-365 * 24 * time.Hour)); err != nil {
+		t.Fatalf("failed to cleanup archives: %v", err)
+	}
+
+	results, _ := s.GetResultsInRange(check.ID, now.Add(-500*24*time.Hour), now)
+	if len(results) != 1 {
+		t.Errorf("expected only the newer archived day kept, got %d results", len(results))
+	}
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"sync"
+	"time"
+)
+
+// StatsCache wraps a Storage and caches GetStats per check for a short TTL.
+// Saving a result for a check drops its entry, so fresh results are visible
+// immediately; everything else passes straight through.
+type StatsCache struct {
+	Storage
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[int64]statsEntry
+}
+
+type statsEntry struct {
+	stats   CheckStats
+	expires time.Time
+}
+
+func NewStatsCache(inner Storage, ttl time.Duration) *StatsCache {
+	return &StatsCache{
+		Storage: inner,
+		ttl:     ttl,
+		entries: make(map[int64]statsEntry),
+	}
+}
+
+func (c *StatsCache) GetStats(checkID int64) (*CheckStats, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[checkID]
+	c.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		stats := entry.stats
+		return &stats, nil
+	}
+
+	stats, err := c.Storage.GetStats(checkID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[checkID] = statsEntry{stats: *stats, expires: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+
+	return stats, nil
+}
+
+func (c *StatsCache) SaveResult(result *CheckResult) error {
+	err := c.Storage.SaveResult(result)
+	c.InvalidateStats(result.CheckID)
+	return err
+}
+
+func (c *StatsCache) ResetCheckHistory(checkID int64) error {
+	err := c.Storage.ResetCheckHistory(checkID)
+	c.InvalidateStats(checkID)
+	return err
+}
+
+func (c *StatsCache) ExtendResult(result *CheckResult) error {
+	err := c.Storage.ExtendResult(result)
+	c.InvalidateStats(result.CheckID)
+	return err
+}
+
+func (c *StatsCache) ImportResults(results []*CheckResult) (int, int, error) {
+	imported, skipped, err := c.Storage.ImportResults(results)
+	for _, result := range results {
+		c.InvalidateStats(result.CheckID)
+	}
+	return imported, skipped, err
+}
+
+func (c *StatsCache) DeleteCheck(id int64) error {
+	err := c.Storage.DeleteCheck(id)
+	c.InvalidateStats(id)
+	return err
+}
+
+// InvalidateStats drops the cached stats for a check
+func (c *StatsCache) InvalidateStats(checkID int64) {
+	c.mu.Lock()
+	delete(c.entries, checkID)
+	c.mu.Unlock()
+}
@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStatsCache(t *testing.T) {
+	s := setupTestDB(t)
+	cache := NewStatsCache(s, time.Minute)
+
+	check := &Check{Name: "Cached", URL: "https://cached.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	cache.CreateCheck(check)
+	cache.SaveResult(&CheckResult{CheckID: check.ID, Status: "up", ResponseTimeMs: 100})
+
+	stats, err := cache.GetStats(check.ID)
+	if err != nil {
+		t.Fatalf("failed to get stats: %v", err)
+	}
+	if stats.UptimePercent24h != 100 {
+		t.Errorf("expected 100%% uptime, got %v", stats.UptimePercent24h)
+	}
+
+	// Writes that bypass the cache are not seen until the entry expires
+	s.SaveResult(&CheckResult{CheckID: check.ID, Status: "down"})
+	stats, _ = cache.GetStats(check.ID)
+	if stats.UptimePercent24h != 100 {
+		t.Errorf("expected cached 100%% uptime, got %v", stats.UptimePercent24h)
+	}
+
+	// Saving through the cache invalidates the entry
+	cache.SaveResult(&CheckResult{CheckID: check.ID, Status: "down"})
+	stats, _ = cache.GetStats(check.ID)
+	if stats.UptimePercent24h >= 50 {
+		t.Errorf("expected fresh uptime below 50%%, got %v", stats.UptimePercent24h)
+	}
+
+	// Imports through the cache invalidate it too
+	cache.ImportResults([]*CheckResult{
+		{CheckID: check.ID, Status: "down", CheckedAt: time.Now().Add(-time.Minute)},
+		{CheckID: check.ID, Status: "down", CheckedAt: time.Now().Add(-2 * time.Minute)},
+	})
+	stats, _ = cache.GetStats(check.ID)
+	if stats.UptimePercent24h != 20 {
+		t.Errorf("expected imported results to count, got %v", stats.UptimePercent24h)
+	}
+
+	// Callers can't modify the cached copy
+	stats.UptimePercent24h = 0
+	again, _ := cache.GetStats(check.ID)
+	if again.UptimePercent24h == 0 {
+		t.Error("expected cached stats to be copied")
+	}
+}
+
+func TestStatsCacheExpires(t *testing.T) {
+	s := setupTestDB(t)
+	cache := NewStatsCache(s, 10*time.Millisecond)
+
+	check := &Check{Name: "Expiring", URL: "https://expiring.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	s.CreateCheck(check)
+	s.SaveResult(&CheckResult{CheckID: check.ID, Status: "up"})
+
+	cache.GetStats(check.ID)
+	s.SaveResult(&CheckResult{CheckID: check.ID, Status: "down"})
+	time.Sleep(20 * time.Millisecond)
+
+	stats, _ := cache.GetStats(check.ID)
+	if stats.UptimePercent24h != 50 {
+		t.Errorf("expected 50%% uptime after expiry, got %v", stats.UptimePercent24h)
+	}
+}
+
+func TestStatsCacheConcurrent(t *testing.T) {
+	s := setupTestDB(t)
+	cache := NewStatsCache(s, time.Minute)
+
+	check := &Check{Name: "Concurrent", URL: "https://concurrent.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	s.CreateCheck(check)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cache.SaveResult(&CheckResult{CheckID: check.ID, Status: "up"})
+			if _, err := cache.GetStats(check.ID); err != nil {
+				t.Errorf("failed to get stats: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
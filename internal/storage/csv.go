@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ResultsCSVHeader names the columns WriteResultsCSV writes, after the
+// result's JSON fields
+var ResultsCSVHeader = []string{"checked_at", "status", "status_code", "response_time_ms", "error_message", "region"}
+
+// WriteResultsCSV writes results as CSV, one row each after a header, for
+// spreadsheets and reports. Times are RFC3339 in UTC.
+func WriteResultsCSV(w io.Writer, results []*CheckResult) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(ResultsCSVHeader); err != nil {
+		return err
+	}
+	for _, r := range results {
+		err := out.Write([]string{
+			r.CheckedAt.UTC().Format(time.RFC3339),
+			r.Status,
+			strconv.Itoa(r.StatusCode),
+			strconv.Itoa(r.ResponseTimeMs),
+			r.ErrorMessage,
+			r.Region,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+)
+
+// sqlDB runs storage queries against SQLite or PostgreSQL. Queries are
+// written once, with ? placeholders and SQL both databases accept; for
+// PostgreSQL the placeholders are rewritten to $1, $2, ...
+type sqlDB struct {
+	*sql.DB
+	postgres bool
+}
+
+func (d *sqlDB) Exec(query string, args ...any) (sql.Result, error) {
+	return d.DB.Exec(rebind(query, d.postgres), args...)
+}
+
+func (d *sqlDB) Query(query string, args ...any) (*sql.Rows, error) {
+	return d.DB.Query(rebind(query, d.postgres), args...)
+}
+
+func (d *sqlDB) QueryRow(query string, args ...any) *sql.Row {
+	return d.DB.QueryRow(rebind(query, d.postgres), args...)
+}
+
+func (d *sqlDB) Begin() (*sqlTx, error) {
+	tx, err := d.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	return &sqlTx{Tx: tx, postgres: d.postgres}, nil
+}
+
+// insert runs an INSERT and returns the new row's id. LastInsertId isn't
+// available on PostgreSQL, so the id comes back through RETURNING instead.
+func (d *sqlDB) insert(query string, args ...any) (int64, error) {
+	var id int64
+	err := d.QueryRow(query+" RETURNING id", args...).Scan(&id)
+	return id, err
+}
+
+// sqlTx is a transaction on a sqlDB, rewriting placeholders the same way
+type sqlTx struct {
+	*sql.Tx
+	postgres bool
+}
+
+func (t *sqlTx) Exec(query string, args ...any) (sql.Result, error) {
+	return t.Tx.Exec(rebind(query, t.postgres), args...)
+}
+
+func (t *sqlTx) Query(query string, args ...any) (*sql.Rows, error) {
+	return t.Tx.Query(rebind(query, t.postgres), args...)
+}
+
+func (t *sqlTx) QueryRow(query string, args ...any) *sql.Row {
+	return t.Tx.QueryRow(rebind(query, t.postgres), args...)
+}
+
+func (t *sqlTx) insert(query string, args ...any) (int64, error) {
+	var id int64
+	err := t.QueryRow(query+" RETURNING id", args...).Scan(&id)
+	return id, err
+}
+
+// rebind numbers the ? placeholders in query for PostgreSQL, leaving any
+// inside string literals alone
+func rebind(query string, postgres bool) string {
+	if !postgres || !strings.Contains(query, "?") {
+		return query
+	}
+
+	var b strings.Builder
+	b.Grow(len(query) + 16)
+	n := 0
+	quoted := false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'':
+			quoted = !quoted
+		case c == '?' && !quoted:
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
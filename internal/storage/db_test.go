@@ -0,0 +1,29 @@
+package storage
+
+import "testing"
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		query    string
+		postgres bool
+		want     string
+	}{
+		{"SELECT * FROM checks WHERE id = ?", false, "SELECT * FROM checks WHERE id = ?"},
+		{"SELECT * FROM checks WHERE id = ?", true, "SELECT * FROM checks WHERE id = $1"},
+		{"UPDATE checks SET name = ?, url = ? WHERE id = ?", true, "UPDATE checks SET name = $1, url = $2 WHERE id = $3"},
+		{"SELECT COALESCE(region, '?') FROM check_results WHERE check_id = ?", true, "SELECT COALESCE(region, '?') FROM check_results WHERE check_id = $1"},
+		{"SELECT 1", true, "SELECT 1"},
+	}
+
+	for _, tt := range tests {
+		if got := rebind(tt.query, tt.postgres); got != tt.want {
+			t.Errorf("rebind(%q, %v) = %q, want %q", tt.query, tt.postgres, got, tt.want)
+		}
+	}
+}
+
+func TestNewPostgresStorageWithoutDriver(t *testing.T) {
+	if _, err := NewPostgresStorage("postgres://localhost/sentinel"); err == nil {
+		t.Error("expected an error when the postgres driver isn't built in")
+	}
+}
@@ -0,0 +1,191 @@
+package storage
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// EncryptionKeyEnv names the environment variable holding the key sensitive
+// columns are encrypted with. Without it nothing is encrypted.
+const EncryptionKeyEnv = "SENTINEL_DB_KEY"
+
+// encryptedPrefix marks an encrypted value, so rows written before a key was
+// set can be told apart and encrypted in place
+const encryptedPrefix = "enc:v1:"
+
+// encryptionCheckSetting holds a known value sealed with the key, so a
+// wrong or missing key is caught at startup instead of on first read
+const (
+	encryptionCheckSetting = "encryption_check"
+	encryptionCheckValue   = "sentinel"
+)
+
+// columnCipher encrypts sensitive column values with AES-256-GCM. The nonce
+// is derived from the value, so equal values encrypt equally and lookups like
+// GetCheckByURL still work; that reveals which rows share a value, and
+// nothing else. A nil columnCipher leaves values as they are.
+type columnCipher struct {
+	aead     cipher.AEAD
+	nonceKey []byte
+}
+
+// newColumnCipher derives the cipher from key, returning nil for no key
+func newColumnCipher(key string) (*columnCipher, error) {
+	if key == "" {
+		return nil, nil
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+
+	nonceKey := sha256.Sum256([]byte("sentinel nonce:" + key))
+	return &columnCipher{aead: aead, nonceKey: nonceKey[:]}, nil
+}
+
+func (c *columnCipher) seal(value string) string {
+	if c == nil || value == "" || strings.HasPrefix(value, encryptedPrefix) {
+		return value
+	}
+
+	mac := hmac.New(sha256.New, c.nonceKey)
+	mac.Write([]byte(value))
+	nonce := mac.Sum(nil)[:c.aead.NonceSize()]
+
+	sealed := c.aead.Seal(nonce, nonce, []byte(value), nil)
+	return encryptedPrefix + base64.RawStdEncoding.EncodeToString(sealed)
+}
+
+// open decrypts a stored value; values without the prefix are returned as
+// they are
+func (c *columnCipher) open(stored string) (string, error) {
+	if !strings.HasPrefix(stored, encryptedPrefix) {
+		return stored, nil
+	}
+	if c == nil {
+		return "", fmt.Errorf("database is encrypted; set %s", EncryptionKeyEnv)
+	}
+
+	data, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(stored, encryptedPrefix))
+	if err != nil || len(data) < c.aead.NonceSize() {
+		return "", fmt.Errorf("decrypting column: malformed value")
+	}
+	size := c.aead.NonceSize()
+	plain, err := c.aead.Open(nil, data[:size], data[size:], nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypting column: wrong %s?", EncryptionKeyEnv)
+	}
+	return string(plain), nil
+}
+
+// setupEncryption checks the key against the database and encrypts values
+// stored before it was set. A database that was encrypted can't be opened
+// without its key.
+func (s *SQLiteStorage) setupEncryption() error {
+	stored, err := s.GetSetting(encryptionCheckSetting)
+	if err != nil {
+		return err
+	}
+	if stored != "" {
+		if _, err := s.cipher.open(stored); err != nil {
+			return err
+		}
+	}
+	if s.cipher == nil {
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting encryption: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Only rows not yet encrypted need it, so this is a no-op after the first run
+	rows, err := tx.Query(`
+		SELECT id, url, COALESCE(baseline_url, ''), COALESCE(headers, ''), COALESCE(request_body, ''), COALESCE(query_params, '') FROM checks
+		WHERE url NOT LIKE 'enc:%' OR (COALESCE(baseline_url, '') <> '' AND baseline_url NOT LIKE 'enc:%')
+			OR (COALESCE(headers, '') <> '' AND headers NOT LIKE 'enc:%')
+			OR (COALESCE(request_body, '') <> '' AND request_body NOT LIKE 'enc:%')
+			OR (COALESCE(query_params, '') <> '' AND query_params NOT LIKE 'enc:%')
+	`)
+	if err != nil {
+		return fmt.Errorf("querying checks to encrypt: %w", err)
+	}
+	type plainCheck struct {
+		id                                            int64
+		url, baselineURL, headers, requestBody, query string
+	}
+	var checks []plainCheck
+	for rows.Next() {
+		var c plainCheck
+		if err := rows.Scan(&c.id, &c.url, &c.baselineURL, &c.headers, &c.requestBody, &c.query); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning check to encrypt: %w", err)
+		}
+		checks = append(checks, c)
+	}
+	rows.Close()
+	for _, c := range checks {
+		if _, err := tx.Exec(`UPDATE checks SET url = ?, baseline_url = ?, headers = ?, request_body = ?, query_params = ? WHERE id = ?`,
+			s.cipher.seal(c.url), s.cipher.seal(c.baselineURL), s.cipher.seal(c.headers), s.cipher.seal(c.requestBody), s.cipher.seal(c.query), c.id); err != nil {
+			return fmt.Errorf("encrypting check: %w", err)
+		}
+	}
+
+	rows, err = tx.Query(`SELECT id, api_key FROM probes WHERE api_key NOT LIKE 'enc:%'`)
+	if err != nil {
+		return fmt.Errorf("querying probes to encrypt: %w", err)
+	}
+	keys := map[int64]string{}
+	for rows.Next() {
+		var id int64
+		var key string
+		if err := rows.Scan(&id, &key); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning probe to encrypt: %w", err)
+		}
+		keys[id] = key
+	}
+	rows.Close()
+	for id, key := range keys {
+		if _, err := tx.Exec(`UPDATE probes SET api_key = ? WHERE id = ?`, s.cipher.seal(key), id); err != nil {
+			return fmt.Errorf("encrypting probe: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(`
+		INSERT INTO settings (key, value) VALUES (?, ?)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value
+	`, encryptionCheckSetting, s.cipher.seal(encryptionCheckValue)); err != nil {
+		return fmt.Errorf("saving encryption check: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// decryptCheck replaces a scanned check's encrypted columns with their values
+func (s *SQLiteStorage) decryptCheck(check *Check) error {
+	var err error
+	if check.URL, err = s.cipher.open(check.URL); err != nil {
+		return err
+	}
+	if check.BaselineURL, err = s.cipher.open(check.BaselineURL); err != nil {
+		return err
+	}
+	if check.RequestBody, err = s.cipher.open(check.RequestBody); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEncryptedColumns(t *testing.T) {
+	t.Setenv(EncryptionKeyEnv, "correct horse battery staple")
+	dbPath := filepath.Join(t.TempDir(), "encrypted.db")
+
+	s, err := NewSQLiteStorage(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	check := &Check{Name: "Secret", URL: "https://internal.example.com/?token=abc", BaselineURL: "https://blue.example.com", Headers: map[string]string{"Authorization": "Bearer secret"}, RequestBody: `{"token":"secret"}`, QueryParams: map[string]string{"key": "secret"}, IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	s.CreateCheck(check)
+	probe := &Probe{Name: "eu-1", Region: "eu", APIKey: "probe-secret", Status: "active"}
+	s.CreateProbe(probe)
+
+	// Stored values are ciphertext
+	var rawURL, rawBaseline, rawHeaders, rawBody, rawQuery, rawKey string
+	s.db.QueryRow(`SELECT url, baseline_url, headers, request_body, query_params FROM checks WHERE id = ?`, check.ID).Scan(&rawURL, &rawBaseline, &rawHeaders, &rawBody, &rawQuery)
+	s.db.QueryRow(`SELECT api_key FROM probes WHERE id = ?`, probe.ID).Scan(&rawKey)
+	for _, raw := range []string{rawURL, rawBaseline, rawHeaders, rawBody, rawQuery, rawKey} {
+		if !strings.HasPrefix(raw, encryptedPrefix) || strings.Contains(raw, "example") || strings.Contains(raw, "secret") {
+			t.Errorf("expected an encrypted value, got %q", raw)
+		}
+	}
+
+	// Reads and lookups see the plain values
+	got, err := s.GetCheckByURL("https://internal.example.com/?token=abc")
+	if err != nil || got == nil {
+		t.Fatalf("expected lookup by URL to find the check, got %v (%v)", got, err)
+	}
+	if got.URL != check.URL || got.BaselineURL != check.BaselineURL {
+		t.Errorf("expected decrypted URLs, got %q and %q", got.URL, got.BaselineURL)
+	}
+	if got.Headers["Authorization"] != "Bearer secret" {
+		t.Errorf("expected decrypted headers, got %v", got.Headers)
+	}
+	if got.RequestBody != check.RequestBody || got.QueryParams["key"] != "secret" {
+		t.Errorf("expected decrypted body and query parameters, got %q and %v", got.RequestBody, got.QueryParams)
+	}
+	if p, _ := s.GetProbeByAPIKey("probe-secret"); p == nil || p.APIKey != "probe-secret" {
+		t.Errorf("expected lookup by API key to find the probe, got %+v", p)
+	}
+	s.Close()
+
+	// The database can't be opened without its key, or with another one
+	t.Setenv(EncryptionKeyEnv, "")
+	if _, err := NewSQLiteStorage(dbPath); err == nil || !strings.Contains(err.Error(), EncryptionKeyEnv) {
+		t.Errorf("expected an error without the key, got %v", err)
+	}
+	t.Setenv(EncryptionKeyEnv, "wrong key")
+	if _, err := NewSQLiteStorage(dbPath); err == nil {
+		t.Error("expected an error with the wrong key")
+	}
+}
+
+func TestEncryptExistingRows(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "plain.db")
+
+	s, err := NewSQLiteStorage(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	check := &Check{Name: "Old", URL: "https://old.example.com", RequestBody: "token=old", QueryParams: map[string]string{"key": "old"}, IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	s.CreateCheck(check)
+	s.Close()
+
+	// Setting a key encrypts what was stored without one
+	t.Setenv(EncryptionKeyEnv, "new key")
+	s, err = NewSQLiteStorage(dbPath)
+	if err != nil {
+		t.Fatalf("failed to reopen with a key: %v", err)
+	}
+	defer s.Close()
+
+	var rawURL, rawBody, rawQuery string
+	s.db.QueryRow(`SELECT url, request_body, query_params FROM checks WHERE id = ?`, check.ID).Scan(&rawURL, &rawBody, &rawQuery)
+	for _, raw := range []string{rawURL, rawBody, rawQuery} {
+		if !strings.HasPrefix(raw, encryptedPrefix) {
+			t.Errorf("expected the existing value to be encrypted, got %q", raw)
+		}
+	}
+	if got, _ := s.GetCheck(check.ID); got == nil || got.URL != "https://old.example.com" || got.RequestBody != "token=old" || got.QueryParams["key"] != "old" {
+		t.Errorf("expected the decrypted URL, got %+v", got)
+	}
+}
@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// Maintenance window recurrences
+const (
+	MaintenanceOnce   = "once"
+	MaintenanceDaily  = "daily"
+	MaintenanceWeekly = "weekly"
+)
+
+// MaintenanceWindow holds back incidents and alerts for one check, or for
+// every check when CheckID is 0, while results keep being recorded. Recurring
+// windows repeat at StartsAt's time of day, and weekly ones on its weekday.
+type MaintenanceWindow struct {
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`
+	CheckID      int64     `json:"check_id,omitempty"`
+	StartsAt     time.Time `json:"starts_at"`
+	DurationSecs int       `json:"duration_seconds"`
+	Recurrence   string    `json:"recurrence"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Validate rejects windows that could never be active or would overlap
+// their own next occurrence
+func (w *MaintenanceWindow) Validate() error {
+	if w.DurationSecs < 1 {
+		return fmt.Errorf("duration must be positive")
+	}
+	if w.StartsAt.IsZero() {
+		return fmt.Errorf("starts_at is required")
+	}
+
+	duration := time.Duration(w.DurationSecs) * time.Second
+	switch w.Recurrence {
+	case MaintenanceOnce:
+	case MaintenanceDaily:
+		if duration >= 24*time.Hour {
+			return fmt.Errorf("daily windows must be shorter than a day")
+		}
+	case MaintenanceWeekly:
+		if duration >= 7*24*time.Hour {
+			return fmt.Errorf("weekly windows must be shorter than a week")
+		}
+	default:
+		return fmt.Errorf("invalid recurrence %q (use once, daily, or weekly)", w.Recurrence)
+	}
+	return nil
+}
+
+// IsActiveAt reports whether t falls inside the window or, for recurring
+// windows, inside the occurrence started most recently before t
+func (w *MaintenanceWindow) IsActiveAt(t time.Time) bool {
+	if t.Before(w.StartsAt) {
+		return false
+	}
+
+	start := w.StartsAt
+	if w.Recurrence == MaintenanceDaily || w.Recurrence == MaintenanceWeekly {
+		loc := w.StartsAt.Location()
+		local := t.In(loc)
+		hour, minute, sec := w.StartsAt.Clock()
+		start = time.Date(local.Year(), local.Month(), local.Day(), hour, minute, sec, 0, loc)
+		if start.After(local) {
+			start = start.AddDate(0, 0, -1)
+		}
+		for w.Recurrence == MaintenanceWeekly && start.Weekday() != w.StartsAt.Weekday() {
+			start = start.AddDate(0, 0, -1)
+		}
+	}
+
+	return t.Sub(start) < time.Duration(w.DurationSecs)*time.Second
+}
+
+// AppliesTo reports whether the window covers the check
+func (w *MaintenanceWindow) AppliesTo(checkID int64) bool {
+	return w.CheckID == 0 || w.CheckID == checkID
+}
+
+// InMaintenance reports whether any of the active windows covers the check
+func InMaintenance(active []*MaintenanceWindow, checkID int64) bool {
+	for _, w := range active {
+		if w.AppliesTo(checkID) {
+			return true
+		}
+	}
+	return false
+}
+
+// BannerSetting is the settings key the maintenance banner is stored under
+const BannerSetting = "maintenance_banner"
+
+// Banner is a message announcing planned work, shown on the dashboard and
+// status pages between StartsAt and EndsAt (each optional). Unlike a
+// maintenance window it only informs viewers; alerts are unaffected.
+type Banner struct {
+	Message   string     `json:"message"`
+	StartsAt  *time.Time `json:"starts_at,omitempty"`
+	EndsAt    *time.Time `json:"ends_at,omitempty"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func (b *Banner) Validate() error {
+	if b.Message == "" {
+		return fmt.Errorf("message is required")
+	}
+	if b.StartsAt != nil && b.EndsAt != nil && !b.EndsAt.After(*b.StartsAt) {
+		return fmt.Errorf("ends_at must be after starts_at")
+	}
+	return nil
+}
+
+// IsActiveAt reports whether the banner should be shown at t
+func (b *Banner) IsActiveAt(t time.Time) bool {
+	if b.StartsAt != nil && t.Before(*b.StartsAt) {
+		return false
+	}
+	return b.EndsAt == nil || t.Before(*b.EndsAt)
+}
@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMaintenanceWindowIsActiveAt(t *testing.T) {
+	// A Monday night window running past midnight
+	start := time.Date(2024, 3, 4, 23, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		recurrence string
+		at         time.Time
+		want       bool
+	}{
+		{"before start", MaintenanceOnce, start.Add(-time.Minute), false},
+		{"once inside", MaintenanceOnce, start.Add(time.Hour), true},
+		{"once after", MaintenanceOnce, start.Add(2 * time.Hour), false},
+		{"once next day", MaintenanceOnce, start.Add(24 * time.Hour), false},
+		{"daily past midnight", MaintenanceDaily, time.Date(2024, 3, 10, 0, 15, 0, 0, time.UTC), true},
+		{"daily midday", MaintenanceDaily, time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC), false},
+		{"weekly on monday", MaintenanceWeekly, time.Date(2024, 3, 11, 23, 15, 0, 0, time.UTC), true},
+		{"weekly past midnight", MaintenanceWeekly, time.Date(2024, 3, 12, 0, 15, 0, 0, time.UTC), true},
+		{"weekly on tuesday night", MaintenanceWeekly, time.Date(2024, 3, 12, 23, 15, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &MaintenanceWindow{StartsAt: start, DurationSecs: 5400, Recurrence: tt.recurrence}
+			if got := w.IsActiveAt(tt.at); got != tt.want {
+				t.Errorf("IsActiveAt(%s) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaintenanceWindowValidate(t *testing.T) {
+	start := time.Now()
+
+	tests := []struct {
+		name    string
+		window  MaintenanceWindow
+		wantErr bool
+	}{
+		{"valid once", MaintenanceWindow{StartsAt: start, DurationSecs: 3600, Recurrence: MaintenanceOnce}, false},
+		{"zero duration", MaintenanceWindow{StartsAt: start, Recurrence: MaintenanceOnce}, true},
+		{"missing start", MaintenanceWindow{DurationSecs: 3600, Recurrence: MaintenanceOnce}, true},
+		{"daily too long", MaintenanceWindow{StartsAt: start, DurationSecs: 86400, Recurrence: MaintenanceDaily}, true},
+		{"weekly a day long", MaintenanceWindow{StartsAt: start, DurationSecs: 86400, Recurrence: MaintenanceWeekly}, false},
+		{"unknown recurrence", MaintenanceWindow{StartsAt: start, DurationSecs: 3600, Recurrence: "hourly"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.window.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMaintenanceWindowsCRUD(t *testing.T) {
+	s := setupTestDB(t)
+
+	check := &Check{Name: "Maintained", URL: "https://maintained.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	s.CreateCheck(check)
+
+	now := time.Now()
+	global := &MaintenanceWindow{Name: "Upgrade", StartsAt: now.Add(-time.Minute), DurationSecs: 3600}
+	if err := s.CreateMaintenanceWindow(global); err != nil {
+		t.Fatalf("failed to create window: %v", err)
+	}
+	if global.ID == 0 || global.Recurrence != MaintenanceOnce {
+		t.Errorf("expected an ID and the default recurrence, got %+v", global)
+	}
+
+	scoped := &MaintenanceWindow{Name: "Later", CheckID: check.ID, StartsAt: now.Add(time.Hour), DurationSecs: 600, Recurrence: MaintenanceOnce}
+	if err := s.CreateMaintenanceWindow(scoped); err != nil {
+		t.Fatalf("failed to create window: %v", err)
+	}
+
+	windows, err := s.ListMaintenanceWindows()
+	if err != nil {
+		t.Fatalf("failed to list windows: %v", err)
+	}
+	if len(windows) != 2 || windows[0].CheckID != 0 || windows[1].CheckID != check.ID {
+		t.Fatalf("expected the global and per-check windows, got %+v", windows)
+	}
+
+	active, _ := s.ListActiveMaintenanceWindows(now)
+	if len(active) != 1 || active[0].ID != global.ID {
+		t.Errorf("expected only the global window active, got %+v", active)
+	}
+	if !InMaintenance(active, check.ID) {
+		t.Error("expected the global window to cover the check")
+	}
+
+	if err := s.DeleteMaintenanceWindow(global.ID); err != nil {
+		t.Fatalf("failed to delete window: %v", err)
+	}
+
+	// Windows go with their check
+	s.DeleteCheck(check.ID)
+	windows, _ = s.ListMaintenanceWindows()
+	if len(windows) != 0 {
+		t.Errorf("expected no windows left, got %d", len(windows))
+	}
+}
+
+func TestBannerIsActiveAt(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Hour)
+
+	open := &Banner{Message: "Upgrading"}
+	if !open.IsActiveAt(now) {
+		t.Error("expected a banner without times to be active")
+	}
+
+	scheduled := &Banner{Message: "Upgrading", StartsAt: &later}
+	if scheduled.IsActiveAt(now) || !scheduled.IsActiveAt(later) {
+		t.Error("expected a banner to start at starts_at")
+	}
+
+	ending := &Banner{Message: "Upgrading", EndsAt: &later}
+	if !ending.IsActiveAt(now) || ending.IsActiveAt(later) {
+		t.Error("expected a banner to end at ends_at")
+	}
+
+	if err := (&Banner{}).Validate(); err == nil {
+		t.Error("expected error for a banner without a message")
+	}
+	if err := (&Banner{Message: "Upgrading", StartsAt: &later, EndsAt: &now}).Validate(); err == nil {
+		t.Error("expected error for a banner ending before it starts")
+	}
+}
+
+func TestSettings(t *testing.T) {
+	store := setupTestDB(t)
+
+	if value, err := store.GetSetting("banner"); err != nil || value != "" {
+		t.Fatalf("expected an unset setting to be empty, got %q (%v)", value, err)
+	}
+
+	store.SetSetting("banner", "one")
+	store.SetSetting("banner", "two")
+	if value, _ := store.GetSetting("banner"); value != "two" {
+		t.Errorf("expected the setting to be replaced, got %q", value)
+	}
+
+	if err := store.DeleteSetting("banner"); err != nil {
+		t.Fatalf("DeleteSetting failed: %v", err)
+	}
+	if value, _ := store.GetSetting("banner"); value != "" {
+		t.Errorf("expected the setting to be cleared, got %q", value)
+	}
+}
@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+)
+
+// memoryDBs numbers in-memory databases so each MemoryStorage gets its own
+var memoryDBs atomic.Int64
+
+// MemoryStorage keeps Sentinel's state in memory only, for tests, demos and
+// embedding Sentinel without a data directory; everything is gone on Close.
+// It's an in-memory SQLite database rather than a second implementation of
+// every query, so it behaves exactly like SQLiteStorage.
+type MemoryStorage struct {
+	*SQLiteStorage
+	keep *sql.Conn
+}
+
+func NewMemoryStorage() (*MemoryStorage, error) {
+	// A plain :memory: database is private to one connection; the memdb VFS
+	// shares one by name across the pool, with the usual locking
+	name := fmt.Sprintf("file:/sentinel-%d?vfs=memdb&_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)", memoryDBs.Add(1))
+	db, err := sql.Open("sqlite", name)
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+	// The database lives as long as a connection to it does, so one is held
+	// open until Close in case the pool drops its idle ones
+	keep, err := db.Conn(context.Background())
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+
+	s := &MemoryStorage{SQLiteStorage: &SQLiteStorage{db: &sqlDB{DB: db}}, keep: keep}
+	if err := s.Migrate(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("running migrations: %w", err)
+	}
+
+	return s, nil
+}
+
+// Backup writes the database to a file on disk. VACUUM INTO writes through
+// the source database's VFS, memdb here, so the target is a URI naming the
+// platform's file VFS instead.
+func (s *MemoryStorage) Backup(path string) error {
+	vfs := "unix"
+	if runtime.GOOS == "windows" {
+		vfs = "win32"
+	}
+	target := &url.URL{Scheme: "file", Path: filepath.ToSlash(path), RawQuery: "vfs=" + vfs}
+	return s.backup(path, target.String())
+}
+
+func (s *MemoryStorage) Close() error {
+	s.keep.Close()
+	return s.SQLiteStorage.Close()
+}
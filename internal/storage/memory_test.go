@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMemoryStorage(t *testing.T) {
+	a, err := NewMemoryStorage()
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	defer a.Close()
+	b, err := NewMemoryStorage()
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	defer b.Close()
+
+	check := &Check{Name: "API", URL: "https://api.example.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	if err := a.CreateCheck(check); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Every connection in the pool sees the same database
+	a.db.SetMaxIdleConns(0)
+	if got, err := a.GetCheck(check.ID); err != nil || got == nil || got.Name != "API" {
+		t.Errorf("expected the check back, got %v (%v)", got, err)
+	}
+
+	// Each MemoryStorage is its own database
+	if checks, _ := b.ListChecks(); len(checks) != 0 {
+		t.Errorf("expected a separate empty database, got %d checks", len(checks))
+	}
+
+	// Foreign keys are on, so results go with their check
+	a.SaveResult(&CheckResult{CheckID: check.ID, Status: "up", StatusCode: 200})
+	if err := a.DeleteCheck(check.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n, _ := a.CountResults(check.ID); n != 0 {
+		t.Errorf("expected results deleted with the check, got %d", n)
+	}
+
+	// A backup lands on disk, not in memory
+	path := filepath.Join(t.TempDir(), "memory backup.db")
+	if err := a.Backup(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("expected the backup written to disk: %v", err)
+	}
+}
@@ -2,27 +2,163 @@ package storage
 
 import (
 	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
+
+	"github.com/katieblackabee/sentinel/internal/cron"
 )
 
 type Check struct {
-	ID             int64     `json:"id"`
-	Name           string    `json:"name"`
-	URL            string    `json:"url"`
-	IntervalSecs   int       `json:"interval_seconds"`
-	TimeoutSecs    int       `json:"timeout_seconds"`
-	ExpectedStatus int       `json:"expected_status"`
-	Enabled        bool      `json:"enabled"`
-	Tags           []string  `json:"tags"`
-	Regions        []string  `json:"regions,omitempty"` // Region codes for multi-region checks
-	MinProbes      int       `json:"min_probes"`        // Minimum probes required (0 = single check)
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID                      int64     `json:"id"`
+	Name                    string    `json:"name"`
+	Type                    string    `json:"type"` // "http", "tcp", "dns", "ping" or "heartbeat"; tcp checks dial URL as host:port, dns checks resolve it, ping checks echo it, heartbeat checks wait for pushes
+	URL                     string    `json:"url"`
+	IntervalSecs            int       `json:"interval_seconds"`
+	Cron                    string    `json:"cron,omitempty"` // Cron expression, e.g. "0 9-17 * * mon-fri"; replaces the interval when set
+	TimeoutSecs             int       `json:"timeout_seconds"`
+	ExpectedStatus          int       `json:"expected_status"`
+	ExpectedStatuses        string    `json:"expected_statuses,omitempty"` // Overrides ExpectedStatus, e.g. "200,204" or "2xx"
+	Enabled                 bool      `json:"enabled"`
+	Tags                    []string  `json:"tags"`
+	Regions                 []string  `json:"regions,omitempty"` // Region codes for multi-region checks
+	MinProbes               int       `json:"min_probes"`        // Minimum probes required (0 = single check)
+	Description             string    `json:"description,omitempty"`
+	RunbookURL              string    `json:"runbook_url,omitempty"`               // Linked from alerts so on-call has context
+	Streaming               bool      `json:"streaming"`                           // Succeed on headers without reading the body to EOF
+	Paused                  bool      `json:"paused"`                              // Scheduled but not executed; keeps its last status
+	SampleSecs              int       `json:"sample_seconds"`                      // Store stable up results at most this often (0 = store all)
+	CompressResults         bool      `json:"compress_results"`                    // Fold identical stable up results into one row per run
+	AlertOnFirstCheck       bool      `json:"alert_on_first_check"`                // A failing first result alerts instead of staying pending
+	JSONAssertions          []string  `json:"json_assertions,omitempty"`           // Checked against the JSON body, e.g. "$.queue_depth < 10000"
+	NoFollowRedirects       bool      `json:"no_follow_redirects"`                 // Treat the first redirect as the final response
+	BypassCache             bool      `json:"bypass_cache"`                        // Send no-cache headers and a cache-busting param to reach the origin
+	ExpectedLocation        string    `json:"expected_location,omitempty"`         // Redirect target, exact or /regex/
+	ExpectedCertFingerprint string    `json:"expected_cert_fingerprint,omitempty"` // Pinned SHA-256 of the leaf certificate
+	BaselineURL             string    `json:"baseline_url,omitempty"`              // Makes URL a canary compared against this
+	CompareFields           []string  `json:"compare_fields,omitempty"`            // status, latency, body (default status and body)
+	LatencyTolerancePct     int       `json:"latency_tolerance_pct,omitempty"`     // How much slower than baseline the canary may be (default 50)
+	BodyContains            string    `json:"body_contains,omitempty"`             // Down unless the body contains this
+	BodyNotContains         string    `json:"body_not_contains,omitempty"`         // Down if the body contains this, e.g. an error page marker
+	CreatedAt               time.Time `json:"created_at"`
+	UpdatedAt               time.Time `json:"updated_at"`
+
+	// Assertions are checked against the body by the matcher AssertionType
+	// names (json, xml or text), or one picked from the Content-Type
+	AssertionType string   `json:"assertion_type,omitempty"`
+	Assertions    []string `json:"assertions,omitempty"`
+
+	// DualStack checks over IPv4 and IPv6 separately and is only up when both
+	// are, so a broken family on a dual-stack host isn't hidden by the other
+	DualStack bool `json:"dual_stack"`
+
+	// InsecureSkipVerify accepts any TLS certificate, for internal endpoints
+	// signed by a private CA; verification is on unless this is set
+	InsecureSkipVerify bool `json:"insecure_skip_verify,omitempty"`
+
+	// DNS checks look up RecordType (default A) for the URL's hostname and,
+	// with ExpectedAnswer set, are down unless it is among the answers
+	RecordType     string `json:"record_type,omitempty"`
+	ExpectedAnswer string `json:"expected_answer,omitempty"`
+
+	// ExpectedTrailer, as "Name: value", must be among the HTTP trailers sent
+	// after the body, where gRPC-style backends put their status
+	ExpectedTrailer string `json:"expected_trailer,omitempty"`
+
+	// MinBodyBytes and ExpectedContentType catch truncated responses and
+	// gateways answering with an HTML error page: the body must be at least
+	// that long, and the Content-Type that media type
+	MinBodyBytes        int    `json:"min_body_bytes,omitempty"`
+	ExpectedContentType string `json:"expected_content_type,omitempty"`
+
+	// ExpectedRedirects is the redirect chain the check must follow, one hop
+	// per entry as "status location", e.g. "301 https://www.example.com/"
+	ExpectedRedirects []string `json:"expected_redirects,omitempty"`
+
+	// Method (default GET), Headers and RequestBody shape the HTTP request,
+	// for endpoints that want a POST or an Authorization header
+	Method      string            `json:"method,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	RequestBody string            `json:"request_body,omitempty"`
+
+	// QueryParams are appended to the URL on every request, each value a Go
+	// template rendered at the time, e.g. "{{.Now.Unix}}" to bust a cache or
+	// an HMAC over the path to sign it
+	QueryParams map[string]string `json:"query_params,omitempty"`
+
+	// DegradedThresholdMs marks a successful response slower than this as
+	// degraded: shown apart on the dashboard, but up as far as alerts go
+	DegradedThresholdMs int `json:"degraded_threshold_ms,omitempty"`
+
+	// SlowMultiplier sends a slow alert when an up response takes this many
+	// times the check's 24h average, e.g. 5; 0 uses alerts.slow_multiplier
+	SlowMultiplier float64 `json:"slow_multiplier,omitempty"`
+
+	// ConsecutiveSuccesses is how many up results in a row the check needs
+	// before it counts as up, so a flapping restart doesn't close its
+	// incident on one lucky response; 0 or 1 means the first will do
+	ConsecutiveSuccesses int `json:"consecutive_successes,omitempty"`
+
+	// ConsecutiveFailures overrides alerts.consecutive_failures for this
+	// check, e.g. more for a flaky third party; 0 uses the global setting
+	ConsecutiveFailures int `json:"consecutive_failures,omitempty"`
+
+	// Group lists the check under that name on the dashboard and in
+	// /api/groups, instead of under its first tag
+	Group string `json:"group,omitempty"`
+
+	// PausedUntil ends a pause on its own; the scheduler resumes the check at
+	// its first run after this. Nil with Paused set pauses until resumed.
+	PausedUntil *time.Time `json:"paused_until,omitempty"`
+
+	// AlertChannels limits alerts to these channels, e.g. "slack" or
+	// "slack:oncall"; empty means every enabled channel
+	AlertChannels []string `json:"alert_channels,omitempty"`
+	// AlertRoutes sends one type of alert ("down", "recovery", ...) to its
+	// own channels instead, e.g. recovery to slack only
+	AlertRoutes map[string][]string `json:"alert_routes,omitempty"`
+	// Escalations re-alert on incidents left open and unacknowledged
+	Escalations []EscalationRule `json:"escalations,omitempty"`
 
 	// Computed fields (not stored in DB)
 	Status         string     `json:"status"`
 	LastResponseMs int        `json:"last_response_ms"`
 	LastCheckedAt  *time.Time `json:"last_checked_at,omitempty"`
+	Stale          bool       `json:"stale,omitempty"`          // Enabled but no recent result; see IsStale
+	InMaintenance  bool       `json:"in_maintenance,omitempty"` // A maintenance window is holding back its alerts
+}
+
+// AnyStatus as a check's ExpectedStatus counts any HTTP response as up;
+// only transport errors (DNS, connect, TLS, timeout) are down
+const AnyStatus = -1
+
+// Check types
+const (
+	CheckTypeHTTP = "http"
+	CheckTypeTCP  = "tcp"
+	CheckTypeDNS  = "dns"
+	CheckTypePing = "ping"
+
+	CheckTypeHeartbeat = "heartbeat"
+)
+
+// SuccessStatus is the status code results are judged against. TCP, DNS,
+// ping and heartbeat checks have no response, so only a failure is down.
+func (c *Check) SuccessStatus() int {
+	if c.hasNoResponse() {
+		return AnyStatus
+	}
+	return c.ExpectedStatus
+}
+
+// SuccessStatuses is the status spec that replaces SuccessStatus when set
+func (c *Check) SuccessStatuses() string {
+	if c.hasNoResponse() {
+		return ""
+	}
+	return c.ExpectedStatuses
 }
 
 func (c *Check) IsUp() bool {
@@ -37,11 +173,146 @@ func (c *Check) IsPending() bool {
 	return c.Status == "pending" || c.Status == ""
 }
 
+// DefaultStaleIntervals is how many intervals a check may go without a result
+// before it counts as stale
+const DefaultStaleIntervals = 3
+
+func (c *Check) hasNoResponse() bool {
+	return c.Type == CheckTypeTCP || c.Type == CheckTypeDNS || c.Type == CheckTypePing || c.Type == CheckTypeHeartbeat
+}
+
+// StaleAfter is how long the check may go without a stored result before it
+// counts as stale. Sampled checks store a result only every sample interval
+// while up, which stretches the wait.
+func (c *Check) StaleAfter(intervals int) time.Duration {
+	return time.Duration(intervals*max(c.IntervalSecs, c.SampleSecs)) * time.Second
+}
+
+// StaleAt is when a check last run at since turns stale: StaleAfter later,
+// or for a cron check, once that many scheduled runs have gone by, so quiet
+// hours outside its schedule don't count
+func (c *Check) StaleAt(since time.Time, intervals int) time.Time {
+	if c.Cron != "" {
+		if schedule, err := cron.Parse(c.Cron); err == nil {
+			at := since
+			for i := 0; i < intervals && !at.IsZero(); i++ {
+				at = schedule.Next(at)
+			}
+			if !at.IsZero() {
+				return at
+			}
+		}
+	}
+	return since.Add(c.StaleAfter(intervals))
+}
+
+// IsPaused reports whether the check is paused at now, a timed pause
+// counting only until PausedUntil
+func (c *Check) IsPaused(now time.Time) bool {
+	return c.Paused && (c.PausedUntil == nil || now.Before(*c.PausedUntil))
+}
+
+// IsStale reports whether an enabled, unpaused check's last result is older
+// than StaleAfter, meaning Sentinel itself stopped running it. Checks with no
+// results yet are pending rather than stale; intervals < 1 disables it.
+// Heartbeat checks are never stale, their silence is what they alert on.
+func (c *Check) IsStale(now time.Time, intervals int) bool {
+	if !c.Enabled || c.IsPaused(now) || c.LastCheckedAt == nil || intervals < 1 || c.Type == CheckTypeHeartbeat {
+		return false
+	}
+	return now.After(c.StaleAt(*c.LastCheckedAt, intervals))
+}
+
+// CheckStatuses are the values a check's computed Status can take
+var CheckStatuses = []string{"up", "down", "pending", "degraded"}
+
+// CheckFilter narrows a check listing. Every field set must match; the zero
+// filter matches every check.
+type CheckFilter struct {
+	Query       string // Case-insensitive substring of the name
+	Tag         string
+	EnabledOnly bool
+	Status      string // Computed status, one of CheckStatuses
+}
+
+// Matches reports whether check passes the filter. Status is compared with
+// the check's computed Status, so that must be filled in first.
+func (f CheckFilter) Matches(check *Check) bool {
+	if f.EnabledOnly && !check.Enabled {
+		return false
+	}
+	if f.Query != "" && !strings.Contains(strings.ToLower(check.Name), strings.ToLower(f.Query)) {
+		return false
+	}
+	if f.Tag != "" && !slices.Contains(check.Tags, f.Tag) {
+		return false
+	}
+	return f.Status == "" || check.Status == f.Status
+}
+
+// ApplyLatest fills the computed fields from the latest result in each
+// region, newest first. A check with no results is pending; one whose regions
+// disagree is degraded; otherwise it takes the status they share.
+func (c *Check) ApplyLatest(latest []*CheckResult) {
+	if len(latest) == 0 {
+		c.Status = "pending"
+		return
+	}
+
+	newest := latest[0]
+	c.Status = newest.Status
+	c.LastResponseMs = newest.ResponseTimeMs
+	c.LastCheckedAt = &newest.CheckedAt
+	for _, r := range latest[1:] {
+		if r.Status != newest.Status {
+			c.Status = "degraded"
+			break
+		}
+	}
+}
+
+// SettledStatus is the status recent results, newest first, add up to for a
+// check that needs successes up results in a row to count as up. Short of
+// that an up result is still down after a failure among them, or pending if
+// the check hasn't failed.
+func SettledStatus(recent []*CheckResult, successes int) string {
+	if len(recent) == 0 {
+		return "pending"
+	}
+	newest := recent[0]
+	if successes <= 1 || !newest.IsUp() {
+		return newest.Status
+	}
+	for i, r := range recent {
+		if !r.IsUp() {
+			return "down"
+		}
+		if i+1 >= successes {
+			return newest.Status
+		}
+	}
+	return "pending"
+}
+
+// SettleStatus holds back an up status the check hasn't yet earned with
+// enough successes in a row. Status must already be set from its latest
+// result; results are only loaded for checks that need several.
+func SettleStatus(store Storage, check *Check) {
+	if check.ConsecutiveSuccesses <= 1 || (check.Status != "up" && check.Status != "degraded") {
+		return
+	}
+	recent, err := store.GetRecentResults(check.ID, check.ConsecutiveSuccesses)
+	if err != nil {
+		return
+	}
+	check.Status = SettledStatus(recent, check.ConsecutiveSuccesses)
+}
+
 type CheckResult struct {
 	ID             int64      `json:"id"`
 	CheckID        int64      `json:"check_id"`
 	Region         string     `json:"region,omitempty"` // Region code (e.g., "us", "eu") or empty for single-region
-	Status         string     `json:"status"`           // "up" or "down"
+	Status         string     `json:"status"`           // "up", "down", or "degraded" (up, but slow)
 	StatusCode     int        `json:"status_code"`
 	ResponseTimeMs int        `json:"response_time_ms"`
 	ErrorMessage   string     `json:"error_message,omitempty"`
@@ -49,10 +320,29 @@ type CheckResult struct {
 	SSLExpiresAt   *time.Time `json:"ssl_expires_at,omitempty"`
 	SSLDaysLeft    int        `json:"ssl_days_left,omitempty"`
 	SSLIssuer      string     `json:"ssl_issuer,omitempty"`
+	SSLFingerprint string     `json:"ssl_fingerprint,omitempty"` // Observed leaf certificate SHA-256, so rotations are visible
+	Weight         int        `json:"weight,omitempty"`          // Evaluations this stored result stands for (sampled or compressed checks)
+	RunStartedAt   *time.Time `json:"run_started_at,omitempty"`  // First evaluation of a compressed run; CheckedAt is the last
+
+	// Comparison checks store both sides' key metrics
+	BodyHash           string `json:"body_hash,omitempty"`
+	BaselineStatusCode int    `json:"baseline_status_code,omitempty"`
+	BaselineResponseMs int    `json:"baseline_response_time_ms,omitempty"`
+	BaselineBodyHash   string `json:"baseline_body_hash,omitempty"`
+
+	// Dual-stack checks store each address family's status ("up" or "down")
+	IPv4Status string `json:"ipv4_status,omitempty"`
+	IPv6Status string `json:"ipv6_status,omitempty"`
+
+	// RedirectChain is the redirects followed, as "status location" hops
+	// joined by " -> "
+	RedirectChain string `json:"redirect_chain,omitempty"`
 }
 
+// IsUp reports whether the check succeeded; a degraded result is a slow
+// success
 func (r *CheckResult) IsUp() bool {
-	return r.Status == "up"
+	return r.Status == "up" || r.Status == "degraded"
 }
 
 // IncidentStatus represents the current status of an incident
@@ -72,8 +362,10 @@ type Incident struct {
 	EndedAt         *time.Time     `json:"ended_at,omitempty"`
 	DurationSeconds int            `json:"duration_seconds"`
 	Cause           string         `json:"cause,omitempty"`
+	Category        string         `json:"category,omitempty"` // Normalized cause: timeout, dns, tls, ...
 	Status          IncidentStatus `json:"status"`
 	Title           string         `json:"title,omitempty"`
+	ExternalID      string         `json:"external_id,omitempty"` // Ticket in an external incident system
 
 	// Joined fields
 	CheckName string          `json:"check_name,omitempty"`
@@ -88,6 +380,26 @@ type IncidentNote struct {
 	CreatedAt  time.Time `json:"created_at"`
 }
 
+// EscalationRule re-alerts on an incident still open and unacknowledged
+// AfterSecs after it started
+type EscalationRule struct {
+	AfterSecs int    `json:"after_seconds"`
+	Channel   string `json:"channel,omitempty"`  // Only this alert channel, e.g. "email" or "slack:oncall"; empty means all
+	Severity  string `json:"severity,omitempty"` // Severity targets filter on (default critical)
+}
+
+// String renders a rule the way the check form takes it, e.g. "1h0m0s slack:oncall critical"
+func (r EscalationRule) String() string {
+	parts := []string{(time.Duration(r.AfterSecs) * time.Second).String()}
+	if r.Channel != "" {
+		parts = append(parts, r.Channel)
+	}
+	if r.Severity != "" {
+		parts = append(parts, r.Severity)
+	}
+	return strings.Join(parts, " ")
+}
+
 func (i *Incident) IsActive() bool {
 	return i.EndedAt == nil
 }
@@ -96,6 +408,12 @@ func (i *Incident) IsResolved() bool {
 	return i.Status == IncidentStatusResolved
 }
 
+// IsAcknowledged reports whether someone has moved the incident past
+// investigating, which stops escalation
+func (i *Incident) IsAcknowledged() bool {
+	return i.Status != "" && i.Status != IncidentStatusInvestigating
+}
+
 func (i *Incident) StatusString() string {
 	switch i.Status {
 	case IncidentStatusInvestigating:
@@ -122,7 +440,11 @@ func (i *Incident) Duration() time.Duration {
 }
 
 func (i *Incident) DurationString() string {
-	d := i.Duration()
+	return formatDuration(i.Duration())
+}
+
+// formatDuration rounds a duration to a readable precision for its size
+func formatDuration(d time.Duration) string {
 	if d < time.Minute {
 		return d.Round(time.Second).String()
 	}
@@ -141,6 +463,13 @@ type AlertLog struct {
 	ErrorMessage string    `json:"error_message,omitempty"`
 }
 
+// GoldenSnapshot is a recorded response body that later responses are diffed against
+type GoldenSnapshot struct {
+	CheckID    int64     `json:"check_id"`
+	Body       string    `json:"body"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
 type CheckStats struct {
 	UptimePercent24h float64 `json:"uptime_percent_24h"`
 	UptimePercent7d  float64 `json:"uptime_percent_7d"`
@@ -148,6 +477,46 @@ type CheckStats struct {
 	AvgResponseMs24h int     `json:"avg_response_ms_24h"`
 	AvgResponseMs7d  int     `json:"avg_response_ms_7d"`
 	AvgResponseMs30d int     `json:"avg_response_ms_30d"`
+
+	// Response time percentiles of up results, 0 for an empty window
+	P50ResponseMs24h int `json:"p50_response_ms_24h"`
+	P95ResponseMs24h int `json:"p95_response_ms_24h"`
+	P99ResponseMs24h int `json:"p99_response_ms_24h"`
+	P50ResponseMs7d  int `json:"p50_response_ms_7d"`
+	P95ResponseMs7d  int `json:"p95_response_ms_7d"`
+	P99ResponseMs7d  int `json:"p99_response_ms_7d"`
+	P50ResponseMs30d int `json:"p50_response_ms_30d"`
+	P95ResponseMs30d int `json:"p95_response_ms_30d"`
+	P99ResponseMs30d int `json:"p99_response_ms_30d"`
+}
+
+// IncidentStats summarizes a check's incidents started within a time range
+type IncidentStats struct {
+	CheckID        int64     `json:"check_id"`
+	From           time.Time `json:"from"`
+	To             time.Time `json:"to"`
+	Count          int       `json:"count"`
+	Resolved       int       `json:"resolved"`
+	MTTRSeconds    int       `json:"mttr_seconds"` // Mean duration of resolved incidents
+	LongestSeconds int       `json:"longest_seconds"`
+
+	ByCategory map[string]int `json:"by_category,omitempty"` // Incident count per cause category
+}
+
+func (s *IncidentStats) MTTRString() string {
+	return formatDuration(time.Duration(s.MTTRSeconds) * time.Second)
+}
+
+func (s *IncidentStats) LongestString() string {
+	return formatDuration(time.Duration(s.LongestSeconds) * time.Second)
+}
+
+// UptimePoint is one bucket of an uptime timeseries, starting at Hour
+type UptimePoint struct {
+	Hour          time.Time `json:"hour"`
+	TotalChecks   int       `json:"total_checks"`
+	UptimePercent float64   `json:"uptime_percent"`
+	AvgResponseMs int       `json:"avg_ms"`
 }
 
 type HourlyAggregate struct {
@@ -165,15 +534,88 @@ type HourlyAggregate struct {
 
 // CreateCheckInput is used for creating new checks via API
 type CreateCheckInput struct {
-	Name           string   `json:"name"`
-	URL            string   `json:"url"`
-	IntervalSecs   int      `json:"interval_seconds,omitempty"`
-	TimeoutSecs    int      `json:"timeout_seconds,omitempty"`
-	ExpectedStatus int      `json:"expected_status,omitempty"`
-	Enabled        *bool    `json:"enabled,omitempty"`
-	Tags           []string `json:"tags,omitempty"`
-	Regions        []string `json:"regions,omitempty"`
-	MinProbes      int      `json:"min_probes,omitempty"`
+	Name                    string   `json:"name"`
+	Type                    string   `json:"type,omitempty"`
+	URL                     string   `json:"url"`
+	IntervalSecs            int      `json:"interval_seconds,omitempty"`
+	Cron                    string   `json:"cron,omitempty"`
+	TimeoutSecs             int      `json:"timeout_seconds,omitempty"`
+	ExpectedStatus          int      `json:"expected_status,omitempty"`
+	ExpectedStatuses        string   `json:"expected_statuses,omitempty"`
+	Enabled                 *bool    `json:"enabled,omitempty"`
+	Tags                    []string `json:"tags,omitempty"`
+	Group                   string   `json:"group,omitempty"`
+	Regions                 []string `json:"regions,omitempty"`
+	MinProbes               int      `json:"min_probes,omitempty"`
+	Description             string   `json:"description,omitempty"`
+	RunbookURL              string   `json:"runbook_url,omitempty"`
+	Streaming               *bool    `json:"streaming,omitempty"`
+	Paused                  *bool    `json:"paused,omitempty"`
+	SampleSecs              int      `json:"sample_seconds,omitempty"`
+	CompressResults         *bool    `json:"compress_results,omitempty"`
+	AlertOnFirstCheck       *bool    `json:"alert_on_first_check,omitempty"`
+	JSONAssertions          []string `json:"json_assertions,omitempty"`
+	NoFollowRedirects       *bool    `json:"no_follow_redirects,omitempty"`
+	BypassCache             *bool    `json:"bypass_cache,omitempty"`
+	DualStack               *bool    `json:"dual_stack,omitempty"`
+	InsecureSkipVerify      *bool    `json:"insecure_skip_verify,omitempty"`
+	RecordType              string   `json:"record_type,omitempty"`
+	ExpectedAnswer          string   `json:"expected_answer,omitempty"`
+	ExpectedTrailer         string   `json:"expected_trailer,omitempty"`
+	MinBodyBytes            int      `json:"min_body_bytes,omitempty"`
+	ExpectedContentType     string   `json:"expected_content_type,omitempty"`
+	ExpectedRedirects       []string `json:"expected_redirects,omitempty"`
+	ExpectedLocation        string   `json:"expected_location,omitempty"`
+	ExpectedCertFingerprint string   `json:"expected_cert_fingerprint,omitempty"`
+	BaselineURL             string   `json:"baseline_url,omitempty"`
+	CompareFields           []string `json:"compare_fields,omitempty"`
+	LatencyTolerancePct     int      `json:"latency_tolerance_pct,omitempty"`
+	DegradedThresholdMs     int      `json:"degraded_threshold_ms,omitempty"`
+	SlowMultiplier          float64  `json:"slow_multiplier,omitempty"`
+	ConsecutiveSuccesses    int      `json:"consecutive_successes,omitempty"`
+	ConsecutiveFailures     int      `json:"consecutive_failures,omitempty"`
+	BodyContains            string   `json:"body_contains,omitempty"`
+	BodyNotContains         string   `json:"body_not_contains,omitempty"`
+
+	AssertionType string              `json:"assertion_type,omitempty"`
+	Assertions    []string            `json:"assertions,omitempty"`
+	AlertChannels []string            `json:"alert_channels,omitempty"`
+	AlertRoutes   map[string][]string `json:"alert_routes,omitempty"`
+	Escalations   []EscalationRule    `json:"escalations,omitempty"`
+	Method        string              `json:"method,omitempty"`
+	Headers       map[string]string   `json:"headers,omitempty"`
+	RequestBody   string              `json:"request_body,omitempty"`
+	QueryParams   map[string]string   `json:"query_params,omitempty"`
+}
+
+// UnmarshalJSON accepts expected_status as a plain code like 200 or -1, or as
+// a string spec like "200,204" or "2xx", which lands in ExpectedStatuses
+func (i *CreateCheckInput) UnmarshalJSON(data []byte) error {
+	type plain CreateCheckInput
+	aux := struct {
+		*plain
+		ExpectedStatus json.RawMessage `json:"expected_status,omitempty"`
+	}{plain: (*plain)(i)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if len(aux.ExpectedStatus) == 0 || string(aux.ExpectedStatus) == "null" {
+		return nil
+	}
+
+	if err := json.Unmarshal(aux.ExpectedStatus, &i.ExpectedStatus); err == nil {
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(aux.ExpectedStatus, &value); err != nil {
+		return fmt.Errorf("expected_status must be a status code or a string like \"2xx\"")
+	}
+	code, spec := SplitExpectedStatus(value)
+	i.ExpectedStatus = code
+	if spec != "" {
+		i.ExpectedStatuses = spec
+	}
+	return nil
 }
 
 func (i *CreateCheckInput) ToCheck() *Check {
@@ -193,20 +635,107 @@ func (i *CreateCheckInput) ToCheck() *Check {
 	}
 
 	expectedStatus := 200
-	if i.ExpectedStatus > 0 {
+	if i.ExpectedStatus > 0 || i.ExpectedStatus == AnyStatus {
 		expectedStatus = i.ExpectedStatus
 	}
 
+	streaming := false
+	if i.Streaming != nil {
+		streaming = *i.Streaming
+	}
+
+	paused := false
+	if i.Paused != nil {
+		paused = *i.Paused
+	}
+
+	alertOnFirstCheck := false
+	if i.AlertOnFirstCheck != nil {
+		alertOnFirstCheck = *i.AlertOnFirstCheck
+	}
+
+	compressResults := false
+	if i.CompressResults != nil {
+		compressResults = *i.CompressResults
+	}
+
+	noFollowRedirects := false
+	if i.NoFollowRedirects != nil {
+		noFollowRedirects = *i.NoFollowRedirects
+	}
+
+	bypassCache := false
+	if i.BypassCache != nil {
+		bypassCache = *i.BypassCache
+	}
+
+	dualStack := false
+	if i.DualStack != nil {
+		dualStack = *i.DualStack
+	}
+
+	insecureSkipVerify := false
+	if i.InsecureSkipVerify != nil {
+		insecureSkipVerify = *i.InsecureSkipVerify
+	}
+
+	checkType := CheckTypeHTTP
+	if i.Type != "" {
+		checkType = i.Type
+	}
+
 	return &Check{
-		Name:           i.Name,
-		URL:            i.URL,
-		IntervalSecs:   intervalSecs,
-		TimeoutSecs:    timeoutSecs,
-		ExpectedStatus: expectedStatus,
-		Enabled:        enabled,
-		Tags:           i.Tags,
-		Regions:        i.Regions,
-		MinProbes:      i.MinProbes,
+		Name:                    i.Name,
+		Type:                    checkType,
+		URL:                     i.URL,
+		IntervalSecs:            intervalSecs,
+		Cron:                    i.Cron,
+		TimeoutSecs:             timeoutSecs,
+		ExpectedStatus:          expectedStatus,
+		ExpectedStatuses:        i.ExpectedStatuses,
+		Enabled:                 enabled,
+		Tags:                    i.Tags,
+		Regions:                 i.Regions,
+		MinProbes:               i.MinProbes,
+		Description:             i.Description,
+		RunbookURL:              i.RunbookURL,
+		Streaming:               streaming,
+		Paused:                  paused,
+		SampleSecs:              i.SampleSecs,
+		CompressResults:         compressResults,
+		AlertOnFirstCheck:       alertOnFirstCheck,
+		JSONAssertions:          i.JSONAssertions,
+		NoFollowRedirects:       noFollowRedirects,
+		BypassCache:             bypassCache,
+		ExpectedLocation:        i.ExpectedLocation,
+		ExpectedCertFingerprint: i.ExpectedCertFingerprint,
+		BaselineURL:             i.BaselineURL,
+		CompareFields:           i.CompareFields,
+		LatencyTolerancePct:     i.LatencyTolerancePct,
+		DegradedThresholdMs:     i.DegradedThresholdMs,
+		SlowMultiplier:          i.SlowMultiplier,
+		ConsecutiveSuccesses:    i.ConsecutiveSuccesses,
+		ConsecutiveFailures:     i.ConsecutiveFailures,
+		Group:                   i.Group,
+		BodyContains:            i.BodyContains,
+		BodyNotContains:         i.BodyNotContains,
+		AssertionType:           i.AssertionType,
+		Assertions:              i.Assertions,
+		DualStack:               dualStack,
+		InsecureSkipVerify:      insecureSkipVerify,
+		RecordType:              i.RecordType,
+		ExpectedAnswer:          i.ExpectedAnswer,
+		ExpectedTrailer:         i.ExpectedTrailer,
+		MinBodyBytes:            i.MinBodyBytes,
+		ExpectedContentType:     i.ExpectedContentType,
+		ExpectedRedirects:       i.ExpectedRedirects,
+		Method:                  i.Method,
+		Headers:                 i.Headers,
+		RequestBody:             i.RequestBody,
+		QueryParams:             i.QueryParams,
+		AlertChannels:           i.AlertChannels,
+		AlertRoutes:             i.AlertRoutes,
+		Escalations:             i.Escalations,
 	}
 }
 
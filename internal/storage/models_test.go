@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 )
@@ -202,3 +203,139 @@ func TestCreateCheckInputToCheckEnabledTrue(t *testing.T) {
 		t.Error("expected enabled to be true")
 	}
 }
+
+func TestCreateCheckInputExpectedStatusForms(t *testing.T) {
+	tests := []struct {
+		body     string
+		code     int
+		statuses string
+	}{
+		{`{"expected_status":204}`, 204, ""},
+		{`{"expected_status":-1}`, AnyStatus, ""},
+		{`{"expected_status":"201"}`, 201, ""},
+		{`{"expected_status":"2xx"}`, 0, "2xx"},
+		{`{"expected_status":"200,204"}`, 0, "200,204"},
+		{`{"expected_statuses":"2xx,304"}`, 0, "2xx,304"},
+	}
+
+	for _, tt := range tests {
+		var input CreateCheckInput
+		if err := json.Unmarshal([]byte(tt.body), &input); err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.body, err)
+			continue
+		}
+		if input.ExpectedStatus != tt.code || input.ExpectedStatuses != tt.statuses {
+			t.Errorf("%s: got %d %q, want %d %q", tt.body, input.ExpectedStatus, input.ExpectedStatuses, tt.code, tt.statuses)
+		}
+	}
+
+	var input CreateCheckInput
+	if err := json.Unmarshal([]byte(`{"expected_status":true}`), &input); err == nil {
+		t.Error("expected error for a boolean expected_status")
+	}
+}
+
+func TestStatusSpec(t *testing.T) {
+	for _, spec := range []string{"2xx", "200,204", "2xx, 304", "5XX"} {
+		if err := ValidateStatusSpec(spec); err != nil {
+			t.Errorf("expected %q to be valid, got %v", spec, err)
+		}
+	}
+	for _, spec := range []string{"", "abc", "2x", "600", "200,,204", "6xx"} {
+		if err := ValidateStatusSpec(spec); err == nil {
+			t.Errorf("expected %q to be invalid", spec)
+		}
+	}
+
+	if !StatusMatches("2xx", 299) || StatusMatches("2xx", 300) {
+		t.Error("expected 2xx to cover exactly 200-299")
+	}
+	if !StatusMatches("200, 204", 204) || StatusMatches("200,204", 202) {
+		t.Error("expected lists to match only listed codes")
+	}
+}
+
+func TestCheckIsStale(t *testing.T) {
+	now := time.Now()
+	last := now.Add(-5 * time.Minute)
+	check := &Check{IntervalSecs: 60, Enabled: true, LastCheckedAt: &last}
+
+	if !check.IsStale(now, 3) {
+		t.Error("expected a check 5 intervals behind to be stale")
+	}
+	if check.IsStale(now, 10) {
+		t.Error("expected a check within its stale window to be fresh")
+	}
+	if check.IsStale(now, -1) {
+		t.Error("expected a negative interval count to disable staleness")
+	}
+
+	// Sampled checks store fewer results, so they get longer
+	check.SampleSecs = 600
+	if check.IsStale(now, 3) {
+		t.Error("expected the sample interval to stretch the stale window")
+	}
+
+	check.SampleSecs = 0
+	check.Paused = true
+	if check.IsStale(now, 3) {
+		t.Error("expected paused checks never to be stale")
+	}
+
+	check.Paused = false
+	check.Type = CheckTypeHeartbeat
+	if check.IsStale(now, 3) {
+		t.Error("expected heartbeat checks never to be stale")
+	}
+
+	if (&Check{IntervalSecs: 60, Enabled: true}).IsStale(now, 3) {
+		t.Error("expected checks without results to be pending, not stale")
+	}
+}
+
+func TestCheckStaleAtCron(t *testing.T) {
+	// Friday evening, after the last weekday run
+	last := time.Date(2026, 1, 16, 17, 0, 5, 0, time.UTC)
+	check := &Check{IntervalSecs: 60, Cron: "0 9-17 * * mon-fri", Enabled: true, LastCheckedAt: &last}
+
+	// The weekend doesn't count; three runs go by on Monday morning
+	if want := time.Date(2026, 1, 19, 11, 0, 0, 0, time.UTC); !check.StaleAt(last, 3).Equal(want) {
+		t.Errorf("expected stale at %v, got %v", want, check.StaleAt(last, 3))
+	}
+	if check.IsStale(time.Date(2026, 1, 18, 12, 0, 0, 0, time.UTC), 3) {
+		t.Error("expected a cron check to stay fresh outside its schedule")
+	}
+	if !check.IsStale(time.Date(2026, 1, 19, 11, 30, 0, 0, time.UTC), 3) {
+		t.Error("expected a cron check that missed its runs to be stale")
+	}
+}
+
+func TestSettledStatus(t *testing.T) {
+	results := func(statuses ...string) []*CheckResult {
+		var recent []*CheckResult
+		for _, s := range statuses {
+			recent = append(recent, &CheckResult{Status: s})
+		}
+		return recent
+	}
+
+	tests := []struct {
+		recent    []*CheckResult
+		successes int
+		want      string
+	}{
+		{nil, 3, "pending"},
+		{results("up", "down"), 1, "up"},
+		{results("up", "down"), 0, "up"},
+		{results("down", "up", "up"), 3, "down"},
+		{results("up", "up", "down"), 3, "down"},
+		{results("up", "degraded", "up"), 3, "up"},
+		{results("degraded", "up", "up"), 3, "degraded"},
+		{results("up", "up"), 3, "pending"},
+	}
+	for _, tt := range tests {
+		if got := SettledStatus(tt.recent, tt.successes); got != tt.want {
+			t.Errorf("SettledStatus(%d results, %d) = %q, want %q", len(tt.recent), tt.successes, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// postgresDriver is the database/sql driver PostgresStorage opens. It is
+// registered by pgx's stdlib package, linked in with the postgres build tag.
+const postgresDriver = "pgx"
+
+// PostgresStorage keeps Sentinel's state in PostgreSQL so several instances
+// behind a load balancer can share it. It runs the same queries as
+// SQLiteStorage; only the schema and placeholder style differ.
+type PostgresStorage struct {
+	*SQLiteStorage
+}
+
+func NewPostgresStorage(dsn string) (*PostgresStorage, error) {
+	if !slices.Contains(sql.Drivers(), postgresDriver) {
+		return nil, fmt.Errorf("postgres support is not built in (build with -tags postgres)")
+	}
+
+	db, err := sql.Open(postgresDriver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("connecting to database: %w", err)
+	}
+
+	columns, err := newColumnCipher(os.Getenv(EncryptionKeyEnv))
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	s := &PostgresStorage{SQLiteStorage: &SQLiteStorage{db: &sqlDB{DB: db, postgres: true}, cipher: columns}}
+	if err := s.Migrate(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("running migrations: %w", err)
+	}
+	if err := s.setupEncryption(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Backup isn't available on PostgreSQL, whose own tools already take
+// consistent online backups
+func (s *PostgresStorage) Backup(path string) error {
+	return fmt.Errorf("backups of PostgreSQL aren't supported; use pg_dump")
+}
+
+// postgresMigrationLock is the advisory lock key held while migrating, so
+// instances starting together don't race to create the same tables
+const postgresMigrationLock = 0x53454e54 // "SENT"
+
+// Migrate creates the schema in one transaction. Tables are created with
+// every column SQLiteStorage has gained through its optional migrations;
+// columns added later go in as ADD COLUMN IF NOT EXISTS after them.
+func (s *PostgresStorage) Migrate() error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting migration: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("SELECT pg_advisory_xact_lock(?)", postgresMigrationLock); err != nil {
+		return fmt.Errorf("locking for migration: %w", err)
+	}
+
+	for _, m := range postgresMigrations {
+		if _, err := tx.Exec(m); err != nil {
+			return fmt.Errorf("executing migration: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+var postgresMigrations = []string{
+	`CREATE TABLE IF NOT EXISTS checks (
+		id BIGSERIAL PRIMARY KEY,
+		name TEXT NOT NULL,
+		url TEXT NOT NULL,
+		interval_seconds INTEGER NOT NULL DEFAULT 3600,
+		timeout_seconds INTEGER NOT NULL DEFAULT 10,
+		expected_status INTEGER NOT NULL DEFAULT 200,
+		enabled BOOLEAN NOT NULL DEFAULT TRUE,
+		tags TEXT,
+		regions TEXT DEFAULT '',
+		min_probes INTEGER NOT NULL DEFAULT 0,
+		description TEXT DEFAULT '',
+		runbook_url TEXT DEFAULT '',
+		streaming BOOLEAN NOT NULL DEFAULT FALSE,
+		paused BOOLEAN NOT NULL DEFAULT FALSE,
+		sample_seconds INTEGER NOT NULL DEFAULT 0,
+		alert_on_first_check BOOLEAN NOT NULL DEFAULT FALSE,
+		json_assertions TEXT DEFAULT '[]',
+		no_follow_redirects BOOLEAN NOT NULL DEFAULT FALSE,
+		expected_location TEXT DEFAULT '',
+		expected_cert_fingerprint TEXT DEFAULT '',
+		baseline_url TEXT DEFAULT '',
+		compare_fields TEXT DEFAULT '[]',
+		latency_tolerance_pct INTEGER NOT NULL DEFAULT 0,
+		type TEXT NOT NULL DEFAULT 'http',
+		body_contains TEXT DEFAULT '',
+		body_not_contains TEXT DEFAULT '',
+		expected_statuses TEXT DEFAULT '',
+		compress_results BOOLEAN NOT NULL DEFAULT FALSE,
+		bypass_cache BOOLEAN NOT NULL DEFAULT FALSE,
+		escalations TEXT DEFAULT '[]',
+		alert_channels TEXT DEFAULT '[]',
+		created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+	)`,
+	`CREATE TABLE IF NOT EXISTS check_results (
+		id BIGSERIAL PRIMARY KEY,
+		check_id BIGINT NOT NULL REFERENCES checks(id) ON DELETE CASCADE,
+		region TEXT DEFAULT '',
+		status TEXT NOT NULL,
+		status_code INTEGER,
+		response_time_ms INTEGER,
+		error_message TEXT,
+		checked_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+		run_started_at TIMESTAMPTZ,
+		weight INTEGER NOT NULL DEFAULT 1,
+		ssl_expires_at TIMESTAMPTZ,
+		ssl_days_left INTEGER,
+		ssl_issuer TEXT,
+		ssl_fingerprint TEXT,
+		body_hash TEXT,
+		baseline_status_code INTEGER,
+		baseline_response_time_ms INTEGER,
+		baseline_body_hash TEXT
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_check_results_check_id ON check_results(check_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_check_results_checked_at ON check_results(checked_at)`,
+	`CREATE TABLE IF NOT EXISTS incidents (
+		id BIGSERIAL PRIMARY KEY,
+		check_id BIGINT NOT NULL REFERENCES checks(id) ON DELETE CASCADE,
+		started_at TIMESTAMPTZ NOT NULL,
+		ended_at TIMESTAMPTZ,
+		duration_seconds INTEGER,
+		cause TEXT,
+		category TEXT DEFAULT '',
+		status TEXT DEFAULT 'investigating',
+		title TEXT
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_incidents_check_id ON incidents(check_id)`,
+	`CREATE TABLE IF NOT EXISTS incident_notes (
+		id BIGSERIAL PRIMARY KEY,
+		incident_id BIGINT NOT NULL REFERENCES incidents(id) ON DELETE CASCADE,
+		content TEXT NOT NULL,
+		author TEXT,
+		created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_incident_notes_incident_id ON incident_notes(incident_id)`,
+	`CREATE TABLE IF NOT EXISTS alert_log (
+		id BIGSERIAL PRIMARY KEY,
+		incident_id BIGINT REFERENCES incidents(id) ON DELETE CASCADE,
+		channel TEXT NOT NULL,
+		sent_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+		success BOOLEAN,
+		error_message TEXT
+	)`,
+	`CREATE TABLE IF NOT EXISTS hourly_aggregates (
+		id BIGSERIAL PRIMARY KEY,
+		check_id BIGINT NOT NULL REFERENCES checks(id) ON DELETE CASCADE,
+		hour TIMESTAMPTZ NOT NULL,
+		total_checks INTEGER NOT NULL,
+		success_count INTEGER NOT NULL,
+		failure_count INTEGER NOT NULL,
+		avg_response_ms INTEGER,
+		min_response_ms INTEGER,
+		max_response_ms INTEGER,
+		uptime_percent DOUBLE PRECISION,
+		UNIQUE (check_id, hour)
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_hourly_aggregates_check_id ON hourly_aggregates(check_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_hourly_aggregates_hour ON hourly_aggregates(hour)`,
+	`CREATE TABLE IF NOT EXISTS probes (
+		id BIGSERIAL PRIMARY KEY,
+		name TEXT NOT NULL,
+		region TEXT NOT NULL,
+		city TEXT,
+		country TEXT,
+		latitude DOUBLE PRECISION,
+		longitude DOUBLE PRECISION,
+		api_key TEXT UNIQUE NOT NULL,
+		status TEXT DEFAULT 'active',
+		last_heartbeat TIMESTAMPTZ,
+		created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+	)`,
+	`CREATE TABLE IF NOT EXISTS probe_results (
+		id BIGSERIAL PRIMARY KEY,
+		check_id BIGINT NOT NULL REFERENCES checks(id) ON DELETE CASCADE,
+		probe_id BIGINT NOT NULL REFERENCES probes(id) ON DELETE CASCADE,
+		status TEXT NOT NULL,
+		response_time_ms INTEGER,
+		status_code INTEGER,
+		error TEXT,
+		checked_at TIMESTAMPTZ NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_probe_results_check_id ON probe_results(check_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_probe_results_probe_id ON probe_results(probe_id)`,
+	`CREATE TABLE IF NOT EXISTS result_archives (
+		check_id BIGINT NOT NULL REFERENCES checks(id) ON DELETE CASCADE,
+		day TIMESTAMPTZ NOT NULL,
+		data BYTEA NOT NULL,
+		PRIMARY KEY (check_id, day)
+	)`,
+	`CREATE TABLE IF NOT EXISTS maintenance_windows (
+		id BIGSERIAL PRIMARY KEY,
+		name TEXT NOT NULL DEFAULT '',
+		check_id BIGINT REFERENCES checks(id) ON DELETE CASCADE,
+		starts_at TIMESTAMPTZ NOT NULL,
+		duration_seconds INTEGER NOT NULL,
+		recurrence TEXT NOT NULL DEFAULT 'once',
+		created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+	)`,
+	`CREATE TABLE IF NOT EXISTS golden_snapshots (
+		check_id BIGINT PRIMARY KEY REFERENCES checks(id) ON DELETE CASCADE,
+		body TEXT NOT NULL,
+		recorded_at TIMESTAMPTZ NOT NULL
+	)`,
+	// Typed body assertions
+	`ALTER TABLE checks ADD COLUMN IF NOT EXISTS assertion_type TEXT DEFAULT ''`,
+	`ALTER TABLE checks ADD COLUMN IF NOT EXISTS assertions TEXT DEFAULT '[]'`,
+	// Dual-stack checks
+	`ALTER TABLE checks ADD COLUMN IF NOT EXISTS dual_stack BOOLEAN NOT NULL DEFAULT FALSE`,
+	`ALTER TABLE check_results ADD COLUMN IF NOT EXISTS ipv4_status TEXT`,
+	`ALTER TABLE check_results ADD COLUMN IF NOT EXISTS ipv6_status TEXT`,
+	// DNS checks
+	`ALTER TABLE checks ADD COLUMN IF NOT EXISTS record_type TEXT DEFAULT ''`,
+	`ALTER TABLE checks ADD COLUMN IF NOT EXISTS expected_answer TEXT DEFAULT ''`,
+	// Per-alert-type routing
+	`ALTER TABLE checks ADD COLUMN IF NOT EXISTS alert_routes TEXT DEFAULT '{}'`,
+	// Settings, such as the maintenance banner
+	`CREATE TABLE IF NOT EXISTS settings (
+		key TEXT PRIMARY KEY,
+		value TEXT NOT NULL
+	)`,
+	// HTTP trailer assertions
+	`ALTER TABLE checks ADD COLUMN IF NOT EXISTS expected_trailer TEXT DEFAULT ''`,
+	// Redirect chain assertions
+	`ALTER TABLE checks ADD COLUMN IF NOT EXISTS expected_redirects TEXT DEFAULT '[]'`,
+	`ALTER TABLE check_results ADD COLUMN IF NOT EXISTS redirect_chain TEXT`,
+	// Request method, headers and body
+	`ALTER TABLE checks ADD COLUMN IF NOT EXISTS method TEXT DEFAULT ''`,
+	`ALTER TABLE checks ADD COLUMN IF NOT EXISTS headers TEXT DEFAULT '{}'`,
+	`ALTER TABLE checks ADD COLUMN IF NOT EXISTS request_body TEXT DEFAULT ''`,
+	// Degraded status
+	`ALTER TABLE checks ADD COLUMN IF NOT EXISTS degraded_threshold_ms INTEGER NOT NULL DEFAULT 0`,
+	// Explicit dashboard group
+	`ALTER TABLE checks ADD COLUMN IF NOT EXISTS group_name TEXT NOT NULL DEFAULT ''`,
+	// Timed pauses
+	`ALTER TABLE checks ADD COLUMN IF NOT EXISTS paused_until TIMESTAMPTZ`,
+	// Templated query parameters
+	`ALTER TABLE checks ADD COLUMN IF NOT EXISTS query_params TEXT DEFAULT '{}'`,
+	// Successes in a row before up
+	`ALTER TABLE checks ADD COLUMN IF NOT EXISTS consecutive_successes INTEGER NOT NULL DEFAULT 0`,
+	// Per-check failures in a row before an incident
+	`ALTER TABLE checks ADD COLUMN IF NOT EXISTS consecutive_failures INTEGER NOT NULL DEFAULT 0`,
+	// Response body length and media type assertions
+	`ALTER TABLE checks ADD COLUMN IF NOT EXISTS min_body_bytes INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE checks ADD COLUMN IF NOT EXISTS expected_content_type TEXT DEFAULT ''`,
+	// Skip TLS certificate verification
+	`ALTER TABLE checks ADD COLUMN IF NOT EXISTS insecure_skip_verify BOOLEAN NOT NULL DEFAULT FALSE`,
+	// Cron expression run instead of the interval
+	`ALTER TABLE checks ADD COLUMN IF NOT EXISTS cron TEXT DEFAULT ''`,
+	// Multiple of the 24h average response time that sends a slow alert
+	`ALTER TABLE checks ADD COLUMN IF NOT EXISTS slow_multiplier DOUBLE PRECISION DEFAULT 0`,
+	// Ticket ID in an external incident system
+	`ALTER TABLE incidents ADD COLUMN IF NOT EXISTS external_id TEXT DEFAULT ''`,
+}
@@ -0,0 +1,9 @@
+//go:build postgres
+
+package storage
+
+// The PostgreSQL driver is opt-in so default builds stay free of it:
+//
+//	go get github.com/jackc/pgx/v5
+//	go build -tags postgres ./cmd/sentinel
+import _ "github.com/jackc/pgx/v5/stdlib"
@@ -4,19 +4,25 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"math"
+	"os"
+	"sort"
 	"time"
 
 	_ "modernc.org/sqlite"
 )
 
 type SQLiteStorage struct {
-	db *sql.DB
+	db *sqlDB
+	// cipher encrypts sensitive columns; nil stores them as they are
+	cipher *columnCipher
 }
 
 func NewSQLiteStorage(dbPath string) (*SQLiteStorage, error) {
 	// Add busy_timeout to handle concurrent access gracefully
-	// Wait up to 5 seconds for locks to clear before failing
-	connStr := dbPath + "?_busy_timeout=5000"
+	// Wait up to 5 seconds for locks to clear before failing. The driver
+	// applies _pragma to every connection it opens.
+	connStr := dbPath + "?_pragma=busy_timeout(5000)"
 	db, err := sql.Open("sqlite", connStr)
 	if err != nil {
 		return nil, fmt.Errorf("opening database: %w", err)
@@ -32,10 +38,19 @@ func NewSQLiteStorage(dbPath string) (*SQLiteStorage, error) {
 		return nil, fmt.Errorf("enabling foreign keys: %w", err)
 	}
 
-	s := &SQLiteStorage{db: db}
+	columns, err := newColumnCipher(os.Getenv(EncryptionKeyEnv))
+	if err != nil {
+		return nil, err
+	}
+
+	s := &SQLiteStorage{db: &sqlDB{DB: db}, cipher: columns}
 	if err := s.Migrate(); err != nil {
 		return nil, fmt.Errorf("running migrations: %w", err)
 	}
+	if err := s.setupEncryption(); err != nil {
+		db.Close()
+		return nil, err
+	}
 
 	return s, nil
 }
@@ -139,6 +154,33 @@ func (s *SQLiteStorage) Migrate() error {
 		)`,
 		`CREATE INDEX IF NOT EXISTS idx_probe_results_check_id ON probe_results(check_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_probe_results_probe_id ON probe_results(probe_id)`,
+		`CREATE TABLE IF NOT EXISTS result_archives (
+			check_id INTEGER NOT NULL,
+			day DATETIME NOT NULL,
+			data BLOB NOT NULL,
+			PRIMARY KEY (check_id, day),
+			FOREIGN KEY (check_id) REFERENCES checks(id) ON DELETE CASCADE
+		)`,
+		`CREATE TABLE IF NOT EXISTS maintenance_windows (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL DEFAULT '',
+			check_id INTEGER,
+			starts_at DATETIME NOT NULL,
+			duration_seconds INTEGER NOT NULL,
+			recurrence TEXT NOT NULL DEFAULT 'once',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (check_id) REFERENCES checks(id) ON DELETE CASCADE
+		)`,
+		`CREATE TABLE IF NOT EXISTS golden_snapshots (
+			check_id INTEGER PRIMARY KEY,
+			body TEXT NOT NULL,
+			recorded_at DATETIME NOT NULL,
+			FOREIGN KEY (check_id) REFERENCES checks(id) ON DELETE CASCADE
+		)`,
+		`CREATE TABLE IF NOT EXISTS settings (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL
+		)`,
 	}
 
 	for _, m := range migrations {
@@ -147,7 +189,8 @@ func (s *SQLiteStorage) Migrate() error {
 		}
 	}
 
-	// Add columns to existing tables (will fail silently if columns exist)
+	// Add columns to existing tables (will fail silently if columns exist).
+	// New columns need adding to postgresMigrations too.
 	optionalMigrations := []string{
 		// SSL columns for check_results
 		`ALTER TABLE check_results ADD COLUMN ssl_expires_at DATETIME`,
@@ -161,6 +204,97 @@ func (s *SQLiteStorage) Migrate() error {
 		`ALTER TABLE checks ADD COLUMN regions TEXT DEFAULT ''`,
 		// Minimum probes for distributed checks
 		`ALTER TABLE checks ADD COLUMN min_probes INTEGER NOT NULL DEFAULT 0`,
+		// Runbook context for alerts
+		`ALTER TABLE checks ADD COLUMN description TEXT DEFAULT ''`,
+		`ALTER TABLE checks ADD COLUMN runbook_url TEXT DEFAULT ''`,
+		// Streaming responses are checked without reading to EOF
+		`ALTER TABLE checks ADD COLUMN streaming INTEGER NOT NULL DEFAULT 0`,
+		// Paused checks stay scheduled but skip execution
+		`ALTER TABLE checks ADD COLUMN paused INTEGER NOT NULL DEFAULT 0`,
+		// Result sampling: heartbeat interval per check, evaluations per stored result
+		`ALTER TABLE checks ADD COLUMN sample_seconds INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE check_results ADD COLUMN weight INTEGER NOT NULL DEFAULT 1`,
+		// Alert on a failing first result instead of waiting for a known state
+		`ALTER TABLE checks ADD COLUMN alert_on_first_check INTEGER NOT NULL DEFAULT 0`,
+		// JSON body assertions, stored as a JSON array like tags
+		`ALTER TABLE checks ADD COLUMN json_assertions TEXT DEFAULT '[]'`,
+		// Redirect policy: stop at the first redirect and assert its Location
+		`ALTER TABLE checks ADD COLUMN no_follow_redirects INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE checks ADD COLUMN expected_location TEXT DEFAULT ''`,
+		// Certificate pinning: the pinned fingerprint and the one each result saw
+		`ALTER TABLE checks ADD COLUMN expected_cert_fingerprint TEXT DEFAULT ''`,
+		`ALTER TABLE check_results ADD COLUMN ssl_fingerprint TEXT`,
+		// Canary comparison: the baseline to compare against and both sides' metrics
+		`ALTER TABLE checks ADD COLUMN baseline_url TEXT DEFAULT ''`,
+		`ALTER TABLE checks ADD COLUMN compare_fields TEXT DEFAULT '[]'`,
+		`ALTER TABLE checks ADD COLUMN latency_tolerance_pct INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE check_results ADD COLUMN body_hash TEXT`,
+		`ALTER TABLE check_results ADD COLUMN baseline_status_code INTEGER`,
+		`ALTER TABLE check_results ADD COLUMN baseline_response_time_ms INTEGER`,
+		`ALTER TABLE check_results ADD COLUMN baseline_body_hash TEXT`,
+		// Normalized incident cause for grouping (timeout, dns, tls, ...)
+		`ALTER TABLE incidents ADD COLUMN category TEXT DEFAULT ''`,
+		// Check type: existing rows are HTTP checks
+		`ALTER TABLE checks ADD COLUMN type TEXT NOT NULL DEFAULT 'http'`,
+		// Keywords the response body must (or must not) contain
+		`ALTER TABLE checks ADD COLUMN body_contains TEXT DEFAULT ''`,
+		`ALTER TABLE checks ADD COLUMN body_not_contains TEXT DEFAULT ''`,
+		// Status spec like "200,204" or "2xx" that overrides expected_status
+		`ALTER TABLE checks ADD COLUMN expected_statuses TEXT DEFAULT ''`,
+		// Run-length compression: a row with run_started_at stands for weight
+		// identical results between run_started_at and checked_at
+		`ALTER TABLE checks ADD COLUMN compress_results INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE check_results ADD COLUMN run_started_at DATETIME`,
+		// Cache bypass for CDN-fronted checks
+		`ALTER TABLE checks ADD COLUMN bypass_cache INTEGER NOT NULL DEFAULT 0`,
+		// Escalation rules, as JSON
+		`ALTER TABLE checks ADD COLUMN escalations TEXT DEFAULT '[]'`,
+		// Per-check alert routing, as JSON
+		`ALTER TABLE checks ADD COLUMN alert_channels TEXT DEFAULT '[]'`,
+		// Typed body assertions, as JSON, and the matcher they are written for
+		`ALTER TABLE checks ADD COLUMN assertion_type TEXT DEFAULT ''`,
+		`ALTER TABLE checks ADD COLUMN assertions TEXT DEFAULT '[]'`,
+		// Dual-stack checks and each address family's outcome
+		`ALTER TABLE checks ADD COLUMN dual_stack INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE check_results ADD COLUMN ipv4_status TEXT`,
+		`ALTER TABLE check_results ADD COLUMN ipv6_status TEXT`,
+		// DNS checks: the record looked up and the answer expected among its values
+		`ALTER TABLE checks ADD COLUMN record_type TEXT DEFAULT ''`,
+		`ALTER TABLE checks ADD COLUMN expected_answer TEXT DEFAULT ''`,
+		// Per-alert-type routing, as JSON
+		`ALTER TABLE checks ADD COLUMN alert_routes TEXT DEFAULT '{}'`,
+		// HTTP trailer assertion, as "Name: value"
+		`ALTER TABLE checks ADD COLUMN expected_trailer TEXT DEFAULT ''`,
+		// Redirect chain assertion, as JSON, and the chain each result followed
+		`ALTER TABLE checks ADD COLUMN expected_redirects TEXT DEFAULT '[]'`,
+		`ALTER TABLE check_results ADD COLUMN redirect_chain TEXT`,
+		// Request method, headers (as JSON) and body
+		`ALTER TABLE checks ADD COLUMN method TEXT DEFAULT ''`,
+		`ALTER TABLE checks ADD COLUMN headers TEXT DEFAULT '{}'`,
+		`ALTER TABLE checks ADD COLUMN request_body TEXT DEFAULT ''`,
+		// Response time above which a success is stored as degraded
+		`ALTER TABLE checks ADD COLUMN degraded_threshold_ms INTEGER DEFAULT 0`,
+		// Explicit dashboard group, over the first tag
+		`ALTER TABLE checks ADD COLUMN group_name TEXT DEFAULT ''`,
+		// A pause that ends on its own; NULL pauses until resumed
+		`ALTER TABLE checks ADD COLUMN paused_until DATETIME`,
+		// Templated query parameters, as JSON
+		`ALTER TABLE checks ADD COLUMN query_params TEXT DEFAULT '{}'`,
+		// Up results in a row needed before a check counts as up
+		`ALTER TABLE checks ADD COLUMN consecutive_successes INTEGER DEFAULT 0`,
+		// Per-check failures in a row before an incident (0 = global)
+		`ALTER TABLE checks ADD COLUMN consecutive_failures INTEGER DEFAULT 0`,
+		// Response body length and media type assertions
+		`ALTER TABLE checks ADD COLUMN min_body_bytes INTEGER DEFAULT 0`,
+		`ALTER TABLE checks ADD COLUMN expected_content_type TEXT DEFAULT ''`,
+		// Skip TLS certificate verification
+		`ALTER TABLE checks ADD COLUMN insecure_skip_verify INTEGER NOT NULL DEFAULT 0`,
+		// Cron expression run instead of the interval ('' = interval)
+		`ALTER TABLE checks ADD COLUMN cron TEXT DEFAULT ''`,
+		// Alert when a response is this many times the 24h average (0 = global)
+		`ALTER TABLE checks ADD COLUMN slow_multiplier REAL DEFAULT 0`,
+		// Ticket ID in an external incident system
+		`ALTER TABLE incidents ADD COLUMN external_id TEXT DEFAULT ''`,
 	}
 	for _, m := range optionalMigrations {
 		s.db.Exec(m) // Ignore errors (column already exists)
@@ -169,6 +303,24 @@ func (s *SQLiteStorage) Migrate() error {
 	return nil
 }
 
+// Backup writes a consistent copy of the database to path, which must not
+// exist yet. VACUUM INTO reads one snapshot, so with WAL it's safe while the
+// scheduler keeps writing, and the copy comes out compacted.
+func (s *SQLiteStorage) Backup(path string) error {
+	return s.backup(path, path)
+}
+
+// backup runs VACUUM INTO target, the file name or URI that writes path
+func (s *SQLiteStorage) backup(path, target string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("backup %s already exists", path)
+	}
+	if _, err := s.db.Exec("VACUUM INTO ?", target); err != nil {
+		return fmt.Errorf("backing up database: %w", err)
+	}
+	return nil
+}
+
 func (s *SQLiteStorage) Close() error {
 	return s.db.Close()
 }
@@ -186,17 +338,57 @@ func (s *SQLiteStorage) CreateCheck(check *Check) error {
 		return fmt.Errorf("marshaling regions: %w", err)
 	}
 
-	result, err := s.db.Exec(`
-		INSERT INTO checks (name, url, interval_seconds, timeout_seconds, expected_status, enabled, tags, regions, min_probes, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, check.Name, check.URL, check.IntervalSecs, check.TimeoutSecs, check.ExpectedStatus, check.Enabled, string(tagsJSON), string(regionsJSON), check.MinProbes, time.Now(), time.Now())
+	assertionsJSON, err := json.Marshal(check.JSONAssertions)
 	if err != nil {
-		return fmt.Errorf("inserting check: %w", err)
+		return fmt.Errorf("marshaling json assertions: %w", err)
 	}
 
-	id, err := result.LastInsertId()
+	compareJSON, err := json.Marshal(check.CompareFields)
 	if err != nil {
-		return fmt.Errorf("getting last insert id: %w", err)
+		return fmt.Errorf("marshaling compare fields: %w", err)
+	}
+
+	escalationsJSON, err := json.Marshal(check.Escalations)
+	if err != nil {
+		return fmt.Errorf("marshaling escalations: %w", err)
+	}
+
+	channelsJSON, err := json.Marshal(check.AlertChannels)
+	if err != nil {
+		return fmt.Errorf("marshaling alert channels: %w", err)
+	}
+
+	bodyAssertionsJSON, err := json.Marshal(check.Assertions)
+	if err != nil {
+		return fmt.Errorf("marshaling assertions: %w", err)
+	}
+
+	routesJSON, err := json.Marshal(check.AlertRoutes)
+	if err != nil {
+		return fmt.Errorf("marshaling alert routes: %w", err)
+	}
+
+	redirectsJSON, err := json.Marshal(check.ExpectedRedirects)
+	if err != nil {
+		return fmt.Errorf("marshaling expected redirects: %w", err)
+	}
+
+	headersJSON, err := json.Marshal(check.Headers)
+	if err != nil {
+		return fmt.Errorf("marshaling headers: %w", err)
+	}
+
+	queryJSON, err := json.Marshal(check.QueryParams)
+	if err != nil {
+		return fmt.Errorf("marshaling query params: %w", err)
+	}
+
+	id, err := s.db.insert(`
+		INSERT INTO checks (name, url, interval_seconds, timeout_seconds, expected_status, enabled, tags, regions, min_probes, description, runbook_url, streaming, paused, sample_seconds, alert_on_first_check, json_assertions, no_follow_redirects, expected_location, expected_cert_fingerprint, baseline_url, compare_fields, latency_tolerance_pct, type, body_contains, body_not_contains, expected_statuses, compress_results, bypass_cache, escalations, alert_channels, assertion_type, assertions, dual_stack, record_type, expected_answer, alert_routes, expected_trailer, expected_redirects, method, headers, request_body, degraded_threshold_ms, group_name, paused_until, query_params, consecutive_successes, consecutive_failures, min_body_bytes, expected_content_type, insecure_skip_verify, cron, slow_multiplier, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, check.Name, s.cipher.seal(check.URL), check.IntervalSecs, check.TimeoutSecs, check.ExpectedStatus, check.Enabled, string(tagsJSON), string(regionsJSON), check.MinProbes, check.Description, check.RunbookURL, check.Streaming, check.Paused, check.SampleSecs, check.AlertOnFirstCheck, string(assertionsJSON), check.NoFollowRedirects, check.ExpectedLocation, check.ExpectedCertFingerprint, s.cipher.seal(check.BaselineURL), string(compareJSON), check.LatencyTolerancePct, checkType(check.Type), check.BodyContains, check.BodyNotContains, check.ExpectedStatuses, check.CompressResults, check.BypassCache, string(escalationsJSON), string(channelsJSON), check.AssertionType, string(bodyAssertionsJSON), check.DualStack, check.RecordType, check.ExpectedAnswer, string(routesJSON), check.ExpectedTrailer, string(redirectsJSON), check.Method, s.cipher.seal(string(headersJSON)), s.cipher.seal(check.RequestBody), check.DegradedThresholdMs, check.Group, check.PausedUntil, s.cipher.seal(string(queryJSON)), check.ConsecutiveSuccesses, check.ConsecutiveFailures, check.MinBodyBytes, check.ExpectedContentType, check.InsecureSkipVerify, check.Cron, check.SlowMultiplier, time.Now(), time.Now())
+	if err != nil {
+		return fmt.Errorf("inserting check: %w", err)
 	}
 
 	check.ID = id
@@ -207,7 +399,7 @@ func (s *SQLiteStorage) CreateCheck(check *Check) error {
 
 func (s *SQLiteStorage) GetCheck(id int64) (*Check, error) {
 	row := s.db.QueryRow(`
-		SELECT id, name, url, interval_seconds, timeout_seconds, expected_status, enabled, tags, regions, COALESCE(min_probes, 0), created_at, updated_at
+		SELECT `+checkColumns+`
 		FROM checks WHERE id = ?
 	`, id)
 
@@ -216,16 +408,16 @@ func (s *SQLiteStorage) GetCheck(id int64) (*Check, error) {
 
 func (s *SQLiteStorage) GetCheckByURL(url string) (*Check, error) {
 	row := s.db.QueryRow(`
-		SELECT id, name, url, interval_seconds, timeout_seconds, expected_status, enabled, tags, regions, COALESCE(min_probes, 0), created_at, updated_at
+		SELECT `+checkColumns+`
 		FROM checks WHERE url = ?
-	`, url)
+	`, s.cipher.seal(url))
 
 	return s.scanCheck(row)
 }
 
 func (s *SQLiteStorage) ListChecks() ([]*Check, error) {
 	rows, err := s.db.Query(`
-		SELECT id, name, url, interval_seconds, timeout_seconds, expected_status, enabled, tags, regions, COALESCE(min_probes, 0), created_at, updated_at
+		SELECT ` + checkColumns + `
 		FROM checks ORDER BY name
 	`)
 	if err != nil {
@@ -238,8 +430,8 @@ func (s *SQLiteStorage) ListChecks() ([]*Check, error) {
 
 func (s *SQLiteStorage) ListEnabledChecks() ([]*Check, error) {
 	rows, err := s.db.Query(`
-		SELECT id, name, url, interval_seconds, timeout_seconds, expected_status, enabled, tags, regions, COALESCE(min_probes, 0), created_at, updated_at
-		FROM checks WHERE enabled = 1 ORDER BY name
+		SELECT ` + checkColumns + `
+		FROM checks WHERE enabled = TRUE ORDER BY name
 	`)
 	if err != nil {
 		return nil, fmt.Errorf("querying enabled checks: %w", err)
@@ -250,24 +442,75 @@ func (s *SQLiteStorage) ListEnabledChecks() ([]*Check, error) {
 }
 
 func (s *SQLiteStorage) ListChecksByTag(tag string) ([]*Check, error) {
-	// Get all enabled checks and filter by tag in Go (SQLite JSON support is limited)
-	checks, err := s.ListEnabledChecks()
+	return s.FilterChecks(CheckFilter{Tag: tag, EnabledOnly: true})
+}
+
+// FilterChecks returns the checks that match filter, by name. Filtered in Go
+// (SQLite JSON support is limited for tags, and status is computed); a status
+// filter fills in the computed fields from one bulk latest-result query. The
+// zero filter is ListChecks.
+func (s *SQLiteStorage) FilterChecks(filter CheckFilter) ([]*Check, error) {
+	var checks []*Check
+	var err error
+	if filter.EnabledOnly {
+		checks, err = s.ListEnabledChecks()
+	} else {
+		checks, err = s.ListChecks()
+	}
+	if err != nil || filter == (CheckFilter{}) {
+		return checks, err
+	}
+
+	var latest map[int64][]*CheckResult
+	if filter.Status != "" {
+		if latest, err = s.GetLatestResults(); err != nil {
+			return nil, err
+		}
+	}
+
+	var result []*Check
+	for _, check := range checks {
+		if filter.Status != "" {
+			check.ApplyLatest(latest[check.ID])
+			SettleStatus(s, check)
+		}
+		if filter.Matches(check) {
+			result = append(result, check)
+		}
+	}
+	return result, nil
+}
+
+// GetChecksModifiedSince returns checks created or updated after t, oldest
+// change first, for incremental sync. Filtered in Go like FilterChecks
+// so the comparison doesn't depend on how timestamps are stored.
+func (s *SQLiteStorage) GetChecksModifiedSince(t time.Time) ([]*Check, error) {
+	checks, err := s.ListChecks()
 	if err != nil {
 		return nil, err
 	}
 
 	var result []*Check
 	for _, check := range checks {
-		for _, t := range check.Tags {
-			if t == tag {
-				result = append(result, check)
-				break
-			}
+		if check.UpdatedAt.After(t) {
+			result = append(result, check)
 		}
 	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].UpdatedAt.Equal(result[j].UpdatedAt) {
+			return result[i].ID < result[j].ID
+		}
+		return result[i].UpdatedAt.Before(result[j].UpdatedAt)
+	})
 	return result, nil
 }
 
+// ListChecksByStatus returns checks whose computed status is status, with
+// their computed fields filled in from one bulk latest-result query
+func (s *SQLiteStorage) ListChecksByStatus(status string) ([]*Check, error) {
+	return s.FilterChecks(CheckFilter{Status: status})
+}
+
 func (s *SQLiteStorage) UpdateCheck(check *Check) error {
 	tagsJSON, err := json.Marshal(check.Tags)
 	if err != nil {
@@ -279,10 +522,55 @@ func (s *SQLiteStorage) UpdateCheck(check *Check) error {
 		return fmt.Errorf("marshaling regions: %w", err)
 	}
 
+	assertionsJSON, err := json.Marshal(check.JSONAssertions)
+	if err != nil {
+		return fmt.Errorf("marshaling json assertions: %w", err)
+	}
+
+	compareJSON, err := json.Marshal(check.CompareFields)
+	if err != nil {
+		return fmt.Errorf("marshaling compare fields: %w", err)
+	}
+
+	escalationsJSON, err := json.Marshal(check.Escalations)
+	if err != nil {
+		return fmt.Errorf("marshaling escalations: %w", err)
+	}
+
+	channelsJSON, err := json.Marshal(check.AlertChannels)
+	if err != nil {
+		return fmt.Errorf("marshaling alert channels: %w", err)
+	}
+
+	bodyAssertionsJSON, err := json.Marshal(check.Assertions)
+	if err != nil {
+		return fmt.Errorf("marshaling assertions: %w", err)
+	}
+
+	routesJSON, err := json.Marshal(check.AlertRoutes)
+	if err != nil {
+		return fmt.Errorf("marshaling alert routes: %w", err)
+	}
+
+	redirectsJSON, err := json.Marshal(check.ExpectedRedirects)
+	if err != nil {
+		return fmt.Errorf("marshaling expected redirects: %w", err)
+	}
+
+	headersJSON, err := json.Marshal(check.Headers)
+	if err != nil {
+		return fmt.Errorf("marshaling headers: %w", err)
+	}
+
+	queryJSON, err := json.Marshal(check.QueryParams)
+	if err != nil {
+		return fmt.Errorf("marshaling query params: %w", err)
+	}
+
 	_, err = s.db.Exec(`
-		UPDATE checks SET name = ?, url = ?, interval_seconds = ?, timeout_seconds = ?, expected_status = ?, enabled = ?, tags = ?, regions = ?, min_probes = ?, updated_at = ?
+		UPDATE checks SET name = ?, url = ?, interval_seconds = ?, timeout_seconds = ?, expected_status = ?, enabled = ?, tags = ?, regions = ?, min_probes = ?, description = ?, runbook_url = ?, streaming = ?, paused = ?, sample_seconds = ?, alert_on_first_check = ?, json_assertions = ?, no_follow_redirects = ?, expected_location = ?, expected_cert_fingerprint = ?, baseline_url = ?, compare_fields = ?, latency_tolerance_pct = ?, type = ?, body_contains = ?, body_not_contains = ?, expected_statuses = ?, compress_results = ?, bypass_cache = ?, escalations = ?, alert_channels = ?, assertion_type = ?, assertions = ?, dual_stack = ?, record_type = ?, expected_answer = ?, alert_routes = ?, expected_trailer = ?, expected_redirects = ?, method = ?, headers = ?, request_body = ?, degraded_threshold_ms = ?, group_name = ?, paused_until = ?, query_params = ?, consecutive_successes = ?, consecutive_failures = ?, min_body_bytes = ?, expected_content_type = ?, insecure_skip_verify = ?, cron = ?, slow_multiplier = ?, updated_at = ?
 		WHERE id = ?
-	`, check.Name, check.URL, check.IntervalSecs, check.TimeoutSecs, check.ExpectedStatus, check.Enabled, string(tagsJSON), string(regionsJSON), check.MinProbes, time.Now(), check.ID)
+	`, check.Name, s.cipher.seal(check.URL), check.IntervalSecs, check.TimeoutSecs, check.ExpectedStatus, check.Enabled, string(tagsJSON), string(regionsJSON), check.MinProbes, check.Description, check.RunbookURL, check.Streaming, check.Paused, check.SampleSecs, check.AlertOnFirstCheck, string(assertionsJSON), check.NoFollowRedirects, check.ExpectedLocation, check.ExpectedCertFingerprint, s.cipher.seal(check.BaselineURL), string(compareJSON), check.LatencyTolerancePct, checkType(check.Type), check.BodyContains, check.BodyNotContains, check.ExpectedStatuses, check.CompressResults, check.BypassCache, string(escalationsJSON), string(channelsJSON), check.AssertionType, string(bodyAssertionsJSON), check.DualStack, check.RecordType, check.ExpectedAnswer, string(routesJSON), check.ExpectedTrailer, string(redirectsJSON), check.Method, s.cipher.seal(string(headersJSON)), s.cipher.seal(check.RequestBody), check.DegradedThresholdMs, check.Group, check.PausedUntil, s.cipher.seal(string(queryJSON)), check.ConsecutiveSuccesses, check.ConsecutiveFailures, check.MinBodyBytes, check.ExpectedContentType, check.InsecureSkipVerify, check.Cron, check.SlowMultiplier, time.Now(), check.ID)
 	if err != nil {
 		return fmt.Errorf("updating check: %w", err)
 	}
@@ -291,6 +579,32 @@ func (s *SQLiteStorage) UpdateCheck(check *Check) error {
 	return nil
 }
 
+// SetCheckPaused pauses a check until it is resumed, or resumes it, clearing
+// any timed pause either way
+func (s *SQLiteStorage) SetCheckPaused(id int64, paused bool) error {
+	result, err := s.db.Exec("UPDATE checks SET paused = ?, paused_until = NULL, updated_at = ? WHERE id = ?", paused, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("updating check paused state: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("check not found")
+	}
+	return nil
+}
+
+// PauseCheckUntil pauses a check until the given time, when the scheduler
+// resumes it
+func (s *SQLiteStorage) PauseCheckUntil(id int64, until time.Time) error {
+	result, err := s.db.Exec("UPDATE checks SET paused = ?, paused_until = ?, updated_at = ? WHERE id = ?", true, until, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("updating check paused state: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("check not found")
+	}
+	return nil
+}
+
 func (s *SQLiteStorage) DeleteCheck(id int64) error {
 	_, err := s.db.Exec("DELETE FROM checks WHERE id = ?", id)
 	if err != nil {
@@ -299,20 +613,123 @@ func (s *SQLiteStorage) DeleteCheck(id int64) error {
 	return nil
 }
 
+// ResetCheckHistory deletes a check's results, aggregates and incidents in one
+// transaction, keeping the check itself so its ID stays valid
+func (s *SQLiteStorage) ResetCheckHistory(checkID int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting reset: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Children of incidents first, in case foreign keys aren't enforced
+	statements := []string{
+		`DELETE FROM incident_notes WHERE incident_id IN (SELECT id FROM incidents WHERE check_id = ?)`,
+		`DELETE FROM alert_log WHERE incident_id IN (SELECT id FROM incidents WHERE check_id = ?)`,
+		`DELETE FROM incidents WHERE check_id = ?`,
+		`DELETE FROM check_results WHERE check_id = ?`,
+		`DELETE FROM hourly_aggregates WHERE check_id = ?`,
+		`DELETE FROM result_archives WHERE check_id = ?`,
+		`DELETE FROM probe_results WHERE check_id = ?`,
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt, checkID); err != nil {
+			return fmt.Errorf("resetting check history: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing reset: %w", err)
+	}
+	return nil
+}
+
+// checkType stores checks created without a type as HTTP checks
+func checkType(t string) string {
+	if t == "" {
+		return CheckTypeHTTP
+	}
+	return t
+}
+
+// checkColumns is the column list selected by every check query, in the
+// order scanCheckRow expects
+const checkColumns = `id, name, url, interval_seconds, timeout_seconds, expected_status, enabled, tags, regions,
+		COALESCE(min_probes, 0), COALESCE(description, ''), COALESCE(runbook_url, ''), COALESCE(streaming, FALSE), COALESCE(paused, FALSE), COALESCE(sample_seconds, 0), COALESCE(alert_on_first_check, FALSE), json_assertions,
+		COALESCE(no_follow_redirects, FALSE), COALESCE(expected_location, ''), COALESCE(expected_cert_fingerprint, ''),
+		COALESCE(baseline_url, ''), compare_fields, COALESCE(latency_tolerance_pct, 0), COALESCE(type, 'http'),
+		COALESCE(body_contains, ''), COALESCE(body_not_contains, ''), COALESCE(expected_statuses, ''), COALESCE(compress_results, FALSE), COALESCE(bypass_cache, FALSE), escalations, alert_channels,
+		COALESCE(assertion_type, ''), assertions, COALESCE(dual_stack, FALSE),
+		COALESCE(record_type, ''), COALESCE(expected_answer, ''), alert_routes, COALESCE(expected_trailer, ''), expected_redirects,
+		COALESCE(method, ''), headers, COALESCE(request_body, ''), COALESCE(degraded_threshold_ms, 0), COALESCE(group_name, ''), paused_until, query_params, COALESCE(consecutive_successes, 0), COALESCE(consecutive_failures, 0), COALESCE(min_body_bytes, 0), COALESCE(expected_content_type, ''), COALESCE(insecure_skip_verify, FALSE), COALESCE(cron, ''), COALESCE(slow_multiplier, 0), created_at, updated_at`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
 func (s *SQLiteStorage) scanCheck(row *sql.Row) (*Check, error) {
+	check, err := s.scanCheckRow(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning check: %w", err)
+	}
+	if err := s.decryptCheck(check); err != nil {
+		return nil, err
+	}
+	return check, nil
+}
+
+func (s *SQLiteStorage) scanChecks(rows *sql.Rows) ([]*Check, error) {
+	var checks []*Check
+
+	for rows.Next() {
+		check, err := s.scanCheckRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning check: %w", err)
+		}
+		if err := s.decryptCheck(check); err != nil {
+			return nil, err
+		}
+		checks = append(checks, check)
+	}
+
+	return checks, nil
+}
+
+func (s *SQLiteStorage) scanCheckRow(row rowScanner) (*Check, error) {
 	var check Check
 	var tagsJSON sql.NullString
 	var regionsJSON sql.NullString
+	var assertionsJSON sql.NullString
+	var compareJSON sql.NullString
+	var escalationsJSON sql.NullString
+	var channelsJSON sql.NullString
+	var bodyAssertionsJSON sql.NullString
+	var routesJSON sql.NullString
+	var redirectsJSON sql.NullString
+	var headersJSON sql.NullString
+	var pausedUntil sql.NullTime
+	var queryJSON sql.NullString
 
 	err := row.Scan(
 		&check.ID, &check.Name, &check.URL, &check.IntervalSecs, &check.TimeoutSecs,
-		&check.ExpectedStatus, &check.Enabled, &tagsJSON, &regionsJSON, &check.MinProbes, &check.CreatedAt, &check.UpdatedAt,
+		&check.ExpectedStatus, &check.Enabled, &tagsJSON, &regionsJSON, &check.MinProbes,
+		&check.Description, &check.RunbookURL, &check.Streaming, &check.Paused, &check.SampleSecs, &check.AlertOnFirstCheck, &assertionsJSON, &check.NoFollowRedirects, &check.ExpectedLocation, &check.ExpectedCertFingerprint,
+		&check.BaselineURL, &compareJSON, &check.LatencyTolerancePct, &check.Type,
+		&check.BodyContains, &check.BodyNotContains, &check.ExpectedStatuses, &check.CompressResults, &check.BypassCache, &escalationsJSON, &channelsJSON,
+		&check.AssertionType, &bodyAssertionsJSON, &check.DualStack,
+		&check.RecordType, &check.ExpectedAnswer, &routesJSON, &check.ExpectedTrailer, &redirectsJSON,
+		&check.Method, &headersJSON, &check.RequestBody, &check.DegradedThresholdMs, &check.Group, &pausedUntil, &queryJSON, &check.ConsecutiveSuccesses, &check.ConsecutiveFailures, &check.MinBodyBytes, &check.ExpectedContentType, &check.InsecureSkipVerify, &check.Cron, &check.SlowMultiplier, &check.CreatedAt, &check.UpdatedAt,
 	)
-	if err == sql.ErrNoRows {
-		return nil, nil
-	}
 	if err != nil {
-		return nil, fmt.Errorf("scanning check: %w", err)
+		return nil, err
+	}
+
+	if pausedUntil.Valid {
+		check.PausedUntil = &pausedUntil.Time
 	}
 
 	if tagsJSON.Valid && tagsJSON.String != "" {
@@ -327,64 +744,176 @@ func (s *SQLiteStorage) scanCheck(row *sql.Row) (*Check, error) {
 		}
 	}
 
-	check.Status = "pending"
-	return &check, nil
-}
+	if assertionsJSON.Valid && assertionsJSON.String != "" {
+		if err := json.Unmarshal([]byte(assertionsJSON.String), &check.JSONAssertions); err != nil {
+			check.JSONAssertions = nil
+		}
+	}
 
-func (s *SQLiteStorage) scanChecks(rows *sql.Rows) ([]*Check, error) {
-	var checks []*Check
+	if compareJSON.Valid && compareJSON.String != "" {
+		if err := json.Unmarshal([]byte(compareJSON.String), &check.CompareFields); err != nil {
+			check.CompareFields = nil
+		}
+	}
 
-	for rows.Next() {
-		var check Check
-		var tagsJSON sql.NullString
-		var regionsJSON sql.NullString
+	if escalationsJSON.Valid && escalationsJSON.String != "" {
+		if err := json.Unmarshal([]byte(escalationsJSON.String), &check.Escalations); err != nil {
+			check.Escalations = nil
+		}
+	}
 
-		err := rows.Scan(
-			&check.ID, &check.Name, &check.URL, &check.IntervalSecs, &check.TimeoutSecs,
-			&check.ExpectedStatus, &check.Enabled, &tagsJSON, &regionsJSON, &check.MinProbes, &check.CreatedAt, &check.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("scanning check: %w", err)
+	if channelsJSON.Valid && channelsJSON.String != "" {
+		if err := json.Unmarshal([]byte(channelsJSON.String), &check.AlertChannels); err != nil {
+			check.AlertChannels = nil
 		}
+	}
 
-		if tagsJSON.Valid && tagsJSON.String != "" {
-			if err := json.Unmarshal([]byte(tagsJSON.String), &check.Tags); err != nil {
-				check.Tags = []string{}
-			}
+	if bodyAssertionsJSON.Valid && bodyAssertionsJSON.String != "" {
+		if err := json.Unmarshal([]byte(bodyAssertionsJSON.String), &check.Assertions); err != nil {
+			check.Assertions = nil
 		}
+	}
 
-		if regionsJSON.Valid && regionsJSON.String != "" {
-			if err := json.Unmarshal([]byte(regionsJSON.String), &check.Regions); err != nil {
-				check.Regions = []string{}
-			}
+	if routesJSON.Valid && routesJSON.String != "" {
+		if err := json.Unmarshal([]byte(routesJSON.String), &check.AlertRoutes); err != nil {
+			check.AlertRoutes = nil
 		}
+	}
 
-		check.Status = "pending"
-		checks = append(checks, &check)
+	if redirectsJSON.Valid && redirectsJSON.String != "" {
+		if err := json.Unmarshal([]byte(redirectsJSON.String), &check.ExpectedRedirects); err != nil {
+			check.ExpectedRedirects = nil
+		}
 	}
 
-	return checks, nil
+	// Headers may carry credentials, so they are encrypted as a whole
+	if headersJSON.Valid && headersJSON.String != "" {
+		headers, err := s.cipher.open(headersJSON.String)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(headers), &check.Headers); err != nil {
+			check.Headers = nil
+		}
+	}
+
+	// Query parameters may carry keys too, like the request body
+	if queryJSON.Valid && queryJSON.String != "" {
+		params, err := s.cipher.open(queryJSON.String)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(params), &check.QueryParams); err != nil {
+			check.QueryParams = nil
+		}
+	}
+
+	check.Status = "pending"
+	return &check, nil
 }
 
 // Check Results
 
 func (s *SQLiteStorage) SaveResult(result *CheckResult) error {
-	res, err := s.db.Exec(`
-		INSERT INTO check_results (check_id, region, status, status_code, response_time_ms, error_message, checked_at, ssl_expires_at, ssl_days_left, ssl_issuer)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, result.CheckID, result.Region, result.Status, result.StatusCode, result.ResponseTimeMs, result.ErrorMessage, time.Now(),
-		result.SSLExpiresAt, result.SSLDaysLeft, result.SSLIssuer)
+	if result.Weight < 1 {
+		result.Weight = 1
+	}
+
+	// Live results are stamped now; callers may pass an explicit time
+	checkedAt := result.CheckedAt
+	if checkedAt.IsZero() {
+		checkedAt = time.Now()
+	}
+
+	id, err := s.db.insert(`
+		INSERT INTO check_results (check_id, region, status, status_code, response_time_ms, error_message, checked_at, ssl_expires_at, ssl_days_left, ssl_issuer, ssl_fingerprint, weight,
+			body_hash, baseline_status_code, baseline_response_time_ms, baseline_body_hash, ipv4_status, ipv6_status, redirect_chain)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, result.CheckID, result.Region, result.Status, result.StatusCode, result.ResponseTimeMs, result.ErrorMessage, checkedAt,
+		result.SSLExpiresAt, result.SSLDaysLeft, result.SSLIssuer, result.SSLFingerprint, result.Weight,
+		result.BodyHash, result.BaselineStatusCode, result.BaselineResponseMs, result.BaselineBodyHash, result.IPv4Status, result.IPv6Status, result.RedirectChain)
 	if err != nil {
 		return fmt.Errorf("inserting result: %w", err)
 	}
 
-	id, err := res.LastInsertId()
+	result.ID = id
+	result.CheckedAt = checkedAt
+	return nil
+}
+
+// ImportResults inserts historical results with their original timestamps in
+// a single transaction. A result matching an existing row's check, region and
+// checked_at is skipped, so importing the same file twice is harmless.
+func (s *SQLiteStorage) ImportResults(results []*CheckResult) (imported int, skipped int, err error) {
+	tx, err := s.db.Begin()
 	if err != nil {
-		return fmt.Errorf("getting last insert id: %w", err)
+		return 0, 0, fmt.Errorf("starting import: %w", err)
 	}
+	defer tx.Rollback()
 
-	result.ID = id
-	result.CheckedAt = time.Now()
+	for _, result := range results {
+		if result.CheckedAt.IsZero() {
+			return 0, 0, fmt.Errorf("result for check %d has no checked_at", result.CheckID)
+		}
+		if result.Status != "up" && result.Status != "down" && result.Status != "degraded" {
+			return 0, 0, fmt.Errorf("result for check %d has invalid status %q", result.CheckID, result.Status)
+		}
+		if result.Weight < 1 {
+			result.Weight = 1
+		}
+
+		var exists int
+		err := tx.QueryRow(`
+			SELECT COUNT(*) FROM check_results WHERE check_id = ? AND COALESCE(region, '') = ? AND checked_at = ?
+		`, result.CheckID, result.Region, result.CheckedAt).Scan(&exists)
+		if err != nil {
+			return 0, 0, fmt.Errorf("checking for duplicate result: %w", err)
+		}
+		if exists > 0 {
+			skipped++
+			continue
+		}
+
+		id, err := tx.insert(`
+			INSERT INTO check_results (check_id, region, status, status_code, response_time_ms, error_message, checked_at, ssl_expires_at, ssl_days_left, ssl_issuer, ssl_fingerprint, weight,
+				body_hash, baseline_status_code, baseline_response_time_ms, baseline_body_hash, ipv4_status, ipv6_status, redirect_chain)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, result.CheckID, result.Region, result.Status, result.StatusCode, result.ResponseTimeMs, result.ErrorMessage, result.CheckedAt,
+			result.SSLExpiresAt, result.SSLDaysLeft, result.SSLIssuer, result.SSLFingerprint, result.Weight,
+			result.BodyHash, result.BaselineStatusCode, result.BaselineResponseMs, result.BaselineBodyHash, result.IPv4Status, result.IPv6Status, result.RedirectChain)
+		if err != nil {
+			return 0, 0, fmt.Errorf("inserting result: %w", err)
+		}
+		result.ID = id
+		imported++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("committing import: %w", err)
+	}
+	return imported, skipped, nil
+}
+
+// ExtendResult folds one more identical evaluation into the stored row
+// result.ID, starting a run or lengthening one to end at result.CheckedAt
+// (now if unset). Response times are averaged over the run.
+func (s *SQLiteStorage) ExtendResult(result *CheckResult) error {
+	checkedAt := result.CheckedAt
+	if checkedAt.IsZero() {
+		checkedAt = time.Now()
+	}
+
+	_, err := s.db.Exec(`
+		UPDATE check_results SET
+			run_started_at = COALESCE(run_started_at, checked_at),
+			response_time_ms = (response_time_ms * weight + ?) / (weight + 1),
+			weight = weight + 1,
+			checked_at = ?
+		WHERE id = ?
+	`, result.ResponseTimeMs, checkedAt, result.ID)
+	if err != nil {
+		return fmt.Errorf("extending result: %w", err)
+	}
 	return nil
 }
 
@@ -401,6 +930,16 @@ func (s *SQLiteStorage) GetResults(checkID int64, limit int, offset int) ([]*Che
 	return s.scanResults(rows)
 }
 
+// CountResults returns how many results GetResults pages through for a check
+func (s *SQLiteStorage) CountResults(checkID int64) (int, error) {
+	var count int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM check_results WHERE check_id = ?`, checkID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("counting results: %w", err)
+	}
+	return count, nil
+}
+
 func (s *SQLiteStorage) GetLatestResult(checkID int64) (*CheckResult, error) {
 	row := s.db.QueryRow(`
 		SELECT id, check_id, COALESCE(region, '') as region, status, status_code, response_time_ms, error_message, checked_at
@@ -428,6 +967,49 @@ func (s *SQLiteStorage) GetLatestResult(checkID int64) (*CheckResult, error) {
 	return &result, nil
 }
 
+// GetLatestResults returns, for every check with results, the most recent
+// result in each region, newest first. One query instead of one per check.
+func (s *SQLiteStorage) GetLatestResults() (map[int64][]*CheckResult, error) {
+	rows, err := s.db.Query(`
+		SELECT id, check_id, region, status, status_code, response_time_ms, error_message, checked_at
+		FROM (
+			SELECT id, check_id, COALESCE(region, '') as region, status, status_code, response_time_ms, error_message, checked_at,
+				ROW_NUMBER() OVER (PARTITION BY check_id, COALESCE(region, '') ORDER BY checked_at DESC, id DESC) as rn
+			FROM check_results
+		) WHERE rn = 1
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("querying latest results: %w", err)
+	}
+	defer rows.Close()
+
+	latest := make(map[int64][]*CheckResult)
+	for rows.Next() {
+		var result CheckResult
+		var errMsg sql.NullString
+		if err := rows.Scan(
+			&result.ID, &result.CheckID, &result.Region, &result.Status, &result.StatusCode,
+			&result.ResponseTimeMs, &errMsg, &result.CheckedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scanning result: %w", err)
+		}
+		if errMsg.Valid {
+			result.ErrorMessage = errMsg.String
+		}
+		latest[result.CheckID] = append(latest[result.CheckID], &result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, results := range latest {
+		sort.SliceStable(results, func(i, j int) bool {
+			return results[i].CheckedAt.After(results[j].CheckedAt)
+		})
+	}
+	return latest, nil
+}
+
 // GetLatestResultsByRegion returns the most recent result for each region of a check
 func (s *SQLiteStorage) GetLatestResultsByRegion(checkID int64) (map[string]*CheckResult, error) {
 	// Get distinct regions for this check
@@ -470,94 +1052,285 @@ func (s *SQLiteStorage) GetLatestResultsByRegion(checkID int64) (map[string]*Che
 		if errMsg.Valid {
 			result.ErrorMessage = errMsg.String
 		}
-
-		if err == nil {
-			results[region] = &result
+
+		if err == nil {
+			results[region] = &result
+		}
+	}
+
+	return results, nil
+}
+
+// CountFailingRegions returns the number of distinct regions with the most recent result being "down"
+func (s *SQLiteStorage) CountFailingRegions(checkID int64) (int, error) {
+	// Get latest result per region and count failures
+	regionResults, err := s.GetLatestResultsByRegion(checkID)
+	if err != nil {
+		return 0, err
+	}
+
+	failingCount := 0
+	for _, result := range regionResults {
+		if result.Status == "down" {
+			failingCount++
+		}
+	}
+	return failingCount, nil
+}
+
+// GetResultsInRange returns results in chronological order. A compressed run
+// is expanded into points at both its start and end so charts stay flat
+// across it instead of jumping from the previous result.
+func (s *SQLiteStorage) GetResultsInRange(checkID int64, start, end time.Time) ([]*CheckResult, error) {
+	rows, err := s.db.Query(`
+		SELECT id, check_id, COALESCE(region, '') as region, status, status_code, response_time_ms, error_message, checked_at,
+			weight, run_started_at
+		FROM check_results WHERE check_id = ? AND checked_at BETWEEN ? AND ? ORDER BY checked_at
+	`, checkID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("querying results: %w", err)
+	}
+	defer rows.Close()
+
+	// Archived days hold everything older than the raw rows
+	results, err := s.archivedResults(checkID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var result CheckResult
+		var errMsg sql.NullString
+		var runStartedAt sql.NullTime
+
+		err := rows.Scan(
+			&result.ID, &result.CheckID, &result.Region, &result.Status, &result.StatusCode,
+			&result.ResponseTimeMs, &errMsg, &result.CheckedAt, &result.Weight, &runStartedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scanning result: %w", err)
+		}
+		result.ErrorMessage = errMsg.String
+
+		if runStartedAt.Valid {
+			result.RunStartedAt = &runStartedAt.Time
+			first := result
+			first.CheckedAt = runStartedAt.Time
+			if first.CheckedAt.Before(start) {
+				first.CheckedAt = start
+			}
+			results = append(results, &first)
+		}
+		results = append(results, &result)
+	}
+
+	return results, rows.Err()
+}
+
+func (s *SQLiteStorage) GetRecentResults(checkID int64, count int) ([]*CheckResult, error) {
+	return s.GetResults(checkID, count, 0)
+}
+
+// GetResultDetails returns the latest results with every stored column,
+// including SSL details and sampling weight, newest first
+func (s *SQLiteStorage) GetResultDetails(checkID int64, limit int) ([]*CheckResult, error) {
+	rows, err := s.db.Query(`
+		SELECT id, check_id, COALESCE(region, ''), status, status_code, response_time_ms, error_message, checked_at,
+			ssl_expires_at, ssl_days_left, ssl_issuer, ssl_fingerprint, weight,
+			body_hash, baseline_status_code, baseline_response_time_ms, baseline_body_hash, run_started_at,
+			ipv4_status, ipv6_status, redirect_chain
+		FROM check_results WHERE check_id = ? ORDER BY checked_at DESC LIMIT ?
+	`, checkID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying result details: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*CheckResult
+	for rows.Next() {
+		var result CheckResult
+		var errMsg, sslIssuer, sslFingerprint sql.NullString
+		var sslExpiresAt sql.NullTime
+		var sslDaysLeft sql.NullInt64
+		var bodyHash, baselineBodyHash sql.NullString
+		var baselineStatusCode, baselineResponseMs sql.NullInt64
+		var runStartedAt sql.NullTime
+		var ipv4Status, ipv6Status sql.NullString
+		var redirectChain sql.NullString
+
+		err := rows.Scan(
+			&result.ID, &result.CheckID, &result.Region, &result.Status, &result.StatusCode,
+			&result.ResponseTimeMs, &errMsg, &result.CheckedAt,
+			&sslExpiresAt, &sslDaysLeft, &sslIssuer, &sslFingerprint, &result.Weight,
+			&bodyHash, &baselineStatusCode, &baselineResponseMs, &baselineBodyHash, &runStartedAt,
+			&ipv4Status, &ipv6Status, &redirectChain,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scanning result: %w", err)
+		}
+
+		result.ErrorMessage = errMsg.String
+		if sslExpiresAt.Valid {
+			result.SSLExpiresAt = &sslExpiresAt.Time
+		}
+		result.SSLDaysLeft = int(sslDaysLeft.Int64)
+		result.SSLIssuer = sslIssuer.String
+		result.SSLFingerprint = sslFingerprint.String
+		result.BodyHash = bodyHash.String
+		result.BaselineStatusCode = int(baselineStatusCode.Int64)
+		result.BaselineResponseMs = int(baselineResponseMs.Int64)
+		result.BaselineBodyHash = baselineBodyHash.String
+		if runStartedAt.Valid {
+			result.RunStartedAt = &runStartedAt.Time
 		}
+		result.IPv4Status = ipv4Status.String
+		result.IPv6Status = ipv6Status.String
+		result.RedirectChain = redirectChain.String
+
+		results = append(results, &result)
 	}
 
-	return results, nil
+	return results, rows.Err()
 }
 
-// CountFailingRegions returns the number of distinct regions with the most recent result being "down"
-func (s *SQLiteStorage) CountFailingRegions(checkID int64) (int, error) {
-	// Get latest result per region and count failures
-	regionResults, err := s.GetLatestResultsByRegion(checkID)
-	if err != nil {
-		return 0, err
-	}
+// GetStats weights each stored result by the evaluations it stands for, so
+// sampled checks report the same uptime as fully stored ones. Archived days
+// count the same as raw results.
+func (s *SQLiteStorage) GetStats(checkID int64) (*CheckStats, error) {
+	stats := &CheckStats{}
 
-	failingCount := 0
-	for _, result := range regionResults {
-		if result.Status == "down" {
-			failingCount++
-		}
+	now := time.Now()
+
+	archived, err := s.archivedResults(checkID, now.Add(-30*24*time.Hour), now)
+	if err != nil {
+		return nil, err
 	}
-	return failingCount, nil
-}
 
-func (s *SQLiteStorage) GetResultsInRange(checkID int64, start, end time.Time) ([]*CheckResult, error) {
-	rows, err := s.db.Query(`
-		SELECT id, check_id, COALESCE(region, '') as region, status, status_code, response_time_ms, error_message, checked_at
-		FROM check_results WHERE check_id = ? AND checked_at BETWEEN ? AND ? ORDER BY checked_at
-	`, checkID, start, end)
+	// SQLite has no percentile functions, so the last 30 days' up response
+	// times are loaded once and each window's percentiles computed here
+	samples, err := s.latencySamples(checkID, now.Add(-30*24*time.Hour), archived)
 	if err != nil {
-		return nil, fmt.Errorf("querying results: %w", err)
+		return nil, err
 	}
-	defer rows.Close()
 
-	return s.scanResults(rows)
-}
+	windows := []struct {
+		name          string
+		since         time.Time
+		uptime        *float64
+		avg           *int
+		p50, p95, p99 *int
+	}{
+		{"24h", now.Add(-24 * time.Hour), &stats.UptimePercent24h, &stats.AvgResponseMs24h, &stats.P50ResponseMs24h, &stats.P95ResponseMs24h, &stats.P99ResponseMs24h},
+		{"7d", now.Add(-7 * 24 * time.Hour), &stats.UptimePercent7d, &stats.AvgResponseMs7d, &stats.P50ResponseMs7d, &stats.P95ResponseMs7d, &stats.P99ResponseMs7d},
+		{"30d", now.Add(-30 * 24 * time.Hour), &stats.UptimePercent30d, &stats.AvgResponseMs30d, &stats.P50ResponseMs30d, &stats.P95ResponseMs30d, &stats.P99ResponseMs30d},
+	}
+	for _, w := range windows {
+		var inWindow []latencySample
+		for _, sample := range samples {
+			if sample.at.After(w.since) {
+				inWindow = append(inWindow, sample)
+			}
+		}
+		*w.p50 = latencyPercentile(inWindow, 50)
+		*w.p95 = latencyPercentile(inWindow, 95)
+		*w.p99 = latencyPercentile(inWindow, 99)
 
-func (s *SQLiteStorage) GetRecentResults(checkID int64, count int) ([]*CheckResult, error) {
-	return s.GetResults(checkID, count, 0)
-}
+		var total, up, upMs int
+		row := s.db.QueryRow(`
+			SELECT 
+				COALESCE(SUM(weight), 0),
+				COALESCE(SUM(CASE WHEN status IN ('up', 'degraded') THEN weight ELSE 0 END), 0),
+				COALESCE(SUM(CASE WHEN status IN ('up', 'degraded') THEN response_time_ms * weight ELSE 0 END), 0)
+			FROM check_results 
+			WHERE check_id = ? AND checked_at > ?
+		`, checkID, w.since)
+		if err := row.Scan(&total, &up, &upMs); err != nil {
+			return nil, fmt.Errorf("querying %s stats: %w", w.name, err)
+		}
 
-func (s *SQLiteStorage) GetStats(checkID int64) (*CheckStats, error) {
-	stats := &CheckStats{}
+		for _, r := range archived {
+			if !r.CheckedAt.After(w.since) {
+				continue
+			}
+			total += r.Weight
+			if r.IsUp() {
+				up += r.Weight
+				upMs += r.ResponseTimeMs * r.Weight
+			}
+		}
 
-	now := time.Now()
+		*w.uptime = 100
+		if total > 0 {
+			*w.uptime = 100.0 * float64(up) / float64(total)
+		}
+		if up > 0 {
+			*w.avg = upMs / up
+		}
+	}
 
-	// 24h stats
-	row := s.db.QueryRow(`
-		SELECT 
-			COALESCE(100.0 * SUM(CASE WHEN status = 'up' THEN 1 ELSE 0 END) / NULLIF(COUNT(*), 0), 100) as uptime,
-			COALESCE(AVG(CASE WHEN status = 'up' THEN response_time_ms END), 0) as avg_response
-		FROM check_results 
-		WHERE check_id = ? AND checked_at > ?
-	`, checkID, now.Add(-24*time.Hour))
+	return stats, nil
+}
+
+// latencySample is an up result's response time, standing for weight results
+type latencySample struct {
+	ms     int
+	weight int
+	at     time.Time
+}
 
-	if err := row.Scan(&stats.UptimePercent24h, &stats.AvgResponseMs24h); err != nil {
-		return nil, fmt.Errorf("querying 24h stats: %w", err)
+// latencySamples returns the up results since a time, live and archived,
+// sorted by response time
+func (s *SQLiteStorage) latencySamples(checkID int64, since time.Time, archived []*CheckResult) ([]latencySample, error) {
+	rows, err := s.db.Query(`
+		SELECT response_time_ms, weight, checked_at FROM check_results
+		WHERE check_id = ? AND status IN ('up', 'degraded') AND checked_at > ?
+	`, checkID, since)
+	if err != nil {
+		return nil, fmt.Errorf("querying response times: %w", err)
 	}
+	defer rows.Close()
 
-	// 7d stats
-	row = s.db.QueryRow(`
-		SELECT 
-			COALESCE(100.0 * SUM(CASE WHEN status = 'up' THEN 1 ELSE 0 END) / NULLIF(COUNT(*), 0), 100) as uptime,
-			COALESCE(AVG(CASE WHEN status = 'up' THEN response_time_ms END), 0) as avg_response
-		FROM check_results 
-		WHERE check_id = ? AND checked_at > ?
-	`, checkID, now.Add(-7*24*time.Hour))
+	var samples []latencySample
+	for rows.Next() {
+		var sample latencySample
+		if err := rows.Scan(&sample.ms, &sample.weight, &sample.at); err != nil {
+			return nil, fmt.Errorf("scanning response time: %w", err)
+		}
+		samples = append(samples, sample)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-	if err := row.Scan(&stats.UptimePercent7d, &stats.AvgResponseMs7d); err != nil {
-		return nil, fmt.Errorf("querying 7d stats: %w", err)
+	for _, r := range archived {
+		if r.IsUp() && r.CheckedAt.After(since) {
+			samples = append(samples, latencySample{ms: r.ResponseTimeMs, weight: r.Weight, at: r.CheckedAt})
+		}
 	}
 
-	// 30d stats
-	row = s.db.QueryRow(`
-		SELECT 
-			COALESCE(100.0 * SUM(CASE WHEN status = 'up' THEN 1 ELSE 0 END) / NULLIF(COUNT(*), 0), 100) as uptime,
-			COALESCE(AVG(CASE WHEN status = 'up' THEN response_time_ms END), 0) as avg_response
-		FROM check_results 
-		WHERE check_id = ? AND checked_at > ?
-	`, checkID, now.Add(-30*24*time.Hour))
+	sort.Slice(samples, func(i, j int) bool { return samples[i].ms < samples[j].ms })
+	return samples, nil
+}
 
-	if err := row.Scan(&stats.UptimePercent30d, &stats.AvgResponseMs30d); err != nil {
-		return nil, fmt.Errorf("querying 30d stats: %w", err)
+// latencyPercentile returns the nearest-rank percentile of samples sorted by
+// response time, counting each by its weight; 0 when there are none
+func latencyPercentile(samples []latencySample, p float64) int {
+	total := 0
+	for _, sample := range samples {
+		total += sample.weight
+	}
+	if total == 0 {
+		return 0
 	}
 
-	return stats, nil
+	rank := int(math.Ceil(p / 100 * float64(total)))
+	seen := 0
+	for _, sample := range samples {
+		seen += sample.weight
+		if seen >= rank {
+			return sample.ms
+		}
+	}
+	return samples[len(samples)-1].ms
 }
 
 func (s *SQLiteStorage) scanResults(rows *sql.Rows) ([]*CheckResult, error) {
@@ -585,6 +1358,49 @@ func (s *SQLiteStorage) scanResults(rows *sql.Rows) ([]*CheckResult, error) {
 	return results, nil
 }
 
+// Golden Snapshots
+
+func (s *SQLiteStorage) SaveGoldenSnapshot(snapshot *GoldenSnapshot) error {
+	if snapshot.RecordedAt.IsZero() {
+		snapshot.RecordedAt = time.Now()
+	}
+
+	_, err := s.db.Exec(`
+		INSERT INTO golden_snapshots (check_id, body, recorded_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (check_id) DO UPDATE SET body = excluded.body, recorded_at = excluded.recorded_at
+	`, snapshot.CheckID, snapshot.Body, snapshot.RecordedAt)
+	if err != nil {
+		return fmt.Errorf("saving golden snapshot: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStorage) GetGoldenSnapshot(checkID int64) (*GoldenSnapshot, error) {
+	row := s.db.QueryRow(`
+		SELECT check_id, body, recorded_at FROM golden_snapshots WHERE check_id = ?
+	`, checkID)
+
+	var snapshot GoldenSnapshot
+	err := row.Scan(&snapshot.CheckID, &snapshot.Body, &snapshot.RecordedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning golden snapshot: %w", err)
+	}
+
+	return &snapshot, nil
+}
+
+func (s *SQLiteStorage) DeleteGoldenSnapshot(checkID int64) error {
+	_, err := s.db.Exec(`DELETE FROM golden_snapshots WHERE check_id = ?`, checkID)
+	if err != nil {
+		return fmt.Errorf("deleting golden snapshot: %w", err)
+	}
+	return nil
+}
+
 // Incidents
 
 func (s *SQLiteStorage) CreateIncident(incident *Incident) error {
@@ -592,19 +1408,14 @@ func (s *SQLiteStorage) CreateIncident(incident *Incident) error {
 	if status == "" {
 		status = IncidentStatusInvestigating
 	}
-	res, err := s.db.Exec(`
-		INSERT INTO incidents (check_id, started_at, cause, status, title)
-		VALUES (?, ?, ?, ?, ?)
-	`, incident.CheckID, incident.StartedAt, incident.Cause, status, incident.Title)
+	id, err := s.db.insert(`
+		INSERT INTO incidents (check_id, started_at, cause, category, status, title)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, incident.CheckID, incident.StartedAt, incident.Cause, incident.Category, status, incident.Title)
 	if err != nil {
 		return fmt.Errorf("inserting incident: %w", err)
 	}
 
-	id, err := res.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("getting last insert id: %w", err)
-	}
-
 	incident.ID = id
 	incident.Status = status
 	return nil
@@ -612,7 +1423,7 @@ func (s *SQLiteStorage) CreateIncident(incident *Incident) error {
 
 func (s *SQLiteStorage) GetIncident(id int64) (*Incident, error) {
 	row := s.db.QueryRow(`
-		SELECT i.id, i.check_id, i.started_at, i.ended_at, i.duration_seconds, i.cause, i.status, i.title, c.name
+		SELECT i.id, i.check_id, i.started_at, i.ended_at, i.duration_seconds, i.cause, COALESCE(i.category, ''), i.status, i.title, COALESCE(i.external_id, ''), c.name
 		FROM incidents i
 		JOIN checks c ON c.id = i.check_id
 		WHERE i.id = ?
@@ -638,7 +1449,7 @@ func (s *SQLiteStorage) GetIncidentWithNotes(id int64) (*Incident, error) {
 
 func (s *SQLiteStorage) GetActiveIncident(checkID int64) (*Incident, error) {
 	row := s.db.QueryRow(`
-		SELECT i.id, i.check_id, i.started_at, i.ended_at, i.duration_seconds, i.cause, i.status, i.title, c.name
+		SELECT i.id, i.check_id, i.started_at, i.ended_at, i.duration_seconds, i.cause, COALESCE(i.category, ''), i.status, i.title, COALESCE(i.external_id, ''), c.name
 		FROM incidents i
 		JOIN checks c ON c.id = i.check_id
 		WHERE i.check_id = ? AND i.ended_at IS NULL
@@ -685,9 +1496,17 @@ func (s *SQLiteStorage) UpdateIncidentTitle(id int64, title string) error {
 	return nil
 }
 
+func (s *SQLiteStorage) SetIncidentExternalID(id int64, externalID string) error {
+	_, err := s.db.Exec(`UPDATE incidents SET external_id = ? WHERE id = ?`, externalID, id)
+	if err != nil {
+		return fmt.Errorf("updating incident external id: %w", err)
+	}
+	return nil
+}
+
 func (s *SQLiteStorage) ListIncidents(limit int, offset int) ([]*Incident, error) {
 	rows, err := s.db.Query(`
-		SELECT i.id, i.check_id, i.started_at, i.ended_at, i.duration_seconds, i.cause, i.status, i.title, c.name
+		SELECT i.id, i.check_id, i.started_at, i.ended_at, i.duration_seconds, i.cause, COALESCE(i.category, ''), i.status, i.title, COALESCE(i.external_id, ''), c.name
 		FROM incidents i
 		JOIN checks c ON c.id = i.check_id
 		ORDER BY i.started_at DESC LIMIT ? OFFSET ?
@@ -700,9 +1519,22 @@ func (s *SQLiteStorage) ListIncidents(limit int, offset int) ([]*Incident, error
 	return s.scanIncidents(rows)
 }
 
+// CountIncidents returns how many incidents ListIncidents pages through
+func (s *SQLiteStorage) CountIncidents() (int, error) {
+	var count int
+	err := s.db.QueryRow(`
+		SELECT COUNT(*) FROM incidents i
+		JOIN checks c ON c.id = i.check_id
+	`).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("counting incidents: %w", err)
+	}
+	return count, nil
+}
+
 func (s *SQLiteStorage) ListIncidentsForCheck(checkID int64, limit int) ([]*Incident, error) {
 	rows, err := s.db.Query(`
-		SELECT i.id, i.check_id, i.started_at, i.ended_at, i.duration_seconds, i.cause, i.status, i.title, c.name
+		SELECT i.id, i.check_id, i.started_at, i.ended_at, i.duration_seconds, i.cause, COALESCE(i.category, ''), i.status, i.title, COALESCE(i.external_id, ''), c.name
 		FROM incidents i
 		JOIN checks c ON c.id = i.check_id
 		WHERE i.check_id = ?
@@ -718,7 +1550,7 @@ func (s *SQLiteStorage) ListIncidentsForCheck(checkID int64, limit int) ([]*Inci
 
 func (s *SQLiteStorage) ListActiveIncidents() ([]*Incident, error) {
 	rows, err := s.db.Query(`
-		SELECT i.id, i.check_id, i.started_at, i.ended_at, i.duration_seconds, i.cause, i.status, i.title, c.name
+		SELECT i.id, i.check_id, i.started_at, i.ended_at, i.duration_seconds, i.cause, COALESCE(i.category, ''), i.status, i.title, COALESCE(i.external_id, ''), c.name
 		FROM incidents i
 		JOIN checks c ON c.id = i.check_id
 		WHERE i.ended_at IS NULL
@@ -732,6 +1564,49 @@ func (s *SQLiteStorage) ListActiveIncidents() ([]*Incident, error) {
 	return s.scanIncidents(rows)
 }
 
+func (s *SQLiteStorage) GetIncidentStats(checkID int64, start, end time.Time) (*IncidentStats, error) {
+	stats := &IncidentStats{CheckID: checkID, From: start, To: end}
+
+	// AVG and MAX skip open incidents, whose duration is still NULL
+	var mttr float64
+	row := s.db.QueryRow(`
+		SELECT COUNT(*), COUNT(ended_at), COALESCE(AVG(duration_seconds), 0), COALESCE(MAX(duration_seconds), 0)
+		FROM incidents
+		WHERE check_id = ? AND started_at >= ? AND started_at <= ?
+	`, checkID, start, end)
+
+	if err := row.Scan(&stats.Count, &stats.Resolved, &mttr, &stats.LongestSeconds); err != nil {
+		return nil, fmt.Errorf("querying incident stats: %w", err)
+	}
+	stats.MTTRSeconds = int(mttr)
+
+	// Incidents from before classification count as other
+	rows, err := s.db.Query(`
+		SELECT COALESCE(NULLIF(category, ''), 'other'), COUNT(*)
+		FROM incidents
+		WHERE check_id = ? AND started_at >= ? AND started_at <= ?
+		GROUP BY 1
+	`, checkID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("querying incident categories: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var category string
+		var count int
+		if err := rows.Scan(&category, &count); err != nil {
+			return nil, fmt.Errorf("scanning incident category: %w", err)
+		}
+		if stats.ByCategory == nil {
+			stats.ByCategory = make(map[string]int)
+		}
+		stats.ByCategory[category] = count
+	}
+
+	return stats, rows.Err()
+}
+
 func (s *SQLiteStorage) scanIncident(row *sql.Row) (*Incident, error) {
 	var incident Incident
 	var endedAt sql.NullTime
@@ -740,7 +1615,7 @@ func (s *SQLiteStorage) scanIncident(row *sql.Row) (*Incident, error) {
 
 	err := row.Scan(
 		&incident.ID, &incident.CheckID, &incident.StartedAt, &endedAt,
-		&duration, &cause, &status, &title, &incident.CheckName,
+		&duration, &cause, &incident.Category, &status, &title, &incident.ExternalID, &incident.CheckName,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -781,7 +1656,7 @@ func (s *SQLiteStorage) scanIncidents(rows *sql.Rows) ([]*Incident, error) {
 
 		err := rows.Scan(
 			&incident.ID, &incident.CheckID, &incident.StartedAt, &endedAt,
-			&duration, &cause, &status, &title, &incident.CheckName,
+			&duration, &cause, &incident.Category, &status, &title, &incident.ExternalID, &incident.CheckName,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scanning incident: %w", err)
@@ -814,7 +1689,7 @@ func (s *SQLiteStorage) scanIncidents(rows *sql.Rows) ([]*Incident, error) {
 // Incident Notes
 
 func (s *SQLiteStorage) AddIncidentNote(note *IncidentNote) error {
-	res, err := s.db.Exec(`
+	id, err := s.db.insert(`
 		INSERT INTO incident_notes (incident_id, content, author, created_at)
 		VALUES (?, ?, ?, ?)
 	`, note.IncidentID, note.Content, note.Author, time.Now())
@@ -822,11 +1697,6 @@ func (s *SQLiteStorage) AddIncidentNote(note *IncidentNote) error {
 		return fmt.Errorf("inserting incident note: %w", err)
 	}
 
-	id, err := res.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("getting last insert id: %w", err)
-	}
-
 	note.ID = id
 	note.CreatedAt = time.Now()
 	return nil
@@ -869,10 +1739,121 @@ func (s *SQLiteStorage) DeleteIncidentNote(id int64) error {
 	return nil
 }
 
+// Maintenance Windows
+
+func (s *SQLiteStorage) CreateMaintenanceWindow(window *MaintenanceWindow) error {
+	if window.Recurrence == "" {
+		window.Recurrence = MaintenanceOnce
+	}
+
+	// Global windows store a NULL check so the foreign key holds
+	var checkID sql.NullInt64
+	if window.CheckID != 0 {
+		checkID = sql.NullInt64{Int64: window.CheckID, Valid: true}
+	}
+
+	now := time.Now()
+	id, err := s.db.insert(`
+		INSERT INTO maintenance_windows (name, check_id, starts_at, duration_seconds, recurrence, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, window.Name, checkID, window.StartsAt, window.DurationSecs, window.Recurrence, now)
+	if err != nil {
+		return fmt.Errorf("inserting maintenance window: %w", err)
+	}
+
+	window.ID = id
+	window.CreatedAt = now
+	return nil
+}
+
+func (s *SQLiteStorage) ListMaintenanceWindows() ([]*MaintenanceWindow, error) {
+	rows, err := s.db.Query(`
+		SELECT id, name, check_id, starts_at, duration_seconds, recurrence, created_at
+		FROM maintenance_windows ORDER BY starts_at
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("querying maintenance windows: %w", err)
+	}
+	defer rows.Close()
+
+	var windows []*MaintenanceWindow
+	for rows.Next() {
+		var window MaintenanceWindow
+		var checkID sql.NullInt64
+		err := rows.Scan(&window.ID, &window.Name, &checkID, &window.StartsAt,
+			&window.DurationSecs, &window.Recurrence, &window.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("scanning maintenance window: %w", err)
+		}
+		window.CheckID = checkID.Int64
+		windows = append(windows, &window)
+	}
+
+	return windows, rows.Err()
+}
+
+// ListActiveMaintenanceWindows returns the windows active at the given time.
+// Recurrence is evaluated here rather than in SQL.
+func (s *SQLiteStorage) ListActiveMaintenanceWindows(at time.Time) ([]*MaintenanceWindow, error) {
+	windows, err := s.ListMaintenanceWindows()
+	if err != nil {
+		return nil, err
+	}
+
+	var active []*MaintenanceWindow
+	for _, window := range windows {
+		if window.IsActiveAt(at) {
+			active = append(active, window)
+		}
+	}
+	return active, nil
+}
+
+func (s *SQLiteStorage) DeleteMaintenanceWindow(id int64) error {
+	_, err := s.db.Exec(`DELETE FROM maintenance_windows WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("deleting maintenance window: %w", err)
+	}
+	return nil
+}
+
+// Settings
+
+func (s *SQLiteStorage) GetSetting(key string) (string, error) {
+	var value string
+	err := s.db.QueryRow(`SELECT value FROM settings WHERE key = ?`, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("getting setting %s: %w", key, err)
+	}
+	return value, nil
+}
+
+func (s *SQLiteStorage) SetSetting(key, value string) error {
+	_, err := s.db.Exec(`
+		INSERT INTO settings (key, value) VALUES (?, ?)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value
+	`, key, value)
+	if err != nil {
+		return fmt.Errorf("saving setting %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *SQLiteStorage) DeleteSetting(key string) error {
+	_, err := s.db.Exec(`DELETE FROM settings WHERE key = ?`, key)
+	if err != nil {
+		return fmt.Errorf("deleting setting %s: %w", key, err)
+	}
+	return nil
+}
+
 // Alert Log
 
 func (s *SQLiteStorage) LogAlert(log *AlertLog) error {
-	res, err := s.db.Exec(`
+	id, err := s.db.insert(`
 		INSERT INTO alert_log (incident_id, channel, sent_at, success, error_message)
 		VALUES (?, ?, ?, ?, ?)
 	`, log.IncidentID, log.Channel, time.Now(), log.Success, log.ErrorMessage)
@@ -880,11 +1861,6 @@ func (s *SQLiteStorage) LogAlert(log *AlertLog) error {
 		return fmt.Errorf("inserting alert log: %w", err)
 	}
 
-	id, err := res.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("getting last insert id: %w", err)
-	}
-
 	log.ID = id
 	log.SentAt = time.Now()
 	return nil
@@ -918,9 +1894,12 @@ func (s *SQLiteStorage) GetLastAlertForIncident(incidentID int64, channel string
 
 func (s *SQLiteStorage) CreateHourlyAggregate(agg *HourlyAggregate) error {
 	_, err := s.db.Exec(`
-		INSERT OR REPLACE INTO hourly_aggregates 
+		INSERT INTO hourly_aggregates 
 		(check_id, hour, total_checks, success_count, failure_count, avg_response_ms, min_response_ms, max_response_ms, uptime_percent)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (check_id, hour) DO UPDATE SET total_checks = excluded.total_checks, success_count = excluded.success_count,
+			failure_count = excluded.failure_count, avg_response_ms = excluded.avg_response_ms, min_response_ms = excluded.min_response_ms,
+			max_response_ms = excluded.max_response_ms, uptime_percent = excluded.uptime_percent
 	`, agg.CheckID, agg.Hour, agg.TotalChecks, agg.SuccessCount, agg.FailureCount,
 		agg.AvgResponseMs, agg.MinResponseMs, agg.MaxResponseMs, agg.UptimePercent)
 	if err != nil {
@@ -956,6 +1935,119 @@ func (s *SQLiteStorage) GetHourlyAggregates(checkID int64, start, end time.Time)
 	return aggregates, nil
 }
 
+// GetUptimeSeries buckets uptime into hours or days. Hours that have been
+// rolled up come from hourly_aggregates; the rest are computed from raw results.
+func (s *SQLiteStorage) GetUptimeSeries(checkID int64, start, end time.Time, resolution time.Duration) ([]*UptimePoint, error) {
+	if resolution != time.Hour && resolution != 24*time.Hour {
+		return nil, fmt.Errorf("unsupported resolution %s", resolution)
+	}
+
+	type bucket struct {
+		total, success, upMs int
+	}
+	hours := make(map[time.Time]*bucket)
+
+	aggregates, err := s.GetHourlyAggregates(checkID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	for _, agg := range aggregates {
+		hours[agg.Hour.UTC()] = &bucket{
+			total:   agg.TotalChecks,
+			success: agg.SuccessCount,
+			upMs:    agg.AvgResponseMs * agg.SuccessCount,
+		}
+	}
+
+	// Raw results are bucketed here rather than in SQL since checked_at is
+	// stored in Go's time format, which strftime cannot parse
+	rows, err := s.db.Query(`
+		SELECT checked_at, status, response_time_ms, weight
+		FROM check_results
+		WHERE check_id = ? AND checked_at BETWEEN ? AND ?
+	`, checkID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("querying results: %w", err)
+	}
+	defer rows.Close()
+
+	raw := make(map[time.Time]*bucket)
+	add := func(checkedAt time.Time, status string, responseMs, weight int) {
+		hour := checkedAt.UTC().Truncate(time.Hour)
+		// Aggregated hours win; raw rows may linger until cleanup runs
+		if _, ok := hours[hour]; ok {
+			return
+		}
+		b, ok := raw[hour]
+		if !ok {
+			b = &bucket{}
+			raw[hour] = b
+		}
+		b.total += weight
+		if status == "up" || status == "degraded" {
+			b.success += weight
+			b.upMs += responseMs * weight
+		}
+	}
+	for rows.Next() {
+		var checkedAt time.Time
+		var status string
+		var responseMs, weight int
+		if err := rows.Scan(&checkedAt, &status, &responseMs, &weight); err != nil {
+			return nil, fmt.Errorf("scanning result: %w", err)
+		}
+		add(checkedAt, status, responseMs, weight)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading results: %w", err)
+	}
+
+	// Archived days fill in hours whose aggregates have expired
+	archived, err := s.archivedResults(checkID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range archived {
+		add(r.CheckedAt, r.Status, r.ResponseTimeMs, r.Weight)
+	}
+	for hour, b := range raw {
+		hours[hour] = b
+	}
+
+	// Roll hours up into the requested resolution
+	buckets := make(map[time.Time]*bucket)
+	for hour, b := range hours {
+		key := hour.Truncate(resolution)
+		agg, ok := buckets[key]
+		if !ok {
+			agg = &bucket{}
+			buckets[key] = agg
+		}
+		agg.total += b.total
+		agg.success += b.success
+		agg.upMs += b.upMs
+	}
+
+	points := make([]*UptimePoint, 0, len(buckets))
+	for key, b := range buckets {
+		if b.total == 0 {
+			continue
+		}
+		point := &UptimePoint{
+			Hour:          key,
+			TotalChecks:   b.total,
+			UptimePercent: float64(b.success) / float64(b.total) * 100,
+		}
+		if b.success > 0 {
+			point.AvgResponseMs = b.upMs / b.success
+		}
+		points = append(points, point)
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Hour.Before(points[j].Hour) })
+
+	return points, nil
+}
+
 // Maintenance
 
 func (s *SQLiteStorage) AggregateResults(olderThan time.Time) error {
@@ -966,56 +2058,57 @@ func (s *SQLiteStorage) AggregateResults(olderThan time.Time) error {
 	}
 
 	for _, check := range checks {
-		// Find all hours with results older than the cutoff
+		// Bucket results older than the cutoff by hour. This is done here
+		// rather than with strftime, which cannot parse Go's time format.
 		rows, err := s.db.Query(`
-			SELECT 
-				strftime('%Y-%m-%d %H:00:00', checked_at) as hour,
-				COUNT(*) as total,
-				SUM(CASE WHEN status = 'up' THEN 1 ELSE 0 END) as success,
-				SUM(CASE WHEN status = 'down' THEN 1 ELSE 0 END) as failure,
-				AVG(CASE WHEN status = 'up' THEN response_time_ms END) as avg_ms,
-				MIN(CASE WHEN status = 'up' THEN response_time_ms END) as min_ms,
-				MAX(CASE WHEN status = 'up' THEN response_time_ms END) as max_ms
+			SELECT checked_at, status, response_time_ms, weight
 			FROM check_results
 			WHERE check_id = ? AND checked_at < ?
-			GROUP BY strftime('%Y-%m-%d %H:00:00', checked_at)
 		`, check.ID, olderThan)
 		if err != nil {
 			continue
 		}
 
+		hours := make(map[time.Time]*HourlyAggregate)
+		upMs := make(map[time.Time]int)
 		for rows.Next() {
-			var hourStr string
-			var total, success, failure int
-			var avgMs, minMs, maxMs *int
-
-			if err := rows.Scan(&hourStr, &total, &success, &failure, &avgMs, &minMs, &maxMs); err != nil {
+			var checkedAt time.Time
+			var status string
+			var responseMs, weight int
+			if err := rows.Scan(&checkedAt, &status, &responseMs, &weight); err != nil {
 				continue
 			}
 
-			hour, _ := time.Parse("2006-01-02 15:04:05", hourStr)
-
-			agg := &HourlyAggregate{
-				CheckID:       check.ID,
-				Hour:          hour,
-				TotalChecks:   total,
-				SuccessCount:  success,
-				FailureCount:  failure,
-				UptimePercent: float64(success) / float64(total) * 100,
+			hour := checkedAt.UTC().Truncate(time.Hour)
+			agg, ok := hours[hour]
+			if !ok {
+				agg = &HourlyAggregate{CheckID: check.ID, Hour: hour}
+				hours[hour] = agg
 			}
-			if avgMs != nil {
-				agg.AvgResponseMs = *avgMs
-			}
-			if minMs != nil {
-				agg.MinResponseMs = *minMs
-			}
-			if maxMs != nil {
-				agg.MaxResponseMs = *maxMs
+			agg.TotalChecks += weight
+			switch status {
+			case "up", "degraded":
+				if agg.SuccessCount == 0 || responseMs < agg.MinResponseMs {
+					agg.MinResponseMs = responseMs
+				}
+				if responseMs > agg.MaxResponseMs {
+					agg.MaxResponseMs = responseMs
+				}
+				agg.SuccessCount += weight
+				upMs[hour] += responseMs * weight
+			case "down":
+				agg.FailureCount += weight
 			}
+		}
+		rows.Close()
 
+		for hour, agg := range hours {
+			agg.UptimePercent = float64(agg.SuccessCount) / float64(agg.TotalChecks) * 100
+			if agg.SuccessCount > 0 {
+				agg.AvgResponseMs = upMs[hour] / agg.SuccessCount
+			}
 			s.CreateHourlyAggregate(agg)
 		}
-		rows.Close()
 	}
 
 	return nil
@@ -1040,19 +2133,14 @@ func (s *SQLiteStorage) CleanupOldAggregates(olderThan time.Time) error {
 // Probes
 
 func (s *SQLiteStorage) CreateProbe(probe *Probe) error {
-	result, err := s.db.Exec(`
+	id, err := s.db.insert(`
 		INSERT INTO probes (name, region, city, country, latitude, longitude, api_key, status, created_at)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, probe.Name, probe.Region, probe.City, probe.Country, probe.Latitude, probe.Longitude, probe.APIKey, probe.Status, time.Now())
+	`, probe.Name, probe.Region, probe.City, probe.Country, probe.Latitude, probe.Longitude, s.cipher.seal(probe.APIKey), probe.Status, time.Now())
 	if err != nil {
 		return fmt.Errorf("inserting probe: %w", err)
 	}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("getting last insert id: %w", err)
-	}
-
 	probe.ID = id
 	probe.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
 	return nil
@@ -1071,7 +2159,7 @@ func (s *SQLiteStorage) GetProbeByAPIKey(apiKey string) (*Probe, error) {
 	row := s.db.QueryRow(`
 		SELECT id, name, region, city, country, latitude, longitude, api_key, status, last_heartbeat, created_at
 		FROM probes WHERE api_key = ?
-	`, apiKey)
+	`, s.cipher.seal(apiKey))
 
 	return s.scanProbe(row)
 }
@@ -1172,6 +2260,9 @@ func (s *SQLiteStorage) scanProbe(row *sql.Row) (*Probe, error) {
 	if err != nil {
 		return nil, fmt.Errorf("scanning probe: %w", err)
 	}
+	if probe.APIKey, err = s.cipher.open(probe.APIKey); err != nil {
+		return nil, err
+	}
 
 	return &probe, nil
 }
@@ -1190,6 +2281,9 @@ func (s *SQLiteStorage) scanProbes(rows *sql.Rows) ([]*Probe, error) {
 		if err != nil {
 			return nil, fmt.Errorf("scanning probe: %w", err)
 		}
+		if probe.APIKey, err = s.cipher.open(probe.APIKey); err != nil {
+			return nil, err
+		}
 
 		probes = append(probes, &probe)
 	}
@@ -1200,7 +2294,7 @@ func (s *SQLiteStorage) scanProbes(rows *sql.Rows) ([]*Probe, error) {
 // Probe Results
 
 func (s *SQLiteStorage) SaveProbeResult(result *ProbeResult) error {
-	res, err := s.db.Exec(`
+	id, err := s.db.insert(`
 		INSERT INTO probe_results (check_id, probe_id, status, response_time_ms, status_code, error, checked_at)
 		VALUES (?, ?, ?, ?, ?, ?, ?)
 	`, result.CheckID, result.ProbeID, result.Status, result.ResponseTimeMs, result.StatusCode, result.Error, result.CheckedAt)
@@ -1208,11 +2302,6 @@ func (s *SQLiteStorage) SaveProbeResult(result *ProbeResult) error {
 		return fmt.Errorf("inserting probe result: %w", err)
 	}
 
-	id, err := res.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("getting last insert id: %w", err)
-	}
-
 	result.ID = id
 	return nil
 }
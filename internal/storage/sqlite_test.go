@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 )
@@ -151,6 +152,12 @@ func TestUpdateCheck(t *testing.T) {
 	check.URL = "https://updated.com"
 	check.Enabled = false
 	check.Tags = []string{"updated", "tags"}
+	check.Description = "Public API"
+	check.RunbookURL = "https://wiki.example.com/runbooks/api"
+	check.Streaming = true
+	check.AlertOnFirstCheck = true
+	check.BodyContains = "healthy"
+	check.BodyNotContains = "Service Unavailable"
 
 	if err := s.UpdateCheck(check); err != nil {
 		t.Fatalf("failed to update check: %v", err)
@@ -173,6 +180,212 @@ func TestUpdateCheck(t *testing.T) {
 	if len(got.Tags) != 2 {
 		t.Errorf("expected 2 tags, got %d", len(got.Tags))
 	}
+	if got.Description != "Public API" {
+		t.Errorf("expected description Public API, got %s", got.Description)
+	}
+	if got.RunbookURL != "https://wiki.example.com/runbooks/api" {
+		t.Errorf("expected runbook url, got %s", got.RunbookURL)
+	}
+	if !got.Streaming {
+		t.Error("expected check to be streaming")
+	}
+	if !got.AlertOnFirstCheck {
+		t.Error("expected check to alert on first check")
+	}
+	if got.BodyContains != "healthy" || got.BodyNotContains != "Service Unavailable" {
+		t.Errorf("expected body keywords, got %q %q", got.BodyContains, got.BodyNotContains)
+	}
+}
+
+func TestGetChecksModifiedSince(t *testing.T) {
+	s := setupTestDB(t)
+
+	first := &Check{Name: "First", URL: "https://first.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	second := &Check{Name: "Second", URL: "https://second.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	s.CreateCheck(first)
+	s.CreateCheck(second)
+
+	time.Sleep(10 * time.Millisecond)
+	since := time.Now()
+	time.Sleep(10 * time.Millisecond)
+
+	// Modified in reverse order of creation
+	s.SetCheckPaused(second.ID, true)
+	time.Sleep(10 * time.Millisecond)
+	first.Description = "changed"
+	s.UpdateCheck(first)
+
+	checks, err := s.GetChecksModifiedSince(since)
+	if err != nil {
+		t.Fatalf("failed to get modified checks: %v", err)
+	}
+	if len(checks) != 2 {
+		t.Fatalf("expected 2 modified checks, got %d", len(checks))
+	}
+	if checks[0].ID != second.ID || checks[1].ID != first.ID {
+		t.Errorf("expected oldest change first, got %s then %s", checks[0].Name, checks[1].Name)
+	}
+
+	checks, _ = s.GetChecksModifiedSince(time.Now())
+	if len(checks) != 0 {
+		t.Errorf("expected no checks modified after now, got %d", len(checks))
+	}
+}
+
+func TestListChecksByStatus(t *testing.T) {
+	s := setupTestDB(t)
+
+	var ids []int64
+	for _, name := range []string{"Up", "Down", "Pending", "Degraded"} {
+		check := &Check{Name: name, URL: "https://" + name + ".com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+		s.CreateCheck(check)
+		ids = append(ids, check.ID)
+	}
+
+	now := time.Now()
+	s.SaveResult(&CheckResult{CheckID: ids[0], Status: "down", CheckedAt: now.Add(-2 * time.Minute)})
+	s.SaveResult(&CheckResult{CheckID: ids[0], Status: "up", ResponseTimeMs: 42, CheckedAt: now.Add(-time.Minute)})
+	s.SaveResult(&CheckResult{CheckID: ids[1], Status: "down", CheckedAt: now})
+	s.SaveResult(&CheckResult{CheckID: ids[3], Region: "us", Status: "up", CheckedAt: now})
+	s.SaveResult(&CheckResult{CheckID: ids[3], Region: "eu", Status: "down", CheckedAt: now})
+
+	latest, err := s.GetLatestResults()
+	if err != nil {
+		t.Fatalf("failed to get latest results: %v", err)
+	}
+	if len(latest[ids[0]]) != 1 || latest[ids[0]][0].Status != "up" {
+		t.Errorf("expected only the newest result for check 1, got %v", latest[ids[0]])
+	}
+	if len(latest[ids[3]]) != 2 {
+		t.Errorf("expected one result per region, got %d", len(latest[ids[3]]))
+	}
+
+	for i, status := range []string{"up", "down", "pending", "degraded"} {
+		checks, err := s.ListChecksByStatus(status)
+		if err != nil {
+			t.Fatalf("failed to list %s checks: %v", status, err)
+		}
+		if len(checks) != 1 || checks[0].ID != ids[i] {
+			t.Errorf("expected only check %d to be %s, got %v", ids[i], status, checks)
+			continue
+		}
+		if checks[0].Status != status {
+			t.Errorf("expected computed status %s, got %s", status, checks[0].Status)
+		}
+	}
+
+	up, _ := s.ListChecksByStatus("up")
+	if len(up) == 1 && (up[0].LastResponseMs != 42 || up[0].LastCheckedAt == nil) {
+		t.Errorf("expected enriched check, got %+v", up[0])
+	}
+}
+
+func TestFilterChecks(t *testing.T) {
+	s := setupTestDB(t)
+
+	api := &Check{Name: "Billing API", URL: "https://billing.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true, Tags: []string{"api"}}
+	web := &Check{Name: "Billing Web", URL: "https://billing.com/app", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true, Tags: []string{"web"}}
+	old := &Check{Name: "Legacy API", URL: "https://legacy.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: false, Tags: []string{"api"}}
+	for _, check := range []*Check{api, web, old} {
+		s.CreateCheck(check)
+	}
+	s.SaveResult(&CheckResult{CheckID: api.ID, Status: "down", CheckedAt: time.Now()})
+	s.SaveResult(&CheckResult{CheckID: web.ID, Status: "up", CheckedAt: time.Now()})
+
+	all, _ := s.ListChecks()
+	tests := []struct {
+		filter CheckFilter
+		want   []string
+	}{
+		{CheckFilter{}, []string{"Billing API", "Billing Web", "Legacy API"}},
+		{CheckFilter{Query: "billing"}, []string{"Billing API", "Billing Web"}},
+		{CheckFilter{Tag: "api"}, []string{"Billing API", "Legacy API"}},
+		{CheckFilter{Tag: "api", EnabledOnly: true}, []string{"Billing API"}},
+		{CheckFilter{Query: "BILLING", Status: "up"}, []string{"Billing Web"}},
+		{CheckFilter{Status: "pending"}, []string{"Legacy API"}},
+		{CheckFilter{Query: "nothing"}, nil},
+	}
+
+	for _, tt := range tests {
+		checks, err := s.FilterChecks(tt.filter)
+		if err != nil {
+			t.Fatalf("%+v: unexpected error: %v", tt.filter, err)
+		}
+		var names []string
+		for _, check := range checks {
+			names = append(names, check.Name)
+		}
+		if !slices.Equal(names, tt.want) {
+			t.Errorf("%+v: expected %v, got %v", tt.filter, tt.want, names)
+		}
+	}
+
+	if zero, _ := s.FilterChecks(CheckFilter{}); len(zero) != len(all) {
+		t.Errorf("expected the zero filter to list every check, got %d of %d", len(zero), len(all))
+	}
+}
+
+func TestSetCheckPaused(t *testing.T) {
+	s := setupTestDB(t)
+
+	check := &Check{Name: "Pausable", URL: "https://pause.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	if err := s.CreateCheck(check); err != nil {
+		t.Fatalf("failed to create check: %v", err)
+	}
+
+	if err := s.SetCheckPaused(check.ID, true); err != nil {
+		t.Fatalf("failed to pause check: %v", err)
+	}
+
+	got, _ := s.GetCheck(check.ID)
+	if !got.Paused {
+		t.Error("expected check to be paused")
+	}
+	if !got.Enabled {
+		t.Error("expected paused check to stay enabled")
+	}
+
+	if err := s.SetCheckPaused(999, true); err == nil {
+		t.Error("expected error pausing missing check")
+	}
+}
+
+func TestPauseCheckUntil(t *testing.T) {
+	s := setupTestDB(t)
+
+	check := &Check{Name: "Timed", URL: "https://timed.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	if err := s.CreateCheck(check); err != nil {
+		t.Fatalf("failed to create check: %v", err)
+	}
+
+	until := time.Now().Add(30 * time.Minute).Truncate(time.Second)
+	if err := s.PauseCheckUntil(check.ID, until); err != nil {
+		t.Fatalf("failed to pause check: %v", err)
+	}
+
+	got, _ := s.GetCheck(check.ID)
+	if !got.Paused || got.PausedUntil == nil || !got.PausedUntil.Equal(until) {
+		t.Fatalf("expected check paused until %v, got paused=%v until=%v", until, got.Paused, got.PausedUntil)
+	}
+	if !got.IsPaused(time.Now()) {
+		t.Error("expected check paused before the deadline")
+	}
+	if got.IsPaused(until.Add(time.Second)) {
+		t.Error("expected the pause to lapse after the deadline")
+	}
+
+	// Resuming clears the deadline too
+	if err := s.SetCheckPaused(check.ID, false); err != nil {
+		t.Fatalf("failed to resume check: %v", err)
+	}
+	got, _ = s.GetCheck(check.ID)
+	if got.Paused || got.PausedUntil != nil {
+		t.Errorf("expected timed pause cleared, got paused=%v until=%v", got.Paused, got.PausedUntil)
+	}
+
+	if err := s.PauseCheckUntil(999, until); err == nil {
+		t.Error("expected error pausing missing check")
+	}
 }
 
 func TestDeleteCheck(t *testing.T) {
@@ -204,6 +417,43 @@ func TestDeleteCheck(t *testing.T) {
 	}
 }
 
+func TestResetCheckHistory(t *testing.T) {
+	s := setupTestDB(t)
+
+	check := &Check{Name: "Repurposed", URL: "https://reset.me", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	other := &Check{Name: "Untouched", URL: "https://keep.me", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	s.CreateCheck(check)
+	s.CreateCheck(other)
+
+	for _, id := range []int64{check.ID, other.ID} {
+		s.SaveResult(&CheckResult{CheckID: id, Status: "down", StatusCode: 500})
+		incident := &Incident{CheckID: id, StartedAt: time.Now()}
+		s.CreateIncident(incident)
+		s.AddIncidentNote(&IncidentNote{IncidentID: incident.ID, Content: "looking"})
+	}
+
+	if err := s.ResetCheckHistory(check.ID); err != nil {
+		t.Fatalf("failed to reset history: %v", err)
+	}
+
+	if got, _ := s.GetCheck(check.ID); got == nil {
+		t.Fatal("expected the check itself to be kept")
+	}
+	if results, _ := s.GetResults(check.ID, 10, 0); len(results) != 0 {
+		t.Errorf("expected results deleted, got %d", len(results))
+	}
+	if incidents, _ := s.ListIncidentsForCheck(check.ID, 10); len(incidents) != 0 {
+		t.Errorf("expected incidents deleted, got %d", len(incidents))
+	}
+
+	if results, _ := s.GetResults(other.ID, 10, 0); len(results) != 1 {
+		t.Errorf("expected other check's results kept, got %d", len(results))
+	}
+	if incidents, _ := s.ListIncidentsForCheck(other.ID, 10); len(incidents) != 1 {
+		t.Errorf("expected other check's incidents kept, got %d", len(incidents))
+	}
+}
+
 func TestSaveAndGetResults(t *testing.T) {
 	s := setupTestDB(t)
 
@@ -259,6 +509,10 @@ func TestSaveAndGetResults(t *testing.T) {
 	if len(page2) != 2 {
 		t.Errorf("expected 2 results with offset, got %d", len(page2))
 	}
+
+	if total, err := s.CountResults(check.ID); err != nil || total != 3 {
+		t.Errorf("expected 3 results counted, got %d (%v)", total, err)
+	}
 }
 
 func TestGetLatestResultNotFound(t *testing.T) {
@@ -448,6 +702,41 @@ func TestGetRecentResults(t *testing.T) {
 	}
 }
 
+func TestGetResultDetails(t *testing.T) {
+	s := setupTestDB(t)
+
+	check := &Check{Name: "Details", URL: "https://details.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	s.CreateCheck(check)
+
+	expires := time.Now().Add(10 * 24 * time.Hour)
+	s.SaveResult(&CheckResult{CheckID: check.ID, Status: "up", StatusCode: 200, SSLExpiresAt: &expires, SSLDaysLeft: 10, SSLIssuer: "Example CA", SSLFingerprint: "abc123", Weight: 3, RedirectChain: "301 https://www.details.com/"})
+	s.SaveResult(&CheckResult{CheckID: check.ID, Status: "down", ErrorMessage: "timeout"})
+
+	results, err := s.GetResultDetails(check.ID, 10)
+	if err != nil {
+		t.Fatalf("failed to get result details: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].ErrorMessage != "timeout" || results[0].SSLExpiresAt != nil {
+		t.Errorf("expected newest result without SSL details first, got %+v", results[0])
+	}
+	got := results[1]
+	if got.SSLExpiresAt == nil || !got.SSLExpiresAt.Equal(expires) || got.SSLDaysLeft != 10 || got.SSLIssuer != "Example CA" {
+		t.Errorf("expected SSL details, got %+v", got)
+	}
+	if got.SSLFingerprint != "abc123" {
+		t.Errorf("expected observed fingerprint, got %q", got.SSLFingerprint)
+	}
+	if got.Weight != 3 {
+		t.Errorf("expected weight 3, got %d", got.Weight)
+	}
+	if got.RedirectChain != "301 https://www.details.com/" {
+		t.Errorf("expected the redirect chain, got %q", got.RedirectChain)
+	}
+}
+
 func TestGetStats(t *testing.T) {
 	s := setupTestDB(t)
 
@@ -496,6 +785,141 @@ func TestGetStats(t *testing.T) {
 	}
 }
 
+func TestGetStatsWeighted(t *testing.T) {
+	s := setupTestDB(t)
+
+	check := &Check{Name: "Sampled", URL: "https://sampled.com", IntervalSecs: 5, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	if err := s.CreateCheck(check); err != nil {
+		t.Fatalf("failed to create check: %v", err)
+	}
+
+	// One heartbeat standing in for three up evaluations, then one failure
+	s.SaveResult(&CheckResult{CheckID: check.ID, Status: "up", StatusCode: 200, ResponseTimeMs: 100, Weight: 3})
+	s.SaveResult(&CheckResult{CheckID: check.ID, Status: "down", StatusCode: 500})
+
+	stats, err := s.GetStats(check.ID)
+	if err != nil {
+		t.Fatalf("failed to get stats: %v", err)
+	}
+
+	if stats.UptimePercent24h != 75 {
+		t.Errorf("expected weighted uptime 75%%, got %.1f", stats.UptimePercent24h)
+	}
+	if stats.AvgResponseMs24h != 100 {
+		t.Errorf("expected avg response 100ms, got %d", stats.AvgResponseMs24h)
+	}
+}
+
+func TestGetStatsCountsDegradedAsUp(t *testing.T) {
+	s := setupTestDB(t)
+
+	check := &Check{Name: "Slow", URL: "https://slow.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true, DegradedThresholdMs: 500}
+	if err := s.CreateCheck(check); err != nil {
+		t.Fatalf("failed to create check: %v", err)
+	}
+
+	s.SaveResult(&CheckResult{CheckID: check.ID, Status: "up", StatusCode: 200, ResponseTimeMs: 100})
+	s.SaveResult(&CheckResult{CheckID: check.ID, Status: "degraded", StatusCode: 200, ResponseTimeMs: 900})
+
+	stats, err := s.GetStats(check.ID)
+	if err != nil {
+		t.Fatalf("failed to get stats: %v", err)
+	}
+	if stats.UptimePercent24h != 100 {
+		t.Errorf("expected degraded results to count as up, got %.1f%%", stats.UptimePercent24h)
+	}
+	if stats.AvgResponseMs24h != 500 {
+		t.Errorf("expected avg response 500ms, got %d", stats.AvgResponseMs24h)
+	}
+
+	got, err := s.GetCheck(check.ID)
+	if err != nil {
+		t.Fatalf("failed to get check: %v", err)
+	}
+	if got.DegradedThresholdMs != 500 {
+		t.Errorf("expected degraded threshold 500, got %d", got.DegradedThresholdMs)
+	}
+}
+
+func TestGetStatsPercentiles(t *testing.T) {
+	s := setupTestDB(t)
+
+	check := &Check{Name: "Tail", URL: "https://tail.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	if err := s.CreateCheck(check); err != nil {
+		t.Fatalf("failed to create check: %v", err)
+	}
+
+	// 100 up results of 1..100ms in the last day, one slow one last week, and
+	// a failure whose time is left out
+	now := time.Now()
+	for i := 1; i <= 100; i++ {
+		s.SaveResult(&CheckResult{CheckID: check.ID, Status: "up", StatusCode: 200, ResponseTimeMs: i, CheckedAt: now.Add(-time.Duration(i) * time.Minute)})
+	}
+	s.SaveResult(&CheckResult{CheckID: check.ID, Status: "up", StatusCode: 200, ResponseTimeMs: 5000, CheckedAt: now.Add(-3 * 24 * time.Hour), Weight: 10})
+	s.SaveResult(&CheckResult{CheckID: check.ID, Status: "down", ResponseTimeMs: 10000, CheckedAt: now.Add(-time.Minute)})
+
+	stats, err := s.GetStats(check.ID)
+	if err != nil {
+		t.Fatalf("failed to get stats: %v", err)
+	}
+
+	if stats.P50ResponseMs24h != 50 || stats.P95ResponseMs24h != 95 || stats.P99ResponseMs24h != 99 {
+		t.Errorf("expected 24h p50/p95/p99 of 50/95/99, got %d/%d/%d", stats.P50ResponseMs24h, stats.P95ResponseMs24h, stats.P99ResponseMs24h)
+	}
+	// The slow result counts ten times over 7 days: 110 samples
+	if stats.P50ResponseMs7d != 55 || stats.P95ResponseMs7d != 5000 || stats.P99ResponseMs7d != 5000 {
+		t.Errorf("expected 7d p50/p95/p99 of 55/5000/5000, got %d/%d/%d", stats.P50ResponseMs7d, stats.P95ResponseMs7d, stats.P99ResponseMs7d)
+	}
+	if stats.P99ResponseMs30d != 5000 {
+		t.Errorf("expected 30d p99 of 5000, got %d", stats.P99ResponseMs30d)
+	}
+}
+
+func TestExtendResult(t *testing.T) {
+	s := setupTestDB(t)
+
+	check := &Check{Name: "Compressed", URL: "https://compressed.com", IntervalSecs: 5, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	if err := s.CreateCheck(check); err != nil {
+		t.Fatalf("failed to create check: %v", err)
+	}
+
+	start := time.Now().Add(-10 * time.Minute)
+	first := &CheckResult{CheckID: check.ID, Status: "up", StatusCode: 200, ResponseTimeMs: 100, CheckedAt: start}
+	if err := s.SaveResult(first); err != nil {
+		t.Fatalf("failed to save result: %v", err)
+	}
+	end := start.Add(5 * time.Minute)
+	if err := s.ExtendResult(&CheckResult{ID: first.ID, CheckID: check.ID, ResponseTimeMs: 200, CheckedAt: end}); err != nil {
+		t.Fatalf("failed to extend result: %v", err)
+	}
+
+	results, _ := s.GetResultDetails(check.ID, 10)
+	if len(results) != 1 {
+		t.Fatalf("expected 1 row, got %d", len(results))
+	}
+	run := results[0]
+	if run.Weight != 2 || run.ResponseTimeMs != 150 {
+		t.Errorf("expected weight 2 averaging 150ms, got weight %d %dms", run.Weight, run.ResponseTimeMs)
+	}
+	if run.RunStartedAt == nil || !run.RunStartedAt.Equal(start) || !run.CheckedAt.Equal(end) {
+		t.Errorf("expected run %v to %v, got %v to %v", start, end, run.RunStartedAt, run.CheckedAt)
+	}
+
+	// Charts get a point at each end of the run
+	points, err := s.GetResultsInRange(check.ID, start.Add(-time.Minute), time.Now())
+	if err != nil {
+		t.Fatalf("failed to get results in range: %v", err)
+	}
+	if len(points) != 2 || !points[0].CheckedAt.Equal(start) || !points[1].CheckedAt.Equal(end) {
+		t.Errorf("expected run expanded to its start and end, got %d points", len(points))
+	}
+
+	stats, _ := s.GetStats(check.ID)
+	if stats.UptimePercent24h != 100 || stats.AvgResponseMs24h != 150 {
+		t.Errorf("expected stats over the run, got %.1f%% %dms", stats.UptimePercent24h, stats.AvgResponseMs24h)
+	}
+}
+
 func TestGetStatsNoResults(t *testing.T) {
 	s := setupTestDB(t)
 
@@ -520,6 +944,9 @@ func TestGetStatsNoResults(t *testing.T) {
 	if stats.UptimePercent24h != 100 {
 		t.Errorf("expected 100%% uptime with no data, got %.2f%%", stats.UptimePercent24h)
 	}
+	if stats.P50ResponseMs24h != 0 || stats.P99ResponseMs30d != 0 {
+		t.Errorf("expected zero percentiles with no data, got p50 %d p99 %d", stats.P50ResponseMs24h, stats.P99ResponseMs30d)
+	}
 }
 
 func TestIncidents(t *testing.T) {
@@ -656,6 +1083,72 @@ func TestListIncidents(t *testing.T) {
 	if len(incidents) != 3 {
 		t.Errorf("expected 3 incidents with offset 2, got %d", len(incidents))
 	}
+
+	if total, err := s.CountIncidents(); err != nil || total != 5 {
+		t.Errorf("expected 5 incidents counted, got %d (%v)", total, err)
+	}
+}
+
+func TestGetIncidentStats(t *testing.T) {
+	s := setupTestDB(t)
+
+	check := &Check{Name: "Stats", URL: "https://stats.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	if err := s.CreateCheck(check); err != nil {
+		t.Fatalf("failed to create check: %v", err)
+	}
+
+	now := time.Now()
+	// Two resolved incidents (2m and 10m), one still open, one outside the range
+	for _, tc := range []struct {
+		startedAgo time.Duration
+		lasted     time.Duration
+		category   string
+	}{
+		{3 * time.Hour, 2 * time.Minute, "timeout"},
+		{2 * time.Hour, 10 * time.Minute, "timeout"},
+		{time.Hour, 0, ""},
+		{72 * time.Hour, time.Hour, "dns"},
+	} {
+		incident := &Incident{CheckID: check.ID, StartedAt: now.Add(-tc.startedAgo), Category: tc.category}
+		if err := s.CreateIncident(incident); err != nil {
+			t.Fatalf("failed to create incident: %v", err)
+		}
+		if tc.lasted > 0 {
+			if err := s.CloseIncident(incident.ID, incident.StartedAt.Add(tc.lasted)); err != nil {
+				t.Fatalf("failed to close incident: %v", err)
+			}
+		}
+	}
+
+	stats, err := s.GetIncidentStats(check.ID, now.Add(-24*time.Hour), now)
+	if err != nil {
+		t.Fatalf("failed to get incident stats: %v", err)
+	}
+
+	if stats.Count != 3 {
+		t.Errorf("expected 3 incidents, got %d", stats.Count)
+	}
+	if stats.Resolved != 2 {
+		t.Errorf("expected 2 resolved incidents, got %d", stats.Resolved)
+	}
+	if stats.MTTRSeconds != 360 {
+		t.Errorf("expected MTTR 360s, got %d", stats.MTTRSeconds)
+	}
+	if stats.LongestSeconds != 600 {
+		t.Errorf("expected longest 600s, got %d", stats.LongestSeconds)
+	}
+	if stats.ByCategory["timeout"] != 2 || stats.ByCategory["other"] != 1 || len(stats.ByCategory) != 2 {
+		t.Errorf("expected 2 timeout and 1 unclassified incident, got %v", stats.ByCategory)
+	}
+
+	// Empty range
+	stats, err = s.GetIncidentStats(check.ID, now.Add(-time.Minute), now)
+	if err != nil {
+		t.Fatalf("failed to get incident stats: %v", err)
+	}
+	if stats.Count != 0 || stats.MTTRSeconds != 0 {
+		t.Errorf("expected empty stats, got %+v", stats)
+	}
 }
 
 func TestListIncidentsForCheck(t *testing.T) {
@@ -1009,6 +1502,155 @@ func TestAggregateResults(t *testing.T) {
 	_ = aggregates
 }
 
+func TestImportResults(t *testing.T) {
+	s := setupTestDB(t)
+
+	check := &Check{Name: "Backfill", URL: "https://backfill.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	s.CreateCheck(check)
+
+	hour := time.Now().UTC().Truncate(time.Hour).Add(-48 * time.Hour)
+	history := func() []*CheckResult {
+		return []*CheckResult{
+			{CheckID: check.ID, Status: "up", StatusCode: 200, ResponseTimeMs: 100, CheckedAt: hour.Add(5 * time.Minute)},
+			{CheckID: check.ID, Status: "up", StatusCode: 200, ResponseTimeMs: 300, CheckedAt: hour.Add(20 * time.Minute)},
+			{CheckID: check.ID, Status: "down", StatusCode: 503, CheckedAt: hour.Add(35 * time.Minute)},
+			{CheckID: check.ID, Status: "up", StatusCode: 200, ResponseTimeMs: 200, CheckedAt: hour.Add(50 * time.Minute)},
+		}
+	}
+
+	imported, skipped, err := s.ImportResults(history())
+	if err != nil {
+		t.Fatalf("failed to import results: %v", err)
+	}
+	if imported != 4 || skipped != 0 {
+		t.Errorf("expected 4 imported and 0 skipped, got %d and %d", imported, skipped)
+	}
+
+	results, _ := s.GetResultsInRange(check.ID, hour, hour.Add(time.Hour))
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results in the original hour, got %d", len(results))
+	}
+
+	// Importing the same history again is a no-op
+	imported, skipped, err = s.ImportResults(history())
+	if err != nil {
+		t.Fatalf("failed to re-import results: %v", err)
+	}
+	if imported != 0 || skipped != 4 {
+		t.Errorf("expected 0 imported and 4 skipped, got %d and %d", imported, skipped)
+	}
+
+	if err := s.AggregateResults(time.Now().Truncate(time.Hour)); err != nil {
+		t.Fatalf("failed to aggregate results: %v", err)
+	}
+	aggregates, _ := s.GetHourlyAggregates(check.ID, hour.Add(-time.Hour), hour.Add(time.Hour))
+	if len(aggregates) != 1 {
+		t.Fatalf("expected 1 hourly aggregate, got %d", len(aggregates))
+	}
+	agg := aggregates[0]
+	if agg.TotalChecks != 4 || agg.SuccessCount != 3 || agg.UptimePercent != 75 {
+		t.Errorf("expected 3 of 4 up, got %+v", agg)
+	}
+	if agg.AvgResponseMs != 200 || agg.MinResponseMs != 100 || agg.MaxResponseMs != 300 {
+		t.Errorf("expected 100/200/300ms min/avg/max, got %d/%d/%d", agg.MinResponseMs, agg.AvgResponseMs, agg.MaxResponseMs)
+	}
+}
+
+func TestImportResultsInvalid(t *testing.T) {
+	s := setupTestDB(t)
+
+	check := &Check{Name: "Bad Backfill", URL: "https://badbackfill.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	s.CreateCheck(check)
+
+	_, _, err := s.ImportResults([]*CheckResult{
+		{CheckID: check.ID, Status: "up", CheckedAt: time.Now().Add(-time.Hour)},
+		{CheckID: check.ID, Status: "up"},
+	})
+	if err == nil {
+		t.Fatal("expected error for a result without checked_at")
+	}
+
+	// The whole import is rolled back
+	results, _ := s.GetResults(check.ID, 10, 0)
+	if len(results) != 0 {
+		t.Errorf("expected no results after a failed import, got %d", len(results))
+	}
+}
+
+func TestSaveResultExplicitTime(t *testing.T) {
+	s := setupTestDB(t)
+
+	check := &Check{Name: "Explicit", URL: "https://explicit.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	s.CreateCheck(check)
+
+	at := time.Now().Add(-72 * time.Hour)
+	result := &CheckResult{CheckID: check.ID, Status: "up", CheckedAt: at}
+	if err := s.SaveResult(result); err != nil {
+		t.Fatalf("failed to save result: %v", err)
+	}
+
+	latest, _ := s.GetLatestResult(check.ID)
+	if latest == nil || !latest.CheckedAt.Equal(at) {
+		t.Errorf("expected checked_at %v to be kept, got %+v", at, latest)
+	}
+}
+
+func TestGetUptimeSeries(t *testing.T) {
+	s := setupTestDB(t)
+
+	check := &Check{Name: "Series", URL: "https://series.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	s.CreateCheck(check)
+
+	// An already rolled-up hour with no raw results left
+	earlier := time.Now().UTC().Truncate(time.Hour).Add(-3 * time.Hour)
+	s.CreateHourlyAggregate(&HourlyAggregate{CheckID: check.ID, Hour: earlier, TotalChecks: 10, SuccessCount: 9, FailureCount: 1, AvgResponseMs: 200})
+
+	// Raw results in the current hour
+	for _, status := range []string{"up", "up", "up", "down"} {
+		s.SaveResult(&CheckResult{CheckID: check.ID, Status: status, ResponseTimeMs: 100})
+	}
+
+	start, end := time.Now().Add(-24*time.Hour), time.Now().Add(time.Hour)
+	points, err := s.GetUptimeSeries(check.ID, start, end, time.Hour)
+	if err != nil {
+		t.Fatalf("failed to get uptime series: %v", err)
+	}
+	if len(points) != 2 {
+		t.Fatalf("expected 2 hourly points, got %d", len(points))
+	}
+	if !points[0].Hour.Equal(earlier) || points[0].UptimePercent != 90 || points[0].AvgResponseMs != 200 {
+		t.Errorf("expected aggregated hour first, got %+v", points[0])
+	}
+	if points[1].TotalChecks != 4 || points[1].UptimePercent != 75 || points[1].AvgResponseMs != 100 {
+		t.Errorf("expected raw hour at 75%%, got %+v", points[1])
+	}
+
+	// Once the current hour is aggregated its raw results must not be counted twice
+	current := time.Now().UTC().Truncate(time.Hour)
+	s.CreateHourlyAggregate(&HourlyAggregate{CheckID: check.ID, Hour: current, TotalChecks: 5, SuccessCount: 5, AvgResponseMs: 100})
+	points, _ = s.GetUptimeSeries(check.ID, start, end, time.Hour)
+	if len(points) != 2 || points[1].TotalChecks != 5 {
+		t.Errorf("expected aggregated hour to replace raw results, got %+v", points)
+	}
+
+	// Daily buckets still add up to every check
+	points, err = s.GetUptimeSeries(check.ID, start, end, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("failed to get daily series: %v", err)
+	}
+	total := 0
+	for _, p := range points {
+		total += p.TotalChecks
+	}
+	if total != 15 {
+		t.Errorf("expected 15 checks across daily buckets, got %d", total)
+	}
+
+	if _, err := s.GetUptimeSeries(check.ID, start, end, time.Minute); err == nil {
+		t.Error("expected error for unsupported resolution")
+	}
+}
+
 func TestCleanupOldResults(t *testing.T) {
 	s := setupTestDB(t)
 
@@ -1111,6 +1753,47 @@ func TestCheckWithEmptyTags(t *testing.T) {
 	}
 }
 
+func TestGoldenSnapshot(t *testing.T) {
+	s := setupTestDB(t)
+
+	check := &Check{Name: "Config", URL: "https://config.example.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	s.CreateCheck(check)
+
+	got, err := s.GetGoldenSnapshot(check.ID)
+	if err != nil {
+		t.Fatalf("failed to get golden snapshot: %v", err)
+	}
+	if got != nil {
+		t.Error("expected no golden snapshot before recording")
+	}
+
+	if err := s.SaveGoldenSnapshot(&GoldenSnapshot{CheckID: check.ID, Body: "first"}); err != nil {
+		t.Fatalf("failed to save golden snapshot: %v", err)
+	}
+	if err := s.SaveGoldenSnapshot(&GoldenSnapshot{CheckID: check.ID, Body: "second"}); err != nil {
+		t.Fatalf("failed to replace golden snapshot: %v", err)
+	}
+
+	got, err = s.GetGoldenSnapshot(check.ID)
+	if err != nil {
+		t.Fatalf("failed to get golden snapshot: %v", err)
+	}
+	if got == nil || got.Body != "second" {
+		t.Fatalf("expected latest golden body 'second', got %+v", got)
+	}
+	if got.RecordedAt.IsZero() {
+		t.Error("expected recorded_at to be set")
+	}
+
+	if err := s.DeleteGoldenSnapshot(check.ID); err != nil {
+		t.Fatalf("failed to delete golden snapshot: %v", err)
+	}
+	got, _ = s.GetGoldenSnapshot(check.ID)
+	if got != nil {
+		t.Error("expected golden snapshot to be deleted")
+	}
+}
+
 // Probe Tests
 
 func TestCreateAndGetProbe(t *testing.T) {
@@ -1574,6 +2257,45 @@ func TestGetLatestProbeResultsByRegionNoResults(t *testing.T) {
 	}
 }
 
+func TestBackup(t *testing.T) {
+	s := setupTestDB(t)
+
+	check := &Check{Name: "Backed Up", URL: "https://backup.example.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	s.CreateCheck(check)
+	s.SaveResult(&CheckResult{CheckID: check.ID, Status: "up", StatusCode: 200, ResponseTimeMs: 80})
+
+	// Writes keep going while the backup runs
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			s.SaveResult(&CheckResult{CheckID: check.ID, Status: "up", StatusCode: 200})
+		}
+	}()
+
+	path := filepath.Join(t.TempDir(), "backup.db")
+	if err := s.Backup(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	<-done
+
+	backup, err := NewSQLiteStorage(path)
+	if err != nil {
+		t.Fatalf("failed to open backup: %v", err)
+	}
+	defer backup.Close()
+	if got, _ := backup.GetCheck(check.ID); got == nil || got.Name != "Backed Up" {
+		t.Errorf("expected the check in the backup, got %v", got)
+	}
+	if n, _ := backup.CountResults(check.ID); n < 1 {
+		t.Error("expected results in the backup")
+	}
+
+	if err := s.Backup(path); err == nil {
+		t.Error("expected an error backing up over an existing file")
+	}
+}
+
 // Helper functions for creating sql.Null* types in tests
 
 func newNullString(s string) sql.NullString {
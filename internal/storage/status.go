@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// statusToken is one entry of a status spec: a code like 204 or a class
+// like 2xx
+var statusToken = regexp.MustCompile(`^([1-5][0-9][0-9]|[1-5]xx)$`)
+
+// ValidateStatusSpec checks a list of acceptable statuses such as "200,204",
+// "2xx", or "2xx,304"
+func ValidateStatusSpec(spec string) error {
+	for _, token := range strings.Split(spec, ",") {
+		if !statusToken.MatchString(strings.ToLower(strings.TrimSpace(token))) {
+			return fmt.Errorf("invalid expected status %q (use codes like 204 or classes like 2xx, separated by commas)", spec)
+		}
+	}
+	return nil
+}
+
+// StatusMatches reports whether code is one of the statuses in spec
+func StatusMatches(spec string, code int) bool {
+	for _, token := range strings.Split(spec, ",") {
+		token = strings.ToLower(strings.TrimSpace(token))
+		if strings.HasSuffix(token, "xx") {
+			if len(token) == 3 && code/100 == int(token[0]-'0') {
+				return true
+			}
+			continue
+		}
+		if n, err := strconv.Atoi(token); err == nil && n == code {
+			return true
+		}
+	}
+	return false
+}
+
+// SplitExpectedStatus separates an expected status as the API and config
+// accept it: a plain code (including AnyStatus) or a spec like "2xx"
+func SplitExpectedStatus(value string) (code int, spec string) {
+	value = strings.TrimSpace(value)
+	if n, err := strconv.Atoi(value); err == nil {
+		return n, ""
+	}
+	return 0, value
+}
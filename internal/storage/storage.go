@@ -13,19 +13,35 @@ type Storage interface {
 	ListChecks() ([]*Check, error)
 	ListEnabledChecks() ([]*Check, error)
 	ListChecksByTag(tag string) ([]*Check, error)
+	GetChecksModifiedSince(t time.Time) ([]*Check, error)
+	ListChecksByStatus(status string) ([]*Check, error)
+	FilterChecks(filter CheckFilter) ([]*Check, error)
 	UpdateCheck(check *Check) error
+	SetCheckPaused(id int64, paused bool) error
+	PauseCheckUntil(id int64, until time.Time) error
 	DeleteCheck(id int64) error
+	ResetCheckHistory(checkID int64) error
 
 	// Check Results
 	SaveResult(result *CheckResult) error
+	ExtendResult(result *CheckResult) error
+	ImportResults(results []*CheckResult) (imported int, skipped int, err error)
 	GetResults(checkID int64, limit int, offset int) ([]*CheckResult, error)
+	CountResults(checkID int64) (int, error)
 	GetLatestResult(checkID int64) (*CheckResult, error)
 	GetLatestResultsByRegion(checkID int64) (map[string]*CheckResult, error)
+	GetLatestResults() (map[int64][]*CheckResult, error)
 	CountFailingRegions(checkID int64) (int, error)
 	GetResultsInRange(checkID int64, start, end time.Time) ([]*CheckResult, error)
 	GetRecentResults(checkID int64, count int) ([]*CheckResult, error)
+	GetResultDetails(checkID int64, limit int) ([]*CheckResult, error)
 	GetStats(checkID int64) (*CheckStats, error)
 
+	// Golden Snapshots
+	SaveGoldenSnapshot(snapshot *GoldenSnapshot) error
+	GetGoldenSnapshot(checkID int64) (*GoldenSnapshot, error)
+	DeleteGoldenSnapshot(checkID int64) error
+
 	// Incidents
 	CreateIncident(incident *Incident) error
 	GetIncident(id int64) (*Incident, error)
@@ -34,15 +50,29 @@ type Storage interface {
 	CloseIncident(id int64, endedAt time.Time) error
 	UpdateIncidentStatus(id int64, status IncidentStatus) error
 	UpdateIncidentTitle(id int64, title string) error
+	SetIncidentExternalID(id int64, externalID string) error
 	ListIncidents(limit int, offset int) ([]*Incident, error)
+	CountIncidents() (int, error)
 	ListIncidentsForCheck(checkID int64, limit int) ([]*Incident, error)
 	ListActiveIncidents() ([]*Incident, error)
+	GetIncidentStats(checkID int64, start, end time.Time) (*IncidentStats, error)
 
 	// Incident Notes
 	AddIncidentNote(note *IncidentNote) error
 	GetIncidentNotes(incidentID int64) ([]*IncidentNote, error)
 	DeleteIncidentNote(id int64) error
 
+	// Maintenance Windows
+	CreateMaintenanceWindow(window *MaintenanceWindow) error
+	ListMaintenanceWindows() ([]*MaintenanceWindow, error)
+	ListActiveMaintenanceWindows(at time.Time) ([]*MaintenanceWindow, error)
+	DeleteMaintenanceWindow(id int64) error
+
+	// Settings, such as the maintenance banner; GetSetting returns "" when unset
+	GetSetting(key string) (string, error)
+	SetSetting(key, value string) error
+	DeleteSetting(key string) error
+
 	// Alert Log
 	LogAlert(log *AlertLog) error
 	GetLastAlertForIncident(incidentID int64, channel string) (*AlertLog, error)
@@ -50,11 +80,15 @@ type Storage interface {
 	// Aggregates
 	CreateHourlyAggregate(agg *HourlyAggregate) error
 	GetHourlyAggregates(checkID int64, start, end time.Time) ([]*HourlyAggregate, error)
+	GetUptimeSeries(checkID int64, start, end time.Time, resolution time.Duration) ([]*UptimePoint, error)
 
 	// Maintenance
 	CleanupOldResults(olderThan time.Time) error
 	AggregateResults(olderThan time.Time) error
 	CleanupOldAggregates(olderThan time.Time) error
+	ArchiveResults(olderThan time.Time) error
+	CleanupOldArchives(olderThan time.Time) error
+	Backup(path string) error
 	Close() error
 
 	// Probes
@@ -1,42 +1,132 @@
 package web
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 
+	"github.com/katieblackabee/sentinel/internal/alerter"
+	"github.com/katieblackabee/sentinel/internal/checker"
 	"github.com/katieblackabee/sentinel/internal/storage"
 )
 
 type APIResponse struct {
 	Data  interface{} `json:"data,omitempty"`
 	Error string      `json:"error,omitempty"`
+	Meta  *PageMeta   `json:"meta,omitempty"` // Set on paged lists
+}
+
+// PageMeta tells a client where a page sits in the full list, so it can
+// offer "page 2 of 9"
+type PageMeta struct {
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
 }
 
 func (s *Server) HandleHealth(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
 }
 
+// HandleListChecks lists checks, narrowed by ?q= (name substring), ?tag=,
+// ?status= and ?enabled=true; without any it lists them all
 func (s *Server) HandleListChecks(c echo.Context) error {
-	checks, err := s.storage.ListChecks()
+	filter, err := checkFilterFromQuery(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+	}
+
+	since := c.QueryParam("since")
+	if since == "" && filter.Status != "" {
+		checks, err := s.storage.FilterChecks(filter)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+		}
+		return c.JSON(http.StatusOK, APIResponse{Data: checks})
+	}
+
+	var checks []*storage.Check
+	if since != "" {
+		// Incremental sync: only checks changed after since, oldest change first
+		t, perr := time.Parse(time.RFC3339, since)
+		if perr != nil {
+			return c.JSON(http.StatusBadRequest, APIResponse{Error: "since must be an RFC3339 timestamp"})
+		}
+		checks, err = s.storage.GetChecksModifiedSince(t)
+	} else {
+		checks, err = s.storage.FilterChecks(filter)
+	}
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
 	}
 
-	// Enrich with latest status
+	if err := s.enrichChecks(checks); err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+	filtered := checks[:0]
 	for _, check := range checks {
-		result, _ := s.storage.GetLatestResult(check.ID)
-		if result != nil {
-			check.Status = result.Status
-			check.LastResponseMs = result.ResponseTimeMs
-			check.LastCheckedAt = &result.CheckedAt
-		} else {
-			check.Status = "pending"
+		if filter.Matches(check) {
+			filtered = append(filtered, check)
+		}
+	}
+
+	return c.JSON(http.StatusOK, APIResponse{Data: filtered})
+}
+
+// checkFilterFromQuery reads a check listing's filter from the query string
+func checkFilterFromQuery(c echo.Context) (storage.CheckFilter, error) {
+	filter := storage.CheckFilter{
+		Query:  strings.TrimSpace(c.QueryParam("q")),
+		Tag:    c.QueryParam("tag"),
+		Status: c.QueryParam("status"),
+	}
+	if filter.Status != "" && !isCheckStatus(filter.Status) {
+		return filter, errors.New("status must be one of " + strings.Join(storage.CheckStatuses, ", "))
+	}
+	if enabled := c.QueryParam("enabled"); enabled != "" {
+		only, err := strconv.ParseBool(enabled)
+		if err != nil {
+			return filter, errors.New("enabled must be true or false")
 		}
+		filter.EnabledOnly = only
+	}
+	return filter, nil
+}
+
+// enrichChecks fills in each check's computed status, staleness and
+// maintenance fields from its latest results
+func (s *Server) enrichChecks(checks []*storage.Check) error {
+	latest, err := s.storage.GetLatestResults()
+	if err != nil {
+		return err
 	}
+	maintenance, err := s.storage.ListActiveMaintenanceWindows(time.Now())
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, check := range checks {
+		check.ApplyLatest(latest[check.ID])
+		storage.SettleStatus(s.storage, check)
+		check.Stale = check.IsStale(now, s.staleIntervals())
+		check.InMaintenance = storage.InMaintenance(maintenance, check.ID)
+	}
+	return nil
+}
 
-	return c.JSON(http.StatusOK, APIResponse{Data: checks})
+func isCheckStatus(status string) bool {
+	for _, s := range storage.CheckStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
 }
 
 func (s *Server) HandleCreateCheck(c echo.Context) error {
@@ -45,11 +135,8 @@ func (s *Server) HandleCreateCheck(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid request body"})
 	}
 
-	if input.Name == "" {
-		return c.JSON(http.StatusBadRequest, APIResponse{Error: "name is required"})
-	}
-	if input.URL == "" {
-		return c.JSON(http.StatusBadRequest, APIResponse{Error: "url is required"})
+	if err := checker.ValidateCheckInput(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
 	}
 
 	check := input.ToCheck()
@@ -67,6 +154,46 @@ func (s *Server) HandleCreateCheck(c echo.Context) error {
 	return c.JSON(http.StatusCreated, APIResponse{Data: check})
 }
 
+// HandleImportChecks creates checks from a JSON or YAML list in the request
+// body, skipping URLs already monitored. Entries that fail validation are
+// reported without stopping the rest.
+func (s *Server) HandleImportChecks(c echo.Context) error {
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid request body"})
+	}
+	inputs, err := checker.ParseCheckImport(body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+	}
+
+	summary, created := checker.ImportChecks(s.storage, inputs)
+	if s.scheduler != nil {
+		for _, check := range created {
+			s.scheduler.AddCheck(check)
+		}
+	}
+
+	return c.JSON(http.StatusOK, APIResponse{Data: summary})
+}
+
+// HandleExportChecks returns every check as a config file's checks section,
+// which another instance can load through include
+func (s *Server) HandleExportChecks(c echo.Context) error {
+	checks, err := s.storage.ListChecks()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+
+	data, err := checker.ExportChecks(checks)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+
+	c.Response().Header().Set("Content-Disposition", `attachment; filename="sentinel-checks.yaml"`)
+	return c.Blob(http.StatusOK, "application/yaml", data)
+}
+
 func (s *Server) HandleGetCheck(c echo.Context) error {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
@@ -90,6 +217,10 @@ func (s *Server) HandleGetCheck(c echo.Context) error {
 	} else {
 		check.Status = "pending"
 	}
+	storage.SettleStatus(s.storage, check)
+	check.Stale = check.IsStale(time.Now(), s.staleIntervals())
+	maintenance, _ := s.storage.ListActiveMaintenanceWindows(time.Now())
+	check.InMaintenance = storage.InMaintenance(maintenance, check.ID)
 
 	return c.JSON(http.StatusOK, APIResponse{Data: check})
 }
@@ -120,14 +251,39 @@ func (s *Server) HandleUpdateCheck(c echo.Context) error {
 	if input.URL != "" {
 		existing.URL = input.URL
 	}
+	if input.Type != "" {
+		existing.Type = input.Type
+	}
+	if input.Type != "" || input.URL != "" {
+		if err := checker.ValidateTarget(existing.Type, existing.URL); err != nil {
+			return c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+		}
+	}
+	// An interval without a cron expression puts a cron check back on it
 	if input.IntervalSecs > 0 {
 		existing.IntervalSecs = input.IntervalSecs
+		existing.Cron = input.Cron
+	}
+	if input.Cron != "" {
+		existing.Cron = input.Cron
+	}
+	if input.Cron != "" || input.Type != "" {
+		if err := checker.ValidateCron(existing.Type, existing.Cron); err != nil {
+			return c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+		}
 	}
 	if input.TimeoutSecs > 0 {
 		existing.TimeoutSecs = input.TimeoutSecs
 	}
-	if input.ExpectedStatus > 0 {
+	if input.ExpectedStatus > 0 || input.ExpectedStatus == storage.AnyStatus {
 		existing.ExpectedStatus = input.ExpectedStatus
+		existing.ExpectedStatuses = ""
+	}
+	if input.ExpectedStatuses != "" {
+		if err := storage.ValidateStatusSpec(input.ExpectedStatuses); err != nil {
+			return c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+		}
+		existing.ExpectedStatuses = input.ExpectedStatuses
 	}
 	if input.Enabled != nil {
 		existing.Enabled = *input.Enabled
@@ -135,6 +291,183 @@ func (s *Server) HandleUpdateCheck(c echo.Context) error {
 	if input.Tags != nil {
 		existing.Tags = input.Tags
 	}
+	if input.Group != "" {
+		existing.Group = input.Group
+	}
+	if input.Description != "" {
+		existing.Description = input.Description
+	}
+	if input.RunbookURL != "" {
+		existing.RunbookURL = input.RunbookURL
+	}
+	if input.Streaming != nil {
+		existing.Streaming = *input.Streaming
+	}
+	if input.Paused != nil {
+		existing.Paused = *input.Paused
+		existing.PausedUntil = nil
+	}
+	if input.SampleSecs > 0 {
+		existing.SampleSecs = input.SampleSecs
+	}
+	if input.CompressResults != nil {
+		existing.CompressResults = *input.CompressResults
+	}
+	if input.AlertOnFirstCheck != nil {
+		existing.AlertOnFirstCheck = *input.AlertOnFirstCheck
+	}
+	if input.JSONAssertions != nil {
+		if err := validateJSONAssertions(input.JSONAssertions); err != nil {
+			return c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+		}
+		existing.JSONAssertions = input.JSONAssertions
+	}
+	if input.NoFollowRedirects != nil {
+		existing.NoFollowRedirects = *input.NoFollowRedirects
+	}
+	if input.BypassCache != nil {
+		existing.BypassCache = *input.BypassCache
+	}
+	if input.DualStack != nil {
+		existing.DualStack = *input.DualStack
+	}
+	if input.InsecureSkipVerify != nil {
+		existing.InsecureSkipVerify = *input.InsecureSkipVerify
+	}
+	if input.RecordType != "" {
+		existing.RecordType = input.RecordType
+	}
+	if input.ExpectedAnswer != "" {
+		existing.ExpectedAnswer = input.ExpectedAnswer
+	}
+	if input.RecordType != "" || input.ExpectedAnswer != "" {
+		if err := checker.ValidateDNSExpectation(existing.RecordType, existing.ExpectedAnswer); err != nil {
+			return c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+		}
+	}
+	if input.ExpectedLocation != "" {
+		if err := checker.ValidateExpectedLocation(input.ExpectedLocation); err != nil {
+			return c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+		}
+		existing.ExpectedLocation = input.ExpectedLocation
+	}
+	if input.ExpectedTrailer != "" {
+		if err := checker.ValidateExpectedTrailer(input.ExpectedTrailer); err != nil {
+			return c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+		}
+		existing.ExpectedTrailer = input.ExpectedTrailer
+	}
+	if input.ExpectedContentType != "" {
+		if err := checker.ValidateExpectedContentType(input.ExpectedContentType); err != nil {
+			return c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+		}
+		existing.ExpectedContentType = input.ExpectedContentType
+	}
+	if input.MinBodyBytes > 0 {
+		if err := checker.ValidateMinBodyBytes(input.MinBodyBytes); err != nil {
+			return c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+		}
+		existing.MinBodyBytes = input.MinBodyBytes
+	}
+	if input.ExpectedRedirects != nil {
+		if err := checker.ValidateExpectedRedirects(input.ExpectedRedirects); err != nil {
+			return c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+		}
+		existing.ExpectedRedirects = input.ExpectedRedirects
+	}
+	if input.Method != "" || input.Headers != nil {
+		method, headers := existing.Method, existing.Headers
+		if input.Method != "" {
+			method = strings.ToUpper(input.Method)
+		}
+		if input.Headers != nil {
+			headers = input.Headers
+		}
+		if err := checker.ValidateRequestOptions(method, headers); err != nil {
+			return c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+		}
+		existing.Method, existing.Headers = method, headers
+	}
+	if input.RequestBody != "" {
+		existing.RequestBody = input.RequestBody
+	}
+	if input.QueryParams != nil {
+		if err := checker.ValidateQueryParams(input.QueryParams); err != nil {
+			return c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+		}
+		existing.QueryParams = input.QueryParams
+	}
+	if input.ExpectedCertFingerprint != "" {
+		fingerprint, err := checker.ParseFingerprint(input.ExpectedCertFingerprint)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+		}
+		existing.ExpectedCertFingerprint = fingerprint
+	}
+	if input.BaselineURL != "" {
+		existing.BaselineURL = input.BaselineURL
+	}
+	if input.CompareFields != nil {
+		if err := checker.ValidateCompareFields(input.CompareFields); err != nil {
+			return c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+		}
+		existing.CompareFields = input.CompareFields
+	}
+	if input.Escalations != nil {
+		if err := checker.ValidateEscalations(input.Escalations); err != nil {
+			return c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+		}
+		existing.Escalations = input.Escalations
+	}
+	if input.AlertChannels != nil {
+		if err := checker.ValidateAlertChannels(input.AlertChannels); err != nil {
+			return c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+		}
+		existing.AlertChannels = input.AlertChannels
+	}
+	if input.AlertRoutes != nil {
+		if err := checker.ValidateAlertRoutes(input.AlertRoutes); err != nil {
+			return c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+		}
+		existing.AlertRoutes = input.AlertRoutes
+	}
+	if input.AssertionType != "" || input.Assertions != nil {
+		assertionType, assertions := existing.AssertionType, existing.Assertions
+		if input.AssertionType != "" {
+			assertionType = input.AssertionType
+		}
+		if input.Assertions != nil {
+			assertions = input.Assertions
+		}
+		if err := checker.ValidateBodyAssertions(assertionType, assertions); err != nil {
+			return c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+		}
+		existing.AssertionType, existing.Assertions = assertionType, assertions
+	}
+	if input.LatencyTolerancePct > 0 {
+		existing.LatencyTolerancePct = input.LatencyTolerancePct
+	}
+	if input.DegradedThresholdMs > 0 {
+		existing.DegradedThresholdMs = input.DegradedThresholdMs
+	}
+	if input.SlowMultiplier != 0 {
+		if input.SlowMultiplier <= 1 {
+			return c.JSON(http.StatusBadRequest, APIResponse{Error: "slow_multiplier must be more than 1"})
+		}
+		existing.SlowMultiplier = input.SlowMultiplier
+	}
+	if input.ConsecutiveSuccesses > 0 {
+		existing.ConsecutiveSuccesses = input.ConsecutiveSuccesses
+	}
+	if input.ConsecutiveFailures > 0 {
+		existing.ConsecutiveFailures = input.ConsecutiveFailures
+	}
+	if input.BodyContains != "" {
+		existing.BodyContains = input.BodyContains
+	}
+	if input.BodyNotContains != "" {
+		existing.BodyNotContains = input.BodyNotContains
+	}
 
 	if err := s.storage.UpdateCheck(existing); err != nil {
 		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
@@ -166,6 +499,82 @@ func (s *Server) HandleDeleteCheck(c echo.Context) error {
 	return c.JSON(http.StatusOK, APIResponse{Data: map[string]bool{"deleted": true}})
 }
 
+// HandleResetCheck deletes a check's results and incidents but keeps the
+// check. It must be confirmed with ?confirm=true since history can't be
+// recovered.
+func (s *Server) HandleResetCheck(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid check ID"})
+	}
+	if c.QueryParam("confirm") != "true" {
+		return c.JSON(http.StatusBadRequest, APIResponse{Error: "Resetting deletes all results and incidents; add ?confirm=true"})
+	}
+
+	check, err := s.storage.GetCheck(id)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+	if check == nil {
+		return c.JSON(http.StatusNotFound, APIResponse{Error: "Check not found"})
+	}
+
+	if err := s.storage.ResetCheckHistory(id); err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, APIResponse{Data: map[string]bool{"reset": true}})
+}
+
+// HandlePauseCheck pauses a check until it is resumed, or with ?minutes=N
+// for that long, after which the scheduler resumes it
+func (s *Server) HandlePauseCheck(c echo.Context) error {
+	var until *time.Time
+	if minutesStr := c.QueryParam("minutes"); minutesStr != "" {
+		minutes, err := strconv.Atoi(minutesStr)
+		if err != nil || minutes <= 0 {
+			return c.JSON(http.StatusBadRequest, APIResponse{Error: "minutes must be a positive number"})
+		}
+		t := time.Now().Add(time.Duration(minutes) * time.Minute)
+		until = &t
+	}
+	return s.setCheckPaused(c, true, until)
+}
+
+func (s *Server) HandleResumeCheck(c echo.Context) error {
+	return s.setCheckPaused(c, false, nil)
+}
+
+// setCheckPaused flips only the paused state; the scheduler sees it on the
+// next tick, so the check never has to be re-added
+func (s *Server) setCheckPaused(c echo.Context, paused bool, until *time.Time) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid check ID"})
+	}
+
+	check, err := s.storage.GetCheck(id)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+	if check == nil {
+		return c.JSON(http.StatusNotFound, APIResponse{Error: "Check not found"})
+	}
+
+	if until != nil {
+		err = s.storage.PauseCheckUntil(id, *until)
+	} else {
+		err = s.storage.SetCheckPaused(id, paused)
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+	check.Paused = paused
+	check.PausedUntil = until
+
+	return c.JSON(http.StatusOK, APIResponse{Data: check})
+}
+
 func (s *Server) HandleGetCheckResults(c echo.Context) error {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
@@ -190,8 +599,47 @@ func (s *Server) HandleGetCheckResults(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
 	}
+	total, err := s.storage.CountResults(id)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, APIResponse{
+		Data: results,
+		Meta: &PageMeta{Total: total, Limit: limit, Offset: offset},
+	})
+}
+
+// HandleExportCheckResults downloads a check's results between from and to
+// (default the last 7 days) as CSV, oldest first
+func (s *Server) HandleExportCheckResults(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid check ID"})
+	}
+
+	from, to, errMsg := parseTimeRange(c, 7*24*time.Hour)
+	if errMsg != "" {
+		return c.JSON(http.StatusBadRequest, APIResponse{Error: errMsg})
+	}
+
+	check, err := s.storage.GetCheck(id)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+	if check == nil {
+		return c.JSON(http.StatusNotFound, APIResponse{Error: "Check not found"})
+	}
+
+	results, err := s.storage.GetResultsInRange(id, from, to)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
 
-	return c.JSON(http.StatusOK, APIResponse{Data: results})
+	c.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="sentinel-check-%d-results.csv"`, id))
+	c.Response().WriteHeader(http.StatusOK)
+	return storage.WriteResultsCSV(c.Response(), results)
 }
 
 func (s *Server) HandleGetCheckStats(c echo.Context) error {
@@ -208,6 +656,86 @@ func (s *Server) HandleGetCheckStats(c echo.Context) error {
 	return c.JSON(http.StatusOK, APIResponse{Data: stats})
 }
 
+func (s *Server) HandleGetIncidentStats(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid check ID"})
+	}
+
+	// Default to the last 30 days
+	from, to, errMsg := parseTimeRange(c, 30*24*time.Hour)
+	if errMsg != "" {
+		return c.JSON(http.StatusBadRequest, APIResponse{Error: errMsg})
+	}
+
+	stats, err := s.storage.GetIncidentStats(id, from, to)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, APIResponse{Data: stats})
+}
+
+func (s *Server) HandleGetUptimeSeries(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid check ID"})
+	}
+
+	var resolution time.Duration
+	switch c.QueryParam("resolution") {
+	case "", "hour":
+		resolution = time.Hour
+	case "day":
+		resolution = 24 * time.Hour
+	default:
+		return c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid resolution (expected hour or day)"})
+	}
+
+	// Default to the last 7 days
+	from, to, errMsg := parseTimeRange(c, 7*24*time.Hour)
+	if errMsg != "" {
+		return c.JSON(http.StatusBadRequest, APIResponse{Error: errMsg})
+	}
+
+	check, err := s.storage.GetCheck(id)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+	if check == nil {
+		return c.JSON(http.StatusNotFound, APIResponse{Error: "Check not found"})
+	}
+
+	points, err := s.storage.GetUptimeSeries(id, from, to, resolution)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, APIResponse{Data: points})
+}
+
+// parseTimeRange reads RFC3339 from/to query params, defaulting to the span
+// ending now. A non-empty message means the range is invalid.
+func parseTimeRange(c echo.Context, span time.Duration) (time.Time, time.Time, string) {
+	var err error
+	to := time.Now()
+	from := to.Add(-span)
+	if v := c.QueryParam("from"); v != "" {
+		if from, err = time.Parse(time.RFC3339, v); err != nil {
+			return from, to, "Invalid from time (expected RFC3339)"
+		}
+	}
+	if v := c.QueryParam("to"); v != "" {
+		if to, err = time.Parse(time.RFC3339, v); err != nil {
+			return from, to, "Invalid to time (expected RFC3339)"
+		}
+	}
+	if !from.Before(to) {
+		return from, to, "from must be before to"
+	}
+	return from, to, ""
+}
+
 func (s *Server) HandleTriggerCheck(c echo.Context) error {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
@@ -223,21 +751,119 @@ func (s *Server) HandleTriggerCheck(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
 	}
 
-	status := "up"
-	if resp.Error != nil || resp.StatusCode != 200 {
-		status = "down"
+	check, err := s.storage.GetCheck(id)
+	if err != nil || check == nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: "Check not found"})
+	}
+
+	return c.JSON(http.StatusOK, APIResponse{Data: triggerResult(resp, check)})
+}
+
+// HandlePushResult records a result the monitored service pushed itself,
+// turning the check into a heartbeat for jobs Sentinel can't reach
+func (s *Server) HandlePushResult(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid check ID"})
+	}
+
+	if s.scheduler == nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: "Scheduler not available"})
+	}
+
+	check, err := s.storage.GetCheck(id)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+	if check == nil {
+		return c.JSON(http.StatusNotFound, APIResponse{Error: "Check not found"})
+	}
+
+	var pushed checker.PushedResult
+	if err := c.Bind(&pushed); err != nil {
+		return c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid request body"})
+	}
+	if err := pushed.Validate(); err != nil {
+		return c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+	}
+
+	status, err := s.scheduler.PushResult(check, &pushed)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, APIResponse{Data: map[string]string{"status": status}})
+}
+
+// validateJSONAssertions rejects assertions the checker could not evaluate
+func validateJSONAssertions(assertions []string) error {
+	for _, a := range assertions {
+		if _, err := checker.ParseJSONAssertion(a); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
+// triggerResult summarizes a manual check run for API responses
+func triggerResult(resp *checker.CheckResponse, check *storage.Check) map[string]interface{} {
 	result := map[string]interface{}{
-		"status":           status,
+		"status":           checker.DetermineCheckStatus(resp, check),
 		"status_code":      resp.StatusCode,
 		"response_time_ms": resp.ResponseTimeMs,
 	}
 	if resp.Error != nil {
 		result["error"] = resp.Error.Error()
 	}
+	return result
+}
 
-	return c.JSON(http.StatusOK, APIResponse{Data: result})
+func (s *Server) HandleRecordGolden(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid check ID"})
+	}
+
+	if s.scheduler == nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: "Scheduler not available"})
+	}
+
+	snapshot, err := s.scheduler.RecordGolden(id)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, APIResponse{Data: snapshot})
+}
+
+func (s *Server) HandleGetGolden(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid check ID"})
+	}
+
+	snapshot, err := s.storage.GetGoldenSnapshot(id)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+	if snapshot == nil {
+		return c.JSON(http.StatusNotFound, APIResponse{Error: "Golden snapshot not found"})
+	}
+
+	return c.JSON(http.StatusOK, APIResponse{Data: snapshot})
+}
+
+func (s *Server) HandleDeleteGolden(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid check ID"})
+	}
+
+	if err := s.storage.DeleteGoldenSnapshot(id); err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, APIResponse{Data: map[string]bool{"deleted": true}})
 }
 
 func (s *Server) HandleListIncidents(c echo.Context) error {
@@ -259,8 +885,15 @@ func (s *Server) HandleListIncidents(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
 	}
+	total, err := s.storage.CountIncidents()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
 
-	return c.JSON(http.StatusOK, APIResponse{Data: incidents})
+	return c.JSON(http.StatusOK, APIResponse{
+		Data: incidents,
+		Meta: &PageMeta{Total: total, Limit: limit, Offset: offset},
+	})
 }
 
 func (s *Server) HandleGetIncident(c echo.Context) error {
@@ -412,3 +1045,11 @@ func (s *Server) HandleDeleteIncidentNote(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, APIResponse{Data: map[string]bool{"deleted": true}})
 }
+
+func (s *Server) HandleListAlertChannels(c echo.Context) error {
+	channels := []alerter.ChannelState{}
+	if s.alerts != nil {
+		channels = append(channels, s.alerts.ChannelStates()...)
+	}
+	return c.JSON(http.StatusOK, APIResponse{Data: channels})
+}
@@ -1,26 +1,24 @@
 package web
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
-	"os"
-	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/labstack/echo/v4"
 
+	"github.com/katieblackabee/sentinel/internal/alerter"
+	"github.com/katieblackabee/sentinel/internal/checker"
 	"github.com/katieblackabee/sentinel/internal/config"
 	"github.com/katieblackabee/sentinel/internal/storage"
 )
 
 func setupTestServer(t *testing.T) (*Server, storage.Storage) {
-	tmpDir := t.TempDir()
-	dbPath := filepath.Join(tmpDir, "test.db")
-
-	store, err := storage.NewSQLiteStorage(dbPath)
+	store, err := storage.NewMemoryStorage()
 	if err != nil {
 		t.Fatalf("failed to create storage: %v", err)
 	}
@@ -34,12 +32,12 @@ func setupTestServer(t *testing.T) (*Server, storage.Storage) {
 		echo:    echo.New(),
 		config:  cfg,
 		storage: store,
+		events:  NewEventBroker(),
 	}
 	server.registerRoutes()
 
 	t.Cleanup(func() {
 		store.Close()
-		os.Remove(dbPath)
 	})
 
 	return server, store
@@ -122,42 +120,610 @@ func TestAPIListChecksWithData(t *testing.T) {
 	}
 }
 
+func TestAPIListChecksSince(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	old := &storage.Check{Name: "Old", URL: "https://old.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	changed := &storage.Check{Name: "Changed", URL: "https://changed.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(old)
+	store.CreateCheck(changed)
+
+	time.Sleep(10 * time.Millisecond)
+	since := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	changed.IntervalSecs = 30
+	store.UpdateCheck(changed)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/checks?since="+since.UTC().Format(time.RFC3339Nano), nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp APIResponse
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	data, _ := resp.Data.([]interface{})
+	if len(data) != 1 || data[0].(map[string]interface{})["name"] != "Changed" {
+		t.Errorf("expected only the changed check, got %v", resp.Data)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/checks?since=yesterday", nil)
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for invalid since, got %d", rec.Code)
+	}
+}
+
+func TestAPIListChecksByStatus(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	healthy := &storage.Check{Name: "Healthy", URL: "https://healthy.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	broken := &storage.Check{Name: "Broken", URL: "https://broken.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(healthy)
+	store.CreateCheck(broken)
+	store.SaveResult(&storage.CheckResult{CheckID: healthy.ID, Status: "up"})
+	store.SaveResult(&storage.CheckResult{CheckID: broken.ID, Status: "down", ErrorMessage: "connection refused"})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/checks?status=down", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp APIResponse
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	data, _ := resp.Data.([]interface{})
+	if len(data) != 1 {
+		t.Fatalf("expected only the down check, got %v", resp.Data)
+	}
+	check := data[0].(map[string]interface{})
+	if check["name"] != "Broken" || check["status"] != "down" || check["last_checked_at"] == nil {
+		t.Errorf("expected enriched down check, got %v", check)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/checks?status=sideways", nil)
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for unknown status, got %d", rec.Code)
+	}
+}
+
+func TestAPIListChecksFiltered(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	for _, check := range []*storage.Check{
+		{Name: "Checkout API", URL: "https://checkout.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true, Tags: []string{"payments"}},
+		{Name: "Checkout Web", URL: "https://checkout.com/web", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: false, Tags: []string{"payments"}},
+		{Name: "Search", URL: "https://search.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true, Tags: []string{"search"}},
+	} {
+		store.CreateCheck(check)
+	}
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"", 3},
+		{"?q=checkout", 2},
+		{"?tag=payments&enabled=true", 1},
+		{"?q=checkout&tag=search", 0},
+		{"?q=search&status=pending", 1},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/checks"+tt.query, nil)
+		rec := httptest.NewRecorder()
+		server.echo.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d: %s", tt.query, rec.Code, rec.Body.String())
+		}
+		var resp APIResponse
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		data, _ := resp.Data.([]interface{})
+		if len(data) != tt.want {
+			t.Errorf("%s: expected %d checks, got %v", tt.query, tt.want, resp.Data)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/checks?enabled=sometimes", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for invalid enabled, got %d", rec.Code)
+	}
+}
+
 func TestAPICreateCheck(t *testing.T) {
 	server, _ := setupTestServer(t)
 
-	body := `{"name":"Test Check","url":"https://example.com"}`
+	body := `{"name":"Test Check","url":"https://example.com"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/checks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Errorf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp APIResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	if resp.Error != "" {
+		t.Errorf("unexpected error: %s", resp.Error)
+	}
+}
+
+func TestAPICreateCheckWithOptions(t *testing.T) {
+	server, _ := setupTestServer(t)
+
+	body := `{"name":"Full Check","url":"https://example.com","interval_seconds":30,"timeout_seconds":5,"expected_status":201,"tags":["api","v2"]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/checks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Errorf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestAPICreateCheckJSONAssertions(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	body := `{"name":"Queue","url":"https://example.com/health","json_assertions":["$.queue_depth < 10000"]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/checks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	check, _ := store.GetCheck(1)
+	if len(check.JSONAssertions) != 1 || check.JSONAssertions[0] != "$.queue_depth < 10000" {
+		t.Errorf("expected assertion to be stored, got %v", check.JSONAssertions)
+	}
+
+	body = `{"name":"Bad","url":"https://example.com/health","json_assertions":["queue_depth is high"]}`
+	req = httptest.NewRequest(http.MethodPost, "/api/checks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for invalid assertion, got %d", rec.Code)
+	}
+}
+
+func TestAPICreateCheckRedirectPolicy(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	body := `{"name":"Login","url":"https://example.com/","expected_status":301,"no_follow_redirects":true,"expected_location":"/^https://example\\.com/login/"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/checks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	check, _ := store.GetCheck(1)
+	if !check.NoFollowRedirects || check.ExpectedLocation != `/^https://example\.com/login/` {
+		t.Errorf("expected redirect policy to be stored, got %v %q", check.NoFollowRedirects, check.ExpectedLocation)
+	}
+
+	body = `{"name":"Bad","url":"https://example.com/","expected_location":"/[/"}`
+	req = httptest.NewRequest(http.MethodPost, "/api/checks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for invalid location regex, got %d", rec.Code)
+	}
+}
+
+func TestAPICreateCheckCertFingerprint(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	pin := strings.Repeat("AB:", 31) + "AB"
+	body := `{"name":"Pinned","url":"https://internal.example.com","expected_cert_fingerprint":"` + pin + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/checks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	check, _ := store.GetCheck(1)
+	if check.ExpectedCertFingerprint != strings.Repeat("ab", 32) {
+		t.Errorf("expected normalized fingerprint to be stored, got %q", check.ExpectedCertFingerprint)
+	}
+
+	body = `{"name":"Bad","url":"https://internal.example.com","expected_cert_fingerprint":"not-a-hash"}`
+	req = httptest.NewRequest(http.MethodPost, "/api/checks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for invalid fingerprint, got %d", rec.Code)
+	}
+}
+
+func TestAPICreateCheckBodyKeywords(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	body := `{"name":"App","url":"https://app.example.com","body_contains":"healthy","body_not_contains":"Maintenance"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/checks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	check, _ := store.GetCheck(1)
+	if check.BodyContains != "healthy" || check.BodyNotContains != "Maintenance" {
+		t.Errorf("expected body keywords to be stored, got %q %q", check.BodyContains, check.BodyNotContains)
+	}
+}
+
+func TestAPICreateCheckAlertChannels(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	body := `{"name":"Noisy","url":"https://noisy.example.com","alert_channels":["slack","discord:ops"]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/checks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	check, _ := store.GetCheck(1)
+	if len(check.AlertChannels) != 2 || check.AlertChannels[0] != "slack" || check.AlertChannels[1] != "discord:ops" {
+		t.Errorf("expected alert channels to be stored, got %v", check.AlertChannels)
+	}
+
+	body = `{"name":"Bad","url":"https://bad.example.com","alert_channels":["pager"]}`
+	req = httptest.NewRequest(http.MethodPost, "/api/checks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for unknown alert channel, got %d", rec.Code)
+	}
+}
+
+func TestAPICreateCheckAlertRoutes(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	body := `{"name":"Payments","url":"https://pay.example.com","alert_routes":{"down":["slack:oncall","email"],"recovery":["slack"]}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/checks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	check, _ := store.GetCheck(1)
+	if len(check.AlertRoutes["down"]) != 2 || strings.Join(check.AlertRoutes["recovery"], ",") != "slack" {
+		t.Errorf("expected alert routes to be stored, got %v", check.AlertRoutes)
+	}
+
+	body = `{"name":"Bad","url":"https://bad.example.com","alert_routes":{"degraded":["slack"]}}`
+	req = httptest.NewRequest(http.MethodPost, "/api/checks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an unknown alert type, got %d", rec.Code)
+	}
+}
+
+func TestAPICreateCheckExpectedTrailer(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	body := `{"name":"gRPC","url":"https://grpc.example.com","expected_trailer":"Grpc-Status: 0"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/checks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	check, _ := store.GetCheck(1)
+	if check.ExpectedTrailer != "Grpc-Status: 0" {
+		t.Errorf("expected the trailer to be stored, got %q", check.ExpectedTrailer)
+	}
+
+	body = `{"name":"Bad","url":"https://bad.example.com","expected_trailer":"Grpc Status: 0"}`
+	req = httptest.NewRequest(http.MethodPost, "/api/checks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an invalid trailer name, got %d", rec.Code)
+	}
+}
+
+func TestAPICreateCheckExpectedRedirects(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	body := `{"name":"SEO","url":"http://example.com","expected_redirects":["301 https://example.com/","301 https://www.example.com/"]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/checks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	check, _ := store.GetCheck(1)
+	if len(check.ExpectedRedirects) != 2 || check.ExpectedRedirects[1] != "301 https://www.example.com/" {
+		t.Errorf("expected the chain to be stored, got %v", check.ExpectedRedirects)
+	}
+
+	body = `{"expected_redirects":["200 https://example.com/"]}`
+	req = httptest.NewRequest(http.MethodPut, "/api/checks/1", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for a hop without a 3xx status, got %d", rec.Code)
+	}
+}
+
+func TestAPICreateCheckRequestOptions(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	body := `{"name":"Health","url":"https://api.example.com/health","method":"post","headers":{"Authorization":"Bearer token"},"request_body":"{}"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/checks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	check, _ := store.GetCheck(1)
+	if check.Method != "POST" || check.Headers["Authorization"] != "Bearer token" || check.RequestBody != "{}" {
+		t.Errorf("expected the request options to be stored, got %q %v %q", check.Method, check.Headers, check.RequestBody)
+	}
+
+	body = `{"method":"FETCH"}`
+	req = httptest.NewRequest(http.MethodPut, "/api/checks/1", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an unknown method, got %d", rec.Code)
+	}
+}
+
+func TestAPICreateCheckQueryParams(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	body := `{"name":"Signed","url":"https://api.example.com/health","query_params":{"ts":"{{.Now.Unix}}"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/checks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	check, _ := store.GetCheck(1)
+	if check.QueryParams["ts"] != "{{.Now.Unix}}" {
+		t.Errorf("expected the query params to be stored, got %v", check.QueryParams)
+	}
+
+	body = `{"query_params":{"ts":"{{.Now.Unix"}}`
+	req = httptest.NewRequest(http.MethodPut, "/api/checks/1", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for a broken template, got %d", rec.Code)
+	}
+}
+
+func TestAPIImportChecks(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	store.CreateCheck(&storage.Check{Name: "Existing", URL: "https://existing.example.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true})
+
+	body := `[{"name":"One","url":"https://one.example.com"},{"name":"Dup","url":"https://existing.example.com"},{"name":"Bad","url":""}]`
+	req := httptest.NewRequest(http.MethodPost, "/api/checks/import", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Data checker.ImportSummary `json:"data"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	if len(resp.Data.Created) != 1 || len(resp.Data.Skipped) != 1 || len(resp.Data.Failed) != 1 {
+		t.Errorf("expected 1 created, 1 skipped and 1 failed, got %+v", resp.Data)
+	}
+	if resp.Data.Failed[0].Error != "url is required" {
+		t.Errorf("expected the failure reason, got %q", resp.Data.Failed[0].Error)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/checks/import", strings.NewReader(`{"name":"x"}`))
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for a body that isn't a list, got %d", rec.Code)
+	}
+}
+
+func TestAPIExportChecks(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	store.CreateCheck(&storage.Check{Name: "API", URL: "https://api.example.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true, Tags: []string{"prod"}})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/checks/export", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/yaml" {
+		t.Errorf("expected YAML, got %q", ct)
+	}
+	for _, want := range []string{"checks:", "name: API", "interval: 60s", "- prod"} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("expected %q in export, got %s", want, rec.Body.String())
+		}
+	}
+}
+
+func TestAPICreateCheckAssertions(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	body := `{"name":"Feed","url":"https://feed.example.com","assertion_type":"xml","assertions":["/rss/channel/title == News"]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/checks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	check, _ := store.GetCheck(1)
+	if check.AssertionType != "xml" || len(check.Assertions) != 1 || check.Assertions[0] != "/rss/channel/title == News" {
+		t.Errorf("expected assertions to be stored, got %q %v", check.AssertionType, check.Assertions)
+	}
+
+	// A JSONPath assertion doesn't suit the check's XML matcher
+	req = httptest.NewRequest(http.MethodPut, "/api/checks/1", strings.NewReader(`{"assertions":["$.title == News"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an assertion the matcher can't read, got %d", rec.Code)
+	}
+}
+
+func TestAPICreateDNSCheck(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	body := `{"name":"Apex DNS","type":"dns","url":"example.com","record_type":"A","expected_answer":"93.184.216.34"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/checks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	check, _ := store.GetCheck(1)
+	if check.Type != "dns" || check.RecordType != "A" || check.ExpectedAnswer != "93.184.216.34" {
+		t.Errorf("expected dns settings to be stored, got %q %q %q", check.Type, check.RecordType, check.ExpectedAnswer)
+	}
+	if check.SuccessStatus() != storage.AnyStatus {
+		t.Errorf("expected dns checks to ignore status codes, got %d", check.SuccessStatus())
+	}
+
+	// An IPv4 answer can't match an AAAA lookup
+	req = httptest.NewRequest(http.MethodPut, "/api/checks/1", strings.NewReader(`{"record_type":"AAAA"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for a mismatched expected answer, got %d", rec.Code)
+	}
+}
+
+func TestAPICreateCheckStatusSpec(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	body := `{"name":"Upload","url":"https://example.com/upload","expected_status":"2xx"}`
 	req := httptest.NewRequest(http.MethodPost, "/api/checks", strings.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	rec := httptest.NewRecorder()
-
 	server.echo.ServeHTTP(rec, req)
 
 	if rec.Code != http.StatusCreated {
-		t.Errorf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+		t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
 	}
-
-	var resp APIResponse
-	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
-		t.Fatalf("failed to parse response: %v", err)
+	check, _ := store.GetCheck(1)
+	if check.ExpectedStatuses != "2xx" {
+		t.Errorf("expected spec 2xx to be stored, got %q", check.ExpectedStatuses)
 	}
 
-	if resp.Error != "" {
-		t.Errorf("unexpected error: %s", resp.Error)
+	body = `{"name":"Bad","url":"https://example.com","expected_status":"2xz"}`
+	req = httptest.NewRequest(http.MethodPost, "/api/checks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for invalid spec, got %d", rec.Code)
 	}
 }
 
-func TestAPICreateCheckWithOptions(t *testing.T) {
-	server, _ := setupTestServer(t)
+func TestAPICreateCheckTCP(t *testing.T) {
+	server, store := setupTestServer(t)
 
-	body := `{"name":"Full Check","url":"https://example.com","interval_seconds":30,"timeout_seconds":5,"expected_status":201,"tags":["api","v2"]}`
+	body := `{"name":"Postgres","type":"tcp","url":"db.internal:5432"}`
 	req := httptest.NewRequest(http.MethodPost, "/api/checks", strings.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	rec := httptest.NewRecorder()
-
 	server.echo.ServeHTTP(rec, req)
 
 	if rec.Code != http.StatusCreated {
-		t.Errorf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+		t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	check, _ := store.GetCheck(1)
+	if check.Type != storage.CheckTypeTCP || check.URL != "db.internal:5432" {
+		t.Errorf("expected tcp check to be stored, got %q %q", check.Type, check.URL)
+	}
+
+	for _, body := range []string{
+		`{"name":"Bad","type":"tcp","url":"https://example.com"}`,
+		`{"name":"Bad","type":"icmp","url":"example.com"}`,
+	} {
+		req = httptest.NewRequest(http.MethodPost, "/api/checks", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec = httptest.NewRecorder()
+		server.echo.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400 for %s, got %d", body, rec.Code)
+		}
 	}
 }
 
@@ -231,6 +797,22 @@ func TestAPIGetCheck(t *testing.T) {
 	}
 }
 
+func TestAPIGetCheckStale(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	check := &storage.Check{Name: "Stuck", URL: "https://stuck.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(check)
+	store.SaveResult(&storage.CheckResult{CheckID: check.ID, Status: "up", StatusCode: 200, CheckedAt: time.Now().Add(-time.Hour)})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/checks/1", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if !strings.Contains(rec.Body.String(), `"stale":true`) {
+		t.Errorf("expected check without results for an hour to be stale, got %s", rec.Body.String())
+	}
+}
+
 func TestAPIGetCheckNotFound(t *testing.T) {
 	server, _ := setupTestServer(t)
 
@@ -291,6 +873,40 @@ func TestAPIUpdateCheck(t *testing.T) {
 	}
 }
 
+func TestAPIUpdateCheckCron(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	check := &storage.Check{Name: "Reports", URL: "https://reports.example.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(check)
+
+	put := func(body string) int {
+		req := httptest.NewRequest(http.MethodPut, "/api/checks/1", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		server.echo.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := put(`{"cron":"0 * * * *"}`); code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", code)
+	}
+	if updated, _ := store.GetCheck(check.ID); updated.Cron != "0 * * * *" {
+		t.Errorf("expected the cron expression stored, got %q", updated.Cron)
+	}
+
+	if code := put(`{"cron":"0 * * *"}`); code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an invalid expression, got %d", code)
+	}
+
+	// An interval alone goes back to interval scheduling
+	if code := put(`{"interval_seconds":120}`); code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", code)
+	}
+	if updated, _ := store.GetCheck(check.ID); updated.Cron != "" || updated.IntervalSecs != 120 {
+		t.Errorf("expected the cron expression cleared, got %q every %ds", updated.Cron, updated.IntervalSecs)
+	}
+}
+
 func TestAPIUpdateCheckNotFound(t *testing.T) {
 	server, _ := setupTestServer(t)
 
@@ -456,6 +1072,62 @@ func TestAPIGetCheckResultsWithPagination(t *testing.T) {
 	if len(data) != 3 {
 		t.Errorf("expected 3 results with limit=3, got %d", len(data))
 	}
+	if resp.Meta == nil || *resp.Meta != (PageMeta{Total: 10, Limit: 3, Offset: 2}) {
+		t.Errorf("expected total 10 at limit 3, offset 2, got %+v", resp.Meta)
+	}
+}
+
+func TestAPIExportCheckResultsCSV(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	check := &storage.Check{Name: "CSV", URL: "https://csv.example.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(check)
+	now := time.Now().Truncate(time.Second)
+	store.SaveResult(&storage.CheckResult{CheckID: check.ID, Status: "up", StatusCode: 200, ResponseTimeMs: 120, CheckedAt: now.Add(-2 * time.Hour)})
+	store.SaveResult(&storage.CheckResult{CheckID: check.ID, Status: "down", ResponseTimeMs: 10000, ErrorMessage: "dial tcp: timeout, giving up", CheckedAt: now.Add(-time.Hour)})
+	store.SaveResult(&storage.CheckResult{CheckID: check.ID, Status: "up", StatusCode: 200, CheckedAt: now.Add(-30 * 24 * time.Hour)})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/checks/1/results.csv", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Errorf("expected a CSV content type, got %q", ct)
+	}
+	if cd := rec.Header().Get("Content-Disposition"); !strings.Contains(cd, "attachment") || !strings.Contains(cd, ".csv") {
+		t.Errorf("expected a CSV attachment, got %q", cd)
+	}
+
+	// Only the last 7 days by default, oldest first, with the error quoted
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse CSV: %v", err)
+	}
+	if len(rows) != 3 || rows[0][0] != "checked_at" {
+		t.Fatalf("expected a header and 2 rows, got %v", rows)
+	}
+	if rows[1][1] != "up" || rows[1][3] != "120" || rows[2][1] != "down" || rows[2][4] != "dial tcp: timeout, giving up" {
+		t.Errorf("unexpected rows: %v", rows[1:])
+	}
+
+	// from and to select the range
+	from := now.Add(-31 * 24 * time.Hour).UTC().Format(time.RFC3339)
+	req = httptest.NewRequest(http.MethodGet, "/api/checks/1/results.csv?from="+from, nil)
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+	if rows, _ := csv.NewReader(rec.Body).ReadAll(); len(rows) != 4 {
+		t.Errorf("expected every result from the wider range, got %d rows", len(rows)-1)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/checks/999/results.csv", nil)
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for an unknown check, got %d", rec.Code)
+	}
 }
 
 func TestAPIGetCheckResultsInvalidID(t *testing.T) {
@@ -502,6 +1174,11 @@ func TestAPIGetCheckStats(t *testing.T) {
 	if resp.Error != "" {
 		t.Errorf("unexpected error: %s", resp.Error)
 	}
+	// Results are 100..190ms, so the nearest-rank p95 is the slowest one
+	stats := resp.Data.(map[string]interface{})
+	if stats["p50_response_ms_24h"] != float64(140) || stats["p95_response_ms_24h"] != float64(190) {
+		t.Errorf("expected p50 140 and p95 190, got %v and %v", stats["p50_response_ms_24h"], stats["p95_response_ms_24h"])
+	}
 }
 
 func TestAPIGetCheckStatsInvalidID(t *testing.T) {
@@ -534,6 +1211,44 @@ func TestAPITriggerCheckNoScheduler(t *testing.T) {
 	}
 }
 
+func TestAPIPushResult(t *testing.T) {
+	server, store := setupTestServer(t)
+	server.scheduler = checker.NewScheduler(store, nil, checker.SchedulerConfig{})
+
+	check := &storage.Check{Name: "Cron", URL: "https://cron.internal", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(check)
+
+	push := func(id, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/checks/"+id+"/push", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		server.echo.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := push("1", `{"status": "down", "response_time_ms": 1500, "message": "exit status 2"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), `"status":"down"`) {
+		t.Errorf("expected the stored status in the response, got %s", rec.Body.String())
+	}
+	latest, _ := store.GetLatestResult(check.ID)
+	if latest == nil || latest.Status != "down" || latest.ResponseTimeMs != 1500 || latest.ErrorMessage != "exit status 2" {
+		t.Errorf("expected the pushed result stored, got %+v", latest)
+	}
+
+	if rec := push("999", `{"status": "up"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for unknown check, got %d", rec.Code)
+	}
+	if rec := push("1", `{"status": "fine"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for invalid status, got %d", rec.Code)
+	}
+	if rec := push("1", `not json`); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for invalid body, got %d", rec.Code)
+	}
+}
+
 func TestAPITriggerCheckInvalidID(t *testing.T) {
 	server, _ := setupTestServer(t)
 
@@ -547,6 +1262,243 @@ func TestAPITriggerCheckInvalidID(t *testing.T) {
 	}
 }
 
+func TestAPIGoldenSnapshot(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	check := &storage.Check{Name: "Golden", URL: "https://golden.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(check)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/checks/1/golden", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 before recording, got %d", rec.Code)
+	}
+
+	store.SaveGoldenSnapshot(&storage.GoldenSnapshot{CheckID: check.ID, Body: "ok"})
+
+	req = httptest.NewRequest(http.MethodGet, "/api/checks/1/golden", nil)
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/checks/1/golden", nil)
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status 200 on delete, got %d", rec.Code)
+	}
+	if snapshot, _ := store.GetGoldenSnapshot(check.ID); snapshot != nil {
+		t.Error("expected golden snapshot to be deleted")
+	}
+
+	// Recording requires the scheduler
+	req = httptest.NewRequest(http.MethodPost, "/api/checks/1/golden", nil)
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500 without scheduler, got %d", rec.Code)
+	}
+}
+
+func TestAPIIncidentStats(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	check := &storage.Check{Name: "Stats", URL: "https://stats.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(check)
+
+	incident := &storage.Incident{CheckID: check.ID, StartedAt: time.Now().Add(-time.Hour)}
+	store.CreateIncident(incident)
+	store.CloseIncident(incident.ID, incident.StartedAt.Add(5*time.Minute))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/checks/1/incident-stats", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp struct {
+		Data storage.IncidentStats `json:"data"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	if resp.Data.Count != 1 || resp.Data.MTTRSeconds != 300 {
+		t.Errorf("expected 1 incident with MTTR 300s, got %+v", resp.Data)
+	}
+
+	// A range before the incident
+	from := time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339)
+	to := time.Now().Add(-24 * time.Hour).UTC().Format(time.RFC3339)
+	req = httptest.NewRequest(http.MethodGet, "/api/checks/1/incident-stats?from="+from+"&to="+to, nil)
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	if resp.Data.Count != 0 {
+		t.Errorf("expected no incidents in range, got %d", resp.Data.Count)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/checks/1/incident-stats?from=yesterday", nil)
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for invalid from, got %d", rec.Code)
+	}
+}
+
+func TestAPIUptimeSeries(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	check := &storage.Check{Name: "Series", URL: "https://series.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(check)
+	store.SaveResult(&storage.CheckResult{CheckID: check.ID, Status: "up", ResponseTimeMs: 120})
+	store.SaveResult(&storage.CheckResult{CheckID: check.ID, Status: "down"})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/checks/1/uptime?resolution=day", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp struct {
+		Data []storage.UptimePoint `json:"data"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	if len(resp.Data) != 1 || resp.Data[0].UptimePercent != 50 || resp.Data[0].AvgResponseMs != 120 {
+		t.Errorf("expected one point at 50%% and 120ms, got %+v", resp.Data)
+	}
+
+	for path, want := range map[string]int{
+		"/api/checks/1/uptime?resolution=week": http.StatusBadRequest,
+		"/api/checks/1/uptime?to=tomorrow":     http.StatusBadRequest,
+		"/api/checks/999/uptime":               http.StatusNotFound,
+	} {
+		req = httptest.NewRequest(http.MethodGet, path, nil)
+		rec = httptest.NewRecorder()
+		server.echo.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("%s: expected status %d, got %d", path, want, rec.Code)
+		}
+	}
+}
+
+func TestAPIPauseResumeCheck(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	check := &storage.Check{Name: "Pause Test", URL: "https://pause.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(check)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/checks/1/pause", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	got, _ := store.GetCheck(check.ID)
+	if !got.Paused {
+		t.Error("expected check to be paused")
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/checks/1/resume", nil)
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	got, _ = store.GetCheck(check.ID)
+	if got.Paused {
+		t.Error("expected check to be resumed")
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/checks/999/pause", nil)
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for missing check, got %d", rec.Code)
+	}
+}
+
+func TestAPIPauseCheckForMinutes(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	check := &storage.Check{Name: "Timed Pause", URL: "https://pause.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(check)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/checks/1/pause?minutes=30", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	got, _ := store.GetCheck(check.ID)
+	if !got.Paused || got.PausedUntil == nil {
+		t.Fatalf("expected a timed pause, got paused=%v until=%v", got.Paused, got.PausedUntil)
+	}
+	if d := time.Until(*got.PausedUntil); d < 29*time.Minute || d > 31*time.Minute {
+		t.Errorf("expected the pause to end in about 30 minutes, got %v", d)
+	}
+
+	for _, minutes := range []string{"0", "-5", "soon"} {
+		req = httptest.NewRequest(http.MethodPost, "/api/checks/1/pause?minutes="+minutes, nil)
+		rec = httptest.NewRecorder()
+		server.echo.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400 for minutes=%s, got %d", minutes, rec.Code)
+		}
+	}
+}
+
+func TestAPIResetCheck(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	check := &storage.Check{Name: "Reset Test", URL: "https://reset.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(check)
+	store.SaveResult(&storage.CheckResult{CheckID: check.ID, Status: "up", StatusCode: 200})
+
+	// Unconfirmed resets are refused
+	req := httptest.NewRequest(http.MethodPost, "/api/checks/1/reset", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 without confirm, got %d", rec.Code)
+	}
+	if latest, _ := store.GetLatestResult(check.ID); latest == nil {
+		t.Error("expected results kept without confirm")
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/checks/1/reset?confirm=true", nil)
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if latest, _ := store.GetLatestResult(check.ID); latest != nil {
+		t.Error("expected results deleted")
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/checks/999/reset?confirm=true", nil)
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for missing check, got %d", rec.Code)
+	}
+}
+
 func TestAPIListIncidents(t *testing.T) {
 	server, _ := setupTestServer(t)
 
@@ -594,6 +1546,9 @@ func TestAPIListIncidentsWithData(t *testing.T) {
 	if len(data) != 2 {
 		t.Errorf("expected 2 incidents with limit=2, got %d", len(data))
 	}
+	if resp.Meta == nil || *resp.Meta != (PageMeta{Total: 3, Limit: 2, Offset: 0}) {
+		t.Errorf("expected total 3 at limit 2, offset 0, got %+v", resp.Meta)
+	}
 }
 
 func TestAPIGetIncident(t *testing.T) {
@@ -681,3 +1636,30 @@ func TestAPIResponseStructure(t *testing.T) {
 		t.Error("expected error field to be present")
 	}
 }
+
+func TestAPIListAlertChannels(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/alerts/channels", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	server.SetAlertManager(alerter.NewManager(&config.AlertsConfig{
+		Discord: config.DiscordConfig{Enabled: true, Targets: []config.WebhookTarget{{Name: "ops", URL: "https://discord.example.com"}}},
+	}, store))
+
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	var resp struct {
+		Data []alerter.ChannelState `json:"data"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	if len(resp.Data) != 1 || resp.Data[0].Channel != "discord:ops" || resp.Data[0].State != alerter.BreakerClosed {
+		t.Errorf("expected closed discord:ops channel, got %+v", resp.Data)
+	}
+}
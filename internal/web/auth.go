@@ -2,9 +2,12 @@ package web
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"crypto/subtle"
 	"encoding/hex"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -20,9 +23,26 @@ type Session struct {
 	ExpiresAt time.Time
 }
 
+// Brute-force protection for the login form: this many failures from one
+// address within loginFailureWindow locks it out for loginLockout
+const (
+	maxLoginFailures   = 5
+	loginFailureWindow = time.Minute
+	loginLockout       = 5 * time.Minute
+)
+
+// loginFailures tracks one client address's recent failed logins
+type loginFailures struct {
+	count       int
+	windowStart time.Time
+	lockedUntil time.Time
+}
+
 type AuthManager struct {
 	users    map[string]string
+	apiKeys  []string // SHA-256 hex digests of accepted API keys
 	sessions map[string]*Session
+	failures map[string]*loginFailures // by client IP
 	basePath string
 	mu       sync.RWMutex
 }
@@ -31,6 +51,7 @@ func NewAuthManager(users map[string]string, basePath string) *AuthManager {
 	return &AuthManager{
 		users:    users,
 		sessions: make(map[string]*Session),
+		failures: make(map[string]*loginFailures),
 		basePath: basePath,
 	}
 }
@@ -44,6 +65,22 @@ func (a *AuthManager) ValidateUser(username, password string) bool {
 	return subtle.ConstantTimeCompare([]byte(password), []byte(storedPass)) == 1
 }
 
+// ValidateAPIKey reports whether key hashes to one of the configured digests.
+// Every digest is compared, in constant time, so timing doesn't leak a match.
+func (a *AuthManager) ValidateAPIKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	sum := sha256.Sum256([]byte(key))
+	digest := []byte(hex.EncodeToString(sum[:]))
+
+	valid := 0
+	for _, stored := range a.apiKeys {
+		valid |= subtle.ConstantTimeCompare(digest, []byte(strings.ToLower(stored)))
+	}
+	return valid == 1
+}
+
 func (a *AuthManager) CreateSession(username string) string {
 	token := generateToken()
 	a.mu.Lock()
@@ -78,6 +115,60 @@ func (a *AuthManager) DeleteSession(token string) {
 	a.mu.Unlock()
 }
 
+// LoginLockedOut returns how much longer ip is locked out of logging in, or
+// zero if it may try
+func (a *AuthManager) LoginLockedOut(ip string) time.Duration {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	f, exists := a.failures[ip]
+	if !exists {
+		return 0
+	}
+	if wait := time.Until(f.lockedUntil); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// RecordLoginFailure counts a failed login from ip, locking it out once it
+// reaches maxLoginFailures within a window
+func (a *AuthManager) RecordLoginFailure(ip string) {
+	now := time.Now()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// Forget addresses that have gone quiet, so the map stays small
+	for addr, f := range a.failures {
+		if now.Sub(f.windowStart) > loginFailureWindow && now.After(f.lockedUntil) {
+			delete(a.failures, addr)
+		}
+	}
+
+	f, exists := a.failures[ip]
+	if !exists {
+		f = &loginFailures{windowStart: now}
+		a.failures[ip] = f
+	}
+	if now.Sub(f.windowStart) > loginFailureWindow {
+		f.count = 0
+		f.windowStart = now
+	}
+	f.count++
+	if f.count >= maxLoginFailures {
+		f.lockedUntil = now.Add(loginLockout)
+		f.count = 0
+		f.windowStart = f.lockedUntil
+	}
+}
+
+// ResetLoginFailures clears ip's failed logins after a successful one
+func (a *AuthManager) ResetLoginFailures(ip string) {
+	a.mu.Lock()
+	delete(a.failures, ip)
+	a.mu.Unlock()
+}
+
 func generateToken() string {
 	bytes := make([]byte, 32)
 	rand.Read(bytes)
@@ -104,6 +195,25 @@ func (a *AuthManager) RequireAuth(next echo.HandlerFunc) echo.HandlerFunc {
 	}
 }
 
+// RequireAPIAuth is RequireAuth that also accepts "Authorization: Bearer
+// <key>" with a valid API key. A bad key gets a 401 rather than the login
+// redirect, since the caller is a script.
+func (a *AuthManager) RequireAPIAuth(next echo.HandlerFunc) echo.HandlerFunc {
+	withSession := a.RequireAuth(next)
+	return func(c echo.Context) error {
+		key, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+		if !ok {
+			return withSession(c)
+		}
+		if !a.ValidateAPIKey(strings.TrimSpace(key)) {
+			return c.JSON(http.StatusUnauthorized, APIResponse{Error: "Invalid API key"})
+		}
+
+		c.Set("username", "api-key")
+		return next(c)
+	}
+}
+
 // Handlers
 func (s *Server) HandleLogin(c echo.Context) error {
 	if c.Request().Method == http.MethodGet {
@@ -114,13 +224,25 @@ func (s *Server) HandleLogin(c echo.Context) error {
 		})
 	}
 
-	// POST - process login
+	// POST - process login, unless this address has failed too often; even
+	// the right password waits out the lockout
+	ip := c.RealIP()
+	if wait := s.auth.LoginLockedOut(ip); wait > 0 {
+		c.Response().Header().Set("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+		return c.Render(http.StatusTooManyRequests, "login.html", map[string]interface{}{
+			"Title": "Login",
+			"Error": "Too many failed attempts; try again in a few minutes",
+		})
+	}
+
 	username := c.FormValue("username")
 	password := c.FormValue("password")
 
 	if !s.auth.ValidateUser(username, password) {
+		s.auth.RecordLoginFailure(ip)
 		return c.Redirect(http.StatusSeeOther, s.BasePath()+"/login?error=Invalid+credentials")
 	}
+	s.auth.ResetLoginFailures(ip)
 
 	// Create session
 	token := s.auth.CreateSession(username)
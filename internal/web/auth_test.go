@@ -1,11 +1,11 @@
 package web
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
-	"os"
-	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -219,10 +219,7 @@ func TestRequireAuthMiddleware(t *testing.T) {
 }
 
 func setupTestServerWithAuth(t *testing.T) (*Server, storage.Storage) {
-	tmpDir := t.TempDir()
-	dbPath := filepath.Join(tmpDir, "test.db")
-
-	store, err := storage.NewSQLiteStorage(dbPath)
+	store, err := storage.NewMemoryStorage()
 	if err != nil {
 		t.Fatalf("failed to create storage: %v", err)
 	}
@@ -239,7 +236,6 @@ func setupTestServerWithAuth(t *testing.T) (*Server, storage.Storage) {
 
 	t.Cleanup(func() {
 		store.Close()
-		os.Remove(dbPath)
 	})
 
 	return server, store
@@ -330,6 +326,94 @@ func TestHandleLoginPostInvalid(t *testing.T) {
 	}
 }
 
+func TestLoginFailureLockout(t *testing.T) {
+	auth := NewAuthManager(map[string]string{"admin": "secret"}, "")
+
+	for i := 0; i < maxLoginFailures-1; i++ {
+		auth.RecordLoginFailure("10.0.0.1")
+	}
+	if auth.LoginLockedOut("10.0.0.1") != 0 {
+		t.Fatal("expected no lockout below the limit")
+	}
+
+	// A success clears the count
+	auth.ResetLoginFailures("10.0.0.1")
+	auth.RecordLoginFailure("10.0.0.1")
+	if auth.LoginLockedOut("10.0.0.1") != 0 {
+		t.Fatal("expected the count reset by a successful login")
+	}
+
+	// Failures outside the window don't add up
+	auth.failures["10.0.0.1"].count = maxLoginFailures - 1
+	auth.failures["10.0.0.1"].windowStart = time.Now().Add(-2 * loginFailureWindow)
+	auth.RecordLoginFailure("10.0.0.1")
+	if auth.LoginLockedOut("10.0.0.1") != 0 {
+		t.Fatal("expected old failures forgotten")
+	}
+
+	for i := 0; i < maxLoginFailures; i++ {
+		auth.RecordLoginFailure("10.0.0.2")
+	}
+	if wait := auth.LoginLockedOut("10.0.0.2"); wait <= 0 || wait > loginLockout {
+		t.Errorf("expected a lockout of up to %v, got %v", loginLockout, wait)
+	}
+	if auth.LoginLockedOut("10.0.0.3") != 0 {
+		t.Error("expected other addresses unaffected")
+	}
+
+	// The lockout ends on its own
+	auth.failures["10.0.0.2"].lockedUntil = time.Now().Add(-time.Second)
+	if auth.LoginLockedOut("10.0.0.2") != 0 {
+		t.Error("expected the lockout expired")
+	}
+}
+
+func TestHandleLoginRateLimited(t *testing.T) {
+	server, _ := setupTestServerWithAuth(t)
+
+	login := func(password string) *httptest.ResponseRecorder {
+		form := url.Values{}
+		form.Add("username", "admin")
+		form.Add("password", password)
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.RemoteAddr = "203.0.113.7:4321"
+		rec := httptest.NewRecorder()
+		server.echo.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := 0; i < maxLoginFailures; i++ {
+		if rec := login("wrongpassword"); rec.Code != http.StatusSeeOther {
+			t.Fatalf("attempt %d: expected redirect 303, got %d", i+1, rec.Code)
+		}
+	}
+
+	// Locked out, even with the right password
+	rec := login("admin123")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status 429, got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("expected a Retry-After header")
+	}
+	if !strings.Contains(rec.Body.String(), "Too many failed attempts") {
+		t.Error("expected the lockout explained on the login page")
+	}
+
+	// A spoofed X-Forwarded-For from a public address doesn't get around it
+	form := url.Values{"username": {"admin"}, "password": {"admin123"}}
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+	req.RemoteAddr = "203.0.113.7:4321"
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("expected status 429 despite X-Forwarded-For, got %d", rec.Code)
+	}
+}
+
 func TestHandleLogoutWithSession(t *testing.T) {
 	server, _ := setupTestServerWithAuth(t)
 
@@ -415,6 +499,146 @@ func TestProtectedRouteWithValidAuth(t *testing.T) {
 	}
 }
 
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestValidateAPIKey(t *testing.T) {
+	auth := NewAuthManager(map[string]string{"admin": "pass"}, "")
+	auth.apiKeys = []string{hashAPIKey("first-key"), strings.ToUpper(hashAPIKey("second-key"))}
+
+	if !auth.ValidateAPIKey("first-key") || !auth.ValidateAPIKey("second-key") {
+		t.Error("expected configured keys to be valid")
+	}
+	if auth.ValidateAPIKey("other-key") {
+		t.Error("expected an unknown key to be rejected")
+	}
+	if auth.ValidateAPIKey(hashAPIKey("first-key")) {
+		t.Error("expected the digest itself to be rejected")
+	}
+	if auth.ValidateAPIKey("") {
+		t.Error("expected an empty key to be rejected")
+	}
+}
+
+func TestAPIKeyAuth(t *testing.T) {
+	store, err := storage.NewMemoryStorage()
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	cfg := &config.ServerConfig{Host: "localhost", Port: 3000, APIKeys: []string{hashAPIKey("automation-key")}}
+	server := NewServer(cfg, nil, store, nil, nil, nil, nil)
+	if server.auth == nil {
+		t.Fatal("expected API keys alone to enable auth")
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		want   int
+	}{
+		{"valid key", "/api/checks", "Bearer automation-key", http.StatusOK},
+		{"invalid key", "/api/checks", "Bearer wrong-key", http.StatusUnauthorized},
+		{"no key", "/api/checks", "", http.StatusSeeOther},
+		{"pages need a session", "/", "Bearer automation-key", http.StatusSeeOther},
+		{"status json is public", "/api/status/missing", "", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			server.echo.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, rec.Code)
+			}
+		})
+	}
+}
+
+func TestAPICORS(t *testing.T) {
+	store, err := storage.NewMemoryStorage()
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	sum := sha256.Sum256([]byte("frontend-key"))
+	cfg := &config.ServerConfig{
+		Users:          map[string]string{"admin": "admin123"},
+		APIKeys:        []string{hex.EncodeToString(sum[:])},
+		AllowedOrigins: []string{"https://app.example.com"},
+	}
+	server := NewServer(cfg, nil, store, nil, cfg.Users, nil, nil)
+
+	// The preflight is answered without credentials
+	req := httptest.NewRequest(http.MethodOptions, "/api/checks/1", nil)
+	req.Header.Set(echo.HeaderOrigin, "https://app.example.com")
+	req.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodPut)
+	req.Header.Set(echo.HeaderAccessControlRequestHeaders, "Authorization, Content-Type")
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", rec.Code)
+	}
+	if got := rec.Header().Get(echo.HeaderAccessControlAllowOrigin); got != "https://app.example.com" {
+		t.Errorf("expected the origin allowed, got %q", got)
+	}
+	if !strings.Contains(rec.Header().Get(echo.HeaderAccessControlAllowMethods), http.MethodPut) {
+		t.Errorf("expected PUT allowed, got %q", rec.Header().Get(echo.HeaderAccessControlAllowMethods))
+	}
+	if !strings.Contains(rec.Header().Get(echo.HeaderAccessControlAllowHeaders), "Authorization") {
+		t.Errorf("expected Authorization allowed, got %q", rec.Header().Get(echo.HeaderAccessControlAllowHeaders))
+	}
+
+	// The request itself still needs a key
+	req = httptest.NewRequest(http.MethodGet, "/api/checks", nil)
+	req.Header.Set(echo.HeaderOrigin, "https://app.example.com")
+	req.Header.Set(echo.HeaderAuthorization, "Bearer frontend-key")
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if got := rec.Header().Get(echo.HeaderAccessControlAllowOrigin); got != "https://app.example.com" {
+		t.Errorf("expected the origin allowed, got %q", got)
+	}
+
+	// Other origins get nothing
+	req = httptest.NewRequest(http.MethodGet, "/api/checks", nil)
+	req.Header.Set(echo.HeaderOrigin, "https://evil.example.com")
+	req.Header.Set(echo.HeaderAuthorization, "Bearer frontend-key")
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get(echo.HeaderAccessControlAllowOrigin); got != "" {
+		t.Errorf("expected no CORS header for another origin, got %q", got)
+	}
+}
+
+func TestAPINoCORSByDefault(t *testing.T) {
+	server, _ := setupTestServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/checks", nil)
+	req.Header.Set(echo.HeaderOrigin, "https://app.example.com")
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get(echo.HeaderAccessControlAllowOrigin); got != "" {
+		t.Errorf("expected no CORS header without allowed_origins, got %q", got)
+	}
+}
+
 func TestSessionStructure(t *testing.T) {
 	session := &Session{
 		Username:  "testuser",
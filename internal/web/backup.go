@@ -0,0 +1,33 @@
+package web
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HandleBackup downloads a consistent copy of the database, taken while
+// checks keep running. The copy holds every check's URL and headers and the
+// probes' keys, so it's only served behind a login or API key.
+func (s *Server) HandleBackup(c echo.Context) error {
+	if s.auth == nil {
+		return c.JSON(http.StatusForbidden, APIResponse{Error: "Backups need server.users or server.api_keys configured"})
+	}
+
+	dir, err := os.MkdirTemp("", "sentinel-backup-")
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "sentinel.db")
+	if err := s.storage.Backup(path); err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+
+	name := "sentinel-backup-" + time.Now().UTC().Format("20060102-150405") + ".db"
+	return c.Attachment(path, name)
+}
@@ -0,0 +1,61 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+func TestHandleBackup(t *testing.T) {
+	server, store := setupTestServerWithAuth(t)
+	store.CreateCheck(&storage.Check{Name: "Backed Up", URL: "https://backup.example.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true})
+
+	// Signed out, there's no download
+	req := httptest.NewRequest(http.MethodGet, "/api/backup", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+	if rec.Code == http.StatusOK {
+		t.Fatal("expected the backup to need a login")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/backup", nil)
+	req.AddCookie(&http.Cookie{Name: "sentinel_session", Value: server.auth.CreateSession("admin")})
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if cd := rec.Header().Get("Content-Disposition"); !strings.Contains(cd, "attachment") || !strings.Contains(cd, ".db") {
+		t.Errorf("expected a database attachment, got %q", cd)
+	}
+
+	// The download is a working database
+	path := filepath.Join(t.TempDir(), "downloaded.db")
+	if err := os.WriteFile(path, rec.Body.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := storage.NewSQLiteStorage(path)
+	if err != nil {
+		t.Fatalf("failed to open the backup: %v", err)
+	}
+	defer restored.Close()
+	if checks, _ := restored.ListChecks(); len(checks) != 1 || checks[0].Name != "Backed Up" {
+		t.Errorf("expected the check in the backup, got %v", checks)
+	}
+}
+
+func TestHandleBackupWithoutAuth(t *testing.T) {
+	server, _ := setupTestServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/backup", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected status 403 without auth configured, got %d", rec.Code)
+	}
+}
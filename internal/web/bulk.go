@@ -0,0 +1,129 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+// bulkOutcome is what a bulk action returns: the results that finished before
+// the deadline, plus a note when some did not
+type bulkOutcome struct {
+	Results   []map[string]interface{} `json:"results"`
+	Total     int                      `json:"total"`
+	Completed int                      `json:"completed"`
+	Note      string                   `json:"note,omitempty"`
+}
+
+// bulkWriteGrace is how long a bulk response has to be written once its
+// deadline has passed
+const bulkWriteGrace = 10 * time.Second
+
+// runBulk applies fn to each check, at most bulk_concurrency at a time, and
+// stops waiting once bulk_timeout has passed. Results keep the input order.
+func (s *Server) runBulk(c echo.Context, checks []*storage.Check, fn func(*storage.Check) map[string]interface{}) (*bulkOutcome, error) {
+	timeout := s.config.GetBulkTimeout()
+	ctx, cancel := context.WithTimeout(c.Request().Context(), timeout)
+	defer cancel()
+
+	// bulk_timeout may outlast the server's write timeout; the partial
+	// results are only any use if the response still gets out
+	deadline := time.Now().Add(timeout + bulkWriteGrace)
+	if err := http.NewResponseController(c.Response()).SetWriteDeadline(deadline); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return nil, err
+	}
+
+	type indexed struct {
+		index  int
+		result map[string]interface{}
+	}
+
+	// Buffered so stragglers finishing after the deadline never block
+	done := make(chan indexed, len(checks))
+	sem := make(chan struct{}, s.config.GetBulkConcurrency())
+
+	go func() {
+		for i, check := range checks {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			go func(i int, check *storage.Check) {
+				defer func() { <-sem }()
+				done <- indexed{index: i, result: fn(check)}
+			}(i, check)
+		}
+	}()
+
+	slots := make([]map[string]interface{}, len(checks))
+	completed := 0
+wait:
+	for completed < len(checks) {
+		select {
+		case r := <-done:
+			slots[r.index] = r.result
+			completed++
+		case <-ctx.Done():
+			break wait
+		}
+	}
+
+	outcome := &bulkOutcome{
+		Results:   make([]map[string]interface{}, 0, completed),
+		Total:     len(checks),
+		Completed: completed,
+	}
+	for _, r := range slots {
+		if r != nil {
+			outcome.Results = append(outcome.Results, r)
+		}
+	}
+	if completed < len(checks) {
+		outcome.Note = fmt.Sprintf("deadline of %s reached: %d of %d checks did not finish", timeout, len(checks)-completed, len(checks))
+	}
+
+	return outcome, nil
+}
+
+func (s *Server) HandleTriggerAll(c echo.Context) error {
+	if s.scheduler == nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: "Scheduler not available"})
+	}
+
+	enabled, err := s.storage.ListEnabledChecks()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+
+	var checks []*storage.Check
+	for _, check := range enabled {
+		if !check.IsPaused(time.Now()) && check.Type != storage.CheckTypeHeartbeat {
+			checks = append(checks, check)
+		}
+	}
+
+	outcome, err := s.runBulk(c, checks, func(check *storage.Check) map[string]interface{} {
+		result := map[string]interface{}{}
+		resp, err := s.scheduler.TriggerCheck(check.ID)
+		if err != nil {
+			result["error"] = err.Error()
+		} else {
+			result = triggerResult(resp, check)
+		}
+		result["check_id"] = check.ID
+		result["name"] = check.Name
+		return result
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, APIResponse{Data: outcome})
+}
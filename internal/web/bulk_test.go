@@ -0,0 +1,170 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/katieblackabee/sentinel/internal/checker"
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+type bulkResponse struct {
+	Data  bulkOutcome `json:"data"`
+	Error string      `json:"error"`
+}
+
+func TestAPITriggerAll(t *testing.T) {
+	server, store := setupTestServer(t)
+	server.scheduler = checker.NewScheduler(store, nil, checker.SchedulerConfig{})
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	for _, name := range []string{"One", "Two", "Three"} {
+		store.CreateCheck(&storage.Check{Name: name, URL: target.URL + "/" + name, IntervalSecs: 60, TimeoutSecs: 5, ExpectedStatus: 200, Enabled: true})
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/checks/trigger-all", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp bulkResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	if resp.Data.Total != 3 || resp.Data.Completed != 3 {
+		t.Errorf("expected 3 of 3 completed, got %d of %d", resp.Data.Completed, resp.Data.Total)
+	}
+	if resp.Data.Note != "" {
+		t.Errorf("expected no note, got %q", resp.Data.Note)
+	}
+	if len(resp.Data.Results) != 3 || resp.Data.Results[0]["name"] != "One" {
+		t.Errorf("expected results in check order, got %v", resp.Data.Results)
+	}
+	for _, r := range resp.Data.Results {
+		if r["status"] != "up" {
+			t.Errorf("expected %v to be up, got %v", r["name"], r["status"])
+		}
+	}
+}
+
+func TestAPITriggerAllAnyStatus(t *testing.T) {
+	server, store := setupTestServer(t)
+	server.scheduler = checker.NewScheduler(store, nil, checker.SchedulerConfig{})
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer target.Close()
+
+	store.CreateCheck(&storage.Check{Name: "Reachable", URL: target.URL, IntervalSecs: 60, TimeoutSecs: 5, ExpectedStatus: storage.AnyStatus, Enabled: true})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/checks/trigger-all", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	var resp bulkResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(resp.Data.Results) != 1 || resp.Data.Results[0]["status"] != "up" {
+		t.Errorf("expected a 500 to count as up, got %v", resp.Data.Results)
+	}
+
+	latest, _ := store.GetLatestResult(1)
+	if latest == nil || latest.Status != "up" {
+		t.Errorf("expected stored result up, got %+v", latest)
+	}
+}
+
+func TestAPITriggerAllDeadline(t *testing.T) {
+	server, store := setupTestServer(t)
+	server.scheduler = checker.NewScheduler(store, nil, checker.SchedulerConfig{})
+	server.config.BulkConcurrency = 1
+	server.config.BulkTimeout = "300ms"
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	for _, name := range []string{"One", "Two", "Three"} {
+		store.CreateCheck(&storage.Check{Name: name, URL: target.URL, IntervalSecs: 60, TimeoutSecs: 5, ExpectedStatus: 200, Enabled: true})
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/checks/trigger-all", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	var resp bulkResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	if resp.Data.Completed != 1 {
+		t.Errorf("expected 1 check to finish before the deadline, got %d", resp.Data.Completed)
+	}
+	if len(resp.Data.Results) != resp.Data.Completed {
+		t.Errorf("expected %d results, got %d", resp.Data.Completed, len(resp.Data.Results))
+	}
+	if resp.Data.Note == "" {
+		t.Error("expected a note about the deadline")
+	}
+}
+
+func TestAPITriggerAllOutlastsWriteTimeout(t *testing.T) {
+	server, store := setupTestServer(t)
+	server.scheduler = checker.NewScheduler(store, nil, checker.SchedulerConfig{})
+	server.config.BulkTimeout = "1s"
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(300 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+	store.CreateCheck(&storage.Check{Name: "Slow", URL: target.URL, IntervalSecs: 60, TimeoutSecs: 5, ExpectedStatus: 200, Enabled: true})
+
+	// A write timeout shorter than the action, as the real server's 30s is
+	// shorter than the default bulk_timeout
+	ts := httptest.NewUnstartedServer(server.echo)
+	ts.Config.WriteTimeout = 100 * time.Millisecond
+	ts.Start()
+	defer ts.Close()
+
+	res, err := http.Post(ts.URL+"/api/checks/trigger-all", "application/json", nil)
+	if err != nil {
+		t.Fatalf("expected the response to get out past the write timeout, got %v", err)
+	}
+	defer res.Body.Close()
+
+	var resp bulkResponse
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.Data.Completed != 1 {
+		t.Errorf("expected the check to finish, got %d", resp.Data.Completed)
+	}
+}
+
+func TestAPITriggerAllNoScheduler(t *testing.T) {
+	server, _ := setupTestServer(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/checks/trigger-all", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500 without scheduler, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,69 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+// checkDiagnostics is everything known about a check's recent executions in
+// one response: its current config, the golden body it is diffed against,
+// and the last N results with every stored detail
+type checkDiagnostics struct {
+	Check   *storage.Check          `json:"check"`
+	Golden  *storage.GoldenSnapshot `json:"golden,omitempty"`
+	Results []*storage.CheckResult  `json:"results"`
+}
+
+func (s *Server) HandleGetCheckDiagnostics(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid check ID"})
+	}
+
+	n := 20
+	if v := c.QueryParam("n"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > 1000 {
+			return c.JSON(http.StatusBadRequest, APIResponse{Error: "n must be between 1 and 1000"})
+		}
+		n = parsed
+	}
+
+	check, err := s.storage.GetCheck(id)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+	if check == nil {
+		return c.JSON(http.StatusNotFound, APIResponse{Error: "Check not found"})
+	}
+
+	results, err := s.storage.GetResultDetails(id, n)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+	if results == nil {
+		results = []*storage.CheckResult{}
+	}
+
+	diag := &checkDiagnostics{Check: check, Results: results}
+	if len(results) > 0 {
+		check.Status = results[0].Status
+		check.LastResponseMs = results[0].ResponseTimeMs
+		check.LastCheckedAt = &results[0].CheckedAt
+	} else {
+		check.Status = "pending"
+	}
+	storage.SettleStatus(s.storage, check)
+
+	golden, err := s.storage.GetGoldenSnapshot(id)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+	diag.Golden = golden
+
+	return c.JSON(http.StatusOK, APIResponse{Data: diag})
+}
@@ -0,0 +1,84 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+type diagnosticsResponse struct {
+	Data  checkDiagnostics `json:"data"`
+	Error string           `json:"error"`
+}
+
+func TestAPICheckDiagnostics(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	check := &storage.Check{Name: "Flaky", URL: "https://flaky.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(check)
+	store.SaveGoldenSnapshot(&storage.GoldenSnapshot{CheckID: check.ID, Body: "ok"})
+
+	expires := time.Now().Add(30 * 24 * time.Hour)
+	for i := 0; i < 3; i++ {
+		store.SaveResult(&storage.CheckResult{CheckID: check.ID, Status: "up", StatusCode: 200, ResponseTimeMs: 100 + i, SSLExpiresAt: &expires, SSLDaysLeft: 30, SSLIssuer: "Test CA"})
+	}
+	store.SaveResult(&storage.CheckResult{CheckID: check.ID, Status: "down", StatusCode: 502, ErrorMessage: "bad gateway"})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/checks/1/diagnostics?n=2", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp diagnosticsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	if resp.Data.Check == nil || resp.Data.Check.URL != "https://flaky.com" || resp.Data.Check.Status != "down" {
+		t.Errorf("expected current check config and status, got %+v", resp.Data.Check)
+	}
+	if resp.Data.Golden == nil || resp.Data.Golden.Body != "ok" {
+		t.Errorf("expected golden snapshot, got %+v", resp.Data.Golden)
+	}
+	if len(resp.Data.Results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(resp.Data.Results))
+	}
+	if resp.Data.Results[0].ErrorMessage != "bad gateway" {
+		t.Errorf("expected newest result first, got %+v", resp.Data.Results[0])
+	}
+	if resp.Data.Results[1].SSLIssuer != "Test CA" || resp.Data.Results[1].SSLExpiresAt == nil {
+		t.Errorf("expected SSL details, got %+v", resp.Data.Results[1])
+	}
+}
+
+func TestAPICheckDiagnosticsErrors(t *testing.T) {
+	server, store := setupTestServer(t)
+	store.CreateCheck(&storage.Check{Name: "Quiet", URL: "https://quiet.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true})
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/api/checks/1/diagnostics", http.StatusOK},
+		{"/api/checks/999/diagnostics", http.StatusNotFound},
+		{"/api/checks/invalid/diagnostics", http.StatusBadRequest},
+		{"/api/checks/1/diagnostics?n=0", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		rec := httptest.NewRecorder()
+		server.echo.ServeHTTP(rec, req)
+
+		if rec.Code != tt.want {
+			t.Errorf("%s: expected status %d, got %d", tt.path, tt.want, rec.Code)
+		}
+	}
+}
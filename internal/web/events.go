@@ -0,0 +1,157 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+// StatusEvent is one check status change on the /events stream
+type StatusEvent struct {
+	CheckID        int64     `json:"check_id"`
+	CheckName      string    `json:"check_name"`
+	From           string    `json:"from"`
+	To             string    `json:"to"`
+	ResponseTimeMs int       `json:"response_time_ms"`
+	Error          string    `json:"error,omitempty"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+// eventBuffer is how far a slow client may fall behind before it misses events
+const eventBuffer = 16
+
+// eventsHeartbeat keeps idle streams open through proxies that drop silent
+// connections
+const eventsHeartbeat = 30 * time.Second
+
+// EventBroker fans check status changes out to /events subscribers. It is a
+// checker.EventSink, so the scheduler publishes to it as results come in.
+type EventBroker struct {
+	mu      sync.Mutex
+	clients map[chan StatusEvent]struct{}
+	closed  bool
+}
+
+func NewEventBroker() *EventBroker {
+	return &EventBroker{clients: make(map[chan StatusEvent]struct{})}
+}
+
+// SendStateChange publishes a transition without blocking the scheduler; a
+// client whose buffer is full misses it and catches up on its next page load
+func (b *EventBroker) SendStateChange(check *storage.Check, from, to string, result *storage.CheckResult) error {
+	event := StatusEvent{
+		CheckID:        check.ID,
+		CheckName:      check.Name,
+		From:           from,
+		To:             to,
+		ResponseTimeMs: result.ResponseTimeMs,
+		Error:          result.ErrorMessage,
+		Timestamp:      time.Now(),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.clients {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+	return nil
+}
+
+// subscribe returns a channel of events, closed when the broker is
+func (b *EventBroker) subscribe() chan StatusEvent {
+	ch := make(chan StatusEvent, eventBuffer)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(ch)
+		return ch
+	}
+	b.clients[ch] = struct{}{}
+	return ch
+}
+
+func (b *EventBroker) unsubscribe(ch chan StatusEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.clients[ch]; ok {
+		delete(b.clients, ch)
+		close(ch)
+	}
+}
+
+// subscribers counts the open streams
+func (b *EventBroker) subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.clients)
+}
+
+// Close ends every stream, so shutdown doesn't wait on them
+func (b *EventBroker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for ch := range b.clients {
+		delete(b.clients, ch)
+		close(ch)
+	}
+}
+
+// HandleEvents streams check status changes as server-sent events until the
+// client disconnects or the server shuts down
+func (s *Server) HandleEvents(c echo.Context) error {
+	w := c.Response()
+
+	// A stream outlives the server's write timeout
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+
+	events := s.events.subscribe()
+	defer s.events.unsubscribe(events)
+
+	w.Header().Set(echo.HeaderContentType, "text/event-stream")
+	w.Header().Set(echo.HeaderCacheControl, "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // nginx would otherwise hold events back
+	w.WriteHeader(http.StatusOK)
+	w.Flush()
+
+	heartbeat := time.NewTicker(eventsHeartbeat)
+	defer heartbeat.Stop()
+
+	ctx := c.Request().Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(w, "event: status\ndata: %s\n\n", data); err != nil {
+				return nil
+			}
+			w.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return nil
+			}
+			w.Flush()
+		}
+	}
+}
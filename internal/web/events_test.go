@@ -0,0 +1,88 @@
+package web
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+// waitFor polls cond until it holds or a second passes
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestHandleEventsStreamsStatusChanges(t *testing.T) {
+	server, _ := setupTestServer(t)
+	ts := httptest.NewServer(server.echo)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/events")
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("expected an event stream, got %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	waitFor(t, func() bool { return server.events.subscribers() == 1 })
+
+	check := &storage.Check{ID: 7, Name: "API"}
+	server.events.SendStateChange(check, "up", "down", &storage.CheckResult{ResponseTimeMs: 120, ErrorMessage: "timeout"})
+
+	reader := bufio.NewReader(resp.Body)
+	line, _ := reader.ReadString('\n')
+	if line != "event: status\n" {
+		t.Fatalf("expected a status event, got %q", line)
+	}
+	line, _ = reader.ReadString('\n')
+	var event StatusEvent
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event); err != nil {
+		t.Fatalf("failed to parse event %q: %v", line, err)
+	}
+	if event.CheckID != 7 || event.From != "up" || event.To != "down" || event.Error != "timeout" {
+		t.Errorf("unexpected event %+v", event)
+	}
+
+	// Disconnecting unsubscribes the stream
+	resp.Body.Close()
+	waitFor(t, func() bool { return server.events.subscribers() == 0 })
+}
+
+func TestHandleEventsRequiresAuth(t *testing.T) {
+	server, _ := setupTestServerWithAuth(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusSeeOther {
+		t.Errorf("expected redirect to login, got %d", rec.Code)
+	}
+}
+
+func TestEventBrokerClose(t *testing.T) {
+	broker := NewEventBroker()
+	events := broker.subscribe()
+
+	broker.Close()
+	if _, ok := <-events; ok {
+		t.Error("expected the stream to close with the broker")
+	}
+	broker.unsubscribe(events) // no double close
+
+	if _, ok := <-broker.subscribe(); ok {
+		t.Error("expected subscribing to a closed broker to end at once")
+	}
+}
@@ -0,0 +1,155 @@
+package web
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/katieblackabee/sentinel/internal/config"
+	"github.com/katieblackabee/sentinel/internal/storage"
+	"github.com/labstack/echo/v4"
+)
+
+// feedIncidents caps how many incidents a status page feed lists
+const feedIncidents = 50
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title     string   `xml:"title"`
+	ID        string   `xml:"id"`
+	Published string   `xml:"published"`
+	Updated   string   `xml:"updated"`
+	Link      atomLink `xml:"link"`
+	Content   atomText `xml:"content"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// handleStatusFeed serves a status page's incidents as an Atom feed, newest
+// first, for feed readers. An incident's entry is updated when it resolves.
+func (s *Server) handleStatusFeed(c echo.Context) error {
+	slug := c.Param("slug")
+	checks, err := s.storage.ListChecksByTag(slug)
+	if err != nil || len(checks) == 0 {
+		return c.String(http.StatusNotFound, "Status page not found")
+	}
+
+	var incidents []*storage.Incident
+	for _, check := range checks {
+		forCheck, _ := s.storage.ListIncidentsForCheck(check.ID, feedIncidents)
+		incidents = append(incidents, forCheck...)
+	}
+	slices.SortFunc(incidents, func(a, b *storage.Incident) int {
+		return b.StartedAt.Compare(a.StartedAt)
+	})
+	if len(incidents) > feedIncidents {
+		incidents = incidents[:feedIncidents]
+	}
+
+	var branding config.Branding
+	if s.fullConfig != nil {
+		branding = s.fullConfig.StatusPage.BrandingFor(slug)
+	}
+	title := branding.Title
+	if title == "" {
+		title = slug + " Status"
+	}
+
+	pageURL := c.Scheme() + "://" + c.Request().Host + s.BasePath() + "/status/" + slug
+	feed := atomFeed{
+		Title:  title + " Incidents",
+		ID:     pageURL + "/feed.xml",
+		Author: atomAuthor{Name: title},
+		Links: []atomLink{
+			{Href: pageURL + "/feed.xml", Rel: "self", Type: "application/atom+xml"},
+			{Href: pageURL, Rel: "alternate", Type: "text/html"},
+		},
+	}
+
+	var updated time.Time
+	for _, incident := range incidents {
+		entryUpdated := incident.StartedAt
+		if incident.EndedAt != nil {
+			entryUpdated = *incident.EndedAt
+		}
+		if entryUpdated.After(updated) {
+			updated = entryUpdated
+		}
+		feed.Entries = append(feed.Entries, atomEntry{
+			Title:     feedEntryTitle(incident),
+			ID:        fmt.Sprintf("%s#incident-%d", pageURL, incident.ID),
+			Published: incident.StartedAt.UTC().Format(time.RFC3339),
+			Updated:   entryUpdated.UTC().Format(time.RFC3339),
+			Link:      atomLink{Href: pageURL, Rel: "alternate"},
+			Content:   atomText{Type: "text", Body: feedEntryContent(incident)},
+		})
+	}
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+	feed.Updated = updated.UTC().Format(time.RFC3339)
+
+	body, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to render feed")
+	}
+	return c.Blob(http.StatusOK, "application/atom+xml; charset=utf-8", append([]byte(xml.Header), body...))
+}
+
+func feedEntryTitle(incident *storage.Incident) string {
+	name := incident.Title
+	if name == "" {
+		name = incident.CheckName + " outage"
+	}
+	if incident.IsActive() {
+		return name + " (ongoing)"
+	}
+	return name + " (resolved)"
+}
+
+// feedEntryContent lists what the status page shows for an incident, one
+// detail per line
+func feedEntryContent(incident *storage.Incident) string {
+	lines := []string{
+		"Check: " + incident.CheckName,
+		"Started: " + incident.StartedAt.UTC().Format(time.RFC1123),
+	}
+	if incident.IsActive() {
+		lines = append(lines, "Status: Ongoing ("+incident.StatusString()+")")
+	} else {
+		lines = append(lines,
+			"Resolved: "+incident.EndedAt.UTC().Format(time.RFC1123),
+			"Duration: "+incident.DurationString(),
+			"Status: Resolved",
+		)
+	}
+	if incident.Cause != "" {
+		lines = append(lines, "Cause: "+incident.Cause)
+	}
+	return strings.Join(lines, "\n")
+}
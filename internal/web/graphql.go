@@ -0,0 +1,183 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+// maxGraphQLBody bounds a POSTed query; real ones are a few KB at most
+const maxGraphQLBody = 64 << 10
+
+// graphQLRequest is a query sent as a POST body, or as ?query= and
+// ?variables= on a GET
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type graphQLResponse struct {
+	Data   *gqlMap    `json:"data,omitempty"`
+	Errors []gqlError `json:"errors,omitempty"`
+}
+
+// HandleGraphQL answers queries over checks, their results and stats, and
+// incidents, so a dashboard can fetch them in one request instead of one per
+// check. Objects have the same fields as the REST API's JSON.
+func (s *Server) HandleGraphQL(c echo.Context) error {
+	var req graphQLRequest
+	if c.Request().Method == http.MethodGet {
+		req.Query = c.QueryParam("query")
+		if v := c.QueryParam("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				return c.JSON(http.StatusBadRequest, graphQLResponse{Errors: []gqlError{{Message: "invalid variables"}}})
+			}
+		}
+	} else if err := json.NewDecoder(http.MaxBytesReader(c.Response(), c.Request().Body, maxGraphQLBody)).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return c.JSON(http.StatusRequestEntityTooLarge, graphQLResponse{Errors: []gqlError{{Message: fmt.Sprintf("request body exceeds %d bytes", maxGraphQLBody)}}})
+		}
+		return c.JSON(http.StatusBadRequest, graphQLResponse{Errors: []gqlError{{Message: "invalid request body"}}})
+	}
+	if req.Query == "" {
+		return c.JSON(http.StatusBadRequest, graphQLResponse{Errors: []gqlError{{Message: "query is required"}}})
+	}
+
+	data, errs := executeGraphQL(s.graphQLSchema(), req.Query, req.Variables)
+	if data == nil {
+		return c.JSON(http.StatusBadRequest, graphQLResponse{Errors: errs})
+	}
+	return c.JSON(http.StatusOK, graphQLResponse{Data: data, Errors: errs})
+}
+
+// graphQLSchema builds the query type. Lists take the same limits as their
+// REST endpoints, and checks are enriched the same way as GET /api/checks.
+func (s *Server) graphQLSchema() *gqlObject {
+	stats := &gqlObject{name: "Stats"}
+	result := &gqlObject{name: "Result"}
+	note := &gqlObject{name: "Note"}
+	check := &gqlObject{name: "Check"}
+	incident := &gqlObject{name: "Incident"}
+
+	check.fields = map[string]*gqlField{
+		"results": {typ: result, resolve: func(parent interface{}, args gqlArgs) (interface{}, error) {
+			limit, offset, err := pageArgs(args, 50, 1000)
+			if err != nil {
+				return nil, err
+			}
+			return s.storage.GetResults(parent.(*storage.Check).ID, limit, offset)
+		}},
+		"stats": {typ: stats, resolve: func(parent interface{}, args gqlArgs) (interface{}, error) {
+			return s.storage.GetStats(parent.(*storage.Check).ID)
+		}},
+		"incidents": {typ: incident, resolve: func(parent interface{}, args gqlArgs) (interface{}, error) {
+			limit, _, err := pageArgs(args, 20, 100)
+			if err != nil {
+				return nil, err
+			}
+			return s.storage.ListIncidentsForCheck(parent.(*storage.Check).ID, limit)
+		}},
+	}
+
+	incident.fields = map[string]*gqlField{
+		"check": {typ: check, resolve: func(parent interface{}, args gqlArgs) (interface{}, error) {
+			return s.graphQLCheck(parent.(*storage.Incident).CheckID)
+		}},
+		"notes": {typ: note, resolve: func(parent interface{}, args gqlArgs) (interface{}, error) {
+			i := parent.(*storage.Incident)
+			if i.Notes != nil {
+				return i.Notes, nil
+			}
+			return s.storage.GetIncidentNotes(i.ID)
+		}},
+	}
+
+	return &gqlObject{name: "Query", fields: map[string]*gqlField{
+		"checks": {typ: check, resolve: func(_ interface{}, args gqlArgs) (interface{}, error) {
+			status, err := args.string("status")
+			if err != nil {
+				return nil, err
+			}
+			if status != "" && !isCheckStatus(status) {
+				return nil, fmt.Errorf("invalid status %q", status)
+			}
+			checks, err := s.storage.ListChecks()
+			if err != nil {
+				return nil, err
+			}
+			if err := s.enrichChecks(checks); err != nil {
+				return nil, err
+			}
+			filtered := checks[:0]
+			for _, check := range checks {
+				if status == "" || check.Status == status {
+					filtered = append(filtered, check)
+				}
+			}
+			return filtered, nil
+		}},
+		"check": {typ: check, resolve: func(_ interface{}, args gqlArgs) (interface{}, error) {
+			id, err := args.id("id")
+			if err != nil {
+				return nil, err
+			}
+			return s.graphQLCheck(id)
+		}},
+		"incidents": {typ: incident, resolve: func(_ interface{}, args gqlArgs) (interface{}, error) {
+			limit, offset, err := pageArgs(args, 20, 100)
+			if err != nil {
+				return nil, err
+			}
+			return s.storage.ListIncidents(limit, offset)
+		}},
+		"activeIncidents": {typ: incident, resolve: func(_ interface{}, args gqlArgs) (interface{}, error) {
+			return s.storage.ListActiveIncidents()
+		}},
+		"incident": {typ: incident, resolve: func(_ interface{}, args gqlArgs) (interface{}, error) {
+			id, err := args.id("id")
+			if err != nil {
+				return nil, err
+			}
+			return s.storage.GetIncidentWithNotes(id)
+		}},
+	}}
+}
+
+// graphQLCheck returns one enriched check, or nil if it doesn't exist
+func (s *Server) graphQLCheck(id int64) (*storage.Check, error) {
+	check, err := s.storage.GetCheck(id)
+	if err != nil || check == nil {
+		return nil, err
+	}
+	if err := s.enrichChecks([]*storage.Check{check}); err != nil {
+		return nil, err
+	}
+	return check, nil
+}
+
+// pageArgs reads limit and offset arguments, rejecting values the REST
+// endpoints would ignore
+func pageArgs(args gqlArgs, defaultLimit, maxLimit int) (int, int, error) {
+	limit, err := args.int("limit", defaultLimit)
+	if err != nil {
+		return 0, 0, err
+	}
+	if limit <= 0 || limit > maxLimit {
+		return 0, 0, fmt.Errorf("limit must be between 1 and %d", maxLimit)
+	}
+	offset, err := args.int("offset", 0)
+	if err != nil {
+		return 0, 0, err
+	}
+	if offset < 0 {
+		return 0, 0, fmt.Errorf("offset must not be negative")
+	}
+	return limit, offset, nil
+}
@@ -0,0 +1,572 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// A small GraphQL executor: enough of the query language for clients to pick
+// the fields they want across nested objects in one request. Queries only;
+// fragments, directives and introspection are not supported.
+
+// The schema is cyclic (a check's incidents each have a check with
+// incidents), so queries are bounded: nesting by gqlMaxDepth while parsing,
+// and the fields resolved by gqlMaxFields while executing. A query over
+// either is refused rather than answered in part.
+const (
+	gqlMaxDepth  = 8
+	gqlMaxFields = 50000
+)
+
+// gqlSelection is one field in a selection set
+type gqlSelection struct {
+	alias      string
+	name       string
+	args       map[string]gqlValue
+	selections []*gqlSelection
+}
+
+func (f *gqlSelection) key() string {
+	if f.alias != "" {
+		return f.alias
+	}
+	return f.name
+}
+
+// gqlValue is an argument: a literal, or a variable resolved at execution
+type gqlValue struct {
+	variable string
+	literal  interface{}
+}
+
+// gqlObject is an object type. Fields with resolvers can take arguments and
+// return other objects; any other field is read from the resolved Go value
+// by its JSON name, so types expose the same fields as the REST API.
+type gqlObject struct {
+	name   string
+	fields map[string]*gqlField
+}
+
+type gqlField struct {
+	typ     *gqlObject // nil for scalars
+	resolve func(parent interface{}, args gqlArgs) (interface{}, error)
+}
+
+type gqlArgs map[string]interface{}
+
+// int returns an integer argument, or def when it is absent
+func (a gqlArgs) int(name string, def int) (int, error) {
+	switch v := a[name].(type) {
+	case nil:
+		return def, nil
+	case int64:
+		return int(v), nil
+	case float64:
+		if v == float64(int(v)) {
+			return int(v), nil
+		}
+	}
+	return 0, fmt.Errorf("argument %s must be an integer", name)
+}
+
+// id returns an ID argument, written as a string or a number
+func (a gqlArgs) id(name string) (int64, error) {
+	switch v := a[name].(type) {
+	case nil:
+		return 0, fmt.Errorf("argument %s is required", name)
+	case int64:
+		return v, nil
+	case float64:
+		return int64(v), nil
+	case string:
+		if id, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return id, nil
+		}
+	}
+	return 0, fmt.Errorf("argument %s must be an ID", name)
+}
+
+func (a gqlArgs) string(name string) (string, error) {
+	switch v := a[name].(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	}
+	return "", fmt.Errorf("argument %s must be a string", name)
+}
+
+type gqlError struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+// gqlMap is a result object, marshalled with its fields in query order
+type gqlMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (m *gqlMap) set(key string, value interface{}) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+func (m *gqlMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(k)
+		buf.Write(key)
+		buf.WriteByte(':')
+		value, err := json.Marshal(m.values[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+type gqlExecutor struct {
+	variables map[string]interface{}
+	errors    []gqlError
+	// fields counts the fields resolved so far, against gqlMaxFields
+	fields   int
+	exceeded bool
+}
+
+// executeGraphQL runs query against root and returns the data (nil when the
+// query couldn't be parsed or resolved too many fields) and any errors
+func executeGraphQL(root *gqlObject, query string, variables map[string]interface{}) (*gqlMap, []gqlError) {
+	selections, defaults, err := parseGraphQL(query)
+	if err != nil {
+		return nil, []gqlError{{Message: err.Error()}}
+	}
+	for name, value := range variables {
+		defaults[name] = value
+	}
+	e := &gqlExecutor{variables: defaults}
+	data := e.object(root, nil, selections, nil)
+	if e.exceeded {
+		return nil, []gqlError{{Message: fmt.Sprintf("query resolves more than %d fields", gqlMaxFields)}}
+	}
+	return data, e.errors
+}
+
+func (e *gqlExecutor) fail(path []interface{}, format string, args ...interface{}) {
+	e.errors = append(e.errors, gqlError{Message: fmt.Sprintf(format, args...), Path: append([]interface{}(nil), path...)})
+}
+
+func (e *gqlExecutor) object(typ *gqlObject, value interface{}, selections []*gqlSelection, path []interface{}) *gqlMap {
+	result := &gqlMap{values: make(map[string]interface{}, len(selections))}
+	for _, sel := range selections {
+		fieldPath := append(path, sel.key())
+		result.set(sel.key(), e.field(typ, value, sel, fieldPath))
+	}
+	return result
+}
+
+func (e *gqlExecutor) field(typ *gqlObject, parent interface{}, sel *gqlSelection, path []interface{}) interface{} {
+	// Once over the limit nothing more is resolved, so no more storage calls
+	if e.fields++; e.fields > gqlMaxFields {
+		e.exceeded = true
+	}
+	if e.exceeded {
+		return nil
+	}
+
+	if sel.name == "__typename" {
+		return typ.name
+	}
+
+	field, ok := typ.fields[sel.name]
+	if !ok {
+		value, found := jsonField(parent, sel.name)
+		if !found {
+			e.fail(path, "cannot query field %q on type %s", sel.name, typ.name)
+			return nil
+		}
+		if len(sel.selections) > 0 {
+			e.fail(path, "field %q on type %s has no subfields", sel.name, typ.name)
+			return nil
+		}
+		return value
+	}
+
+	args := make(gqlArgs, len(sel.args))
+	for name, arg := range sel.args {
+		if arg.variable != "" {
+			args[name] = e.variables[arg.variable]
+		} else {
+			args[name] = arg.literal
+		}
+	}
+
+	value, err := field.resolve(parent, args)
+	if err != nil {
+		e.fail(path, "%v", err)
+		return nil
+	}
+	if field.typ == nil {
+		return value
+	}
+	if len(sel.selections) == 0 {
+		e.fail(path, "field %q of type %s must have a selection of subfields", sel.name, field.typ.name)
+		return nil
+	}
+	return e.value(field.typ, value, sel.selections, path)
+}
+
+// value executes selections against an object, or each object in a list
+func (e *gqlExecutor) value(typ *gqlObject, value interface{}, selections []*gqlSelection, path []interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() || (v.Kind() == reflect.Pointer && v.IsNil()) {
+		return nil
+	}
+	if v.Kind() != reflect.Slice {
+		return e.object(typ, value, selections, path)
+	}
+
+	list := make([]interface{}, v.Len())
+	for i := range list {
+		list[i] = e.value(typ, v.Index(i).Interface(), selections, append(path, i))
+	}
+	return list
+}
+
+// jsonFieldIndex caches, per struct type, the field index for each JSON name
+var jsonFieldIndex sync.Map
+
+// jsonField reads the field of a struct (or pointer to one) that marshals
+// under name
+func jsonField(value interface{}, name string) (interface{}, bool) {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	index, ok := jsonFieldIndex.Load(v.Type())
+	if !ok {
+		fields := make(map[string]int)
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if f.IsExported() && tag != "" && tag != "-" {
+				fields[tag] = i
+			}
+		}
+		index, _ = jsonFieldIndex.LoadOrStore(v.Type(), fields)
+	}
+
+	i, ok := index.(map[string]int)[name]
+	if !ok {
+		return nil, false
+	}
+	return v.Field(i).Interface(), true
+}
+
+// gqlParser reads a query document into the selections of its operation
+type gqlParser struct {
+	src   string
+	pos   int
+	depth int // Selection sets open at pos
+}
+
+// parseGraphQL returns the operation's selections and its variables' defaults
+func parseGraphQL(query string) ([]*gqlSelection, map[string]interface{}, error) {
+	p := &gqlParser{src: query}
+	defaults := make(map[string]interface{})
+
+	if p.peek() != '{' {
+		keyword := p.name()
+		switch keyword {
+		case "query":
+		case "mutation", "subscription":
+			return nil, nil, fmt.Errorf("only queries are supported, not %s", keyword)
+		default:
+			return nil, nil, p.errorf("expected a query")
+		}
+		if isNameStart(p.peek()) {
+			p.name() // Operation name
+		}
+		if p.peek() == '(' {
+			if err := p.variableDefinitions(defaults); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	selections, err := p.selectionSet()
+	if err != nil {
+		return nil, nil, err
+	}
+	if p.skip(); p.pos < len(p.src) {
+		return nil, nil, p.errorf("only one operation per request is supported")
+	}
+	return selections, defaults, nil
+}
+
+func (p *gqlParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("syntax error at offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+// skip passes whitespace, commas and comments
+func (p *gqlParser) skip() {
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			p.pos++
+		case c == '#':
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *gqlParser) peek() byte {
+	p.skip()
+	if p.pos >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *gqlParser) expect(c byte) error {
+	if p.peek() != c {
+		return p.errorf("expected %q", c)
+	}
+	p.pos++
+	return nil
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func (p *gqlParser) name() string {
+	p.skip()
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if !isNameStart(c) && !(c >= '0' && c <= '9' && p.pos > start) {
+			break
+		}
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+// variableDefinitions reads ($id: ID!, $limit: Int = 10) into defaults.
+// Types are only checked for syntax; resolvers check the values they get.
+func (p *gqlParser) variableDefinitions(defaults map[string]interface{}) error {
+	p.pos++ // (
+	for p.peek() != ')' {
+		if err := p.expect('$'); err != nil {
+			return err
+		}
+		name := p.name()
+		if name == "" {
+			return p.errorf("expected a variable name")
+		}
+		if err := p.expect(':'); err != nil {
+			return err
+		}
+		if err := p.typeRef(); err != nil {
+			return err
+		}
+		if p.peek() == '=' {
+			p.pos++
+			value, err := p.value()
+			if err != nil {
+				return err
+			}
+			if value.variable != "" {
+				return p.errorf("variable default must be a constant")
+			}
+			defaults[name] = value.literal
+		}
+	}
+	p.pos++ // )
+	return nil
+}
+
+func (p *gqlParser) typeRef() error {
+	if p.peek() == '[' {
+		p.pos++
+		if err := p.typeRef(); err != nil {
+			return err
+		}
+		if err := p.expect(']'); err != nil {
+			return err
+		}
+	} else if p.name() == "" {
+		return p.errorf("expected a type")
+	}
+	if p.peek() == '!' {
+		p.pos++
+	}
+	return nil
+}
+
+func (p *gqlParser) selectionSet() ([]*gqlSelection, error) {
+	if err := p.expect('{'); err != nil {
+		return nil, err
+	}
+	if p.depth++; p.depth > gqlMaxDepth {
+		return nil, fmt.Errorf("query is nested more than %d levels deep", gqlMaxDepth)
+	}
+	defer func() { p.depth-- }()
+
+	var selections []*gqlSelection
+	for p.peek() != '}' {
+		switch p.peek() {
+		case 0:
+			return nil, p.errorf("unterminated selection set")
+		case '.':
+			return nil, p.errorf("fragments are not supported")
+		}
+
+		sel := &gqlSelection{name: p.name()}
+		if sel.name == "" {
+			return nil, p.errorf("expected a field name")
+		}
+		if p.peek() == ':' {
+			p.pos++
+			sel.alias, sel.name = sel.name, p.name()
+			if sel.name == "" {
+				return nil, p.errorf("expected a field name after alias %q", sel.alias)
+			}
+		}
+		if p.peek() == '(' {
+			args, err := p.arguments()
+			if err != nil {
+				return nil, err
+			}
+			sel.args = args
+		}
+		if p.peek() == '@' {
+			return nil, p.errorf("directives are not supported")
+		}
+		if p.peek() == '{' {
+			children, err := p.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+			sel.selections = children
+		}
+		selections = append(selections, sel)
+	}
+	p.pos++ // }
+
+	if len(selections) == 0 {
+		return nil, p.errorf("empty selection set")
+	}
+	return selections, nil
+}
+
+func (p *gqlParser) arguments() (map[string]gqlValue, error) {
+	p.pos++ // (
+	args := make(map[string]gqlValue)
+	for p.peek() != ')' {
+		name := p.name()
+		if name == "" {
+			return nil, p.errorf("expected an argument name")
+		}
+		if err := p.expect(':'); err != nil {
+			return nil, err
+		}
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		args[name] = value
+	}
+	p.pos++ // )
+	return args, nil
+}
+
+// value reads a scalar literal or a $variable. Enum values read as strings.
+func (p *gqlParser) value() (gqlValue, error) {
+	switch c := p.peek(); {
+	case c == '$':
+		p.pos++
+		name := p.name()
+		if name == "" {
+			return gqlValue{}, p.errorf("expected a variable name")
+		}
+		return gqlValue{variable: name}, nil
+	case c == '"':
+		return p.stringValue()
+	case c == '-' || (c >= '0' && c <= '9'):
+		start := p.pos
+		p.pos++
+		for p.pos < len(p.src) && strings.IndexByte("0123456789.eE+-", p.src[p.pos]) >= 0 {
+			p.pos++
+		}
+		text := p.src[start:p.pos]
+		if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return gqlValue{literal: n}, nil
+		}
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return gqlValue{}, p.errorf("invalid number %q", text)
+		}
+		return gqlValue{literal: f}, nil
+	case isNameStart(c):
+		switch name := p.name(); name {
+		case "true":
+			return gqlValue{literal: true}, nil
+		case "false":
+			return gqlValue{literal: false}, nil
+		case "null":
+			return gqlValue{}, nil
+		default:
+			return gqlValue{literal: name}, nil
+		}
+	default:
+		return gqlValue{}, p.errorf("expected a value")
+	}
+}
+
+func (p *gqlParser) stringValue() (gqlValue, error) {
+	start := p.pos
+	p.pos++ // "
+	for p.pos < len(p.src) && p.src[p.pos] != '"' {
+		if p.src[p.pos] == '\\' {
+			p.pos++
+		}
+		p.pos++
+	}
+	if p.pos >= len(p.src) {
+		return gqlValue{}, p.errorf("unterminated string")
+	}
+	p.pos++ // "
+
+	// GraphQL's escapes are JSON's
+	var s string
+	if err := json.Unmarshal([]byte(p.src[start:p.pos]), &s); err != nil {
+		return gqlValue{}, p.errorf("invalid string %s", p.src[start:p.pos])
+	}
+	return gqlValue{literal: s}, nil
+}
@@ -0,0 +1,248 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+func setupGraphQLServer(t *testing.T) (*Server, storage.Storage) {
+	server, store := setupTestServer(t)
+	server.config.GraphQL = true
+	server.echo = echo.New()
+	server.registerRoutes()
+	return server, store
+}
+
+type testGraphQLResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []gqlError             `json:"errors"`
+}
+
+func postGraphQL(t *testing.T, server *Server, body string) (int, testGraphQLResponse) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	var resp testGraphQLResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response %q: %v", rec.Body.String(), err)
+	}
+	return rec.Code, resp
+}
+
+func TestGraphQLDisabledByDefault(t *testing.T) {
+	server, _ := setupTestServer(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query":"{checks{id}}"}`))
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 without graphql enabled, got %d", rec.Code)
+	}
+}
+
+func TestGraphQLNestedQuery(t *testing.T) {
+	server, store := setupGraphQLServer(t)
+
+	check := &storage.Check{Name: "API", URL: "https://api.example.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(check)
+	for i := 0; i < 3; i++ {
+		store.SaveResult(&storage.CheckResult{CheckID: check.ID, Status: "up", StatusCode: 200, ResponseTimeMs: 100 + i, CheckedAt: time.Now().Add(time.Duration(i) * time.Second)})
+	}
+	incident := &storage.Incident{CheckID: check.ID, StartedAt: time.Now(), Cause: "Timeout"}
+	store.CreateIncident(incident)
+	store.AddIncidentNote(&storage.IncidentNote{IncidentID: incident.ID, Content: "Looking into it"})
+
+	code, resp := postGraphQL(t, server, `{"query":"{ checks { name status results(limit: 2) { status_code } stats { uptime_percent_24h } incidents { cause notes { content } check { name } } } }"}`)
+	if code != http.StatusOK || len(resp.Errors) > 0 {
+		t.Fatalf("expected success, got %d with errors %+v", code, resp.Errors)
+	}
+
+	checks := resp.Data["checks"].([]interface{})
+	if len(checks) != 1 {
+		t.Fatalf("expected 1 check, got %d", len(checks))
+	}
+	got := checks[0].(map[string]interface{})
+	if got["name"] != "API" || got["status"] != "up" {
+		t.Errorf("expected the enriched check, got %v", got)
+	}
+	if _, ok := got["url"]; ok {
+		t.Error("expected only the selected fields")
+	}
+	if results := got["results"].([]interface{}); len(results) != 2 {
+		t.Errorf("expected 2 results, got %d", len(results))
+	}
+	if uptime := got["stats"].(map[string]interface{})["uptime_percent_24h"]; uptime != float64(100) {
+		t.Errorf("expected 100%% uptime, got %v", uptime)
+	}
+
+	incidents := got["incidents"].([]interface{})
+	if len(incidents) != 1 {
+		t.Fatalf("expected 1 incident, got %d", len(incidents))
+	}
+	inc := incidents[0].(map[string]interface{})
+	notes := inc["notes"].([]interface{})
+	if len(notes) != 1 || notes[0].(map[string]interface{})["content"] != "Looking into it" {
+		t.Errorf("expected the incident's note, got %v", notes)
+	}
+	if inc["check"].(map[string]interface{})["name"] != "API" {
+		t.Errorf("expected the incident's check, got %v", inc["check"])
+	}
+}
+
+func TestGraphQLAliasesAndVariables(t *testing.T) {
+	server, store := setupGraphQLServer(t)
+
+	first := &storage.Check{Name: "First", URL: "https://first.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	second := &storage.Check{Name: "Second", URL: "https://second.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(first)
+	store.CreateCheck(second)
+
+	body, _ := json.Marshal(graphQLRequest{
+		Query:     `query Pair($a: ID!, $b: ID!) { one: check(id: $a) { name } two: check(id: $b) { name __typename } missing: check(id: 999) { name } }`,
+		Variables: map[string]interface{}{"a": first.ID, "b": "2"},
+	})
+	code, resp := postGraphQL(t, server, string(body))
+	if code != http.StatusOK || len(resp.Errors) > 0 {
+		t.Fatalf("expected success, got %d with errors %+v", code, resp.Errors)
+	}
+
+	if name := resp.Data["one"].(map[string]interface{})["name"]; name != "First" {
+		t.Errorf("expected First, got %v", name)
+	}
+	two := resp.Data["two"].(map[string]interface{})
+	if two["name"] != "Second" || two["__typename"] != "Check" {
+		t.Errorf("expected Second as a Check, got %v", two)
+	}
+	if resp.Data["missing"] != nil {
+		t.Errorf("expected null for a missing check, got %v", resp.Data["missing"])
+	}
+}
+
+func TestGraphQLGet(t *testing.T) {
+	server, store := setupGraphQLServer(t)
+
+	store.CreateCheck(&storage.Check{Name: "Get", URL: "https://get.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true})
+
+	req := httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape("{ checks(status: pending) { name } }"), nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), `"name":"Get"`) {
+		t.Errorf("expected the pending check, got %s", rec.Body.String())
+	}
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	server, store := setupGraphQLServer(t)
+
+	store.CreateCheck(&storage.Check{Name: "Err", URL: "https://err.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true})
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+		wantErr  string
+	}{
+		{"missing query", `{}`, http.StatusBadRequest, "query is required"},
+		{"bad body", `not json`, http.StatusBadRequest, "invalid request body"},
+		{"syntax error", `{"query":"{ checks { name }"}`, http.StatusBadRequest, "syntax error"},
+		{"mutation", `{"query":"mutation { deleteCheck }"}`, http.StatusBadRequest, "only queries are supported"},
+		{"fragment", `{"query":"{ checks { ...F } }"}`, http.StatusBadRequest, "fragments are not supported"},
+		{"unknown field", `{"query":"{ checks { name password } }"}`, http.StatusOK, `cannot query field "password" on type Check`},
+		{"missing subfields", `{"query":"{ checks }"}`, http.StatusOK, "must have a selection of subfields"},
+		{"bad limit", `{"query":"{ incidents(limit: 500) { id } }"}`, http.StatusOK, "limit must be between 1 and 100"},
+		{"bad status", `{"query":"{ checks(status: \"sideways\") { id } }"}`, http.StatusOK, "invalid status"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, resp := postGraphQL(t, server, tt.body)
+			if code != tt.wantCode {
+				t.Errorf("expected %d, got %d", tt.wantCode, code)
+			}
+			if len(resp.Errors) == 0 || !strings.Contains(resp.Errors[0].Message, tt.wantErr) {
+				t.Errorf("expected error containing %q, got %+v", tt.wantErr, resp.Errors)
+			}
+		})
+	}
+}
+
+func TestGraphQLErrorPath(t *testing.T) {
+	server, store := setupGraphQLServer(t)
+
+	store.CreateCheck(&storage.Check{Name: "Path", URL: "https://path.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true})
+
+	_, resp := postGraphQL(t, server, `{"query":"{ checks { name results(limit: 0) { id } } }"}`)
+	if len(resp.Errors) != 1 {
+		t.Fatalf("expected 1 error, got %+v", resp.Errors)
+	}
+	path, _ := json.Marshal(resp.Errors[0].Path)
+	if string(path) != `["checks",0,"results"]` {
+		t.Errorf("expected the error's path, got %s", path)
+	}
+	// The rest of the query still resolves
+	check := resp.Data["checks"].([]interface{})[0].(map[string]interface{})
+	if check["name"] != "Path" || check["results"] != nil {
+		t.Errorf("expected name with null results, got %v", check)
+	}
+}
+
+func TestGraphQLLimits(t *testing.T) {
+	server, store := setupGraphQLServer(t)
+
+	check := &storage.Check{Name: "Busy", URL: "https://busy.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(check)
+	for i := 0; i < 100; i++ {
+		store.CreateIncident(&storage.Incident{CheckID: check.ID, StartedAt: time.Now().Add(-time.Duration(i) * time.Minute), Cause: "Timeout"})
+	}
+
+	t.Run("body size", func(t *testing.T) {
+		body := `{"query":"{ checks { id } }","variables":{"pad":"` + strings.Repeat("x", maxGraphQLBody) + `"}}`
+		code, resp := postGraphQL(t, server, body)
+		if code != http.StatusRequestEntityTooLarge || len(resp.Errors) == 0 || !strings.Contains(resp.Errors[0].Message, "request body exceeds") {
+			t.Errorf("expected 413 for an oversized body, got %d with %+v", code, resp.Errors)
+		}
+	})
+
+	t.Run("depth", func(t *testing.T) {
+		query := "{ checks { name } }"
+		for i := 0; i < gqlMaxDepth; i++ {
+			query = strings.Replace(query, "{ name }", "{ incidents { check { name } } }", 1)
+		}
+		body, _ := json.Marshal(graphQLRequest{Query: query})
+		code, resp := postGraphQL(t, server, string(body))
+		if code != http.StatusBadRequest || len(resp.Errors) == 0 || !strings.Contains(resp.Errors[0].Message, "nested more than") {
+			t.Errorf("expected a too-deep query refused, got %d with %+v", code, resp.Errors)
+		}
+
+		// Up to the limit is fine
+		_, resp = postGraphQL(t, server, `{"query":"{ checks { incidents(limit: 1) { check { incidents(limit: 1) { check { incidents(limit: 1) { check { name } } } } } } } }"}`)
+		if len(resp.Errors) > 0 {
+			t.Errorf("expected a query %d levels deep to run, got %+v", gqlMaxDepth, resp.Errors)
+		}
+	})
+
+	t.Run("fields", func(t *testing.T) {
+		// 100 incidents, each with a check with 100 incidents of 5 fields
+		code, resp := postGraphQL(t, server, `{"query":"{ checks { incidents(limit: 100) { check { incidents(limit: 100) { id check_id cause started_at ended_at } } } } }"}`)
+		if code != http.StatusBadRequest || resp.Data != nil || len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, "more than") {
+			t.Errorf("expected a query over the field limit refused, got %d with %+v", code, resp.Errors)
+		}
+	})
+}
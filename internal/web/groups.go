@@ -0,0 +1,124 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+// CheckGroup is one dashboard group with its rolled-up status
+type CheckGroup struct {
+	Name          string           `json:"name"`
+	Status        string           `json:"status"`             // Worst member status
+	UptimePercent float64          `json:"uptime_percent_24h"` // Mean of the members' 24h uptime
+	Checks        []*storage.Check `json:"checks"`
+}
+
+// groupStatusRank orders statuses from best to worst for the rollup
+var groupStatusRank = map[string]int{"up": 0, "pending": 1, "degraded": 2, "down": 3}
+
+// checkGroupName names the group a check is listed under: its explicit
+// group, else its first tag, else "default"
+func checkGroupName(check *storage.Check) string {
+	if check.Group != "" {
+		return check.Group
+	}
+	if len(check.Tags) > 0 {
+		return check.Tags[0]
+	}
+	return "default"
+}
+
+// GroupByTag lists each check under every one of its tags instead of one
+// group, with untagged checks under UntaggedGroup
+const (
+	GroupByTag    = "tag"
+	UntaggedGroup = "untagged"
+)
+
+// checkGroupNames names every group a check is listed under: every tag
+// when grouping by tag, otherwise just checkGroupName
+func checkGroupNames(check *storage.Check, groupBy string) []string {
+	if groupBy != GroupByTag {
+		return []string{checkGroupName(check)}
+	}
+	if len(check.Tags) == 0 {
+		return []string{UntaggedGroup}
+	}
+	return slices.Compact(slices.Sorted(slices.Values(check.Tags)))
+}
+
+// groupByFromQuery reads ?group_by=, which is "group" (the default) or "tag"
+func groupByFromQuery(c echo.Context) (string, error) {
+	switch groupBy := c.QueryParam("group_by"); groupBy {
+	case "", "group":
+		return "", nil
+	case GroupByTag:
+		return groupBy, nil
+	default:
+		return "", fmt.Errorf("group_by must be group or tag")
+	}
+}
+
+// rollupStatus is the worst of the checks' statuses, so a group is only up
+// when every member is
+func rollupStatus(checks []*storage.Check) string {
+	status := "up"
+	for _, check := range checks {
+		if groupStatusRank[check.Status] > groupStatusRank[status] {
+			status = check.Status
+		}
+	}
+	return status
+}
+
+// HandleListGroups returns the dashboard's check groups, sorted by name,
+// each with its members, rolled-up status and uptime. ?group_by=tag groups
+// by every tag, like the dashboard.
+func (s *Server) HandleListGroups(c echo.Context) error {
+	groupBy, err := groupByFromQuery(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+	}
+
+	checks, err := s.storage.ListChecks()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+	if err := s.enrichChecks(checks); err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+
+	byName := make(map[string]*CheckGroup)
+	for _, check := range checks {
+		uptime := 100.0
+		if stats, _ := s.storage.GetStats(check.ID); stats != nil {
+			uptime = stats.UptimePercent24h
+		}
+
+		for _, name := range checkGroupNames(check, groupBy) {
+			group := byName[name]
+			if group == nil {
+				group = &CheckGroup{Name: name}
+				byName[name] = group
+			}
+			group.Checks = append(group.Checks, check)
+			group.UptimePercent += uptime
+		}
+	}
+
+	groups := make([]*CheckGroup, 0, len(byName))
+	for _, group := range byName {
+		group.Status = rollupStatus(group.Checks)
+		group.UptimePercent /= float64(len(group.Checks))
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+
+	return c.JSON(http.StatusOK, APIResponse{Data: groups})
+}
@@ -0,0 +1,157 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+func TestCheckGroupName(t *testing.T) {
+	tests := []struct {
+		check *storage.Check
+		want  string
+	}{
+		{&storage.Check{}, "default"},
+		{&storage.Check{Tags: []string{"api", "prod"}}, "api"},
+		{&storage.Check{Tags: []string{"api"}, Group: "Payments"}, "Payments"},
+	}
+	for _, tt := range tests {
+		if got := checkGroupName(tt.check); got != tt.want {
+			t.Errorf("checkGroupName(%+v) = %q, want %q", tt.check, got, tt.want)
+		}
+	}
+}
+
+func TestCheckGroupNames(t *testing.T) {
+	tests := []struct {
+		check   *storage.Check
+		groupBy string
+		want    []string
+	}{
+		{&storage.Check{Tags: []string{"api", "prod"}}, "", []string{"api"}},
+		{&storage.Check{Tags: []string{"prod", "api", "prod"}}, GroupByTag, []string{"api", "prod"}},
+		{&storage.Check{Tags: []string{"api"}, Group: "Payments"}, GroupByTag, []string{"api"}},
+		{&storage.Check{}, GroupByTag, []string{UntaggedGroup}},
+	}
+	for _, tt := range tests {
+		if got := checkGroupNames(tt.check, tt.groupBy); !slices.Equal(got, tt.want) {
+			t.Errorf("checkGroupNames(%+v, %q) = %v, want %v", tt.check, tt.groupBy, got, tt.want)
+		}
+	}
+}
+
+func TestRollupStatus(t *testing.T) {
+	tests := []struct {
+		statuses []string
+		want     string
+	}{
+		{[]string{"up", "up"}, "up"},
+		{[]string{"up", "pending"}, "pending"},
+		{[]string{"pending", "degraded", "up"}, "degraded"},
+		{[]string{"degraded", "down", "up"}, "down"},
+	}
+	for _, tt := range tests {
+		var checks []*storage.Check
+		for _, status := range tt.statuses {
+			checks = append(checks, &storage.Check{Status: status})
+		}
+		if got := rollupStatus(checks); got != tt.want {
+			t.Errorf("rollupStatus(%v) = %q, want %q", tt.statuses, got, tt.want)
+		}
+	}
+}
+
+func TestAPIListGroups(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	checks := []*storage.Check{
+		{Name: "Web", URL: "https://web.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true, Tags: []string{"frontend"}},
+		{Name: "API", URL: "https://api.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true, Tags: []string{"backend"}, Group: "frontend"},
+		{Name: "DB", URL: "db.internal:5432", Type: "tcp", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true},
+	}
+	for _, c := range checks {
+		if err := store.CreateCheck(c); err != nil {
+			t.Fatalf("failed to create check: %v", err)
+		}
+	}
+	store.SaveResult(&storage.CheckResult{CheckID: checks[0].ID, Status: "up", StatusCode: 200})
+	store.SaveResult(&storage.CheckResult{CheckID: checks[1].ID, Status: "down", StatusCode: 500})
+	store.SaveResult(&storage.CheckResult{CheckID: checks[2].ID, Status: "up"})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/groups", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp struct {
+		Data []CheckGroup `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	if len(resp.Data) != 2 {
+		t.Fatalf("expected 2 groups, got %+v", resp.Data)
+	}
+	def, frontend := resp.Data[0], resp.Data[1]
+	if def.Name != "default" || def.Status != "up" || len(def.Checks) != 1 || def.UptimePercent != 100 {
+		t.Errorf("unexpected default group: %+v", def)
+	}
+	if frontend.Name != "frontend" || len(frontend.Checks) != 2 {
+		t.Fatalf("expected the tagged and the explicit check in frontend, got %+v", frontend)
+	}
+	if frontend.Status != "down" {
+		t.Errorf("expected frontend to roll up as down, got %q", frontend.Status)
+	}
+	if frontend.UptimePercent != 50 {
+		t.Errorf("expected 50%% mean uptime, got %.1f", frontend.UptimePercent)
+	}
+}
+
+func TestAPIListGroupsByTag(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	for _, c := range []*storage.Check{
+		{Name: "API", URL: "https://api.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true, Tags: []string{"api", "prod"}},
+		{Name: "Staging", URL: "https://staging.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true, Tags: []string{"api"}},
+		{Name: "DB", URL: "db.internal:5432", Type: "tcp", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true},
+	} {
+		store.CreateCheck(c)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/groups?group_by=tag", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp struct {
+		Data []CheckGroup `json:"data"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+
+	sizes := make(map[string]int)
+	for _, group := range resp.Data {
+		sizes[group.Name] = len(group.Checks)
+	}
+	if len(sizes) != 3 || sizes["api"] != 2 || sizes["prod"] != 1 || sizes[UntaggedGroup] != 1 {
+		t.Errorf("expected API under both its tags and DB untagged, got %v", sizes)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/groups?group_by=color", nil)
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for unknown group_by, got %d", rec.Code)
+	}
+}
@@ -4,10 +4,13 @@ import (
 	"net/http"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
 
+	"github.com/katieblackabee/sentinel/internal/checker"
+	"github.com/katieblackabee/sentinel/internal/config"
 	"github.com/katieblackabee/sentinel/internal/storage"
 )
 
@@ -17,14 +20,17 @@ type DashboardData struct {
 	AllOperational  bool
 	OverallUptime   float64
 	CheckGroups     map[string][]*CheckWithStatus
+	GroupBy         string // GroupByTag, or empty for each check's one group
 	RecentIncidents []*storage.Incident
 	LastUpdated     time.Time
+	Banner          *storage.Banner // Maintenance banner, when one is active
+	RefreshSecs     int             // How often the page reloads itself
 }
 
 type CheckWithStatus struct {
 	*storage.Check
 	UptimePercent  float64
-	Sparkline      []bool // Last 24 checks: true = up, false = down
+	Sparkline      []string // Last 24 result statuses: "up", "degraded" or "down"
 	SSLDaysLeft    int    // Days until SSL cert expires (0 if no SSL)
 	SSLExpiresDate string // Formatted expiry date
 	RegionStatuses []RegionStatus // Per-region status (empty if no regions configured)
@@ -44,7 +50,9 @@ type CheckDetailData struct {
 	Stats     *storage.CheckStats
 	Results   []*storage.CheckResult
 	Incidents []*storage.Incident
-	Period    string // "24h", "7d", "30d"
+	// IncidentStats covers the same period as the chart
+	IncidentStats *storage.IncidentStats
+	Period        string // "24h", "7d", "30d"
 }
 
 type SettingsData struct {
@@ -61,6 +69,7 @@ type AlertConfigView struct {
 	ConsecutiveFailures  int
 	RecoveryNotification bool
 	CooldownMinutes      int
+	RenotifyMinutes      int
 	EmailEnabled         bool
 	SMTPHost             string
 	SMTPPort             int
@@ -71,6 +80,7 @@ type AlertConfigView struct {
 type RetentionConfigView struct {
 	ResultsDays    int
 	AggregatesDays int
+	ArchiveDays    int
 }
 
 type EditCheckData struct {
@@ -81,6 +91,9 @@ type EditCheckData struct {
 }
 
 func (s *Server) HandleDashboard(c echo.Context) error {
+	// An unknown ?group_by= falls back to the default grouping
+	groupBy, _ := groupByFromQuery(c)
+
 	checks, err := s.storage.ListChecks()
 	if err != nil {
 		return c.String(http.StatusInternalServerError, "Failed to load checks")
@@ -90,6 +103,7 @@ func (s *Server) HandleDashboard(c echo.Context) error {
 	checkGroups := make(map[string][]*CheckWithStatus)
 	var totalUptime float64
 	allUp := true
+	maintenance, _ := s.storage.ListActiveMaintenanceWindows(time.Now())
 
 	for _, check := range checks {
 		// Get latest result
@@ -101,6 +115,9 @@ func (s *Server) HandleDashboard(c echo.Context) error {
 		} else {
 			check.Status = "pending"
 		}
+		storage.SettleStatus(s.storage, check)
+		check.Stale = check.IsStale(time.Now(), s.staleIntervals())
+		check.InMaintenance = storage.InMaintenance(maintenance, check.ID)
 
 		// Get stats
 		stats, _ := s.storage.GetStats(check.ID)
@@ -116,9 +133,9 @@ func (s *Server) HandleDashboard(c echo.Context) error {
 
 		// Get sparkline data (last 24 results)
 		results, _ := s.storage.GetResults(check.ID, 24, 0)
-		sparkline := make([]bool, len(results))
+		sparkline := make([]string, len(results))
 		for i, r := range results {
-			sparkline[len(results)-1-i] = r.Status == "up"
+			sparkline[len(results)-1-i] = r.Status
 		}
 
 		// Get SSL info from most recent result
@@ -155,12 +172,9 @@ func (s *Server) HandleDashboard(c echo.Context) error {
 			RegionStatuses: regionStatuses,
 		}
 
-		// Group by first tag or "default"
-		groupName := "default"
-		if len(check.Tags) > 0 {
-			groupName = check.Tags[0]
+		for _, groupName := range checkGroupNames(check, groupBy) {
+			checkGroups[groupName] = append(checkGroups[groupName], cws)
 		}
-		checkGroups[groupName] = append(checkGroups[groupName], cws)
 	}
 
 	// Calculate overall uptime
@@ -169,8 +183,12 @@ func (s *Server) HandleDashboard(c echo.Context) error {
 		overallUptime = totalUptime / float64(len(checks))
 	}
 
-	// Get recent incidents
-	incidents, _ := s.storage.ListIncidents(5, 0)
+	// Get recent incidents; ?incidents=N overrides dashboard_incidents for this view
+	incidentLimit := s.config.GetDashboardIncidents()
+	if n, err := strconv.Atoi(c.QueryParam("incidents")); err == nil && n > 0 {
+		incidentLimit = min(n, config.MaxDashboardIncidents)
+	}
+	incidents, _ := s.storage.ListIncidents(incidentLimit, 0)
 
 	data := DashboardData{
 		Title:           "Dashboard",
@@ -178,8 +196,11 @@ func (s *Server) HandleDashboard(c echo.Context) error {
 		AllOperational:  allUp,
 		OverallUptime:   overallUptime,
 		CheckGroups:     checkGroups,
+		GroupBy:         groupBy,
 		RecentIncidents: incidents,
 		LastUpdated:     time.Now(),
+		Banner:          s.activeBanner(),
+		RefreshSecs:     int(s.config.GetDashboardRefresh().Seconds()),
 	}
 
 	return c.Render(http.StatusOK, "dashboard.html", data)
@@ -204,6 +225,10 @@ func (s *Server) HandleCheckDetail(c echo.Context) error {
 		check.LastResponseMs = result.ResponseTimeMs
 		check.LastCheckedAt = &result.CheckedAt
 	}
+	storage.SettleStatus(s.storage, check)
+	check.Stale = check.IsStale(time.Now(), s.staleIntervals())
+	maintenance, _ := s.storage.ListActiveMaintenanceWindows(time.Now())
+	check.InMaintenance = storage.InMaintenance(maintenance, check.ID)
 
 	// Get stats
 	stats, _ := s.storage.GetStats(check.ID)
@@ -234,15 +259,17 @@ func (s *Server) HandleCheckDetail(c echo.Context) error {
 
 	// Get incidents
 	incidents, _ := s.storage.ListIncidentsForCheck(check.ID, 10)
+	incidentStats, _ := s.storage.GetIncidentStats(check.ID, startTime, now)
 
 	data := CheckDetailData{
-		Title:     check.Name,
-		BasePath:  s.BasePath(),
-		Check:     check,
-		Stats:     stats,
-		Results:   results,
-		Incidents: incidents,
-		Period:    period,
+		Title:         check.Name,
+		BasePath:      s.BasePath(),
+		Check:         check,
+		Stats:         stats,
+		Results:       results,
+		Incidents:     incidents,
+		IncidentStats: incidentStats,
+		Period:        period,
 	}
 
 	return c.Render(http.StatusOK, "check.html", data)
@@ -268,6 +295,7 @@ func (s *Server) HandleSettings(c echo.Context) error {
 			ConsecutiveFailures:  s.fullConfig.Alerts.ConsecutiveFailures,
 			RecoveryNotification: s.fullConfig.Alerts.RecoveryNotification,
 			CooldownMinutes:      s.fullConfig.Alerts.CooldownMinutes,
+			RenotifyMinutes:      s.fullConfig.Alerts.RenotifyMinutes,
 			EmailEnabled:         s.fullConfig.Alerts.Email.Enabled,
 			SMTPHost:             s.fullConfig.Alerts.Email.SMTPHost,
 			SMTPPort:             s.fullConfig.Alerts.Email.SMTPPort,
@@ -277,6 +305,7 @@ func (s *Server) HandleSettings(c echo.Context) error {
 		retentionConfig = &RetentionConfigView{
 			ResultsDays:    s.fullConfig.Retention.ResultsDays,
 			AggregatesDays: s.fullConfig.Retention.AggregatesDays,
+			ArchiveDays:    s.fullConfig.Retention.ArchiveDays,
 		}
 	}
 
@@ -316,6 +345,9 @@ func (s *Server) HandleCreateCheckForm(c echo.Context) error {
 		TimeoutSecs:    10,
 		ExpectedStatus: 200,
 		Enabled:        true,
+		Description:    c.FormValue("description"),
+		RunbookURL:     c.FormValue("runbook_url"),
+		BodyContains:   c.FormValue("body_contains"),
 	}
 
 	if err := s.storage.CreateCheck(check); err != nil {
@@ -349,6 +381,44 @@ func (s *Server) HandleDeleteCheckForm(c echo.Context) error {
 	return c.Redirect(http.StatusSeeOther, s.BasePath()+"/settings?message=Check+deleted")
 }
 
+// HandlePauseCheckForm pauses a check from the settings page, for the chosen
+// number of minutes or, with none, until it is resumed
+func (s *Server) HandlePauseCheckForm(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.Redirect(http.StatusSeeOther, s.BasePath()+"/settings?error=Invalid+check+ID")
+	}
+
+	if minutesStr := c.FormValue("minutes"); minutesStr != "" {
+		minutes, convErr := strconv.Atoi(minutesStr)
+		if convErr != nil || minutes <= 0 {
+			return c.Redirect(http.StatusSeeOther, s.BasePath()+"/settings?error=Invalid+pause+duration")
+		}
+		err = s.storage.PauseCheckUntil(id, time.Now().Add(time.Duration(minutes)*time.Minute))
+	} else {
+		err = s.storage.SetCheckPaused(id, true)
+	}
+	if err != nil {
+		return c.Redirect(http.StatusSeeOther, s.BasePath()+"/settings?error=Failed+to+pause+check")
+	}
+
+	return c.Redirect(http.StatusSeeOther, s.BasePath()+"/settings?message=Check+paused")
+}
+
+// HandleResumeCheckForm resumes a paused check from the settings page
+func (s *Server) HandleResumeCheckForm(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.Redirect(http.StatusSeeOther, s.BasePath()+"/settings?error=Invalid+check+ID")
+	}
+
+	if err := s.storage.SetCheckPaused(id, false); err != nil {
+		return c.Redirect(http.StatusSeeOther, s.BasePath()+"/settings?error=Failed+to+resume+check")
+	}
+
+	return c.Redirect(http.StatusSeeOther, s.BasePath()+"/settings?message=Check+resumed")
+}
+
 func (s *Server) HandleEditCheckForm(c echo.Context) error {
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
@@ -374,6 +444,10 @@ func (s *Server) HandleEditCheckForm(c echo.Context) error {
 	// Handle POST - process form
 	check.Name = c.FormValue("name")
 	check.URL = c.FormValue("url")
+	check.Description = c.FormValue("description")
+	check.Group = strings.TrimSpace(c.FormValue("group"))
+	check.RunbookURL = c.FormValue("runbook_url")
+	check.Cron = strings.TrimSpace(c.FormValue("cron"))
 
 	if intervalStr := c.FormValue("interval"); intervalStr != "" {
 		if i, err := strconv.Atoi(intervalStr); err == nil && i > 0 {
@@ -387,13 +461,174 @@ func (s *Server) HandleEditCheckForm(c echo.Context) error {
 		}
 	}
 
+	if sampleStr := c.FormValue("sample_seconds"); sampleStr != "" {
+		if s, err := strconv.Atoi(sampleStr); err == nil && s >= 0 {
+			check.SampleSecs = s
+		}
+	}
+
+	// A plain code, or a spec like "200,204" or "2xx"
 	if statusStr := c.FormValue("expected_status"); statusStr != "" {
-		if s, err := strconv.Atoi(statusStr); err == nil && s > 0 {
-			check.ExpectedStatus = s
+		code, spec := storage.SplitExpectedStatus(statusStr)
+		if code > 0 || code == storage.AnyStatus {
+			check.ExpectedStatus = code
+			check.ExpectedStatuses = ""
+		} else if spec != "" {
+			check.ExpectedStatuses = spec
 		}
 	}
 
 	check.Enabled = c.FormValue("enabled") == "1"
+	check.Streaming = c.FormValue("streaming") == "1"
+	check.Paused = c.FormValue("paused") == "1"
+	if !check.Paused {
+		check.PausedUntil = nil
+	}
+	check.AlertOnFirstCheck = c.FormValue("alert_on_first_check") == "1"
+	check.CompressResults = c.FormValue("compress_results") == "1"
+	check.NoFollowRedirects = c.FormValue("no_follow_redirects") == "1"
+	check.BypassCache = c.FormValue("bypass_cache") == "1"
+	check.DualStack = c.FormValue("dual_stack") == "1"
+	check.InsecureSkipVerify = c.FormValue("insecure_skip_verify") == "1"
+	check.ExpectedLocation = strings.TrimSpace(c.FormValue("expected_location"))
+	check.ExpectedTrailer = strings.TrimSpace(c.FormValue("expected_trailer"))
+	check.ExpectedContentType = strings.TrimSpace(c.FormValue("expected_content_type"))
+	check.ExpectedCertFingerprint = strings.TrimSpace(c.FormValue("expected_cert_fingerprint"))
+	check.BaselineURL = strings.TrimSpace(c.FormValue("baseline_url"))
+	check.BodyContains = c.FormValue("body_contains")
+	check.BodyNotContains = c.FormValue("body_not_contains")
+	if checkType := c.FormValue("type"); checkType != "" {
+		check.Type = checkType
+	}
+	check.CompareFields = strings.Fields(strings.ReplaceAll(c.FormValue("compare_fields"), ",", " "))
+	check.AlertChannels = strings.Fields(strings.ReplaceAll(c.FormValue("alert_channels"), ",", " "))
+	check.AssertionType = c.FormValue("assertion_type")
+	check.RecordType = c.FormValue("record_type")
+	check.ExpectedAnswer = strings.TrimSpace(c.FormValue("expected_answer"))
+	check.Method = strings.ToUpper(c.FormValue("method"))
+	check.RequestBody = c.FormValue("request_body")
+
+	if tolStr := c.FormValue("latency_tolerance_pct"); tolStr != "" {
+		if t, err := strconv.Atoi(tolStr); err == nil && t >= 0 {
+			check.LatencyTolerancePct = t
+		}
+	}
+	if thresholdStr := c.FormValue("degraded_threshold_ms"); thresholdStr != "" {
+		if t, err := strconv.Atoi(thresholdStr); err == nil && t >= 0 {
+			check.DegradedThresholdMs = t
+		}
+	}
+	if multiplierStr := c.FormValue("slow_multiplier"); multiplierStr != "" {
+		if m, err := strconv.ParseFloat(multiplierStr, 64); err == nil && (m == 0 || m > 1) {
+			check.SlowMultiplier = m
+		}
+	}
+	if successStr := c.FormValue("consecutive_successes"); successStr != "" {
+		if n, err := strconv.Atoi(successStr); err == nil && n >= 0 {
+			check.ConsecutiveSuccesses = n
+		}
+	}
+	if failureStr := c.FormValue("consecutive_failures"); failureStr != "" {
+		if n, err := strconv.Atoi(failureStr); err == nil && n >= 0 {
+			check.ConsecutiveFailures = n
+		}
+	}
+	if minStr := c.FormValue("min_body_bytes"); minStr != "" {
+		if n, err := strconv.Atoi(minStr); err == nil && n >= 0 {
+			check.MinBodyBytes = n
+		}
+	}
+
+	// One assertion per line
+	check.JSONAssertions = nil
+	for _, line := range strings.Split(c.FormValue("json_assertions"), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			check.JSONAssertions = append(check.JSONAssertions, line)
+		}
+	}
+
+	check.Assertions = nil
+	for _, line := range strings.Split(c.FormValue("assertions"), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			check.Assertions = append(check.Assertions, line)
+		}
+	}
+
+	// One redirect hop per line, e.g. "301 https://www.example.com/"
+	check.ExpectedRedirects = nil
+	for _, line := range strings.Split(c.FormValue("expected_redirects"), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			check.ExpectedRedirects = append(check.ExpectedRedirects, line)
+		}
+	}
+
+	// One escalation per line, e.g. "1h slack:oncall critical"
+	check.Escalations = nil
+	var escalationErr error
+	for _, line := range strings.Split(c.FormValue("escalations"), "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		rule, err := checker.ParseEscalation(line)
+		if err != nil {
+			escalationErr = err
+			continue
+		}
+		check.Escalations = append(check.Escalations, rule)
+	}
+
+	// One route per line, e.g. "recovery slack"
+	check.AlertRoutes = nil
+	var routeErr error
+	for _, line := range strings.Split(c.FormValue("alert_routes"), "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		alertType, channels, err := checker.ParseAlertRoute(line)
+		if err != nil {
+			routeErr = err
+			continue
+		}
+		if check.AlertRoutes == nil {
+			check.AlertRoutes = make(map[string][]string)
+		}
+		check.AlertRoutes[alertType] = channels
+	}
+
+	// One header per line, e.g. "Authorization: Bearer ..."
+	check.Headers = nil
+	var headerErr error
+	for _, line := range strings.Split(c.FormValue("headers"), "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		name, value, err := checker.ParseHeader(line)
+		if err != nil {
+			headerErr = err
+			continue
+		}
+		if check.Headers == nil {
+			check.Headers = make(map[string]string)
+		}
+		check.Headers[name] = value
+	}
+
+	// One parameter per line, e.g. "ts={{.Now.Unix}}"
+	check.QueryParams = nil
+	for _, line := range strings.Split(c.FormValue("query_params"), "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		name, value, err := checker.ParseQueryParam(line)
+		if err != nil {
+			headerErr = err
+			continue
+		}
+		if check.QueryParams == nil {
+			check.QueryParams = make(map[string]string)
+		}
+		check.QueryParams[name] = value
+	}
 
 	if check.Name == "" || check.URL == "" {
 		data := EditCheckData{
@@ -405,6 +640,164 @@ func (s *Server) HandleEditCheckForm(c echo.Context) error {
 		return c.Render(http.StatusOK, "edit.html", data)
 	}
 
+	if err := validateJSONAssertions(check.JSONAssertions); err != nil {
+		data := EditCheckData{
+			Title:    "Edit Check",
+			BasePath: s.BasePath(),
+			Check:    check,
+			Error:    err.Error(),
+		}
+		return c.Render(http.StatusOK, "edit.html", data)
+	}
+
+	if check.ExpectedStatuses != "" {
+		if err := storage.ValidateStatusSpec(check.ExpectedStatuses); err != nil {
+			data := EditCheckData{
+				Title:    "Edit Check",
+				BasePath: s.BasePath(),
+				Check:    check,
+				Error:    err.Error(),
+			}
+			return c.Render(http.StatusOK, "edit.html", data)
+		}
+	}
+
+	if err := checker.ValidateTarget(check.Type, check.URL); err != nil {
+		data := EditCheckData{
+			Title:    "Edit Check",
+			BasePath: s.BasePath(),
+			Check:    check,
+			Error:    err.Error(),
+		}
+		return c.Render(http.StatusOK, "edit.html", data)
+	}
+
+	if err := checker.ValidateDNSExpectation(check.RecordType, check.ExpectedAnswer); err != nil {
+		data := EditCheckData{
+			Title:    "Edit Check",
+			BasePath: s.BasePath(),
+			Check:    check,
+			Error:    err.Error(),
+		}
+		return c.Render(http.StatusOK, "edit.html", data)
+	}
+
+	if err := checker.ValidateExpectedTrailer(check.ExpectedTrailer); err != nil {
+		data := EditCheckData{
+			Title:    "Edit Check",
+			BasePath: s.BasePath(),
+			Check:    check,
+			Error:    err.Error(),
+		}
+		return c.Render(http.StatusOK, "edit.html", data)
+	}
+
+	if err := checker.ValidateCron(check.Type, check.Cron); err != nil {
+		data := EditCheckData{
+			Title:    "Edit Check",
+			BasePath: s.BasePath(),
+			Check:    check,
+			Error:    err.Error(),
+		}
+		return c.Render(http.StatusOK, "edit.html", data)
+	}
+
+	if err := checker.ValidateExpectedContentType(check.ExpectedContentType); err != nil {
+		data := EditCheckData{
+			Title:    "Edit Check",
+			BasePath: s.BasePath(),
+			Check:    check,
+			Error:    err.Error(),
+		}
+		return c.Render(http.StatusOK, "edit.html", data)
+	}
+
+	if err := checker.ValidateMinBodyBytes(check.MinBodyBytes); err != nil {
+		data := EditCheckData{
+			Title:    "Edit Check",
+			BasePath: s.BasePath(),
+			Check:    check,
+			Error:    err.Error(),
+		}
+		return c.Render(http.StatusOK, "edit.html", data)
+	}
+
+	if err := checker.ValidateExpectedRedirects(check.ExpectedRedirects); err != nil {
+		data := EditCheckData{
+			Title:    "Edit Check",
+			BasePath: s.BasePath(),
+			Check:    check,
+			Error:    err.Error(),
+		}
+		return c.Render(http.StatusOK, "edit.html", data)
+	}
+
+	if err := checker.ValidateCompareFields(check.CompareFields); err != nil {
+		data := EditCheckData{
+			Title:    "Edit Check",
+			BasePath: s.BasePath(),
+			Check:    check,
+			Error:    err.Error(),
+		}
+		return c.Render(http.StatusOK, "edit.html", data)
+	}
+
+	if err := checker.ValidateAlertChannels(check.AlertChannels); err != nil {
+		data := EditCheckData{
+			Title:    "Edit Check",
+			BasePath: s.BasePath(),
+			Check:    check,
+			Error:    err.Error(),
+		}
+		return c.Render(http.StatusOK, "edit.html", data)
+	}
+
+	if err := checker.ValidateBodyAssertions(check.AssertionType, check.Assertions); err != nil {
+		data := EditCheckData{
+			Title:    "Edit Check",
+			BasePath: s.BasePath(),
+			Check:    check,
+			Error:    err.Error(),
+		}
+		return c.Render(http.StatusOK, "edit.html", data)
+	}
+
+	if escalationErr != nil {
+		data := EditCheckData{
+			Title:    "Edit Check",
+			BasePath: s.BasePath(),
+			Check:    check,
+			Error:    escalationErr.Error(),
+		}
+		return c.Render(http.StatusOK, "edit.html", data)
+	}
+
+	if routeErr != nil {
+		data := EditCheckData{
+			Title:    "Edit Check",
+			BasePath: s.BasePath(),
+			Check:    check,
+			Error:    routeErr.Error(),
+		}
+		return c.Render(http.StatusOK, "edit.html", data)
+	}
+
+	if headerErr == nil {
+		headerErr = checker.ValidateRequestOptions(check.Method, check.Headers)
+	}
+	if headerErr == nil {
+		headerErr = checker.ValidateQueryParams(check.QueryParams)
+	}
+	if headerErr != nil {
+		data := EditCheckData{
+			Title:    "Edit Check",
+			BasePath: s.BasePath(),
+			Check:    check,
+			Error:    headerErr.Error(),
+		}
+		return c.Render(http.StatusOK, "edit.html", data)
+	}
+
 	if err := s.storage.UpdateCheck(check); err != nil {
 		data := EditCheckData{
 			Title:    "Edit Check",
@@ -427,23 +820,39 @@ func (s *Server) HandleEditCheckForm(c echo.Context) error {
 type StatusPageData struct {
 	Title           string
 	Slug            string
+	Branding        config.Branding
 	AllOperational  bool
 	OverallUptime   float64
 	Checks          []*CheckWithStatus
 	RecentIncidents []*storage.Incident
 	LastUpdated     time.Time
+	Banner          *storage.Banner
+	FeedURL         string // Atom feed of the page's incidents
 }
 
 // handleStatusPage renders a public status page for a given tag/slug
 func (s *Server) handleStatusPage(c echo.Context) error {
-	slug := c.Param("slug")
-	if slug == "" {
+	data := s.statusPageData(c.Param("slug"))
+	if data == nil {
 		return c.String(http.StatusNotFound, "Status page not found")
 	}
 
+	return c.Render(http.StatusOK, "status.html", data)
+}
+
+// statusPageData gathers what a status page shows for the checks tagged
+// with its slug, or nil when there are none
+func (s *Server) statusPageData(slug string) *StatusPageData {
+	if slug == "" {
+		return nil
+	}
+
 	checks, err := s.storage.ListChecksByTag(slug)
 	if err != nil || len(checks) == 0 {
-		return c.String(http.StatusNotFound, "Status page not found")
+		return nil
+	}
+	if err := s.enrichChecks(checks); err != nil {
+		return nil
 	}
 
 	// Build status data
@@ -461,9 +870,9 @@ func (s *Server) handleStatusPage(c echo.Context) error {
 
 		// Get sparkline
 		results, _ := s.storage.GetRecentResults(check.ID, 24)
-		sparkline := make([]bool, len(results))
+		sparkline := make([]string, len(results))
 		for i, r := range results {
-			sparkline[i] = r.IsUp()
+			sparkline[i] = r.Status
 		}
 
 		if check.Status != "up" {
@@ -499,19 +908,32 @@ func (s *Server) handleStatusPage(c echo.Context) error {
 		recentIncidents = append(recentIncidents, incidents...)
 	}
 	// Sort by started_at desc and limit to 5
+	sort.Slice(recentIncidents, func(i, j int) bool {
+		return recentIncidents[i].StartedAt.After(recentIncidents[j].StartedAt)
+	})
 	if len(recentIncidents) > 5 {
 		recentIncidents = recentIncidents[:5]
 	}
 
-	data := StatusPageData{
-		Title:           slug + " Status",
+	var branding config.Branding
+	if s.fullConfig != nil {
+		branding = s.fullConfig.StatusPage.BrandingFor(slug)
+	}
+	title := branding.Title
+	if title == "" {
+		title = slug + " Status"
+	}
+
+	return &StatusPageData{
+		Title:           title,
 		Slug:            slug,
+		Branding:        branding,
 		AllOperational:  allUp,
 		OverallUptime:   overallUptime,
 		Checks:          statusChecks,
 		RecentIncidents: recentIncidents,
 		LastUpdated:     time.Now(),
+		Banner:          s.activeBanner(),
+		FeedURL:         s.BasePath() + "/status/" + slug + "/feed.xml",
 	}
-
-	return c.Render(http.StatusOK, "status.html", data)
 }
@@ -1,11 +1,10 @@
 package web
 
 import (
+	"encoding/xml"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
-	"os"
-	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -15,10 +14,7 @@ import (
 )
 
 func setupTestServerWithTemplates(t *testing.T) (*Server, storage.Storage) {
-	tmpDir := t.TempDir()
-	dbPath := filepath.Join(tmpDir, "test.db")
-
-	store, err := storage.NewSQLiteStorage(dbPath)
+	store, err := storage.NewMemoryStorage()
 	if err != nil {
 		t.Fatalf("failed to create storage: %v", err)
 	}
@@ -53,7 +49,6 @@ func setupTestServerWithTemplates(t *testing.T) (*Server, storage.Storage) {
 
 	t.Cleanup(func() {
 		store.Close()
-		os.Remove(dbPath)
 	})
 
 	return server, store
@@ -77,6 +72,33 @@ func TestHandleDashboardEmpty(t *testing.T) {
 	}
 }
 
+func TestHandleDashboardRefresh(t *testing.T) {
+	server, _ := setupTestServerWithTemplates(t)
+	server.config.DashboardRefresh = "10s"
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if !strings.Contains(rec.Body.String(), `data-refresh="10"`) {
+		t.Error("expected the dashboard to carry the configured refresh interval")
+	}
+}
+
+func TestHandleDashboardBanner(t *testing.T) {
+	server, store := setupTestServerWithTemplates(t)
+
+	store.SetSetting(storage.BannerSetting, `{"message":"Planned database upgrade"}`)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if !strings.Contains(rec.Body.String(), "Planned database upgrade") {
+		t.Error("expected the dashboard to show the maintenance banner")
+	}
+}
+
 func TestHandleDashboardWithChecks(t *testing.T) {
 	server, store := setupTestServerWithTemplates(t)
 
@@ -115,6 +137,30 @@ func TestHandleDashboardWithChecks(t *testing.T) {
 	}
 }
 
+func TestHandleDashboardGroupByTag(t *testing.T) {
+	server, store := setupTestServerWithTemplates(t)
+
+	store.CreateCheck(&storage.Check{Name: "Edge API", URL: "https://edge.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true, Tags: []string{"api", "prod"}})
+	store.CreateCheck(&storage.Check{Name: "Loose", URL: "https://loose.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true})
+
+	req := httptest.NewRequest(http.MethodGet, "/?group_by=tag", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	if strings.Count(body, ">Edge API<") != 2 {
+		t.Error("expected the check listed under both of its tags")
+	}
+	for _, group := range []string{">api<", ">prod<", ">untagged<"} {
+		if !strings.Contains(body, group) {
+			t.Errorf("expected group %s on the dashboard", group)
+		}
+	}
+}
+
 func TestHandleDashboardWithIncidents(t *testing.T) {
 	server, store := setupTestServerWithTemplates(t)
 
@@ -138,6 +184,38 @@ func TestHandleDashboardWithIncidents(t *testing.T) {
 	}
 }
 
+func TestHandleDashboardIncidentLimit(t *testing.T) {
+	server, store := setupTestServerWithTemplates(t)
+
+	check := &storage.Check{Name: "Flappy", URL: "https://flappy.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(check)
+	for i := 0; i < 8; i++ {
+		store.CreateIncident(&storage.Incident{CheckID: check.ID, StartedAt: time.Now().Add(-time.Duration(i) * time.Hour)})
+	}
+
+	countIncidents := func(path string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		server.echo.ServeHTTP(rec, req)
+		return strings.Count(rec.Body.String(), `<div class="incident `)
+	}
+
+	if n := countIncidents("/"); n != 5 {
+		t.Errorf("expected 5 incidents by default, got %d", n)
+	}
+
+	server.config.DashboardIncidents = 7
+	if n := countIncidents("/"); n != 7 {
+		t.Errorf("expected 7 incidents from config, got %d", n)
+	}
+	if n := countIncidents("/?incidents=3"); n != 3 {
+		t.Errorf("expected query param to override config, got %d", n)
+	}
+	if n := countIncidents("/?incidents=0"); n != 7 {
+		t.Errorf("expected invalid query param to be ignored, got %d", n)
+	}
+}
+
 func TestHandleCheckDetail(t *testing.T) {
 	server, store := setupTestServerWithTemplates(t)
 
@@ -417,6 +495,7 @@ func TestHandleEditCheckFormPost(t *testing.T) {
 	form.Add("timeout", "5")
 	form.Add("expected_status", "201")
 	form.Add("enabled", "1")
+	form.Add("runbook_url", "https://wiki.example.com/runbooks/api")
 
 	req := httptest.NewRequest(http.MethodPost, "/settings/checks/1/edit", strings.NewReader(form.Encode()))
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
@@ -433,6 +512,9 @@ func TestHandleEditCheckFormPost(t *testing.T) {
 	if updated.Name != "Updated Name" {
 		t.Errorf("expected name 'Updated Name', got %s", updated.Name)
 	}
+	if updated.RunbookURL != "https://wiki.example.com/runbooks/api" {
+		t.Errorf("expected runbook url to be saved, got %s", updated.RunbookURL)
+	}
 }
 
 func TestHandleEditCheckFormNotFound(t *testing.T) {
@@ -513,9 +595,7 @@ func TestHandleLogout(t *testing.T) {
 }
 
 func TestBasePath(t *testing.T) {
-	tmpDir := t.TempDir()
-	dbPath := filepath.Join(tmpDir, "test.db")
-	store, _ := storage.NewSQLiteStorage(dbPath)
+	store, _ := storage.NewMemoryStorage()
 	defer store.Close()
 
 	cfg := &config.ServerConfig{
@@ -553,7 +633,7 @@ func TestCheckWithStatusStructure(t *testing.T) {
 	cws := &CheckWithStatus{
 		Check:         check,
 		UptimePercent: 99.5,
-		Sparkline:     []bool{true, true, false, true},
+		Sparkline:     []string{"up", "up", "down", "degraded"},
 	}
 
 	if cws.UptimePercent != 99.5 {
@@ -610,6 +690,107 @@ func TestHandleStatusPage(t *testing.T) {
 	}
 }
 
+func TestHandleStatusPageBranding(t *testing.T) {
+	server, store := setupTestServerWithTemplates(t)
+	server.fullConfig.StatusPage = config.StatusPageConfig{
+		Branding: config.Branding{
+			Title:        "Acme Status",
+			LogoURL:      "https://acme.example.com/logo.svg",
+			PrimaryColor: "#0066ff",
+			Footer:       "Acme Corp",
+		},
+		Pages: map[string]config.Branding{
+			"internal": {Title: "Acme Internal"},
+		},
+	}
+
+	for _, tag := range []string{"public", "internal"} {
+		store.CreateCheck(&storage.Check{Name: "API " + tag, URL: "https://" + tag + ".example.com", IntervalSecs: 30, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true, Tags: []string{tag}})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/status/public", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	body := rec.Body.String()
+	for _, want := range []string{"Acme Status", "https://acme.example.com/logo.svg", "--orange: #0066ff", "Acme Corp"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected status page to contain %q", want)
+		}
+	}
+	if strings.Contains(body, "Powered by Sentinel") {
+		t.Error("expected custom footer to replace the default")
+	}
+
+	// Per-page title, inherited logo
+	req = httptest.NewRequest(http.MethodGet, "/status/internal", nil)
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	body = rec.Body.String()
+	if !strings.Contains(body, "Acme Internal") || !strings.Contains(body, "logo.svg") {
+		t.Error("expected per-page title with the default logo")
+	}
+}
+
+func TestHandleStatusFeed(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	public := &storage.Check{Name: "API Server", URL: "https://api.example.com", IntervalSecs: 30, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true, Tags: []string{"public"}}
+	private := &storage.Check{Name: "Admin", URL: "https://admin.example.com", IntervalSecs: 30, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true, Tags: []string{"internal"}}
+	store.CreateCheck(public)
+	store.CreateCheck(private)
+
+	resolved := &storage.Incident{CheckID: public.ID, StartedAt: time.Now().Add(-2 * time.Hour), Cause: "connection refused"}
+	store.CreateIncident(resolved)
+	store.CloseIncident(resolved.ID, resolved.StartedAt.Add(15*time.Minute))
+	store.CreateIncident(&storage.Incident{CheckID: public.ID, StartedAt: time.Now().Add(-time.Minute), Cause: "HTTP 503"})
+	store.CreateIncident(&storage.Incident{CheckID: private.ID, StartedAt: time.Now(), Cause: "secret backend down"})
+
+	req := httptest.NewRequest(http.MethodGet, "/status/public/feed.xml", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/atom+xml") {
+		t.Errorf("expected an Atom content type, got %q", ct)
+	}
+
+	var feed struct {
+		Title   string `xml:"title"`
+		Entries []struct {
+			Title   string `xml:"title"`
+			Content string `xml:"content"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(rec.Body.Bytes(), &feed); err != nil {
+		t.Fatalf("expected valid XML, got %v", err)
+	}
+	if feed.Title != "public Status Incidents" {
+		t.Errorf("unexpected feed title %q", feed.Title)
+	}
+	if len(feed.Entries) != 2 {
+		t.Fatalf("expected only the page's 2 incidents, got %d", len(feed.Entries))
+	}
+
+	// Newest first
+	if feed.Entries[0].Title != "API Server outage (ongoing)" || !strings.Contains(feed.Entries[0].Content, "Cause: HTTP 503") {
+		t.Errorf("unexpected first entry: %+v", feed.Entries[0])
+	}
+	if feed.Entries[1].Title != "API Server outage (resolved)" || !strings.Contains(feed.Entries[1].Content, "Duration: 15m0s") {
+		t.Errorf("unexpected second entry: %+v", feed.Entries[1])
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/status/nonexistent/feed.xml", nil)
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for an unknown page, got %d", rec.Code)
+	}
+}
+
 func TestHandleStatusPageNotFound(t *testing.T) {
 	server, _ := setupTestServerWithTemplates(t)
 
@@ -0,0 +1,166 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+type CreateMaintenanceWindowInput struct {
+	Name         string    `json:"name"`
+	CheckID      int64     `json:"check_id,omitempty"` // 0 covers every check
+	StartsAt     time.Time `json:"starts_at"`
+	DurationSecs int       `json:"duration_seconds"`
+	Recurrence   string    `json:"recurrence"` // once (default), daily or weekly
+}
+
+// HandleListMaintenanceWindows lists every window, or only those active now
+// with ?active=true
+func (s *Server) HandleListMaintenanceWindows(c echo.Context) error {
+	var windows []*storage.MaintenanceWindow
+	var err error
+	if c.QueryParam("active") == "true" {
+		windows, err = s.storage.ListActiveMaintenanceWindows(time.Now())
+	} else {
+		windows, err = s.storage.ListMaintenanceWindows()
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+	if windows == nil {
+		windows = []*storage.MaintenanceWindow{}
+	}
+
+	return c.JSON(http.StatusOK, APIResponse{Data: windows})
+}
+
+func (s *Server) HandleCreateMaintenanceWindow(c echo.Context) error {
+	var input CreateMaintenanceWindowInput
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid request body"})
+	}
+
+	window := &storage.MaintenanceWindow{
+		Name:         input.Name,
+		CheckID:      input.CheckID,
+		StartsAt:     input.StartsAt,
+		DurationSecs: input.DurationSecs,
+		Recurrence:   input.Recurrence,
+	}
+	if window.Recurrence == "" {
+		window.Recurrence = storage.MaintenanceOnce
+	}
+	if err := window.Validate(); err != nil {
+		return c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+	}
+
+	if window.CheckID != 0 {
+		check, err := s.storage.GetCheck(window.CheckID)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+		}
+		if check == nil {
+			return c.JSON(http.StatusNotFound, APIResponse{Error: "Check not found"})
+		}
+	}
+
+	if err := s.storage.CreateMaintenanceWindow(window); err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, APIResponse{Data: window})
+}
+
+func (s *Server) HandleDeleteMaintenanceWindow(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid maintenance window ID"})
+	}
+
+	if err := s.storage.DeleteMaintenanceWindow(id); err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, APIResponse{Data: map[string]bool{"deleted": true}})
+}
+
+type SetBannerInput struct {
+	Message  string     `json:"message"`
+	StartsAt *time.Time `json:"starts_at,omitempty"` // Shown from now when omitted
+	EndsAt   *time.Time `json:"ends_at,omitempty"`   // Shown until cleared when omitted
+}
+
+// HandleGetBanner returns the maintenance banner, active or not, or null
+// when none is set
+func (s *Server) HandleGetBanner(c echo.Context) error {
+	banner, err := s.loadBanner()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+	return c.JSON(http.StatusOK, APIResponse{Data: banner})
+}
+
+// HandleSetBanner replaces the maintenance banner
+func (s *Server) HandleSetBanner(c echo.Context) error {
+	var input SetBannerInput
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid request body"})
+	}
+
+	banner := &storage.Banner{
+		Message:   strings.TrimSpace(input.Message),
+		StartsAt:  input.StartsAt,
+		EndsAt:    input.EndsAt,
+		UpdatedAt: time.Now(),
+	}
+	if err := banner.Validate(); err != nil {
+		return c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+	}
+
+	data, err := json.Marshal(banner)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+	if err := s.storage.SetSetting(storage.BannerSetting, string(data)); err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, APIResponse{Data: banner})
+}
+
+func (s *Server) HandleClearBanner(c echo.Context) error {
+	if err := s.storage.DeleteSetting(storage.BannerSetting); err != nil {
+		return c.JSON(http.StatusInternalServerError, APIResponse{Error: err.Error()})
+	}
+	return c.JSON(http.StatusOK, APIResponse{Data: map[string]bool{"deleted": true}})
+}
+
+// loadBanner returns the stored maintenance banner, or nil if none is set
+func (s *Server) loadBanner() (*storage.Banner, error) {
+	value, err := s.storage.GetSetting(storage.BannerSetting)
+	if err != nil || value == "" {
+		return nil, err
+	}
+	var banner storage.Banner
+	if err := json.Unmarshal([]byte(value), &banner); err != nil {
+		return nil, fmt.Errorf("reading maintenance banner: %w", err)
+	}
+	return &banner, nil
+}
+
+// activeBanner returns the banner pages should show now, if any. A banner
+// that can't be read is left off rather than failing the page.
+func (s *Server) activeBanner() *storage.Banner {
+	banner, err := s.loadBanner()
+	if err != nil || banner == nil || !banner.IsActiveAt(time.Now()) {
+		return nil
+	}
+	return banner
+}
@@ -0,0 +1,122 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+func TestAPIMaintenanceWindows(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	check := &storage.Check{Name: "Maintained", URL: "https://maintained.com", IntervalSecs: 60, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(check)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/maintenance", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		server.echo.ServeHTTP(rec, req)
+		return rec
+	}
+
+	now := time.Now().UTC()
+	rec := post(fmt.Sprintf(`{"name":"Upgrade","check_id":%d,"starts_at":%q,"duration_seconds":3600}`, check.ID, now.Add(-time.Minute).Format(time.RFC3339)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), `"recurrence":"once"`) {
+		t.Errorf("expected the default recurrence, got %s", rec.Body.String())
+	}
+
+	rec = post(fmt.Sprintf(`{"name":"Nightly","starts_at":%q,"duration_seconds":600,"recurrence":"daily"}`, now.Add(time.Hour).Format(time.RFC3339)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if rec := post(fmt.Sprintf(`{"starts_at":%q,"duration_seconds":600,"recurrence":"hourly"}`, now.Format(time.RFC3339))); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an unknown recurrence, got %d", rec.Code)
+	}
+	if rec := post(fmt.Sprintf(`{"check_id":999,"starts_at":%q,"duration_seconds":600}`, now.Format(time.RFC3339))); rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for an unknown check, got %d", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/maintenance?active=true", nil)
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+	if !strings.Contains(rec.Body.String(), `"Upgrade"`) || strings.Contains(rec.Body.String(), `"Nightly"`) {
+		t.Errorf("expected only the current window active, got %s", rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/checks/%d", check.ID), nil)
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+	if !strings.Contains(rec.Body.String(), `"in_maintenance":true`) {
+		t.Errorf("expected the check to be in maintenance, got %s", rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/maintenance/1", nil)
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", rec.Code)
+	}
+
+	windows, _ := store.ListMaintenanceWindows()
+	if len(windows) != 1 || windows[0].Name != "Nightly" {
+		t.Errorf("expected only the nightly window left, got %+v", windows)
+	}
+}
+
+func TestAPIMaintenanceBanner(t *testing.T) {
+	server, _ := setupTestServer(t)
+
+	do := func(method, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/maintenance/banner", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		server.echo.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do(http.MethodGet, ""); !strings.Contains(rec.Body.String(), `"data":null`) {
+		t.Errorf("expected no banner, got %s", rec.Body.String())
+	}
+
+	if rec := do(http.MethodPut, `{"message":"  "}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an empty message, got %d", rec.Code)
+	}
+
+	ends := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	rec := do(http.MethodPut, fmt.Sprintf(`{"message":"Database upgrade in progress","ends_at":%q}`, ends))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if banner := server.activeBanner(); banner == nil || banner.Message != "Database upgrade in progress" {
+		t.Errorf("expected the banner to be active, got %+v", banner)
+	}
+	if rec := do(http.MethodGet, ""); !strings.Contains(rec.Body.String(), "Database upgrade in progress") {
+		t.Errorf("expected the banner, got %s", rec.Body.String())
+	}
+
+	// A banner scheduled for later is stored but not shown yet
+	starts := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	if rec := do(http.MethodPut, fmt.Sprintf(`{"message":"Tomorrow","starts_at":%q}`, starts)); rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if banner := server.activeBanner(); banner != nil {
+		t.Errorf("expected a future banner to be hidden, got %+v", banner)
+	}
+
+	if rec := do(http.MethodDelete, ""); rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if rec := do(http.MethodGet, ""); !strings.Contains(rec.Body.String(), `"data":null`) {
+		t.Errorf("expected the banner to be cleared, got %s", rec.Body.String())
+	}
+}
@@ -0,0 +1,95 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+// metricsContentType is the Prometheus text exposition format, which every
+// scraper understands without the client library
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// metric is one family in the exposition: its help and type lines, then a
+// sample per check
+type metric struct {
+	name    string
+	help    string
+	kind    string
+	samples []string
+}
+
+func (m *metric) add(check *storage.Check, value any) {
+	m.samples = append(m.samples, fmt.Sprintf("%s{check=\"%s\",id=\"%d\"} %v", m.name, escapeLabel(check.Name), check.ID, value))
+}
+
+func (m *metric) writeTo(b *strings.Builder) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind)
+	for _, sample := range m.samples {
+		b.WriteString(sample)
+		b.WriteByte('\n')
+	}
+}
+
+// HandleMetrics exposes each check's current state for Prometheus. Pending
+// checks have no up or response time sample until their first result.
+func (s *Server) HandleMetrics(c echo.Context) error {
+	checks, err := s.storage.ListChecks()
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+
+	up := &metric{name: "sentinel_check_up", help: "Whether the check's latest result is up (1) or down (0).", kind: "gauge"}
+	responseTime := &metric{name: "sentinel_check_response_time_ms", help: "Response time of the check's latest result in milliseconds.", kind: "gauge"}
+	sslDays := &metric{name: "sentinel_check_ssl_days_left", help: "Days until the check's SSL certificate expires.", kind: "gauge"}
+	uptime := &metric{name: "sentinel_check_uptime_percent_24h", help: "Weighted uptime of the check over the last 24 hours.", kind: "gauge"}
+	incidents := &metric{name: "sentinel_check_incidents_total", help: "Incidents opened for the check.", kind: "counter"}
+	active := &metric{name: "sentinel_check_incident_active", help: "Whether the check has an open incident.", kind: "gauge"}
+
+	now := time.Now()
+	for _, check := range checks {
+		// GetResultDetails rather than GetLatestResult since it carries SSL details
+		results, err := s.storage.GetResultDetails(check.ID, 1)
+		if err != nil {
+			return c.String(http.StatusInternalServerError, err.Error())
+		}
+		if len(results) > 0 {
+			latest := results[0]
+			value := 0
+			if latest.IsUp() {
+				value = 1
+			}
+			up.add(check, value)
+			responseTime.add(check, latest.ResponseTimeMs)
+			if latest.SSLExpiresAt != nil {
+				sslDays.add(check, latest.SSLDaysLeft)
+			}
+		}
+
+		if stats, err := s.storage.GetStats(check.ID); err == nil {
+			uptime.add(check, stats.UptimePercent24h)
+		}
+
+		if stats, err := s.storage.GetIncidentStats(check.ID, time.Time{}, now); err == nil {
+			incidents.add(check, stats.Count)
+			active.add(check, stats.Count-stats.Resolved)
+		}
+	}
+
+	var b strings.Builder
+	for _, m := range []*metric{up, responseTime, sslDays, uptime, incidents, active} {
+		m.writeTo(&b)
+	}
+
+	return c.Blob(http.StatusOK, metricsContentType, []byte(b.String()))
+}
+
+// escapeLabel escapes a label value per the exposition format
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
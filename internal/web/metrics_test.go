@@ -0,0 +1,53 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+func TestMetrics(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	api := &storage.Check{Name: `API "v2"`, URL: "https://api.example.com", IntervalSecs: 60, TimeoutSecs: 5, ExpectedStatus: 200, Enabled: true}
+	store.CreateCheck(api)
+	store.CreateCheck(&storage.Check{Name: "Pending", URL: "https://pending.example.com", IntervalSecs: 60, TimeoutSecs: 5, ExpectedStatus: 200, Enabled: true})
+
+	expires := time.Now().Add(30 * 24 * time.Hour)
+	store.SaveResult(&storage.CheckResult{CheckID: api.ID, Status: "up", StatusCode: 200, ResponseTimeMs: 120, SSLExpiresAt: &expires, SSLDaysLeft: 30})
+	store.CreateIncident(&storage.Incident{CheckID: api.ID, StartedAt: time.Now()})
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("expected Prometheus content type, got %q", ct)
+	}
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE sentinel_check_up gauge",
+		`sentinel_check_up{check="API \"v2\"",id="1"} 1`,
+		`sentinel_check_response_time_ms{check="API \"v2\"",id="1"} 120`,
+		`sentinel_check_ssl_days_left{check="API \"v2\"",id="1"} 30`,
+		"# TYPE sentinel_check_incidents_total counter",
+		`sentinel_check_incidents_total{check="API \"v2\"",id="1"} 1`,
+		`sentinel_check_incident_active{check="API \"v2\"",id="1"} 1`,
+		`sentinel_check_incidents_total{check="Pending",id="2"} 0`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected metrics to contain %q, got:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, `sentinel_check_up{check="Pending"`) {
+		t.Error("expected no up sample for a pending check")
+	}
+}
@@ -13,8 +13,8 @@ import (
 	"github.com/katieblackabee/sentinel/internal/storage"
 )
 
-func setupProbeHandler(t *testing.T) (*ProbeHandler, *storage.SQLiteStorage) {
-	store, err := storage.NewSQLiteStorage(":memory:")
+func setupProbeHandler(t *testing.T) (*ProbeHandler, *storage.MemoryStorage) {
+	store, err := storage.NewMemoryStorage()
 	if err != nil {
 		t.Fatalf("Failed to create storage: %v", err)
 	}
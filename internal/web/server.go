@@ -8,11 +8,13 @@ import (
 	"io"
 	"io/fs"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 
+	"github.com/katieblackabee/sentinel/internal/alerter"
 	"github.com/katieblackabee/sentinel/internal/checker"
 	"github.com/katieblackabee/sentinel/internal/config"
 	"github.com/katieblackabee/sentinel/internal/probe"
@@ -33,6 +35,8 @@ type Server struct {
 	scheduler    *checker.Scheduler
 	auth         *AuthManager
 	probeHandler *ProbeHandler
+	alerts       *alerter.Manager
+	events       *EventBroker
 }
 
 type Template struct {
@@ -51,6 +55,21 @@ func (t *Template) Render(w io.Writer, name string, data interface{}, c echo.Con
 func NewServer(cfg *config.ServerConfig, fullCfg *config.Config, store storage.Storage, sched *checker.Scheduler, users map[string]string, registry *probe.ProbeRegistry, coordinator *probe.Coordinator) *Server {
 	e := echo.New()
 	e.HideBanner = true
+	// Client addresses, as login rate limiting sees them, come from
+	// X-Forwarded-For only when a proxy on a private network set it
+	e.IPExtractor = echo.ExtractIPFromXFFHeader()
+
+	// Frontends on other origins may call the API. This is on the server
+	// rather than the /api group because the router answers preflight
+	// OPTIONS requests itself, outside the group and its auth.
+	if len(cfg.AllowedOrigins) > 0 {
+		e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+			Skipper: func(c echo.Context) bool {
+				return !strings.HasPrefix(c.Request().URL.Path, "/api/")
+			},
+			AllowOrigins: cfg.AllowedOrigins,
+		}))
+	}
 
 	// Middleware
 	e.Use(middleware.Recover())
@@ -70,8 +89,9 @@ func NewServer(cfg *config.ServerConfig, fullCfg *config.Config, store storage.S
 
 	// Auth manager
 	var auth *AuthManager
-	if len(users) > 0 {
+	if len(users) > 0 || len(cfg.APIKeys) > 0 {
 		auth = NewAuthManager(users, basePath)
+		auth.apiKeys = cfg.APIKeys
 	}
 
 	// Probe handler
@@ -88,6 +108,7 @@ func NewServer(cfg *config.ServerConfig, fullCfg *config.Config, store storage.S
 		scheduler:    sched,
 		auth:         auth,
 		probeHandler: probeHandler,
+		events:       NewEventBroker(),
 	}
 
 	// Register routes
@@ -102,11 +123,14 @@ func (s *Server) registerRoutes() {
 	s.echo.POST("/login", s.HandleLogin)
 	s.echo.GET("/logout", s.HandleLogout)
 
-	// Health check (public)
+	// Health check and Prometheus metrics (public)
 	s.echo.GET("/api/health", s.HandleHealth)
+	s.echo.GET("/metrics", s.HandleMetrics)
 
 	// Public status pages
 	s.echo.GET("/status/:slug", s.handleStatusPage)
+	s.echo.GET("/status/:slug/feed.xml", s.handleStatusFeed)
+	s.echo.GET("/api/status/:slug", s.HandleStatusPageJSON)
 
 	// Protected routes
 	if s.auth != nil {
@@ -114,21 +138,47 @@ func (s *Server) registerRoutes() {
 		s.echo.GET("/", s.HandleDashboard, s.auth.RequireAuth)
 		s.echo.GET("/checks/:id", s.HandleCheckDetail, s.auth.RequireAuth)
 		s.echo.GET("/settings", s.HandleSettings, s.auth.RequireAuth)
+		s.echo.GET("/events", s.HandleEvents, s.auth.RequireAuth)
 		s.echo.POST("/settings/checks", s.HandleCreateCheckForm, s.auth.RequireAuth)
 		s.echo.GET("/settings/checks/:id/edit", s.HandleEditCheckForm, s.auth.RequireAuth)
 		s.echo.POST("/settings/checks/:id/edit", s.HandleEditCheckForm, s.auth.RequireAuth)
 		s.echo.POST("/settings/checks/:id/delete", s.HandleDeleteCheckForm, s.auth.RequireAuth)
+		s.echo.POST("/settings/checks/:id/pause", s.HandlePauseCheckForm, s.auth.RequireAuth)
+		s.echo.POST("/settings/checks/:id/resume", s.HandleResumeCheckForm, s.auth.RequireAuth)
 
 		// API with auth
-		api := s.echo.Group("/api", s.auth.RequireAuth)
+		api := s.echo.Group("/api", s.auth.RequireAPIAuth)
 		api.GET("/checks", s.HandleListChecks)
 		api.POST("/checks", s.HandleCreateCheck)
+		api.POST("/checks/trigger-all", s.HandleTriggerAll)
+		api.POST("/checks/import", s.HandleImportChecks)
+		api.GET("/checks/export", s.HandleExportChecks)
+		api.GET("/backup", s.HandleBackup)
 		api.GET("/checks/:id", s.HandleGetCheck)
 		api.PUT("/checks/:id", s.HandleUpdateCheck)
 		api.DELETE("/checks/:id", s.HandleDeleteCheck)
 		api.GET("/checks/:id/results", s.HandleGetCheckResults)
+		api.GET("/checks/:id/results.csv", s.HandleExportCheckResults)
 		api.GET("/checks/:id/stats", s.HandleGetCheckStats)
+		api.GET("/checks/:id/incident-stats", s.HandleGetIncidentStats)
+		api.GET("/checks/:id/uptime", s.HandleGetUptimeSeries)
+		api.GET("/checks/:id/diagnostics", s.HandleGetCheckDiagnostics)
 		api.POST("/checks/:id/trigger", s.HandleTriggerCheck)
+		api.POST("/checks/:id/push", s.HandlePushResult)
+		api.POST("/checks/:id/pause", s.HandlePauseCheck)
+		api.POST("/checks/:id/resume", s.HandleResumeCheck)
+		api.POST("/checks/:id/reset", s.HandleResetCheck)
+		api.GET("/checks/:id/golden", s.HandleGetGolden)
+		api.POST("/checks/:id/golden", s.HandleRecordGolden)
+		api.DELETE("/checks/:id/golden", s.HandleDeleteGolden)
+		api.GET("/groups", s.HandleListGroups)
+		api.GET("/alerts/channels", s.HandleListAlertChannels)
+		api.GET("/maintenance", s.HandleListMaintenanceWindows)
+		api.POST("/maintenance", s.HandleCreateMaintenanceWindow)
+		api.DELETE("/maintenance/:id", s.HandleDeleteMaintenanceWindow)
+		api.GET("/maintenance/banner", s.HandleGetBanner)
+		api.PUT("/maintenance/banner", s.HandleSetBanner)
+		api.DELETE("/maintenance/banner", s.HandleClearBanner)
 		api.GET("/incidents", s.HandleListIncidents)
 		api.GET("/incidents/active", s.HandleListActiveIncidents)
 		api.GET("/incidents/:id", s.HandleGetIncident)
@@ -137,6 +187,11 @@ func (s *Server) registerRoutes() {
 		api.POST("/incidents/:id/notes", s.HandleAddIncidentNote)
 		api.DELETE("/incidents/:id/notes/:noteId", s.HandleDeleteIncidentNote)
 
+		if s.config.GraphQL {
+			s.echo.GET("/graphql", s.HandleGraphQL, s.auth.RequireAPIAuth)
+			s.echo.POST("/graphql", s.HandleGraphQL, s.auth.RequireAPIAuth)
+		}
+
 		// Probe routes
 		if s.probeHandler != nil {
 			api.POST("/probes/register", s.probeHandler.RegisterProbe)
@@ -151,20 +206,46 @@ func (s *Server) registerRoutes() {
 		s.echo.GET("/", s.HandleDashboard)
 		s.echo.GET("/checks/:id", s.HandleCheckDetail)
 		s.echo.GET("/settings", s.HandleSettings)
+		s.echo.GET("/events", s.HandleEvents)
 		s.echo.POST("/settings/checks", s.HandleCreateCheckForm)
 		s.echo.GET("/settings/checks/:id/edit", s.HandleEditCheckForm)
 		s.echo.POST("/settings/checks/:id/edit", s.HandleEditCheckForm)
 		s.echo.POST("/settings/checks/:id/delete", s.HandleDeleteCheckForm)
+		s.echo.POST("/settings/checks/:id/pause", s.HandlePauseCheckForm)
+		s.echo.POST("/settings/checks/:id/resume", s.HandleResumeCheckForm)
 
 		api := s.echo.Group("/api")
 		api.GET("/checks", s.HandleListChecks)
 		api.POST("/checks", s.HandleCreateCheck)
+		api.POST("/checks/trigger-all", s.HandleTriggerAll)
+		api.POST("/checks/import", s.HandleImportChecks)
+		api.GET("/checks/export", s.HandleExportChecks)
+		api.GET("/backup", s.HandleBackup)
 		api.GET("/checks/:id", s.HandleGetCheck)
 		api.PUT("/checks/:id", s.HandleUpdateCheck)
 		api.DELETE("/checks/:id", s.HandleDeleteCheck)
 		api.GET("/checks/:id/results", s.HandleGetCheckResults)
+		api.GET("/checks/:id/results.csv", s.HandleExportCheckResults)
 		api.GET("/checks/:id/stats", s.HandleGetCheckStats)
+		api.GET("/checks/:id/incident-stats", s.HandleGetIncidentStats)
+		api.GET("/checks/:id/uptime", s.HandleGetUptimeSeries)
+		api.GET("/checks/:id/diagnostics", s.HandleGetCheckDiagnostics)
 		api.POST("/checks/:id/trigger", s.HandleTriggerCheck)
+		api.POST("/checks/:id/push", s.HandlePushResult)
+		api.POST("/checks/:id/pause", s.HandlePauseCheck)
+		api.POST("/checks/:id/resume", s.HandleResumeCheck)
+		api.POST("/checks/:id/reset", s.HandleResetCheck)
+		api.GET("/checks/:id/golden", s.HandleGetGolden)
+		api.POST("/checks/:id/golden", s.HandleRecordGolden)
+		api.DELETE("/checks/:id/golden", s.HandleDeleteGolden)
+		api.GET("/groups", s.HandleListGroups)
+		api.GET("/alerts/channels", s.HandleListAlertChannels)
+		api.GET("/maintenance", s.HandleListMaintenanceWindows)
+		api.POST("/maintenance", s.HandleCreateMaintenanceWindow)
+		api.DELETE("/maintenance/:id", s.HandleDeleteMaintenanceWindow)
+		api.GET("/maintenance/banner", s.HandleGetBanner)
+		api.PUT("/maintenance/banner", s.HandleSetBanner)
+		api.DELETE("/maintenance/banner", s.HandleClearBanner)
 		api.GET("/incidents", s.HandleListIncidents)
 		api.GET("/incidents/active", s.HandleListActiveIncidents)
 		api.GET("/incidents/:id", s.HandleGetIncident)
@@ -173,6 +254,11 @@ func (s *Server) registerRoutes() {
 		api.POST("/incidents/:id/notes", s.HandleAddIncidentNote)
 		api.DELETE("/incidents/:id/notes/:noteId", s.HandleDeleteIncidentNote)
 
+		if s.config.GraphQL {
+			s.echo.GET("/graphql", s.HandleGraphQL)
+			s.echo.POST("/graphql", s.HandleGraphQL)
+		}
+
 		// Probe routes
 		if s.probeHandler != nil {
 			api.POST("/probes/register", s.probeHandler.RegisterProbe)
@@ -185,10 +271,30 @@ func (s *Server) registerRoutes() {
 	}
 }
 
+// SetAlertManager exposes alert channel health through the API
+func (s *Server) SetAlertManager(m *alerter.Manager) {
+	s.alerts = m
+}
+
+// SetEventBroker replaces the broker /events streams from, so it can be
+// shared with a scheduler created before the server
+func (s *Server) SetEventBroker(b *EventBroker) {
+	s.events = b
+}
+
 func (s *Server) BasePath() string {
 	return s.config.BaseURL
 }
 
+// staleIntervals is how many intervals a check may go without a result
+// before it is shown as stale
+func (s *Server) staleIntervals() int {
+	if s.fullConfig == nil {
+		return storage.DefaultStaleIntervals
+	}
+	return s.fullConfig.Alerts.GetStaleIntervals()
+}
+
 func (s *Server) Start() error {
 	addr := fmt.Sprintf("%s:%d", s.config.Host, s.config.Port)
 	fmt.Printf("Starting server on %s\n", addr)
@@ -203,5 +309,7 @@ func (s *Server) Start() error {
 }
 
 func (s *Server) Shutdown(ctx context.Context) error {
+	// Open event streams would otherwise hold shutdown until ctx expires
+	s.events.Close()
 	return s.echo.Shutdown(ctx)
 }
@@ -48,7 +48,8 @@ function getThemeColors() {
         textBright: style.getPropertyValue('--text-bright').trim(),
         orange: style.getPropertyValue('--orange').trim(),
         statusUp: style.getPropertyValue('--status-up').trim(),
-        statusDown: style.getPropertyValue('--status-down').trim()
+        statusDown: style.getPropertyValue('--status-down').trim(),
+        statusDegraded: style.getPropertyValue('--status-degraded').trim()
     };
 }
 
@@ -58,7 +59,9 @@ function getThemeColors() {
     const isDashboard = path === '/' || path.endsWith('/');
     
     if (isDashboard) {
-        let countdown = 30;
+        // Set by the server from dashboard_refresh
+        const refreshSecs = parseInt(document.body.dataset.refresh, 10) || 30;
+        let countdown = refreshSecs;
         const el = document.querySelector('.last-updated');
         
         if (el) {
@@ -70,10 +73,32 @@ function getThemeColors() {
             }, 1000);
         }
         
-        setTimeout(() => location.reload(), 30000);
+        setTimeout(() => location.reload(), refreshSecs * 1000);
     }
 })();
 
+// Live status updates
+(function() {
+    const path = window.location.pathname;
+    const isDashboard = path === '/' || path.endsWith('/');
+    if (!isDashboard || typeof EventSource === 'undefined') return;
+
+    const events = new EventSource((document.body.dataset.basePath || '') + '/events');
+    events.addEventListener('status', (e) => {
+        const event = JSON.parse(e.data);
+        const card = document.querySelector(`.check-card[data-check-id="${event.check_id}"]`);
+        if (!card) return;
+
+        const status = card.querySelector('.check-status');
+        if (status) status.className = `check-status ${event.to}`;
+
+        const time = card.querySelector('.response-time');
+        if (time && event.response_time_ms) {
+            time.innerHTML = `${event.response_time_ms}<small>ms</small>`;
+        }
+    });
+})();
+
 // Chart
 function drawResponseChart() {
     const canvas = document.getElementById('responseChart');
@@ -146,6 +171,8 @@ function drawResponseChart() {
             ctx.fillStyle = theme.statusUp;
         } else if (statuses[i] === 'down') {
             ctx.fillStyle = theme.statusDown;
+        } else if (statuses[i] === 'degraded') {
+            ctx.fillStyle = theme.statusDegraded;
         } else {
             ctx.fillStyle = theme.textDim;
         }
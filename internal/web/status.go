@@ -0,0 +1,88 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// statusCacheSecs is how long clients and CDNs may cache a status page's
+// JSON, about as often as the data changes
+const statusCacheSecs = 30
+
+// StatusPageJSON is the public, machine-readable form of a status page. It
+// carries what the page shows, never check URLs or other config.
+type StatusPageJSON struct {
+	Slug           string               `json:"slug"`
+	Title          string               `json:"title"`
+	AllOperational bool                 `json:"all_operational"`
+	OverallUptime  float64              `json:"overall_uptime"`
+	Checks         []StatusCheckJSON    `json:"checks"`
+	Incidents      []StatusIncidentJSON `json:"incidents"`
+	GeneratedAt    time.Time            `json:"generated_at"`
+}
+
+type StatusCheckJSON struct {
+	Name           string     `json:"name"`
+	Status         string     `json:"status"`
+	UptimePercent  float64    `json:"uptime_percent"` // Last 24 hours
+	ResponseTimeMs int        `json:"response_time_ms,omitempty"`
+	LastCheckedAt  *time.Time `json:"last_checked_at,omitempty"`
+}
+
+type StatusIncidentJSON struct {
+	CheckName       string     `json:"check_name"`
+	StartedAt       time.Time  `json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at,omitempty"`
+	DurationSeconds int        `json:"duration_seconds"`
+	Cause           string     `json:"cause,omitempty"`
+	Status          string     `json:"status"`
+}
+
+// HandleStatusPageJSON serves a status page as JSON, for sites that render
+// status in their own design. It's public like the page itself.
+func (s *Server) HandleStatusPageJSON(c echo.Context) error {
+	data := s.statusPageData(c.Param("slug"))
+	if data == nil {
+		return c.JSON(http.StatusNotFound, APIResponse{Error: "status page not found"})
+	}
+
+	page := StatusPageJSON{
+		Slug:           data.Slug,
+		Title:          data.Title,
+		AllOperational: data.AllOperational,
+		OverallUptime:  data.OverallUptime,
+		Checks:         make([]StatusCheckJSON, 0, len(data.Checks)),
+		Incidents:      make([]StatusIncidentJSON, 0, len(data.RecentIncidents)),
+		GeneratedAt:    data.LastUpdated.UTC(),
+	}
+	for _, check := range data.Checks {
+		page.Checks = append(page.Checks, StatusCheckJSON{
+			Name:           check.Name,
+			Status:         check.Status,
+			UptimePercent:  check.UptimePercent,
+			ResponseTimeMs: check.LastResponseMs,
+			LastCheckedAt:  check.LastCheckedAt,
+		})
+	}
+	for _, incident := range data.RecentIncidents {
+		status := "ongoing"
+		if !incident.IsActive() {
+			status = "resolved"
+		}
+		page.Incidents = append(page.Incidents, StatusIncidentJSON{
+			CheckName:       incident.CheckName,
+			StartedAt:       incident.StartedAt,
+			EndedAt:         incident.EndedAt,
+			DurationSeconds: int(incident.Duration().Seconds()),
+			Cause:           incident.Cause,
+			Status:          status,
+		})
+	}
+
+	c.Response().Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", statusCacheSecs))
+	c.Response().Header().Set("Access-Control-Allow-Origin", "*")
+	return c.JSON(http.StatusOK, APIResponse{Data: page})
+}
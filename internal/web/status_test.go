@@ -0,0 +1,72 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/katieblackabee/sentinel/internal/storage"
+)
+
+func TestHandleStatusPageJSON(t *testing.T) {
+	server, store := setupTestServer(t)
+
+	api := &storage.Check{Name: "API Server", URL: "https://api.example.com/internal-health", IntervalSecs: 30, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true, Tags: []string{"public"}}
+	web := &storage.Check{Name: "Web App", URL: "https://app.example.com", IntervalSecs: 30, TimeoutSecs: 10, ExpectedStatus: 200, Enabled: true, Tags: []string{"public"}}
+	store.CreateCheck(api)
+	store.CreateCheck(web)
+	store.SaveResult(&storage.CheckResult{CheckID: api.ID, Status: "up", StatusCode: 200, ResponseTimeMs: 120})
+	store.SaveResult(&storage.CheckResult{CheckID: web.ID, Status: "down", StatusCode: 503, ErrorMessage: "HTTP 503"})
+	store.CreateIncident(&storage.Incident{CheckID: web.ID, StartedAt: time.Now().Add(-time.Minute), Cause: "HTTP 503"})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/status/public", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if cc := rec.Header().Get("Cache-Control"); cc != "public, max-age=30" {
+		t.Errorf("expected a short public cache, got %q", cc)
+	}
+	if strings.Contains(rec.Body.String(), "internal-health") {
+		t.Error("expected check URLs to stay private")
+	}
+
+	var resp struct {
+		Data StatusPageJSON `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	page := resp.Data
+	if page.AllOperational || page.GeneratedAt.IsZero() {
+		t.Errorf("expected a degraded page with generated_at, got %+v", page)
+	}
+
+	statuses := map[string]string{}
+	for _, check := range page.Checks {
+		statuses[check.Name] = check.Status
+	}
+	if statuses["API Server"] != "up" || statuses["Web App"] != "down" {
+		t.Errorf("expected current statuses, got %v", statuses)
+	}
+	if len(page.Incidents) != 1 || page.Incidents[0].Status != "ongoing" || page.Incidents[0].Cause != "HTTP 503" {
+		t.Errorf("expected the ongoing incident, got %+v", page.Incidents)
+	}
+}
+
+func TestHandleStatusPageJSONNotFound(t *testing.T) {
+	server, _ := setupTestServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/status/nonexistent", nil)
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", rec.Code)
+	}
+}